@@ -0,0 +1,172 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/role"
+)
+
+// AccessibleState holds flags that describe the state of a panel, or of an item within one, to assistive technologies.
+// The states that can be derived from the panel itself, such as whether it is enabled, visible or has the keyboard
+// focus, are added by the platform and need not be set.
+type AccessibleState uint32
+
+// Possible AccessibleState flags.
+const (
+	AccessibleChecked AccessibleState = 1 << iota
+	AccessibleMixed
+	AccessiblePressed
+	AccessibleSelectable
+	AccessibleSelected
+	AccessibleMultiSelectable
+	AccessibleExpandable
+	AccessibleExpanded
+	AccessibleEditable
+	AccessibleReadOnly
+	AccessibleMultiLine
+	AccessibleHasPopup
+	AccessibleInvalid
+	AccessibleDefault
+)
+
+// Accessibility describes a panel, or an item within one, to assistive technologies such as screen readers.
+type Accessibility struct {
+	// Action is called when an assistive technology asks to activate the panel or item, typically as if it had been
+	// clicked. May be nil.
+	Action func()
+	// Item returns the description of the item at the index. Items expose content that a panel draws itself rather
+	// than building from child panels, such as the rows of a List or Table. The Bounds field of the result is in the
+	// panel's local coordinates.
+	Item func(index int) Accessibility
+	// Name is the text that identifies the panel or item, such as the title of a button.
+	Name string
+	// Description provides additional information, such as the text of a tooltip.
+	Description string
+	// Value is the text content of an editable or selectable panel, such as the text within a Field or the title of the
+	// selected item of a PopupMenu.
+	Value string
+	// Bounds is the area covered by an item. Ignored for panels, which use their frame.
+	Bounds geom.Rect
+	// Minimum, Maximum and Current describe a numeric value within a range, such as that of a slider. Only used when
+	// Maximum is greater than Minimum.
+	Minimum float64
+	Maximum float64
+	Current float64
+	// ItemCount is the number of items available via Item.
+	ItemCount int
+	// ActiveItem is the index of the item that has the keyboard focus within the panel, or -1 if there is none. Only
+	// used when ItemCount is greater than zero.
+	ActiveItem int
+	// SelectionStart and SelectionEnd are the rune offsets of the selection within Value for editable text. They are
+	// the same when nothing is selected, in which case they are the position of the caret.
+	SelectionStart int
+	SelectionEnd   int
+	// Role identifies what the panel or item is. Panels with a role of role.None are not exposed; their children are
+	// presented as children of their parent instead.
+	Role  role.Enum
+	State AccessibleState
+}
+
+// nextAccessibleID is the source of the identifiers handed out by Panel.accessibleIdentifier(). UI-thread-only.
+var nextAccessibleID uint64
+
+// Accessibility returns the description of this panel for assistive technologies. Panels without an
+// AccessibilityCallback are not exposed. If the description has no Description, the text of the panel's tooltip is
+// used.
+func (p *Panel) Accessibility() Accessibility {
+	a := Accessibility{ActiveItem: -1}
+	if p.AccessibilityCallback != nil {
+		SafeCall(func() { a = p.AccessibilityCallback() })
+	}
+	if a.Description == "" && a.Role != role.None && p.Tooltip != nil {
+		if a.Description = AccessibleText(p.Tooltip); a.Description == a.Name {
+			a.Description = ""
+		}
+	}
+	return a
+}
+
+// AccessibleChildren returns the panels that assistive technologies present as the children of this panel: its
+// visible children, with any that are not exposed replaced by their own accessible children.
+func (p *Panel) AccessibleChildren() []*Panel {
+	return p.appendAccessibleChildren(nil)
+}
+
+func (p *Panel) appendAccessibleChildren(list []*Panel) []*Panel {
+	for _, child := range p.children {
+		if child.Hidden {
+			continue
+		}
+		if child.Accessibility().Role == role.None {
+			list = child.appendAccessibleChildren(list)
+		} else {
+			list = append(list, child)
+		}
+	}
+	return list
+}
+
+// AccessibleParent returns the nearest ancestor that assistive technologies present as the parent of this panel, or nil
+// if there is none. The root panel of a window always represents the window itself.
+func (p *Panel) AccessibleParent() *Panel {
+	for parent := p.parent; parent != nil; parent = parent.parent {
+		if parent.parent == nil || parent.Accessibility().Role != role.None {
+			return parent
+		}
+	}
+	return nil
+}
+
+// accessibleIdentifier returns an identifier for this panel that is unique for the life of the process, assigning one
+// if needed.
+func (p *Panel) accessibleIdentifier() uint64 {
+	if p.accessibleID == 0 {
+		nextAccessibleID++
+		p.accessibleID = nextAccessibleID
+	}
+	return p.accessibleID
+}
+
+// accessibleFocus returns the panel that assistive technologies should consider to have the keyboard focus within the
+// window: the focused panel, if it is exposed, or its nearest exposed ancestor.
+func (w *Window) accessibleFocus() *Panel {
+	focus := w.Focus()
+	if focus != nil && focus.parent != nil && focus.Accessibility().Role == role.None {
+		focus = focus.AccessibleParent()
+	}
+	return focus
+}
+
+// AccessibleText returns the text of the labels within the panel, which is how panels that contain only text, such as
+// tooltips and table cells, are presented to assistive technologies.
+func AccessibleText(panel Paneler) string {
+	var buffer strings.Builder
+	appendAccessibleText(&buffer, panel.AsPanel())
+	return buffer.String()
+}
+
+func appendAccessibleText(buffer *strings.Builder, p *Panel) {
+	if p.Hidden {
+		return
+	}
+	if a := p.Accessibility(); a.Name != "" {
+		if buffer.Len() != 0 {
+			buffer.WriteByte(' ')
+		}
+		buffer.WriteString(a.Name)
+		return
+	}
+	for _, child := range p.children {
+		appendAccessibleText(buffer, child)
+	}
+}
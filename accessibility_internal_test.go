@@ -0,0 +1,169 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	checkstate "github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/role"
+)
+
+// TestAccessibleChildren verifies that panels that are not exposed are replaced by their children, and that hidden
+// panels are left out entirely.
+func TestAccessibleChildren(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	group := NewPanel()
+	button := NewButton()
+	button.SetTitle("OK")
+	label := NewLabel()
+	label.SetTitle("Name:")
+	spacer := NewLabel()
+	hidden := NewField()
+	hidden.Hidden = true
+	group.AddChild(label)
+	group.AddChild(spacer)
+	content.AddChild(group)
+	content.AddChild(button)
+	content.AddChild(hidden)
+	w := newHeadlessTestWindow(t, content)
+	w.SetTitle("Title")
+
+	root := w.root.AsPanel()
+	a := root.Accessibility()
+	c.Equal(role.Window, a.Role)
+	c.Equal("Title", a.Name)
+	c.Equal(role.None, group.Accessibility().Role)
+	c.Equal(role.None, spacer.Accessibility().Role)
+	c.Equal([]*Panel{label.AsPanel(), button.AsPanel()}, root.AccessibleChildren())
+	c.Equal(root, label.AccessibleParent())
+	c.Equal(0, len(label.AccessibleChildren()))
+	c.Equal("Name: OK", AccessibleText(content))
+
+	// Identifiers are stable and unique.
+	id := button.accessibleIdentifier()
+	c.NotEqual(uint64(0), id)
+	c.Equal(id, button.accessibleIdentifier())
+	c.NotEqual(id, label.accessibleIdentifier())
+
+	// The accessible focus is the nearest exposed panel.
+	group.SetFocusable(true)
+	group.RequestFocus()
+	c.Equal(root, w.accessibleFocus())
+	button.RequestFocus()
+	c.Equal(button.AsPanel(), w.accessibleFocus())
+}
+
+// TestWidgetAccessibility verifies the descriptions provided by the standard widgets.
+func TestWidgetAccessibility(t *testing.T) {
+	c := check.New(t)
+
+	button := NewButton()
+	button.SetTitle("Apply")
+	button.Tooltip = NewTooltipWithText("Apply the changes")
+	clicked := false
+	button.ClickCallback = func() { clicked = true }
+	a := button.Accessibility()
+	c.Equal(role.Button, a.Role)
+	c.Equal("Apply", a.Name)
+	c.Equal("Apply the changes", a.Description)
+	a.Action()
+	c.True(clicked)
+
+	// A button without a title is named by its tooltip, which then isn't repeated as its description.
+	button.SetTitle("")
+	a = button.Accessibility()
+	c.Equal("Apply the changes", a.Name)
+	c.Equal("", a.Description)
+
+	button.Sticky = true
+	group := NewGroup(button)
+	c.Equal(role.ToggleButton, button.Accessibility().Role)
+	c.Equal(AccessibleState(0), button.Accessibility().State)
+	group.Select(button)
+	c.Equal(AccessiblePressed, button.Accessibility().State)
+
+	checkBox := NewCheckBox()
+	checkBox.SetTitle("Enabled")
+	a = checkBox.Accessibility()
+	c.Equal(role.CheckBox, a.Role)
+	c.Equal("Enabled", a.Name)
+	c.Equal(AccessibleState(0), a.State)
+	checkBox.State = checkstate.Mixed
+	c.Equal(AccessibleMixed, checkBox.Accessibility().State)
+	checkBox.State = checkstate.On
+	c.Equal(AccessibleChecked, checkBox.Accessibility().State)
+
+	field := NewField()
+	field.Watermark = "Password"
+	field.SetText("secret")
+	field.SetSelection(1, 3)
+	a = field.Accessibility()
+	c.Equal(role.TextField, a.Role)
+	c.Equal("Password", a.Name)
+	c.Equal("secret", a.Value)
+	c.Equal(1, a.SelectionStart)
+	c.Equal(3, a.SelectionEnd)
+	c.Equal(AccessibleEditable, a.State)
+	field.ObscurementRune = '*'
+	field.SetEnabled(false)
+	a = field.Accessibility()
+	c.Equal(role.PasswordField, a.Role)
+	c.Equal("******", a.Value)
+	c.Equal(AccessibleReadOnly, a.State)
+
+	popup := NewPopupMenu[string]()
+	popup.AddItem("One", "Two")
+	popup.SelectIndex(1)
+	a = popup.Accessibility()
+	c.Equal(role.ComboBox, a.Role)
+	c.Equal("Two", a.Value)
+	c.Equal(AccessibleHasPopup, a.State)
+
+	var target string
+	link := NewLink("Home", "", "https://example.com", nil, func(_ Paneler, where string) { target = where })
+	a = link.Accessibility()
+	c.Equal(role.Link, a.Role)
+	c.Equal("Home", a.Name)
+	a.Action()
+	c.Equal("https://example.com", target)
+}
+
+// TestListAccessibility verifies that the rows of a list are exposed as items that can be selected.
+func TestListAccessibility(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	list := NewList[string]()
+	list.Append("Red", "Green", "Blue")
+	list.SetFrameRect(geom.NewRect(0, 0, 100, 100))
+	newHeadlessTestWindow(t, list)
+
+	a := list.Accessibility()
+	c.Equal(role.List, a.Role)
+	c.Equal(3, a.ItemCount)
+	c.Equal(-1, a.ActiveItem)
+	item := a.Item(1)
+	c.Equal(role.ListItem, item.Role)
+	c.Equal("Green", item.Name)
+	c.Equal(AccessibleSelectable, item.State)
+	c.Equal(list.RowRect(1), item.Bounds)
+
+	selected := 0
+	list.NewSelectionCallback = func() { selected++ }
+	item.Action()
+	c.Equal(1, selected)
+	c.Equal(1, list.Accessibility().ActiveItem)
+	c.Equal(AccessibleSelectable|AccessibleSelected, list.Accessibility().Item(1).State)
+	c.Equal(role.None, list.Accessibility().Item(3).Role)
+}
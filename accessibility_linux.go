@@ -0,0 +1,569 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison/enums/role"
+	"github.com/richardwilkes/unison/internal/x11"
+)
+
+// Assistive technologies on Linux reach the application through the AT-SPI bridge. Panels are identified by paths
+// built from their accessible identifiers, and items within them by appending their indexes, so that
+// "/org/a11y/atspi/accessible/12_3_1" is cell 1 of row 3 of the panel with identifier 12. The application itself is the
+// root, and the root panels of its windows are its children. All of this state is UI-thread-only.
+var (
+	x11Accessibility     *x11.AccessibilityBridge
+	x11AccessibleFocused x11AccessibleSnapshot
+	x11AccessibleWindows []*Window
+)
+
+// x11AccessibilityTimeout is how long a call from an assistive technology waits for the UI thread.
+const x11AccessibilityTimeout = 2 * time.Second
+
+// x11AccessibleSnapshot holds what was last reported about the panel with the accessible focus, so that changes to it
+// can be announced.
+type x11AccessibleSnapshot struct {
+	path   string
+	active string
+	node   x11.AccessibleNode
+}
+
+var x11AccessibleRoles = map[role.Enum]x11.AccessibleRole{
+	role.Window:        x11.AccessibleRoleFrame,
+	role.Dialog:        x11.AccessibleRoleDialog,
+	role.Group:         x11.AccessibleRolePanel,
+	role.Label:         x11.AccessibleRoleLabel,
+	role.Heading:       x11.AccessibleRoleHeading,
+	role.Paragraph:     x11.AccessibleRoleParagraph,
+	role.Document:      x11.AccessibleRoleDocumentFrame,
+	role.Image:         x11.AccessibleRoleImage,
+	role.Link:          x11.AccessibleRoleLink,
+	role.Button:        x11.AccessibleRolePushButton,
+	role.ToggleButton:  x11.AccessibleRoleToggleButton,
+	role.CheckBox:      x11.AccessibleRoleCheckBox,
+	role.RadioButton:   x11.AccessibleRoleRadioButton,
+	role.TextField:     x11.AccessibleRoleEntry,
+	role.PasswordField: x11.AccessibleRolePasswordText,
+	role.SpinButton:    x11.AccessibleRoleSpinButton,
+	role.ComboBox:      x11.AccessibleRoleComboBox,
+	role.MenuBar:       x11.AccessibleRoleMenuBar,
+	role.Menu:          x11.AccessibleRoleMenu,
+	role.MenuItem:      x11.AccessibleRoleMenuItem,
+	role.List:          x11.AccessibleRoleListBox,
+	role.ListItem:      x11.AccessibleRoleListItem,
+	role.Table:         x11.AccessibleRoleTable,
+	role.TreeTable:     x11.AccessibleRoleTreeTable,
+	role.TableRow:      x11.AccessibleRoleTableRow,
+	role.TableCell:     x11.AccessibleRoleTableCell,
+	role.ColumnHeader:  x11.AccessibleRoleTableColumnHeader,
+	role.Slider:        x11.AccessibleRoleSlider,
+	role.ProgressBar:   x11.AccessibleRoleProgressBar,
+	role.ScrollBar:     x11.AccessibleRoleScrollBar,
+	role.ScrollPane:    x11.AccessibleRoleScrollPane,
+	role.Separator:     x11.AccessibleRoleSeparator,
+	role.TabList:       x11.AccessibleRolePageTabList,
+	role.Tab:           x11.AccessibleRolePageTab,
+	role.SplitPane:     x11.AccessibleRoleSplitPane,
+	role.ToolBar:       x11.AccessibleRoleToolBar,
+	role.StatusBar:     x11.AccessibleRoleStatusBar,
+	role.Tooltip:       x11.AccessibleRoleToolTip,
+	role.Calendar:      x11.AccessibleRoleCalendar,
+	role.DateEditor:    x11.AccessibleRoleDateEditor,
+}
+
+var x11AccessibleStates = []struct {
+	state AccessibleState
+	atspi x11.AccessibleStates
+}{
+	{AccessibleChecked, x11.AccessibleStateChecked},
+	{AccessibleMixed, x11.AccessibleStateIndeterminate},
+	{AccessiblePressed, x11.AccessibleStatePressed},
+	{AccessibleSelectable, x11.AccessibleStateSelectable},
+	{AccessibleSelected, x11.AccessibleStateSelected},
+	{AccessibleMultiSelectable, x11.AccessibleStateMultiSelectable},
+	{AccessibleExpandable, x11.AccessibleStateExpandable},
+	{AccessibleExpanded, x11.AccessibleStateExpanded},
+	{AccessibleEditable, x11.AccessibleStateEditable},
+	{AccessibleReadOnly, x11.AccessibleStateReadOnly},
+	{AccessibleMultiLine, x11.AccessibleStateMultiLine},
+	{AccessibleHasPopup, x11.AccessibleStateHasPopup},
+	{AccessibleInvalid, x11.AccessibleStateInvalidEntry},
+	{AccessibleDefault, x11.AccessibleStateIsDefault},
+}
+
+// x11StartAccessibility connects to the accessibility bus in the background, since doing so involves several D-Bus
+// round trips, some of which require the UI thread to answer questions about the application.
+func x11StartAccessibility() {
+	if !x11.AccessibilityRequested() {
+		return
+	}
+	go func() {
+		bridge, err := x11.OpenAccessibilityBridge(x11AccessibleProvider{})
+		if err != nil {
+			slog.Info("unable to connect to the accessibility bus; screen readers will not be able to see the application",
+				"error", err)
+			return
+		}
+		InvokeTask(func() {
+			if x11Conn == nil {
+				bridge.Close()
+				return
+			}
+			x11Accessibility = bridge
+		})
+	}()
+}
+
+func x11StopAccessibility() {
+	if x11Accessibility != nil {
+		x11Accessibility.Close()
+		x11Accessibility = nil
+	}
+	x11AccessibleFocused = x11AccessibleSnapshot{}
+	x11AccessibleWindows = nil
+}
+
+// x11AccessibilityWindowActivated tells assistive technologies that the window has gained or lost the keyboard focus.
+func x11AccessibilityWindowActivated(w *Window, active bool) {
+	if x11Accessibility == nil {
+		return
+	}
+	path := x11AccessiblePath(w.root.AsPanel())
+	if active {
+		x11Accessibility.EmitStatesChanged(path, 0, x11.AccessibleStateActive)
+		x11Accessibility.EmitWindowActivated(path, true)
+		return
+	}
+	if x11AccessibleFocused.path != "" {
+		x11Accessibility.EmitStatesChanged(x11AccessibleFocused.path, x11.AccessibleStateFocused, 0)
+		x11AccessibleFocused = x11AccessibleSnapshot{}
+	}
+	x11Accessibility.EmitStatesChanged(path, x11.AccessibleStateActive, 0)
+	x11Accessibility.EmitWindowActivated(path, false)
+}
+
+// x11AccessibilityWindowClosed tells assistive technologies that the window is gone.
+func x11AccessibilityWindowClosed(w *Window) {
+	if i := slices.Index(x11AccessibleWindows, w); i != -1 {
+		x11AccessibleWindows = slices.Delete(x11AccessibleWindows, i, i+1)
+		if x11Accessibility != nil {
+			x11Accessibility.EmitChildrenChanged(x11.AccessibleRootPath, false, int32(i),
+				x11AccessiblePath(w.root.AsPanel()))
+		}
+	}
+}
+
+// apiUpdateAccessibility announces changes to the panel with the accessible focus. Since such changes can only become
+// visible as the result of a redraw, this is called after each one.
+func (w *Window) apiUpdateAccessibility() {
+	if x11Accessibility == nil {
+		return
+	}
+	if !slices.Contains(x11AccessibleWindows, w) {
+		x11AccessibleWindows = append(x11AccessibleWindows, w)
+		x11Accessibility.EmitChildrenChanged(x11.AccessibleRootPath, true,
+			int32(slices.Index(x11AccessibleWindowList(), w)), x11AccessiblePath(w.root.AsPanel()))
+	}
+	if !w.focused {
+		return
+	}
+	var current x11AccessibleSnapshot
+	if focus := w.accessibleFocus(); focus != nil {
+		a := focus.Accessibility()
+		current.path = x11AccessiblePath(focus)
+		current.node = x11PanelNode(focus, &a)
+		if a.ItemCount > 0 && a.ActiveItem >= 0 && a.ActiveItem < a.ItemCount {
+			current.active = current.path + "_" + strconv.Itoa(a.ActiveItem)
+		}
+	}
+	x11AccessibleFocusChanged(x11Accessibility, &x11AccessibleFocused, &current)
+	x11AccessibleFocused = current
+}
+
+// x11AccessibleFocusChanged emits the events that describe the difference between two snapshots of the accessible
+// focus.
+func x11AccessibleFocusChanged(bridge *x11.AccessibilityBridge, before, after *x11AccessibleSnapshot) {
+	if before.path != after.path {
+		if before.path != "" {
+			bridge.EmitStatesChanged(before.path, x11.AccessibleStateFocused, 0)
+		}
+		if after.path != "" {
+			bridge.EmitStatesChanged(after.path, 0, x11.AccessibleStateFocused)
+			if after.active != "" {
+				bridge.EmitActiveDescendantChanged(after.path, after.active)
+			}
+		}
+		return
+	}
+	if after.path == "" {
+		return
+	}
+	if before.node.Name != after.node.Name {
+		bridge.EmitNameChanged(after.path, after.node.Name)
+	}
+	bridge.EmitStatesChanged(after.path, before.node.States, after.node.States)
+	if before.node.Text != after.node.Text {
+		prefix, deleted, inserted := x11TextDifference(before.node.Text, after.node.Text)
+		if deleted != "" {
+			bridge.EmitTextChanged(after.path, false, int32(prefix), deleted)
+		}
+		if inserted != "" {
+			bridge.EmitTextChanged(after.path, true, int32(prefix), inserted)
+		}
+	}
+	if before.node.CaretOffset != after.node.CaretOffset {
+		bridge.EmitCaretMoved(after.path, after.node.CaretOffset)
+	}
+	if after.node.HasValue && before.node.Current != after.node.Current {
+		bridge.EmitValueChanged(after.path, after.node.Current)
+	}
+	if before.active != after.active && after.active != "" {
+		bridge.EmitActiveDescendantChanged(after.path, after.active)
+	}
+}
+
+// x11TextDifference returns the text that was deleted and inserted to turn before into after, assuming a single edit,
+// along with the rune offset at which it happened.
+func x11TextDifference(before, after string) (prefix int, deleted, inserted string) {
+	b := []rune(before)
+	a := []rune(after)
+	for prefix < len(b) && prefix < len(a) && b[prefix] == a[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(b)-prefix && suffix < len(a)-prefix && b[len(b)-1-suffix] == a[len(a)-1-suffix] {
+		suffix++
+	}
+	return prefix, string(b[prefix : len(b)-suffix]), string(a[prefix : len(a)-suffix])
+}
+
+// x11AccessiblePath returns the path of the panel.
+func x11AccessiblePath(p *Panel) string {
+	return x11.AccessiblePathPrefix + strconv.FormatUint(p.accessibleIdentifier(), 10)
+}
+
+// x11AccessibleTarget is what a path refers to: a panel, or an item within one.
+type x11AccessibleTarget struct {
+	panel  *Panel
+	a      Accessibility
+	path   string
+	parent string
+	index  int // Index of the item within its parent; only valid for items.
+	isItem bool
+}
+
+// x11ResolveAccessiblePath returns the target of the path, which must not be the root.
+func x11ResolveAccessiblePath(path string) (x11AccessibleTarget, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, x11.AccessiblePathPrefix), "_")
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || id == 0 {
+		return x11AccessibleTarget{}, false
+	}
+	var panel *Panel
+	for _, w := range Windows() {
+		if panel = x11FindAccessiblePanel(w.root.AsPanel(), id); panel != nil {
+			break
+		}
+	}
+	if panel == nil {
+		return x11AccessibleTarget{}, false
+	}
+	t := x11AccessibleTarget{panel: panel, a: panel.Accessibility(), path: x11.AccessiblePathPrefix + parts[0]}
+	for _, part := range parts[1:] {
+		index, convErr := strconv.Atoi(part)
+		if convErr != nil || index < 0 || index >= t.a.ItemCount || t.a.Item == nil {
+			return x11AccessibleTarget{}, false
+		}
+		t.parent = t.path
+		t.path += "_" + part
+		t.index = index
+		t.isItem = true
+		item := t.a.Item
+		SafeCall(func() { t.a = item(index) })
+	}
+	return t, true
+}
+
+func x11FindAccessiblePanel(p *Panel, id uint64) *Panel {
+	if p.accessibleID == id {
+		return p
+	}
+	for _, child := range p.children {
+		if found := x11FindAccessiblePanel(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// x11AccessibleNodeFor returns the description of the object with the path.
+func x11AccessibleNodeFor(path string) (x11.AccessibleNode, bool) {
+	if path == x11.AccessibleRootPath {
+		node := x11.AccessibleNode{Name: xos.AppName, Role: x11.AccessibleRoleApplication}
+		for _, w := range x11AccessibleWindowList() {
+			node.Children = append(node.Children, x11AccessiblePath(w.root.AsPanel()))
+		}
+		return node, true
+	}
+	t, ok := x11ResolveAccessiblePath(path)
+	if !ok {
+		return x11.AccessibleNode{}, false
+	}
+	if !t.isItem {
+		return x11PanelNode(t.panel, &t.a), true
+	}
+	node := x11BaseNode(t.panel, &t.a)
+	node.Bounds = x11ScreenRect(t.panel, t.panel.RectToRoot(t.a.Bounds))
+	node.Parent = t.parent
+	node.IndexInParent = int32(t.index)
+	if t.parent == x11AccessiblePath(t.panel) {
+		node.IndexInParent += int32(len(t.panel.AccessibleChildren()))
+	}
+	node.Children = x11ItemPaths(node.Children, t.path, &t.a)
+	if w := t.panel.Window(); w != nil && w.focused && w.accessibleFocus() == t.panel {
+		if parent, pok := x11ResolveAccessiblePath(t.parent); pok && parent.a.ActiveItem == t.index {
+			node.States |= x11.AccessibleStateFocused
+		}
+	}
+	return node, true
+}
+
+// x11AccessibleWindowList returns the windows that are presented as children of the application.
+func x11AccessibleWindowList() []*Window {
+	list := Windows()
+	return slices.DeleteFunc(list, func(w *Window) bool { return !w.IsVisible() })
+}
+
+// x11BaseNode returns the parts of the description of a panel, or an item within it, that come from its Accessibility.
+func x11BaseNode(p *Panel, a *Accessibility) x11.AccessibleNode {
+	node := x11.AccessibleNode{
+		Name:        a.Name,
+		Description: a.Description,
+		Role:        x11AccessibleRoles[a.Role],
+	}
+	if w := p.Window(); w != nil {
+		node.Window = x11ScreenRect(p, geom.Rect{Size: w.ContentRect().Size})
+	}
+	if a.Action != nil {
+		node.Actions = []string{"click"}
+	}
+	if p.Enabled() {
+		node.States |= x11.AccessibleStateEnabled | x11.AccessibleStateSensitive
+	}
+	if !p.Hidden {
+		node.States |= x11.AccessibleStateVisible | x11.AccessibleStateShowing
+	}
+	for _, one := range x11AccessibleStates {
+		if a.State&one.state != 0 {
+			node.States |= one.atspi
+		}
+	}
+	if a.State&(AccessibleExpandable|AccessibleExpanded) == AccessibleExpandable {
+		node.States |= x11.AccessibleStateCollapsed
+	}
+	switch a.Role {
+	case role.CheckBox, role.RadioButton:
+		node.States |= x11.AccessibleStateCheckable
+	case role.TextField, role.PasswordField:
+		node.HasText = true
+		node.Text = a.Value
+		length := utf8.RuneCountInString(a.Value)
+		node.SelectionStart = int32(max(min(a.SelectionStart, length), 0))
+		node.SelectionEnd = int32(max(min(a.SelectionEnd, length), int(node.SelectionStart)))
+		node.CaretOffset = node.SelectionEnd
+		node.States |= x11.AccessibleStateSelectableText
+		if a.State&AccessibleMultiLine == 0 {
+			node.States |= x11.AccessibleStateSingleLine
+		}
+	default:
+	}
+	if a.Maximum > a.Minimum {
+		node.HasValue = true
+		node.Minimum = a.Minimum
+		node.Maximum = a.Maximum
+		node.Current = a.Current
+	}
+	return node
+}
+
+// x11PanelNode returns the description of a panel.
+func x11PanelNode(p *Panel, a *Accessibility) x11.AccessibleNode {
+	node := x11BaseNode(p, a)
+	w := p.Window()
+	node.Bounds = x11ScreenRect(p, p.RectToRoot(p.ContentRect(true)))
+	if p.parent == nil {
+		node.Parent = x11.AccessibleRootPath
+		node.IndexInParent = int32(slices.Index(x11AccessibleWindowList(), w))
+		if w != nil && w.focused {
+			node.States |= x11.AccessibleStateActive
+		}
+		if a.Role == role.Dialog {
+			node.States |= x11.AccessibleStateModal
+		}
+		if w != nil && w.Resizable() {
+			node.States |= x11.AccessibleStateResizable
+		}
+	} else if parent := p.AccessibleParent(); parent != nil {
+		node.Parent = x11AccessiblePath(parent)
+		node.IndexInParent = int32(slices.Index(parent.AccessibleChildren(), p))
+	}
+	if p.Focusable() {
+		node.States |= x11.AccessibleStateFocusable
+		if w != nil && w.focused && w.accessibleFocus() == p {
+			node.States |= x11.AccessibleStateFocused
+		}
+	}
+	children := p.AccessibleChildren()
+	node.Children = make([]string, 0, len(children)+a.ItemCount)
+	for _, child := range children {
+		node.Children = append(node.Children, x11AccessiblePath(child))
+	}
+	node.Children = x11ItemPaths(node.Children, x11AccessiblePath(p), a)
+	return node
+}
+
+func x11ItemPaths(list []string, path string, a *Accessibility) []string {
+	if a.Item != nil {
+		for i := range a.ItemCount {
+			list = append(list, path+"_"+strconv.Itoa(i))
+		}
+	}
+	return list
+}
+
+// x11ScreenRect converts a rectangle in the root coordinates of the panel's window to screen pixels.
+func x11ScreenRect(p *Panel, r geom.Rect) x11.AccessibleRect {
+	w := p.Window()
+	if w == nil {
+		return x11.AccessibleRect{}
+	}
+	content := w.ContentRect()
+	scale := w.BackingScale()
+	return x11.AccessibleRect{
+		X:      int32((content.X + r.X) * scale.X),
+		Y:      int32((content.Y + r.Y) * scale.Y),
+		Width:  int32(r.Width * scale.X),
+		Height: int32(r.Height * scale.Y),
+	}
+}
+
+// x11AccessibleNodeAt returns the path of the deepest object within the one with the path that contains the point,
+// which is in screen pixels.
+func x11AccessibleNodeAt(path string, x, y int32) string {
+	var p *Panel
+	if path == x11.AccessibleRootPath {
+		for _, w := range x11AccessibleWindowList() {
+			if x11ScreenRect(w.root.AsPanel(), geom.Rect{Size: w.ContentRect().Size}).Contains(x, y) {
+				p = w.root.AsPanel()
+				break
+			}
+		}
+	} else if t, ok := x11ResolveAccessiblePath(path); ok && !t.isItem {
+		p = t.panel
+	}
+	if p == nil {
+		return ""
+	}
+	found := ""
+	for {
+		a := p.Accessibility()
+		var next *Panel
+		for _, child := range p.AccessibleChildren() {
+			if x11ScreenRect(child, child.RectToRoot(child.ContentRect(true))).Contains(x, y) {
+				next = child
+			}
+		}
+		if next == nil {
+			if a.Item != nil {
+				for i := range a.ItemCount {
+					var item Accessibility
+					SafeCall(func() { item = a.Item(i) })
+					if x11ScreenRect(p, p.RectToRoot(item.Bounds)).Contains(x, y) {
+						return x11AccessiblePath(p) + "_" + strconv.Itoa(i)
+					}
+				}
+			}
+			return found
+		}
+		p = next
+		found = x11AccessiblePath(p)
+	}
+}
+
+// x11AccessibleProvider supplies the accessibility tree to the bridge. The bridge calls it from its own goroutine, so
+// each call is handed to the UI thread. If the UI thread is too busy to answer in time, the call fails rather than
+// leaving the assistive technology waiting.
+type x11AccessibleProvider struct{}
+
+func x11OnUIThread(f func()) bool {
+	done := make(chan struct{})
+	InvokeTask(func() {
+		defer close(done)
+		f()
+	})
+	select {
+	case <-done:
+		return true
+	case <-time.After(x11AccessibilityTimeout):
+		return false
+	}
+}
+
+func (x11AccessibleProvider) AccessibleNode(path string) (x11.AccessibleNode, bool) {
+	var node x11.AccessibleNode
+	var ok bool
+	if !x11OnUIThread(func() { node, ok = x11AccessibleNodeFor(path) }) {
+		return x11.AccessibleNode{}, false
+	}
+	return node, ok
+}
+
+func (x11AccessibleProvider) AccessibleNodeAt(path string, x, y int32) string {
+	var found string
+	if !x11OnUIThread(func() { found = x11AccessibleNodeAt(path, x, y) }) {
+		return ""
+	}
+	return found
+}
+
+func (x11AccessibleProvider) DoAccessibleAction(path string, index int) bool {
+	var done bool
+	if !x11OnUIThread(func() {
+		if t, ok := x11ResolveAccessiblePath(path); ok && index == 0 && t.a.Action != nil && t.panel.Enabled() {
+			SafeCall(t.a.Action)
+			done = true
+		}
+	}) {
+		return false
+	}
+	return done
+}
+
+func (x11AccessibleProvider) GrabAccessibleFocus(path string) bool {
+	var focused bool
+	if !x11OnUIThread(func() {
+		if t, ok := x11ResolveAccessiblePath(path); ok && !t.isItem && t.panel.Focusable() {
+			t.panel.RequestFocus()
+			focused = t.panel.Focused()
+		}
+	}) {
+		return false
+	}
+	return focused
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/internal/x11"
+)
+
+// TestX11AccessibleNodes verifies the tree presented to the AT-SPI bridge, including the paths of items.
+func TestX11AccessibleNodes(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	button := NewButton()
+	button.SetTitle("OK")
+	button.SetFrameRect(geom.NewRect(5, 5, 40, 20))
+	list := NewList[string]()
+	list.Append("Red", "Green")
+	list.SetFrameRect(geom.NewRect(5, 30, 90, 60))
+	content.AddChild(button)
+	content.AddChild(list)
+	w := newHeadlessTestWindow(t, content)
+	button.RequestFocus()
+
+	rootPath := x11AccessiblePath(w.root.AsPanel())
+	app, ok := x11AccessibleNodeFor(x11.AccessibleRootPath)
+	c.True(ok)
+	c.Equal(x11.AccessibleRoleApplication, app.Role)
+	c.Equal([]string{rootPath}, app.Children)
+
+	frame, ok := x11AccessibleNodeFor(rootPath)
+	c.True(ok)
+	c.Equal(x11.AccessibleRoleFrame, frame.Role)
+	c.Equal(x11.AccessibleRootPath, frame.Parent)
+	c.True(frame.States&x11.AccessibleStateActive != 0)
+	listPath := x11AccessiblePath(list.AsPanel())
+	c.Equal([]string{x11AccessiblePath(button.AsPanel()), listPath}, frame.Children)
+
+	node, ok := x11AccessibleNodeFor(x11AccessiblePath(button.AsPanel()))
+	c.True(ok)
+	c.Equal(x11.AccessibleRolePushButton, node.Role)
+	c.Equal("OK", node.Name)
+	c.Equal(rootPath, node.Parent)
+	c.Equal([]string{"click"}, node.Actions)
+	c.Equal(x11.AccessibleRect{X: 15, Y: 15, Width: 40, Height: 20}, node.Bounds)
+	c.True(node.States&x11.AccessibleStateFocused != 0)
+
+	node, ok = x11AccessibleNodeFor(listPath)
+	c.True(ok)
+	c.Equal([]string{listPath + "_0", listPath + "_1"}, node.Children)
+	node, ok = x11AccessibleNodeFor(listPath + "_1")
+	c.True(ok)
+	c.Equal(x11.AccessibleRoleListItem, node.Role)
+	c.Equal("Green", node.Name)
+	c.Equal(listPath, node.Parent)
+	c.Equal(int32(1), node.IndexInParent)
+	c.Equal(listPath+"_1", x11AccessibleNodeAt(x11.AccessibleRootPath, node.Bounds.X+1, node.Bounds.Y+1))
+	_, ok = x11AccessibleNodeFor(listPath + "_2")
+	c.False(ok)
+}
+
+// TestX11TextDifference verifies that an edit is reduced to the text deleted and inserted at a single offset, which is
+// how it is reported to assistive technologies.
+func TestX11TextDifference(t *testing.T) {
+	c := check.New(t)
+	for _, one := range []struct {
+		before, after     string
+		deleted, inserted string
+		prefix            int
+	}{
+		{"hello", "hello", "", "", 5},
+		{"hello", "help", "lo", "p", 3},
+		{"", "ab", "", "ab", 0},
+		{"aaa", "aa", "a", "", 2},
+		{"héllo", "hélo", "l", "", 3},
+	} {
+		prefix, deleted, inserted := x11TextDifference(one.before, one.after)
+		c.Equal(one.prefix, prefix, one.before+" -> "+one.after)
+		c.Equal(one.deleted, deleted, one.before+" -> "+one.after)
+		c.Equal(one.inserted, inserted, one.before+" -> "+one.after)
+	}
+}
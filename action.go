@@ -15,11 +15,14 @@ import (
 )
 
 var (
-	cutAction       *Action
-	copyAction      *Action
-	pasteAction     *Action
-	deleteAction    *Action
-	selectAllAction *Action
+	cutAction          *Action
+	copyAction         *Action
+	pasteAction        *Action
+	deleteAction       *Action
+	selectAllAction    *Action
+	findAction         *Action
+	findNextAction     *Action
+	findPreviousAction *Action
 )
 
 // Action describes an action that can be performed.
@@ -159,3 +162,45 @@ func SelectAllAction() *Action {
 	}
 	return selectAllAction
 }
+
+// FindAction returns the action that shows the find bar for the current focus.
+func FindAction() *Action {
+	if findAction == nil {
+		findAction = &Action{
+			ID:              FindItemID,
+			Title:           i18n.Text("Find…"),
+			KeyBinding:      KeyBinding{KeyCode: KeyF, Modifiers: mod.OSMenuCommand()},
+			EnabledCallback: RouteActionToFocusEnabledFunc,
+			ExecuteCallback: RouteActionToFocusExecuteFunc,
+		}
+	}
+	return findAction
+}
+
+// FindNextAction returns the action that selects the next match of the current search.
+func FindNextAction() *Action {
+	if findNextAction == nil {
+		findNextAction = &Action{
+			ID:              FindNextItemID,
+			Title:           i18n.Text("Find Next"),
+			KeyBinding:      KeyBinding{KeyCode: KeyG, Modifiers: mod.OSMenuCommand()},
+			EnabledCallback: RouteActionToFocusEnabledFunc,
+			ExecuteCallback: RouteActionToFocusExecuteFunc,
+		}
+	}
+	return findNextAction
+}
+
+// FindPreviousAction returns the action that selects the previous match of the current search.
+func FindPreviousAction() *Action {
+	if findPreviousAction == nil {
+		findPreviousAction = &Action{
+			ID:              FindPreviousItemID,
+			Title:           i18n.Text("Find Previous"),
+			KeyBinding:      KeyBinding{KeyCode: KeyG, Modifiers: mod.Shift | mod.OSMenuCommand()},
+			EnabledCallback: RouteActionToFocusEnabledFunc,
+			ExecuteCallback: RouteActionToFocusExecuteFunc,
+		}
+	}
+	return findPreviousAction
+}
@@ -186,13 +186,16 @@ func start() error {
 		initialized = err == nil
 		initTermLock.Unlock()
 	}()
+	applyHeadlessEnvRequest()
 	applyCPURenderingEnvRequest()
-	err = apiBeginStartup()
+	if !headlessActive.Load() {
+		err = apiBeginStartup()
+	}
 	return err
 }
 
 func processEvents() {
-	apiWaitEvents()
+	waitEvents()
 	finishProcessingEvents()
 }
 
@@ -202,7 +205,7 @@ func processEvents() {
 // macOS), since tasks and draws make platform calls that produce autoreleased objects, and this is the outermost spot
 // on the thread that can reclaim them.
 func finishProcessingEvents() {
-	apiWithAutoreleasePool(func() {
+	withAutoreleasePool(func() {
 		processNextTask()
 		if len(redrawSet) > 0 {
 			set := redrawSet
@@ -224,11 +227,26 @@ func finishProcessingEvents() {
 func finishStartup() {
 	codecs.Register()
 	RebuildDynamicColors()
+	scanSystemFonts()
+	if headlessActive.Load() {
+		SafeCall(startupFinishedCallback)
+		return
+	}
 	apiLateInit()
 	SafeCall(startupFinishedCallback)
 	apiFinalFinishStartup()
 }
 
+// withAutoreleasePool runs f within a platform autorelease pool. The headless backend makes no platform calls, so it
+// runs f directly.
+func withAutoreleasePool(f func()) {
+	if headlessActive.Load() {
+		f()
+		return
+	}
+	apiWithAutoreleasePool(f)
+}
+
 // ThemeChanged marks dynamic colors for rebuilding, rebuilds the built-in cursors if the cursor settings they were
 // built with have changed (notifying any registered cursor change callbacks), calls any installed theme change
 // callback, and then refreshes the cursor of and redraws all windows. This is normally called automatically for you,
@@ -332,6 +350,9 @@ func finishQuit() error {
 	for len(cursorList) != 0 {
 		cursorList[len(cursorList)-1].Destroy()
 	}
+	if headlessActive.Load() {
+		return nil
+	}
 	return apiTerminate()
 }
 
@@ -362,7 +383,9 @@ func closeAllWindows() bool {
 
 // Beep plays the system beep sound.
 func Beep() {
-	apiBeep()
+	if !headlessActive.Load() {
+		apiBeep()
+	}
 }
 
 // CurrentThemeMode returns the current theme mode state. It is safe to call from any goroutine.
@@ -381,7 +404,7 @@ func SetThemeMode(mode thememode.Enum) {
 // IsColorModeTrackingPossible returns true if the underlying platform can provide the current dark mode state. On those
 // platforms that return false from this function, thememode.Auto is the same as thememode.Light.
 func IsColorModeTrackingPossible() bool {
-	return apiIsColorModeTrackingPossible()
+	return !headlessActive.Load() && apiIsColorModeTrackingPossible()
 }
 
 // IsDarkModeEnabled returns true if the OS is currently using a "dark mode".
@@ -394,7 +417,7 @@ func IsDarkModeEnabled() bool {
 	default:
 		if needPlatformDarkModeUpdate {
 			needPlatformDarkModeUpdate = false
-			platformDarkModeEnabled = apiIsDarkModeEnabled()
+			platformDarkModeEnabled = !headlessActive.Load() && apiIsDarkModeEnabled()
 		}
 		return platformDarkModeEnabled
 	}
@@ -403,6 +426,9 @@ func IsDarkModeEnabled() bool {
 // DoubleClickParameters returns the maximum delay between clicks and the maximum pixel drift allowed to register as a
 // double-click.
 func DoubleClickParameters() (maxDelay time.Duration, maxMouseDrift float32) {
+	if headlessActive.Load() {
+		return 500 * time.Millisecond, 5
+	}
 	return apiDoubleClickInterval(), 5
 }
 
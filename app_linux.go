@@ -68,6 +68,8 @@ func apiLateInit() {
 			}
 		})
 	})
+	x11StartInputMethod()
+	x11StartAccessibility()
 }
 
 // linuxRecomputeDarkMode recombines the portal and XSETTINGS sources into the cached dark-mode state, returning whether
@@ -106,6 +108,8 @@ func apiFinalFinishStartup() {
 }
 
 func apiTerminate() error {
+	x11StopInputMethod()
+	x11StopAccessibility()
 	if x11Conn != nil {
 		// Withdraw the connection from apiPostEmptyEvent before closing it. A goroutine that loaded the pointer just
 		// before the swap may still call PostEmptyEvent concurrently with (or after) Close, which is safe: it becomes
@@ -15,6 +15,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/side"
 )
 
@@ -89,6 +90,7 @@ func NewButton() *Button {
 	b.MouseUpCallback = b.DefaultMouseUp
 	b.KeyDownCallback = b.DefaultKeyDown
 	b.UpdateCursorCallback = b.DefaultUpdateCursor
+	b.AccessibilityCallback = b.DefaultAccessibility
 	return b
 }
 
@@ -211,6 +213,22 @@ func (b *Button) Click() {
 	SafeCall(b.ClickCallback)
 }
 
+// DefaultAccessibility provides the default accessibility description. A button without a title, such as one created
+// by NewSVGButton(), is named by its tooltip.
+func (b *Button) DefaultAccessibility() Accessibility {
+	a := Accessibility{Action: b.Click, Name: b.Text.String(), ActiveItem: -1, Role: role.Button}
+	if a.Name == "" && b.Tooltip != nil {
+		a.Name = AccessibleText(b.Tooltip)
+	}
+	if b.Sticky {
+		a.Role = role.ToggleButton
+		if b.group.Selected(b) {
+			a.State |= AccessiblePressed
+		}
+	}
+	return a
+}
+
 // DefaultMouseDown provides the default mouse down handling.
 func (b *Button) DefaultMouseDown(_ geom.Point, _, _ int, _ mod.Modifiers) bool {
 	b.Pressed = true
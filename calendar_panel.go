@@ -0,0 +1,384 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// DefaultCalendarTheme holds the default CalendarTheme values for CalendarPanels. Modifying this data will not alter
+// existing CalendarPanels, but will alter any CalendarPanels created in the future.
+var DefaultCalendarTheme = CalendarTheme{
+	Font:            LabelFont,
+	HeaderFont:      EmphasizedSystemFont,
+	BackgroundInk:   ThemeAboveSurface,
+	OnBackgroundInk: ThemeOnAboveSurface,
+	WeekdayInk:      ThemeSurfaceEdge,
+	OtherMonthInk:   ThemeSurfaceEdge,
+	TodayInk:        ThemeFocus,
+	SelectionInk:    ThemeFocus,
+	OnSelectionInk:  ThemeOnFocus,
+	RangeInk:        ThemeDeepFocus,
+	OnRangeInk:      ThemeOnDeepFocus,
+	CellPadding:     4,
+	CornerRadius:    geom.NewUniformSize(4),
+}
+
+// CalendarTheme holds theming data for a CalendarPanel.
+type CalendarTheme struct {
+	Font            Font
+	HeaderFont      Font
+	BackgroundInk   Ink
+	OnBackgroundInk Ink
+	WeekdayInk      Ink
+	OtherMonthInk   Ink // Also used for days outside of the allowed range
+	TodayInk        Ink
+	SelectionInk    Ink
+	OnSelectionInk  Ink
+	RangeInk        Ink
+	OnRangeInk      Ink
+	CellPadding     float32
+	CornerRadius    geom.Size
+}
+
+// CalendarPanel shows the days of a month, one week per row, and permits the user to pick one of them. The arrow keys
+// move the selection by a day or a week, Page Up and Page Down move it by a month (or a year, with Shift) and Return
+// or Enter chooses the selected date.
+type CalendarPanel struct {
+	Panel
+	CalendarTheme
+	// DateChosenCallback is called when the user chooses a date by clicking on it or pressing Return or Enter.
+	DateChosenCallback func(date time.Time)
+	// SelectionChangedCallback is called when the selected date changes.
+	SelectionChangedCallback func()
+	date                     time.Time
+	month                    time.Time
+	minimum                  time.Time
+	maximum                  time.Time
+	rangeStart               time.Time
+	rangeEnd                 time.Time
+	// FirstWeekday is the day each row of the calendar starts with.
+	FirstWeekday time.Weekday
+}
+
+// NewCalendarPanel creates a new CalendarPanel with the date selected.
+func NewCalendarPanel(date time.Time) *CalendarPanel {
+	c := &CalendarPanel{
+		CalendarTheme: DefaultCalendarTheme,
+		FirstWeekday:  DefaultDateTimeFormat.FirstWeekday,
+	}
+	c.Self = c
+	c.SetFocusable(true)
+	c.SetBorder(NewEmptyBorder(geom.NewUniformInsets(4)))
+	c.SetSizer(c.DefaultSizes)
+	c.DrawCallback = c.DefaultDraw
+	c.MouseDownCallback = c.DefaultMouseDown
+	c.KeyDownCallback = c.DefaultKeyDown
+	c.date = startOfDay(date)
+	c.month = c.date.AddDate(0, 0, 1-c.date.Day())
+	return c
+}
+
+// Date returns the selected date.
+func (c *CalendarPanel) Date() time.Time {
+	return c.date
+}
+
+// SetDate selects the date, limited to the allowed range, and shows its month.
+func (c *CalendarPanel) SetDate(date time.Time) {
+	date = c.constrain(startOfDay(date))
+	c.SetMonth(date)
+	if !date.Equal(c.date) {
+		c.date = date
+		c.MarkForRedraw()
+		if c.SelectionChangedCallback != nil {
+			c.SelectionChangedCallback()
+		}
+	}
+}
+
+// Month returns the first day of the month being shown.
+func (c *CalendarPanel) Month() time.Time {
+	return c.month
+}
+
+// SetMonth shows the month the date falls within, without altering the selection.
+func (c *CalendarPanel) SetMonth(date time.Time) {
+	date = startOfDay(date)
+	if month := date.AddDate(0, 0, 1-date.Day()); !month.Equal(c.month) {
+		c.month = month
+		c.MarkForRedraw()
+	}
+}
+
+// Min returns the earliest date that may be selected. A zero value means there is no limit.
+func (c *CalendarPanel) Min() time.Time {
+	return c.minimum
+}
+
+// Max returns the latest date that may be selected. A zero value means there is no limit.
+func (c *CalendarPanel) Max() time.Time {
+	return c.maximum
+}
+
+// SetMinMax sets the earliest and latest dates that may be selected. A zero value means there is no limit.
+func (c *CalendarPanel) SetMinMax(minimum, maximum time.Time) {
+	if !minimum.IsZero() {
+		minimum = startOfDay(minimum)
+	}
+	if !maximum.IsZero() {
+		maximum = startOfDay(maximum)
+	}
+	c.minimum = minimum
+	c.maximum = maximum
+	c.SetDate(c.date)
+	c.MarkForRedraw()
+}
+
+// HighlightedRange returns the range of dates being highlighted.
+func (c *CalendarPanel) HighlightedRange() (start, end time.Time) {
+	return c.rangeStart, c.rangeEnd
+}
+
+// SetHighlightedRange sets the range of dates, inclusive, to highlight, such as when picking the end of a date range.
+// Pass zero values to remove the highlight.
+func (c *CalendarPanel) SetHighlightedRange(start, end time.Time) {
+	if !start.IsZero() {
+		start = startOfDay(start)
+	}
+	if !end.IsZero() {
+		end = startOfDay(end)
+	}
+	c.rangeStart = start
+	c.rangeEnd = end
+	c.MarkForRedraw()
+}
+
+func (c *CalendarPanel) allowed(date time.Time) bool {
+	return (c.minimum.IsZero() || !date.Before(c.minimum)) && (c.maximum.IsZero() || !date.After(c.maximum))
+}
+
+func (c *CalendarPanel) constrain(date time.Time) time.Time {
+	if !c.minimum.IsZero() && date.Before(c.minimum) {
+		return c.minimum
+	}
+	if !c.maximum.IsZero() && date.After(c.maximum) {
+		return c.maximum
+	}
+	return date
+}
+
+func (c *CalendarPanel) inHighlightedRange(date time.Time) bool {
+	return !c.rangeStart.IsZero() && !c.rangeEnd.IsZero() && !date.Before(c.rangeStart) && !date.After(c.rangeEnd)
+}
+
+// firstShownDate returns the date shown in the first cell, which may fall within the month prior to the one being
+// shown.
+func (c *CalendarPanel) firstShownDate() time.Time {
+	return c.month.AddDate(0, 0, -((int(c.month.Weekday()) - int(c.FirstWeekday) + 7) % 7))
+}
+
+func (c *CalendarPanel) cellSize() geom.Size {
+	height := xmath.Ceil(c.Font.LineHeight()) + 2*c.CellPadding
+	width := c.Font.SimpleWidth("00")
+	for day := range time.Weekday(7) {
+		width = max(width, c.Font.SimpleWidth(shortWeekdayName(day)))
+	}
+	width = xmath.Ceil(width) + 2*c.CellPadding
+	return geom.NewSize(max(width, height), height)
+}
+
+func (c *CalendarPanel) headerHeight() float32 {
+	return xmath.Ceil(c.HeaderFont.LineHeight()) + 2*c.CellPadding
+}
+
+func (c *CalendarPanel) previousMonthRect() geom.Rect {
+	r := c.ContentRect(false)
+	return geom.NewRect(r.X, r.Y, c.cellSize().Width, c.headerHeight())
+}
+
+func (c *CalendarPanel) nextMonthRect() geom.Rect {
+	r := c.ContentRect(false)
+	width := c.cellSize().Width
+	return geom.NewRect(r.Right()-width, r.Y, width, c.headerHeight())
+}
+
+// dayRect returns the rectangle of the cell holding the date, which will be empty if the date isn't shown.
+func (c *CalendarPanel) dayRect(date time.Time) geom.Rect {
+	index := int(math.Round(startOfDay(date).Sub(c.firstShownDate()).Hours() / 24))
+	if index < 0 || index >= 42 {
+		return geom.Rect{}
+	}
+	cell := c.cellSize()
+	r := c.ContentRect(false)
+	return geom.NewRect(r.X+float32(index%7)*cell.Width, r.Y+c.headerHeight()+float32(1+index/7)*cell.Height,
+		cell.Width, cell.Height)
+}
+
+// dayAt returns the date shown at the point, if any.
+func (c *CalendarPanel) dayAt(where geom.Point) (time.Time, bool) {
+	cell := c.cellSize()
+	r := c.ContentRect(false)
+	x := where.X - r.X
+	y := where.Y - (r.Y + c.headerHeight() + cell.Height)
+	if x < 0 || y < 0 {
+		return time.Time{}, false
+	}
+	col := int(x / cell.Width)
+	row := int(y / cell.Height)
+	if col > 6 || row > 5 {
+		return time.Time{}, false
+	}
+	return c.firstShownDate().AddDate(0, 0, row*7+col), true
+}
+
+// DefaultSizes provides the default sizing.
+func (c *CalendarPanel) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	cell := c.cellSize()
+	prefSize = geom.NewSize(7*cell.Width, c.headerHeight()+7*cell.Height)
+	if border := c.Border(); border != nil {
+		prefSize = prefSize.Add(border.Insets().Size())
+	}
+	return prefSize, prefSize, prefSize
+}
+
+// DefaultDraw provides the default drawing.
+func (c *CalendarPanel) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
+	rect := c.ContentRect(true)
+	canvas.DrawRect(dirty, c.BackgroundInk.Paint(canvas, rect, paintstyle.Fill))
+	r := c.ContentRect(false)
+	headerHeight := c.headerHeight()
+	title := NewText(monthName(c.month.Month())+" "+strconv.Itoa(c.month.Year()),
+		&TextDecoration{Font: c.HeaderFont, OnBackgroundInk: c.OnBackgroundInk})
+	title.Draw(canvas, geom.NewPoint(r.X+(r.Width-title.Width())/2,
+		r.Y+(headerHeight-title.Extents().Height)/2+title.Baseline()))
+	c.drawArrow(canvas, c.previousMonthRect(), true)
+	c.drawArrow(canvas, c.nextMonthRect(), false)
+
+	cell := c.cellSize()
+	for i := range 7 {
+		text := NewText(shortWeekdayName(c.FirstWeekday+time.Weekday(i)),
+			&TextDecoration{Font: c.Font, OnBackgroundInk: c.WeekdayInk})
+		c.drawCellText(canvas, text, geom.NewRect(r.X+float32(i)*cell.Width, r.Y+headerHeight, cell.Width, cell.Height))
+	}
+
+	today := startOfDay(time.Now())
+	date := c.firstShownDate()
+	for range 42 {
+		cellRect := c.dayRect(date)
+		if cellRect.Intersects(dirty) {
+			ink := c.OnBackgroundInk
+			switch {
+			case date.Equal(c.date):
+				canvas.DrawRoundedRect(cellRect, c.CornerRadius, c.SelectionInk.Paint(canvas, cellRect, paintstyle.Fill))
+				ink = c.OnSelectionInk
+			case c.inHighlightedRange(date):
+				canvas.DrawRect(cellRect, c.RangeInk.Paint(canvas, cellRect, paintstyle.Fill))
+				ink = c.OnRangeInk
+			case date.Month() != c.month.Month() || !c.allowed(date):
+				ink = c.OtherMonthInk
+			}
+			if date.Equal(today) {
+				ringRect := cellRect.Inset(geom.NewUniformInsets(0.5))
+				canvas.DrawRoundedRect(ringRect, c.CornerRadius, c.TodayInk.Paint(canvas, ringRect, paintstyle.Stroke))
+			}
+			c.drawCellText(canvas, NewText(strconv.Itoa(date.Day()),
+				&TextDecoration{Font: c.Font, OnBackgroundInk: ink}), cellRect)
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+}
+
+func (c *CalendarPanel) drawCellText(canvas *Canvas, text *Text, r geom.Rect) {
+	text.Draw(canvas, geom.NewPoint(r.X+(r.Width-text.Width())/2, r.Y+(r.Height-text.Extents().Height)/2+text.Baseline()))
+}
+
+func (c *CalendarPanel) drawArrow(canvas *Canvas, r geom.Rect, left bool) {
+	size := min(r.Width, r.Height) / 4
+	center := r.Center()
+	path := NewPath()
+	if left {
+		path.MoveTo(geom.NewPoint(center.X+size/2, center.Y-size))
+		path.LineTo(geom.NewPoint(center.X-size/2, center.Y))
+		path.LineTo(geom.NewPoint(center.X+size/2, center.Y+size))
+	} else {
+		path.MoveTo(geom.NewPoint(center.X-size/2, center.Y-size))
+		path.LineTo(geom.NewPoint(center.X+size/2, center.Y))
+		path.LineTo(geom.NewPoint(center.X-size/2, center.Y+size))
+	}
+	path.Close()
+	canvas.DrawPath(path, c.OnBackgroundInk.Paint(canvas, r, paintstyle.Fill))
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (c *CalendarPanel) DefaultMouseDown(where geom.Point, button, _ int, _ mod.Modifiers) bool {
+	if c.Focusable() {
+		c.RequestFocus()
+	}
+	if button != ButtonLeft {
+		return true
+	}
+	switch {
+	case where.In(c.previousMonthRect()):
+		c.SetMonth(c.month.AddDate(0, -1, 0))
+	case where.In(c.nextMonthRect()):
+		c.SetMonth(c.month.AddDate(0, 1, 0))
+	default:
+		if date, ok := c.dayAt(where); ok && c.allowed(date) {
+			c.SetDate(date)
+			c.choose()
+		}
+	}
+	return true
+}
+
+// DefaultKeyDown provides the default key down handling.
+func (c *CalendarPanel) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, _ bool) bool {
+	if mods&(mod.Control|mod.Option|mod.Command) != 0 {
+		return false
+	}
+	months := 1
+	if mods.ShiftDown() {
+		months = 12
+	}
+	switch keyCode {
+	case KeyLeft:
+		c.SetDate(c.date.AddDate(0, 0, -1))
+	case KeyRight:
+		c.SetDate(c.date.AddDate(0, 0, 1))
+	case KeyUp:
+		c.SetDate(c.date.AddDate(0, 0, -7))
+	case KeyDown:
+		c.SetDate(c.date.AddDate(0, 0, 7))
+	case KeyPageUp:
+		c.SetDate(addMonthsClamped(c.date, -months))
+	case KeyPageDown:
+		c.SetDate(addMonthsClamped(c.date, months))
+	case KeyReturn, KeyNumPadEnter:
+		c.choose()
+	default:
+		return false
+	}
+	return true
+}
+
+func (c *CalendarPanel) choose() {
+	if c.DateChosenCallback != nil {
+		date := c.date
+		SafeCall(func() { c.DateChosenCallback(date) })
+	}
+}
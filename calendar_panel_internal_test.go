@@ -0,0 +1,94 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+func calendarDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestCalendarPanelGrid(t *testing.T) {
+	c := check.New(t)
+	cal := NewCalendarPanel(time.Date(2026, time.February, 18, 15, 30, 0, 0, time.Local))
+	cal.FirstWeekday = time.Sunday
+	c.Equal(calendarDate(2026, time.February, 18), cal.Date(), "the time of day is dropped")
+	c.Equal(calendarDate(2026, time.February, 1), cal.Month())
+	c.Equal(calendarDate(2026, time.February, 1), cal.firstShownDate(), "February 1, 2026 is a Sunday")
+	cal.FirstWeekday = time.Monday
+	c.Equal(calendarDate(2026, time.January, 26), cal.firstShownDate())
+
+	_, pref, _ := cal.Sizes(geom.Size{})
+	cal.SetFrameRect(geom.Rect{Size: pref})
+	first := cal.dayRect(calendarDate(2026, time.January, 26))
+	c.False(first.Empty())
+	c.Equal(cal.ContentRect(false).X, first.X)
+	for _, date := range []time.Time{
+		calendarDate(2026, time.January, 26),
+		calendarDate(2026, time.February, 25),
+		calendarDate(2026, time.March, 8),
+	} {
+		found, ok := cal.dayAt(cal.dayRect(date).Center())
+		c.True(ok)
+		c.Equal(date, found)
+	}
+	c.True(cal.dayRect(calendarDate(2026, time.March, 9)).Empty(), "only six weeks are shown")
+	_, ok := cal.dayAt(cal.previousMonthRect().Center())
+	c.False(ok)
+}
+
+func TestCalendarPanelInteraction(t *testing.T) {
+	c := check.New(t)
+	cal := NewCalendarPanel(calendarDate(2026, time.February, 18))
+	cal.FirstWeekday = time.Sunday
+	_, pref, _ := cal.Sizes(geom.Size{})
+	cal.SetFrameRect(geom.Rect{Size: pref})
+	var chosen []time.Time
+	cal.DateChosenCallback = func(date time.Time) { chosen = append(chosen, date) }
+
+	c.True(cal.DefaultMouseDown(cal.dayRect(calendarDate(2026, time.March, 3)).Center(), ButtonLeft, 1, 0))
+	c.Equal(calendarDate(2026, time.March, 3), cal.Date())
+	c.Equal(calendarDate(2026, time.March, 1), cal.Month(), "choosing a day in another month shows that month")
+	c.Equal([]time.Time{calendarDate(2026, time.March, 3)}, chosen)
+
+	cal.DefaultMouseDown(cal.nextMonthRect().Center(), ButtonLeft, 1, 0)
+	c.Equal(calendarDate(2026, time.April, 1), cal.Month())
+	c.Equal(calendarDate(2026, time.March, 3), cal.Date(), "changing the month doesn't change the selection")
+	cal.DefaultMouseDown(cal.previousMonthRect().Center(), ButtonLeft, 1, 0)
+	c.Equal(calendarDate(2026, time.March, 1), cal.Month())
+
+	c.True(cal.DefaultKeyDown(KeyRight, 0, false))
+	c.True(cal.DefaultKeyDown(KeyDown, 0, false))
+	c.Equal(calendarDate(2026, time.March, 11), cal.Date())
+	c.True(cal.DefaultKeyDown(KeyPageDown, 0, false))
+	c.Equal(calendarDate(2026, time.April, 11), cal.Date())
+	c.Equal(calendarDate(2026, time.April, 1), cal.Month())
+	c.True(cal.DefaultKeyDown(KeyPageUp, mod.Shift, false))
+	c.Equal(calendarDate(2025, time.April, 11), cal.Date())
+	c.True(cal.DefaultKeyDown(KeyReturn, 0, false))
+	c.Equal(2, len(chosen))
+	c.False(cal.DefaultKeyDown(KeyRight, mod.Control, false))
+
+	cal.SetMinMax(calendarDate(2025, time.April, 1), calendarDate(2025, time.April, 30))
+	cal.SetDate(calendarDate(2020, time.January, 1))
+	c.Equal(calendarDate(2025, time.April, 1), cal.Date(), "the selection is limited to the allowed range")
+	cal.DefaultKeyDown(KeyLeft, 0, false)
+	c.Equal(calendarDate(2025, time.April, 1), cal.Date())
+	cal.DefaultMouseDown(cal.dayRect(calendarDate(2025, time.March, 31)).Center(), ButtonLeft, 1, 0)
+	c.Equal(calendarDate(2025, time.April, 1), cal.Date(), "days outside the allowed range can't be chosen")
+	c.Equal(2, len(chosen))
+}
@@ -57,4 +57,21 @@ type InputCallbacks struct {
 	// KeyUpCallback is called when a key is released. Return true to stop further handling or false to propagate up to
 	// parents.
 	KeyUpCallback func(keyCode KeyCode, mods mod.Modifiers) bool
+	// CompositionStartCallback is called when an input method begins composing text, such as when a dead key is
+	// pressed or a phonetic spelling is being converted to Chinese characters. Return true to accept the composition
+	// and receive the CompositionUpdateCallback and CompositionCommitCallback calls for it, or false to propagate up to
+	// parents. If no panel accepts the composition, its in-progress text is not shown, but the text it produces is
+	// still delivered via RuneTypedCallback.
+	CompositionStartCallback func() bool
+	// CompositionUpdateCallback is called on the panel that accepted the composition whenever the in-progress text
+	// changes. It should be displayed at the insertion point, typically underlined, without modifying the panel's
+	// content. caret is the position of the caret within the text, in runes.
+	CompositionUpdateCallback func(text string, caret int)
+	// CompositionCommitCallback is called on the panel that accepted the composition when it ends. text holds the
+	// result, which is empty if the composition was cancelled. Return true if the text was consumed; otherwise, each of
+	// its runes is then delivered via RuneTypedCallback.
+	CompositionCommitCallback func(text string) bool
+	// CompositionCaretRectCallback is called to obtain the location of the caret, in the panel's local coordinates, so
+	// that the input method can place its candidate window next to it. Return an empty rect to propagate up to parents.
+	CompositionCaretRectCallback func() geom.Rect
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 // DefaultCheckBoxTheme holds the default CheckBoxTheme values for CheckBoxes. Modifying this data will not alter
@@ -50,9 +51,22 @@ func NewCheckBox() *CheckBox {
 		}
 	}
 	c.drawMark = c.drawCheck
+	c.AccessibilityCallback = c.DefaultAccessibility
 	return &c
 }
 
+// DefaultAccessibility provides the default accessibility description.
+func (c *CheckBox) DefaultAccessibility() Accessibility {
+	var state AccessibleState
+	switch c.State {
+	case check.On:
+		state = AccessibleChecked
+	case check.Mixed:
+		state = AccessibleMixed
+	}
+	return c.accessibility(role.CheckBox, state)
+}
+
 func (c *CheckBox) drawCheck(canvas *Canvas, rect geom.Rect, thickness float32, fg, bg, edge Ink) {
 	DrawRoundedRectBase(canvas, rect, c.CornerRadius, thickness, bg, edge)
 	rect = rect.Inset(geom.NewUniformInsets(0.5))
@@ -16,6 +16,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/xmath"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 type checkRadioBase struct {
@@ -81,6 +82,10 @@ func (c *checkRadioBase) markSize() float32 {
 	return xmath.Ceil(c.baseTheme.Font.Baseline())
 }
 
+func (c *checkRadioBase) accessibility(r role.Enum, state AccessibleState) Accessibility {
+	return Accessibility{Action: c.Click, Name: c.Text.String(), ActiveItem: -1, Role: r, State: state}
+}
+
 // Click makes the checkbox behave as if a user clicked on it.
 func (c *checkRadioBase) Click() {
 	c.updateState()
@@ -40,16 +40,34 @@ func ClipboardSetText(text string) {
 
 // ClipboardHasDataType returns true if the clipboard contains data of the specified type.
 func ClipboardHasDataType(dataType *uti.DataType) bool {
+	if headlessActive.Load() {
+		return headlessClipboardGetData(selectDataType(dataType, headlessClipboardAvailableDataTypes())) != nil
+	}
 	return apiClipboardHasDataType(selectDataType(dataType, apiClipboardAvailableDataTypes()))
 }
 
+// clipboardAvailableDataTypes returns the UTIs of the data types on the clipboard.
+func clipboardAvailableDataTypes() []string {
+	if headlessActive.Load() {
+		return headlessClipboardAvailableDataTypes()
+	}
+	return apiClipboardAvailableDataTypes()
+}
+
 // ClipboardGetData returns the data associated with the specified type on the clipboard.
 func ClipboardGetData(dataType *uti.DataType) []byte {
+	if headlessActive.Load() {
+		return headlessClipboardGetData(selectDataType(dataType, headlessClipboardAvailableDataTypes()))
+	}
 	return apiClipboardGetData(selectDataType(dataType, apiClipboardAvailableDataTypes()))
 }
 
 // ClipboardSetData sets data onto the clipboard, replacing the previous content.
 func ClipboardSetData(data ...drag.Data) {
+	if headlessActive.Load() {
+		headlessClipboardSetData(data...)
+		return
+	}
 	apiClipboardSetData(data...)
 }
 
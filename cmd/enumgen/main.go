@@ -74,6 +74,18 @@ func main() {
 		xos.ExitWithMsg("unexpected working directory: " + originalWD)
 	}
 	removeExistingGenFiles(wd)
+	processSourceTemplate(wd, &enumInfo{
+		Pkg:  "enums/aggregate",
+		Name: "aggregate",
+		Desc: "specifies how the values of a column are summarized",
+		Values: []enumValue{
+			{Key: "count"},
+			{Key: "sum"},
+			{Key: "min", String: "Minimum"},
+			{Key: "max", String: "Maximum"},
+			{Key: "average"},
+		},
+	})
 	processSourceTemplate(wd, &enumInfo{
 		Pkg:  "enums/align",
 		Name: "align",
@@ -281,6 +293,54 @@ func main() {
 			{Key: "polygon"},
 		},
 	})
+	processSourceTemplate(wd, &enumInfo{
+		Pkg:  "enums/role",
+		Name: "role",
+		Desc: "identifies what a panel is to assistive technologies, such as screen readers",
+		Values: []enumValue{
+			{Key: "none", Comment: "Not exposed; its children are presented as children of its parent"},
+			{Key: "window"},
+			{Key: "dialog"},
+			{Key: "group"},
+			{Key: "label"},
+			{Key: "heading"},
+			{Key: "paragraph"},
+			{Key: "document"},
+			{Key: "image"},
+			{Key: "link"},
+			{Key: "button"},
+			{Key: "toggle-button", String: "Toggle Button"},
+			{Key: "check-box", String: "Check Box"},
+			{Key: "radio-button", String: "Radio Button"},
+			{Key: "text-field", String: "Text Field"},
+			{Key: "password-field", String: "Password Field"},
+			{Key: "spin-button", String: "Spin Button"},
+			{Key: "combo-box", String: "Combo Box"},
+			{Key: "menu-bar", String: "Menu Bar"},
+			{Key: "menu"},
+			{Key: "menu-item", String: "Menu Item"},
+			{Key: "list"},
+			{Key: "list-item", String: "List Item"},
+			{Key: "table"},
+			{Key: "tree-table", String: "Tree Table"},
+			{Key: "table-row", String: "Table Row"},
+			{Key: "table-cell", String: "Table Cell"},
+			{Key: "column-header", String: "Column Header"},
+			{Key: "slider"},
+			{Key: "progress-bar", String: "Progress Bar"},
+			{Key: "scroll-bar", String: "Scroll Bar"},
+			{Key: "scroll-pane", String: "Scroll Pane"},
+			{Key: "separator"},
+			{Key: "tab-list", String: "Tab List"},
+			{Key: "tab"},
+			{Key: "split-pane", String: "Split Pane"},
+			{Key: "tool-bar", String: "Tool Bar"},
+			{Key: "status-bar", String: "Status Bar"},
+			{Key: "tooltip"},
+			{Key: "calendar"},
+			{Key: "date-editor", String: "Date Editor"},
+		},
+	})
 	processSourceTemplate(wd, &enumInfo{
 		Pkg:  "enums/side",
 		Name: "side",
@@ -360,6 +420,25 @@ func main() {
 			{Key: "decal"},
 		},
 	})
+	processSourceTemplate(wd, &enumInfo{
+		Pkg:  "enums/tokenkind",
+		Name: "tokenkind",
+		Desc: "identifies the kind of a token found by a syntax highlighting tokenizer",
+		Values: []enumValue{
+			{Key: "plain"},
+			{Key: "keyword"},
+			{Key: "type"},
+			{Key: "identifier"},
+			{Key: "function"},
+			{Key: "constant"},
+			{Key: "number"},
+			{Key: "string"},
+			{Key: "comment"},
+			{Key: "operator"},
+			{Key: "punctuation"},
+			{Key: "invalid"},
+		},
+	})
 	processSourceTemplate(wd, &enumInfo{
 		Pkg:  "enums/trimmode",
 		Name: "trimmode",
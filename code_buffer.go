@@ -0,0 +1,212 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"sort"
+)
+
+// pieceTable holds the text of a CodeEditor. The original text is never modified; inserted text is appended to a
+// second buffer, and the content is described by a sequence of pieces referring to spans of the two. Edits therefore
+// cost time proportional to the number of pieces rather than the size of the text, and each piece records where its
+// line feeds are, so that lines can be located without scanning the text.
+type pieceTable struct {
+	original []rune
+	added    []rune
+	pieces   []piece
+	// starts holds the offset at which each piece begins, followed by the total length.
+	starts []int
+	// feeds holds the number of line feeds that precede each piece, followed by the total number of line feeds.
+	feeds []int
+}
+
+type piece struct {
+	// feeds holds the offsets of the line feeds within the piece.
+	feeds  []int
+	start  int
+	length int
+	added  bool
+}
+
+func newPieceTable(runes []rune) *pieceTable {
+	t := &pieceTable{original: runes}
+	if len(runes) != 0 {
+		t.pieces = []piece{t.newPiece(false, 0, len(runes))}
+	}
+	t.reindex()
+	return t
+}
+
+func (t *pieceTable) newPiece(added bool, start, length int) piece {
+	p := piece{added: added, start: start, length: length}
+	for i, r := range t.source(p)[start : start+length] {
+		if r == '\n' {
+			p.feeds = append(p.feeds, i)
+		}
+	}
+	return p
+}
+
+func (t *pieceTable) source(p piece) []rune {
+	if p.added {
+		return t.added
+	}
+	return t.original
+}
+
+func (t *pieceTable) runes(p piece) []rune {
+	return t.source(p)[p.start : p.start+p.length]
+}
+
+func (t *pieceTable) reindex() {
+	t.starts = slices.Grow(t.starts[:0], len(t.pieces)+1)
+	t.feeds = slices.Grow(t.feeds[:0], len(t.pieces)+1)
+	offset := 0
+	feeds := 0
+	for _, p := range t.pieces {
+		t.starts = append(t.starts, offset)
+		t.feeds = append(t.feeds, feeds)
+		offset += p.length
+		feeds += len(p.feeds)
+	}
+	t.starts = append(t.starts, offset)
+	t.feeds = append(t.feeds, feeds)
+}
+
+// Len returns the number of runes in the text.
+func (t *pieceTable) Len() int {
+	return t.starts[len(t.starts)-1]
+}
+
+// LineCount returns the number of lines in the text, which is always at least one.
+func (t *pieceTable) LineCount() int {
+	return t.feeds[len(t.feeds)-1] + 1
+}
+
+// split ensures a piece boundary exists at the offset and returns the index of the piece that begins there.
+func (t *pieceTable) split(offset int) int {
+	i := sort.SearchInts(t.starts, offset)
+	if i < len(t.pieces) && t.starts[i] == offset {
+		return i
+	}
+	if i >= len(t.pieces) && offset >= t.Len() {
+		return len(t.pieces)
+	}
+	i--
+	p := t.pieces[i]
+	within := offset - t.starts[i]
+	k := sort.SearchInts(p.feeds, within)
+	left := piece{added: p.added, start: p.start, length: within, feeds: slices.Clone(p.feeds[:k])}
+	right := piece{added: p.added, start: p.start + within, length: p.length - within}
+	for _, f := range p.feeds[k:] {
+		right.feeds = append(right.feeds, f-within)
+	}
+	t.pieces = slices.Replace(t.pieces, i, i+1, left, right)
+	t.reindex()
+	return i + 1
+}
+
+// Replace replaces the runes in the range [start, end) with the given runes.
+func (t *pieceTable) Replace(start, end int, runes []rune) {
+	length := t.Len()
+	start = max(min(start, length), 0)
+	end = max(min(end, length), start)
+	first := t.split(start)
+	last := t.split(end)
+	var inserted []piece
+	if len(runes) != 0 {
+		// Typing produces a stream of adjacent insertions, which extend the previous piece rather than adding more.
+		if first > 0 && first == last {
+			if prev := &t.pieces[first-1]; prev.added && prev.start+prev.length == len(t.added) {
+				t.added = append(t.added, runes...)
+				extension := t.newPiece(true, len(t.added)-len(runes), len(runes))
+				for _, f := range extension.feeds {
+					prev.feeds = append(prev.feeds, f+prev.length)
+				}
+				prev.length += len(runes)
+				t.reindex()
+				return
+			}
+		}
+		t.added = append(t.added, runes...)
+		inserted = append(inserted, t.newPiece(true, len(t.added)-len(runes), len(runes)))
+	}
+	t.pieces = slices.Replace(t.pieces, first, last, inserted...)
+	t.reindex()
+}
+
+// Slice returns a copy of the runes in the range [start, end).
+func (t *pieceTable) Slice(start, end int) []rune {
+	length := t.Len()
+	start = max(min(start, length), 0)
+	end = max(min(end, length), start)
+	result := make([]rune, 0, end-start)
+	i := max(sort.SearchInts(t.starts, start+1)-1, 0)
+	for ; i < len(t.pieces) && t.starts[i] < end; i++ {
+		runes := t.runes(t.pieces[i])
+		from := max(start-t.starts[i], 0)
+		to := min(end-t.starts[i], len(runes))
+		result = append(result, runes[from:to]...)
+	}
+	return result
+}
+
+// RuneAt returns the rune at the offset, or 0 if the offset is out of range.
+func (t *pieceTable) RuneAt(offset int) rune {
+	if offset < 0 || offset >= t.Len() {
+		return 0
+	}
+	i := sort.SearchInts(t.starts, offset+1) - 1
+	return t.runes(t.pieces[i])[offset-t.starts[i]]
+}
+
+// String returns the text.
+func (t *pieceTable) String() string {
+	return string(t.Slice(0, t.Len()))
+}
+
+// LineStart returns the offset of the first rune of the line.
+func (t *pieceTable) LineStart(line int) int {
+	if line <= 0 {
+		return 0
+	}
+	if line >= t.LineCount() {
+		return t.Len()
+	}
+	// Find the piece containing the line feed that ends the previous line.
+	i := sort.SearchInts(t.feeds, line) - 1
+	return t.starts[i] + t.pieces[i].feeds[line-t.feeds[i]-1] + 1
+}
+
+// LineEnd returns the offset just past the last rune of the line, not counting its line feed.
+func (t *pieceTable) LineEnd(line int) int {
+	if line >= t.LineCount()-1 {
+		return t.Len()
+	}
+	return t.LineStart(line+1) - 1
+}
+
+// Line returns a copy of the runes of the line, not including its line feed.
+func (t *pieceTable) Line(line int) []rune {
+	return t.Slice(t.LineStart(line), t.LineEnd(line))
+}
+
+// LineForOffset returns the line containing the offset.
+func (t *pieceTable) LineForOffset(offset int) int {
+	if offset <= 0 || len(t.pieces) == 0 {
+		return 0
+	}
+	if offset >= t.Len() {
+		return t.LineCount() - 1
+	}
+	i := sort.SearchInts(t.starts, offset+1) - 1
+	return t.feeds[i] + sort.SearchInts(t.pieces[i].feeds, offset-t.starts[i])
+}
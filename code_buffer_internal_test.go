@@ -0,0 +1,80 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPieceTableLines(t *testing.T) {
+	c := check.New(t)
+	pt := newPieceTable([]rune("one\ntwo\n\nfour"))
+	c.Equal(4, pt.LineCount())
+	c.Equal("two", string(pt.Line(1)))
+	c.Equal("", string(pt.Line(2)))
+	c.Equal(9, pt.LineStart(3))
+	c.Equal(7, pt.LineEnd(1))
+	c.Equal(1, pt.LineForOffset(7), "the line feed belongs to the line it ends")
+	c.Equal(2, pt.LineForOffset(8))
+
+	pt.Replace(4, 7, []rune("2\n2"))
+	c.Equal("one\n2\n2\n\nfour", pt.String())
+	c.Equal(5, pt.LineCount())
+	c.Equal("2", string(pt.Line(2)))
+	c.Equal('f', pt.RuneAt(10))
+
+	empty := newPieceTable(nil)
+	c.Equal(1, empty.LineCount())
+	c.Equal(0, empty.LineEnd(0))
+	c.Equal("", string(empty.Line(0)))
+}
+
+func TestPieceTableTypingExtendsPiece(t *testing.T) {
+	c := check.New(t)
+	pt := newPieceTable([]rune("ab"))
+	for i, r := range "xyz" {
+		pt.Replace(1+i, 1+i, []rune{r})
+	}
+	c.Equal("axyzb", pt.String())
+	c.Equal(3, len(pt.pieces))
+}
+
+func TestPieceTableMatchesSliceEdits(t *testing.T) {
+	c := check.New(t)
+	rnd := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // Deterministic values are wanted for the test
+	alphabet := []rune("ab\n")
+	expected := []rune("hello\nworld\n")
+	pt := newPieceTable(slices.Clone(expected))
+	for range 2000 {
+		start := rnd.IntN(len(expected) + 1)
+		end := start + rnd.IntN(min(len(expected)-start, 4)+1)
+		insert := make([]rune, rnd.IntN(4))
+		for i := range insert {
+			insert[i] = alphabet[rnd.IntN(len(alphabet))]
+		}
+		expected = slices.Replace(expected, start, end, insert...)
+		pt.Replace(start, end, insert)
+		c.Equal(string(expected), pt.String())
+		lines := strings.Split(string(expected), "\n")
+		c.Equal(len(lines), pt.LineCount())
+		offset := 0
+		for i, line := range lines {
+			c.Equal(offset, pt.LineStart(i))
+			c.Equal(line, string(pt.Line(i)))
+			c.Equal(i, pt.LineForOffset(offset))
+			offset += len([]rune(line)) + 1
+		}
+	}
+}
@@ -0,0 +1,1365 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/pathop"
+	"github.com/richardwilkes/unison/enums/role"
+	"github.com/richardwilkes/unison/enums/tokenkind"
+)
+
+const (
+	codeEditorTextInset = 4
+	// codeEditorBracketScanLimit is the number of runes that will be examined when looking for a matching bracket.
+	codeEditorBracketScanLimit = 1 << 16
+)
+
+// DefaultCodeEditorTheme holds the default CodeEditorTheme values for CodeEditors. Modifying this data will not alter
+// existing CodeEditors, but will alter any CodeEditors created in the future.
+var DefaultCodeEditorTheme = CodeEditorTheme{
+	Font:                 MonospacedFont,
+	BackgroundInk:        ThemeDeepBelowSurface,
+	OnBackgroundInk:      ThemeOnDeepBelowSurface,
+	CurrentLineInk:       ThemeBelowSurface,
+	SelectionInk:         ThemeFocus,
+	OnSelectionInk:       ThemeOnFocus,
+	InactiveSelectionInk: ThemeSurfaceEdge,
+	BracketMatchInk:      ThemeFocus,
+	SearchMatchInk:       ThemeSearchMatch,
+	TokenDecorations:     DefaultCodeEditorTokenDecorations(),
+	BlinkRate:            560 * time.Millisecond,
+	TabWidth:             4,
+}
+
+// CodeEditorTheme holds theming data for a CodeEditor.
+type CodeEditorTheme struct {
+	Font                 Font
+	BackgroundInk        Ink
+	OnBackgroundInk      Ink
+	CurrentLineInk       Ink
+	SelectionInk         Ink
+	OnSelectionInk       Ink
+	InactiveSelectionInk Ink
+	BracketMatchInk      Ink
+	SearchMatchInk       Ink
+	// TokenDecorations holds the decoration used to draw each kind of token. A nil Font within a decoration is replaced
+	// by the theme's Font and a nil OnBackgroundInk by the theme's OnBackgroundInk. Kinds without an entry are drawn
+	// as plain text.
+	TokenDecorations map[tokenkind.Enum]*TextDecoration
+	BlinkRate        time.Duration
+	TabWidth         int
+}
+
+// DefaultCodeEditorTokenDecorations returns the default decorations for each kind of token.
+func DefaultCodeEditorTokenDecorations() map[tokenkind.Enum]*TextDecoration {
+	return map[tokenkind.Enum]*TextDecoration{
+		tokenkind.Keyword:  {OnBackgroundInk: &ThemeColor{Light: RGB(155, 35, 147), Dark: RGB(252, 95, 163)}},
+		tokenkind.Type:     {OnBackgroundInk: &ThemeColor{Light: RGB(11, 79, 121), Dark: RGB(93, 216, 255)}},
+		tokenkind.Function: {OnBackgroundInk: &ThemeColor{Light: RGB(50, 109, 116), Dark: RGB(103, 183, 164)}},
+		tokenkind.Constant: {OnBackgroundInk: &ThemeColor{Light: RGB(120, 73, 42), Dark: RGB(208, 191, 105)}},
+		tokenkind.Number:   {OnBackgroundInk: &ThemeColor{Light: RGB(28, 0, 207), Dark: RGB(208, 191, 105)}},
+		tokenkind.String:   {OnBackgroundInk: &ThemeColor{Light: RGB(196, 26, 22), Dark: RGB(252, 106, 93)}},
+		tokenkind.Comment:  {OnBackgroundInk: &ThemeColor{Light: RGB(93, 108, 121), Dark: RGB(108, 121, 134)}},
+		tokenkind.Invalid:  {OnBackgroundInk: ThemeError, Underline: true},
+	}
+}
+
+// CodeEditor provides a multi-line editor for source code and other plain text. Its text is held in a piece table, so
+// that large files can be edited efficiently, and only the lines that are visible are laid out and drawn. It is
+// intended to be placed within a ScrollPanel, typically with a CodeEditorGutter as the row header to show line
+// numbers.
+type CodeEditor struct {
+	// ModifiedCallback is called after the text has been changed, including by undo and redo.
+	ModifiedCallback func()
+	buffer           *pieceTable
+	tokenizer        Tokenizer
+	gutter           *CodeEditorGutter
+	// states holds the tokenizer state at the start of each line, for as many lines as have been tokenized since the
+	// last edit that might have changed them.
+	states         []int
+	searchMatches  [][2]int
+	forceShowUntil time.Time
+	CodeEditorTheme
+	Panel
+	undoID          int64
+	selectionStart  int
+	selectionEnd    int
+	selectionAnchor int
+	// goalX is the horizontal position, relative to the left edge of the text, that vertical caret movement tries to
+	// maintain, or -1 if it should be taken from the caret.
+	goalX float32
+	// maxColumns is the number of columns in the widest line seen since the text was set. It is not reduced as lines
+	// are shortened, so that the width of the editor doesn't change while typing.
+	maxColumns int
+	// IndentWithSpaces causes indentation to be inserted as spaces rather than tab characters.
+	IndentWithSpaces bool
+	showCursor       bool
+	pending          bool
+	extendByWord     bool
+	keepGoalX        bool
+}
+
+type codeEditorChange struct {
+	removed  []rune
+	inserted []rune
+	offset   int
+}
+
+type codeEditorUndoState struct {
+	// changes holds the changes made by the edit, in the order they were applied. Only present in the before state.
+	changes        []codeEditorChange
+	selectionStart int
+	selectionEnd   int
+}
+
+type codeEditorLine struct {
+	text *Text
+	// columns holds the display column of each rune of the line, plus one for the end of the line, to account for tabs
+	// having been expanded to spaces. It is nil if the line has no tabs.
+	columns []int
+}
+
+// NewCodeEditor creates a new, empty, code editor.
+func NewCodeEditor() *CodeEditor {
+	e := &CodeEditor{
+		CodeEditorTheme: DefaultCodeEditorTheme,
+		buffer:          newPieceTable(nil),
+		states:          []int{0},
+		undoID:          NextUndoID(),
+		goalX:           -1,
+	}
+	e.Self = e
+	e.SetFocusable(true)
+	e.SetSizer(e.DefaultSizes)
+	e.DrawCallback = e.DefaultDraw
+	e.GainedFocusCallback = e.DefaultFocusGained
+	e.LostFocusCallback = e.DefaultFocusLost
+	e.MouseDownCallback = e.DefaultMouseDown
+	e.MouseDragCallback = e.DefaultMouseDrag
+	e.MouseUpCallback = e.DefaultMouseUp
+	e.UpdateCursorCallback = e.DefaultUpdateCursor
+	e.KeyDownCallback = e.DefaultKeyDown
+	e.RuneTypedCallback = e.DefaultRuneTyped
+	e.AccessibilityCallback = e.DefaultAccessibility
+	e.InstallCmdHandlers(CutItemID, func(_ any) bool { return e.CanCut() }, func(_ any) { e.Cut() })
+	e.InstallCmdHandlers(CopyItemID, func(_ any) bool { return e.CanCopy() }, func(_ any) { e.Copy() })
+	e.InstallCmdHandlers(PasteItemID, func(_ any) bool { return e.CanPaste() }, func(_ any) { e.Paste() })
+	e.InstallCmdHandlers(DeleteItemID, func(_ any) bool { return e.CanDelete() }, func(_ any) { e.Delete() })
+	e.InstallCmdHandlers(SelectAllItemID, func(_ any) bool { return e.CanSelectAll() }, func(_ any) { e.SelectAll() })
+	return e
+}
+
+// Text returns the content of the editor.
+func (e *CodeEditor) Text() string {
+	return e.buffer.String()
+}
+
+// SetText replaces the content of the editor and moves the caret to the beginning. This is not undoable and does not
+// call the ModifiedCallback, as it is intended for loading new content.
+func (e *CodeEditor) SetText(text string) {
+	e.buffer = newPieceTable([]rune(strings.ReplaceAll(text, "\r\n", "\n")))
+	e.states = e.states[:1]
+	e.searchMatches = nil
+	e.maxColumns = 0
+	e.updateMaxColumns(0, e.buffer.LineCount()-1)
+	e.undoID = NextUndoID()
+	e.selectionStart = 0
+	e.selectionEnd = 0
+	e.selectionAnchor = 0
+	e.syncSize()
+	e.ScrollSelectionIntoView()
+}
+
+// Len returns the number of runes in the editor.
+func (e *CodeEditor) Len() int {
+	return e.buffer.Len()
+}
+
+// LineCount returns the number of lines in the editor.
+func (e *CodeEditor) LineCount() int {
+	return e.buffer.LineCount()
+}
+
+// Line returns the text of the line, without its line feed.
+func (e *CodeEditor) Line(line int) string {
+	return string(e.buffer.Line(line))
+}
+
+// LineForOffset returns the line containing the rune offset.
+func (e *CodeEditor) LineForOffset(offset int) int {
+	return e.buffer.LineForOffset(offset)
+}
+
+// LineStart returns the rune offset of the start of the line.
+func (e *CodeEditor) LineStart(line int) int {
+	return e.buffer.LineStart(line)
+}
+
+// Tokenizer returns the Tokenizer used for syntax highlighting. May be nil.
+func (e *CodeEditor) Tokenizer() Tokenizer {
+	return e.tokenizer
+}
+
+// SetTokenizer sets the Tokenizer used for syntax highlighting. Pass nil to disable syntax highlighting.
+func (e *CodeEditor) SetTokenizer(tokenizer Tokenizer) {
+	e.tokenizer = tokenizer
+	e.states = e.states[:1]
+	e.MarkForRedraw()
+}
+
+// CurrentUndoID returns the undo ID to use.
+func (e *CodeEditor) CurrentUndoID() int64 {
+	return e.undoID
+}
+
+func (e *CodeEditor) lineHeight() float32 {
+	return xmath.Ceil(e.Font.LineHeight())
+}
+
+func (e *CodeEditor) columnWidth() float32 {
+	return e.Font.SimpleWidth("0")
+}
+
+func (e *CodeEditor) tabWidth() int {
+	return max(e.TabWidth, 1)
+}
+
+func (e *CodeEditor) indentUnit() []rune {
+	if e.IndentWithSpaces {
+		return []rune(strings.Repeat(" ", e.tabWidth()))
+	}
+	return []rune{'\t'}
+}
+
+// DefaultSizes provides the default sizing.
+func (e *CodeEditor) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	prefSize.Width = xmath.Ceil(2*codeEditorTextInset + float32(e.maxColumns)*e.columnWidth() + 1)
+	prefSize.Height = float32(e.buffer.LineCount()) * e.lineHeight()
+	minSize = geom.NewSize(prefSize.Width, e.lineHeight())
+	if border := e.Border(); border != nil {
+		insets := border.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+	}
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// syncSize adjusts the frame of the editor to its preferred size, so that any containing ScrollPanel can update in
+// response to a change in the number or width of the lines.
+func (e *CodeEditor) syncSize() {
+	_, pref, _ := e.DefaultSizes(geom.Size{})
+	rect := e.FrameRect()
+	if rect.Size != pref {
+		rect.Size = pref
+		e.SetFrameRect(rect)
+		e.MarkForLayoutRecursivelyUpward()
+		if e.gutter != nil {
+			e.gutter.MarkForLayoutRecursivelyUpward()
+		}
+	}
+	e.MarkForRedraw()
+}
+
+// updateMaxColumns ensures maxColumns accounts for the lines in the range [first, last].
+func (e *CodeEditor) updateMaxColumns(first, last int) {
+	tabWidth := e.tabWidth()
+	for i := first; i <= last; i++ {
+		columns := 0
+		for _, r := range e.buffer.Line(i) {
+			if r == '\t' {
+				columns += tabWidth - columns%tabWidth
+			} else {
+				columns++
+			}
+		}
+		e.maxColumns = max(e.maxColumns, columns)
+	}
+}
+
+// tokenizerStateFor returns the tokenizer state at the start of the line.
+func (e *CodeEditor) tokenizerStateFor(line int) int {
+	for len(e.states) <= line {
+		last := len(e.states) - 1
+		_, state := e.tokenizer.Tokenize(e.buffer.Line(last), e.states[last])
+		e.states = append(e.states, state)
+	}
+	return e.states[line]
+}
+
+// decorationsForDrawing returns the decorations to use for each kind of token, resolved against the theme.
+func (e *CodeEditor) decorationsForDrawing() []*TextDecoration {
+	decorations := make([]*TextDecoration, len(tokenkind.All))
+	plain := &TextDecoration{Font: e.Font, OnBackgroundInk: e.OnBackgroundInk}
+	for i, kind := range tokenkind.All {
+		d := e.TokenDecorations[kind]
+		if d == nil {
+			decorations[i] = plain
+			continue
+		}
+		d = d.Clone()
+		if d.Font == nil {
+			d.Font = e.Font
+		}
+		if d.OnBackgroundInk == nil {
+			d.OnBackgroundInk = e.OnBackgroundInk
+		}
+		decorations[i] = d
+	}
+	return decorations
+}
+
+// buildLine lays out the line for display, expanding its tabs and applying syntax highlighting. The decorations may be
+// nil, in which case they are resolved from the theme.
+func (e *CodeEditor) buildLine(index int, decorations []*TextDecoration) *codeEditorLine {
+	if decorations == nil {
+		decorations = e.decorationsForDrawing()
+	}
+	runes := e.buffer.Line(index)
+	kinds := make([]tokenkind.Enum, len(runes))
+	if e.tokenizer != nil {
+		tokens, _ := e.tokenizer.Tokenize(runes, e.tokenizerStateFor(index))
+		for _, token := range tokens {
+			for i := max(token.Start, 0); i < min(token.End, len(runes)); i++ {
+				kinds[i] = token.Kind.EnsureValid()
+			}
+		}
+	}
+	line := &codeEditorLine{text: NewTextFromRunes(nil, decorations[tokenkind.Plain])}
+	tabWidth := e.tabWidth()
+	display := make([]rune, 0, len(runes))
+	runStart := 0
+	for i, r := range runes {
+		if i > 0 && kinds[i] != kinds[i-1] {
+			line.text.AddRunes(display[runStart:], decorations[kinds[i-1]])
+			runStart = len(display)
+		}
+		if r == '\t' {
+			if line.columns == nil {
+				line.columns = make([]int, i, len(runes)+1)
+				for j := range i {
+					line.columns[j] = j
+				}
+			}
+			line.columns = append(line.columns, len(display))
+			for range tabWidth - len(display)%tabWidth {
+				display = append(display, ' ')
+			}
+			continue
+		}
+		if line.columns != nil {
+			line.columns = append(line.columns, len(display))
+		}
+		display = append(display, r)
+	}
+	if line.columns != nil {
+		line.columns = append(line.columns, len(display))
+	}
+	if len(runes) != 0 {
+		line.text.AddRunes(display[runStart:], decorations[kinds[len(runes)-1]])
+	}
+	return line
+}
+
+// position returns the horizontal position of the rune index within the line, relative to the left edge of the text.
+func (l *codeEditorLine) position(index int) float32 {
+	if l.columns != nil {
+		index = l.columns[max(min(index, len(l.columns)-1), 0)]
+	}
+	return l.text.PositionForRuneIndex(index)
+}
+
+// extents returns the horizontal extents covered by the runes [start, end) of the line, relative to the left edge of the
+// text.
+func (l *codeEditorLine) extents(start, end int) [][2]float32 {
+	return [][2]float32{{l.position(start), l.position(end)}}
+}
+
+// runeIndex returns the rune index within the line that is closest to the horizontal position, which is relative to
+// the left edge of the text.
+func (l *codeEditorLine) runeIndex(x float32) int {
+	index := l.text.RuneIndexForPosition(x)
+	if l.columns == nil {
+		return index
+	}
+	for i := 1; i < len(l.columns); i++ {
+		if l.columns[i] > index {
+			// The position falls within the expansion of a tab, so pick whichever side of it is nearer.
+			if index-l.columns[i-1] <= l.columns[i]-index {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return len(l.columns) - 1
+}
+
+func (e *CodeEditor) textLeft() float32 {
+	return e.ContentRect(false).X + codeEditorTextInset
+}
+
+// DefaultDraw provides the default drawing.
+func (e *CodeEditor) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
+	rect := e.ContentRect(true)
+	canvas.DrawRect(rect.Intersect(dirty), e.BackgroundInk.Paint(canvas, rect, paintstyle.Fill))
+	content := e.ContentRect(false)
+	canvas.ClipRect(content, pathop.Intersect, false)
+	enabled := e.Enabled()
+	focused := e.Focused()
+	hasSelectionRange := e.HasSelectionRange()
+	lineHeight := e.lineHeight()
+	caret := e.caret()
+	caretLine := e.buffer.LineForOffset(caret)
+	bracket1, bracket2 := e.MatchingBrackets()
+	selectionInk := e.SelectionInk
+	if !focused {
+		selectionInk = e.InactiveSelectionInk
+	}
+	decorations := e.decorationsForDrawing()
+	if !enabled {
+		for i, d := range decorations {
+			d = d.Clone()
+			d.OnBackgroundInk = &ColorFilteredInk{OriginalInk: d.OnBackgroundInk, ColorFilter: Grayscale30Filter()}
+			decorations[i] = d
+		}
+	}
+	left := e.textLeft()
+	first := max(int((dirty.Y-content.Y)/lineHeight), 0)
+	last := min(int((dirty.Bottom()-content.Y)/lineHeight), e.buffer.LineCount()-1)
+	for i := first; i <= last; i++ {
+		y := content.Y + float32(i)*lineHeight
+		if i == caretLine && enabled {
+			r := geom.NewRect(content.X, y, content.Width, lineHeight)
+			canvas.DrawRect(r, e.CurrentLineInk.Paint(canvas, r, paintstyle.Fill))
+		}
+		line := e.buildLine(i, decorations)
+		lineStart := e.buffer.LineStart(i)
+		lineEnd := e.buffer.LineEnd(i)
+		if len(e.searchMatches) != 0 {
+			drawSearchMatches(canvas, e.searchMatches, lineStart, lineEnd, left, y, lineHeight, e.SearchMatchInk,
+				paintstyle.Fill, line.extents)
+		}
+		for _, pos := range []int{bracket1, bracket2} {
+			if pos >= lineStart && pos < lineEnd {
+				x1 := left + line.position(pos-lineStart)
+				x2 := left + line.position(pos-lineStart+1)
+				r := geom.NewRect(x1, y, x2-x1, lineHeight).Inset(geom.NewUniformInsets(0.5))
+				canvas.DrawRect(r, e.BracketMatchInk.Paint(canvas, r, paintstyle.Stroke))
+			}
+		}
+		var selRect geom.Rect
+		if hasSelectionRange && e.selectionStart <= lineEnd && e.selectionEnd > lineStart {
+			x1 := left + line.position(max(e.selectionStart, lineStart)-lineStart)
+			x2 := left + line.position(min(e.selectionEnd, lineEnd)-lineStart)
+			if e.selectionEnd > lineEnd {
+				// The line feed is selected, too.
+				x2 += e.columnWidth()
+			}
+			selRect = geom.NewRect(x1, y, x2-x1, lineHeight)
+			canvas.DrawRect(selRect, selectionInk.Paint(canvas, selRect, paintstyle.Fill))
+		}
+		pt := geom.NewPoint(left, y+line.text.Baseline())
+		line.text.Draw(canvas, pt)
+		if focused && selRect.Width > 0 {
+			saved := line.text.AdjustDecorations(func(d *TextDecoration) { d.OnBackgroundInk = e.OnSelectionInk })
+			canvas.Save()
+			canvas.ClipRect(selRect, pathop.Intersect, false)
+			line.text.Draw(canvas, pt)
+			canvas.Restore()
+			line.text.RestoreDecorations(saved)
+		}
+		if i == caretLine && !hasSelectionRange && enabled && focused && e.showCursor {
+			x := left + line.position(caret-lineStart)
+			r := geom.NewRect(x-0.5, y, 1, lineHeight)
+			canvas.DrawRect(r, e.OnBackgroundInk.Paint(canvas, r, paintstyle.Fill))
+		}
+	}
+	if !hasSelectionRange && enabled && focused {
+		e.scheduleBlink()
+	}
+}
+
+func (e *CodeEditor) scheduleBlink() {
+	window := e.Window()
+	if window != nil && window.IsValid() && !e.pending && e.Enabled() && e.Focused() {
+		e.pending = true
+		InvokeTaskAfter(e.blink, e.BlinkRate)
+	}
+}
+
+func (e *CodeEditor) blink() {
+	e.pending = false
+	window := e.Window()
+	if window != nil && window.IsValid() {
+		if time.Now().After(e.forceShowUntil) {
+			e.showCursor = !e.showCursor
+			e.MarkForRedraw()
+		}
+		e.scheduleBlink()
+	}
+}
+
+// DefaultFocusGained provides the default focus gained handling.
+func (e *CodeEditor) DefaultFocusGained() {
+	e.showCursor = true
+	e.ScrollSelectionIntoView()
+	e.MarkForRedraw()
+}
+
+// DefaultFocusLost provides the default focus lost handling.
+func (e *CodeEditor) DefaultFocusLost() {
+	e.undoID = NextUndoID()
+	e.MarkForRedraw()
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (e *CodeEditor) DefaultMouseDown(where geom.Point, button, clickCount int, mods mod.Modifiers) bool {
+	e.undoID = NextUndoID()
+	e.RequestFocus()
+	if button == ButtonRight && clickCount == 1 {
+		// As with Field, the context menu is shown on mouse up.
+		return true
+	}
+	if button != ButtonLeft {
+		return false
+	}
+	pos := e.ToSelectionIndex(where)
+	e.extendByWord = false
+	switch clickCount {
+	case 2:
+		start, end := e.findWordAt(pos)
+		e.SetSelection(start, end)
+		e.extendByWord = true
+	case 3:
+		line := e.buffer.LineForOffset(pos)
+		e.SetSelection(e.buffer.LineStart(line), e.buffer.LineStart(line+1))
+	default:
+		if mods.ShiftDown() {
+			e.setSelectionFromAnchor(e.selectionAnchor, pos)
+		} else {
+			e.setSelection(pos, pos, pos)
+		}
+	}
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (e *CodeEditor) DefaultMouseDrag(where geom.Point, button int, _ mod.Modifiers) bool {
+	if button != ButtonLeft {
+		return true
+	}
+	anchor := e.selectionAnchor
+	pos := e.ToSelectionIndex(where)
+	if e.extendByWord {
+		s1, e1 := e.findWordAt(anchor)
+		s2, e2 := e.findWordAt(pos)
+		e.setSelection(min(s1, s2), max(e1, e2), anchor)
+	} else {
+		e.setSelectionFromAnchor(anchor, pos)
+	}
+	e.ScrollRectIntoView(geom.NewRect(where.X, where.Y, 1, 1))
+	return true
+}
+
+// DefaultMouseUp provides the default mouse up handling.
+func (e *CodeEditor) DefaultMouseUp(where geom.Point, button int, _ mod.Modifiers) bool {
+	if button == ButtonRight {
+		if where.In(e.ContentRect(true)) {
+			e.ShowContextMenu(where)
+		}
+		return true
+	}
+	return false
+}
+
+// ShowContextMenu displays the context menu for the editor at the specified position, which should be in local
+// coordinates.
+func (e *CodeEditor) ShowContextMenu(where geom.Point) {
+	fac := DefaultMenuFactory()
+	cm := fac.NewMenu(PopupMenuTemporaryBaseID|ContextMenuIDFlag, "", nil)
+	cm.InsertItem(-1, CutAction().NewContextMenuItemFromAction(fac))
+	cm.InsertItem(-1, CopyAction().NewContextMenuItemFromAction(fac))
+	cm.InsertItem(-1, PasteAction().NewContextMenuItemFromAction(fac))
+	cm.InsertItem(-1, SelectAllAction().NewContextMenuItemFromAction(fac))
+	if cm.Count() > 0 {
+		where = e.PointToRoot(where)
+		cm.Popup(geom.NewRect(where.X, where.Y, 1, 1), 0)
+	}
+	cm.Dispose()
+}
+
+// DefaultUpdateCursor provides the default cursor update handling.
+func (e *CodeEditor) DefaultUpdateCursor(_ geom.Point) *Cursor {
+	if e.Enabled() {
+		return TextCursor()
+	}
+	return ArrowCursor()
+}
+
+// DefaultKeyDown provides the default key down handling.
+func (e *CodeEditor) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, _ bool) bool {
+	if wnd := e.Window(); wnd != nil {
+		wnd.HideCursorUntilMouseMoves()
+	}
+	extend := mods.ShiftDown()
+	if mods.OSMenuCommandDown() {
+		switch keyCode {
+		case KeyLeft:
+			e.moveTo(e.buffer.LineStart(e.buffer.LineForOffset(e.caret())), extend)
+		case KeyRight:
+			e.moveTo(e.buffer.LineEnd(e.buffer.LineForOffset(e.caret())), extend)
+		case KeyUp, KeyHome:
+			e.moveTo(0, extend)
+		case KeyDown, KeyEnd:
+			e.moveTo(e.buffer.Len(), extend)
+		default:
+			// Handle cut/copy/paste/select all commands directly in case no menu is present
+			if mods&mod.NonSticky == mod.OSMenuCommand() {
+				switch keyCode {
+				case KeyA:
+					if e.CanSelectAll() {
+						e.SelectAll()
+						return true
+					}
+				case KeyX:
+					if e.CanCut() {
+						e.Cut()
+						return true
+					}
+				case KeyC:
+					if e.CanCopy() {
+						e.Copy()
+						return true
+					}
+				case KeyV:
+					if e.CanPaste() {
+						e.Paste()
+						return true
+					}
+				default:
+				}
+			}
+			return false
+		}
+		return true
+	}
+	switch keyCode {
+	case KeyBackspace:
+		e.Delete()
+	case KeyDelete:
+		if e.HasSelectionRange() {
+			e.Delete()
+		} else if e.selectionEnd < e.buffer.Len() {
+			e.editOnce(i18n.Text("Delete"), []codeEditorChange{e.change(e.selectionEnd, e.selectionEnd+1, nil)},
+				e.selectionEnd, e.selectionEnd)
+		}
+	case KeyLeft:
+		switch {
+		case e.HasSelectionRange() && !extend:
+			e.moveTo(e.selectionStart, false)
+		case mods.OptionDown():
+			e.moveTo(e.wordStartBefore(e.caret()), extend)
+		default:
+			e.moveTo(e.caret()-1, extend)
+		}
+	case KeyRight:
+		switch {
+		case e.HasSelectionRange() && !extend:
+			e.moveTo(e.selectionEnd, false)
+		case mods.OptionDown():
+			e.moveTo(e.wordEndAfter(e.caret()), extend)
+		default:
+			e.moveTo(e.caret()+1, extend)
+		}
+	case KeyUp:
+		e.moveVertically(-1, extend)
+	case KeyDown:
+		e.moveVertically(1, extend)
+	case KeyPageUp:
+		e.moveVertically(-e.linesPerPage(), extend)
+	case KeyPageDown:
+		e.moveVertically(e.linesPerPage(), extend)
+	case KeyHome:
+		e.moveTo(e.homeFor(e.caret()), extend)
+	case KeyEnd:
+		e.moveTo(e.buffer.LineEnd(e.buffer.LineForOffset(e.caret())), extend)
+	case KeyTab:
+		switch {
+		case mods.ShiftDown():
+			e.Outdent()
+		case e.selectionSpansLines():
+			e.Indent()
+		default:
+			e.insert(i18n.Text("Typing"), e.tabInsertion(), true)
+		}
+	case KeyReturn, KeyNumPadEnter:
+		e.insertLineFeed()
+	case KeyEscape:
+		return false
+	default:
+		return false
+	}
+	return true
+}
+
+// DefaultRuneTyped provides the default rune typed handling.
+func (e *CodeEditor) DefaultRuneTyped(ch rune) bool {
+	if wnd := e.Window(); wnd != nil {
+		wnd.HideCursorUntilMouseMoves()
+	}
+	if unicode.IsControl(ch) {
+		return false
+	}
+	e.insert(i18n.Text("Typing"), []rune{ch}, true)
+	return true
+}
+
+// DefaultAccessibility provides the default accessibility description.
+func (e *CodeEditor) DefaultAccessibility() Accessibility {
+	a := Accessibility{
+		Value:          e.buffer.String(),
+		ActiveItem:     -1,
+		SelectionStart: e.selectionStart,
+		SelectionEnd:   e.selectionEnd,
+		Role:           role.TextField,
+		State:          AccessibleMultiLine,
+	}
+	if e.Enabled() {
+		a.State |= AccessibleEditable
+	} else {
+		a.State |= AccessibleReadOnly
+	}
+	return a
+}
+
+// tabInsertion returns the runes to insert when Tab is pressed with the caret at the start of the selection.
+func (e *CodeEditor) tabInsertion() []rune {
+	if !e.IndentWithSpaces {
+		return []rune{'\t'}
+	}
+	line := e.buffer.LineForOffset(e.selectionStart)
+	tabWidth := e.tabWidth()
+	columns := 0
+	for _, r := range e.buffer.Slice(e.buffer.LineStart(line), e.selectionStart) {
+		if r == '\t' {
+			columns += tabWidth - columns%tabWidth
+		} else {
+			columns++
+		}
+	}
+	return []rune(strings.Repeat(" ", tabWidth-columns%tabWidth))
+}
+
+// insertLineFeed inserts a line feed, indenting the new line to match the current one. If the caret follows an opening
+// bracket, the new line is indented one more level, and if it also precedes the matching closing bracket, the closing
+// bracket is moved to a line of its own.
+func (e *CodeEditor) insertLineFeed() {
+	lineStart := e.buffer.LineStart(e.buffer.LineForOffset(e.selectionStart))
+	var indent []rune
+	for _, r := range e.buffer.Slice(lineStart, e.selectionStart) {
+		if r != ' ' && r != '\t' {
+			break
+		}
+		indent = append(indent, r)
+	}
+	runes := append([]rune{'\n'}, indent...)
+	caret := len(runes)
+	before := e.buffer.RuneAt(e.selectionStart - 1)
+	if closing, ok := codeEditorClosingBrackets[before]; ok && e.selectionStart > lineStart {
+		runes = append(runes, e.indentUnit()...)
+		caret = len(runes)
+		if e.buffer.RuneAt(e.selectionEnd) == closing && e.selectionEnd < e.buffer.Len() {
+			runes = append(append(runes, '\n'), indent...)
+		}
+	}
+	start := e.selectionStart
+	e.editOnce(i18n.Text("Typing"), []codeEditorChange{e.change(start, e.selectionEnd, runes)}, start+caret,
+		start+caret)
+}
+
+// insert replaces the selection with the runes, leaving the caret after them. Consecutive calls with typing set are
+// combined into a single undoable edit until the caret is moved by other means.
+func (e *CodeEditor) insert(name string, runes []rune, typing bool) {
+	start := e.selectionStart
+	changes := []codeEditorChange{e.change(start, e.selectionEnd, runes)}
+	if typing {
+		e.edit(name, changes, start+len(runes), start+len(runes))
+	} else {
+		e.editOnce(name, changes, start+len(runes), start+len(runes))
+	}
+}
+
+func (e *CodeEditor) selectionSpansLines() bool {
+	return e.HasSelectionRange() &&
+		e.buffer.LineForOffset(e.selectionStart) != e.buffer.LineForOffset(e.selectionEnd-1)
+}
+
+// selectedLines returns the range of lines touched by the selection. A selection that ends at the start of a line
+// does not include that line.
+func (e *CodeEditor) selectedLines() (first, last int) {
+	first = e.buffer.LineForOffset(e.selectionStart)
+	last = e.buffer.LineForOffset(e.selectionEnd)
+	if last > first && e.buffer.LineStart(last) == e.selectionEnd {
+		last--
+	}
+	return first, last
+}
+
+// Indent adds a level of indentation to each line touched by the selection.
+func (e *CodeEditor) Indent() {
+	first, last := e.selectedLines()
+	unit := e.indentUnit()
+	changes := make([]codeEditorChange, 0, last-first+1)
+	for line := last; line >= first; line-- {
+		if e.buffer.LineStart(line) != e.buffer.LineEnd(line) {
+			changes = append(changes, e.change(e.buffer.LineStart(line), e.buffer.LineStart(line), unit))
+		}
+	}
+	e.editLines(i18n.Text("Indent"), changes)
+}
+
+// Outdent removes a level of indentation from each line touched by the selection.
+func (e *CodeEditor) Outdent() {
+	first, last := e.selectedLines()
+	tabWidth := e.tabWidth()
+	changes := make([]codeEditorChange, 0, last-first+1)
+	for line := last; line >= first; line-- {
+		start := e.buffer.LineStart(line)
+		end := start
+		for end < start+tabWidth && e.buffer.RuneAt(end) == ' ' {
+			end++
+		}
+		if end == start && e.buffer.RuneAt(start) == '\t' {
+			end++
+		}
+		if end != start {
+			changes = append(changes, e.change(start, end, nil))
+		}
+	}
+	e.editLines(i18n.Text("Outdent"), changes)
+}
+
+// editLines applies changes made to the starts of lines, which must be in descending order of offset, adjusting the
+// selection to match.
+func (e *CodeEditor) editLines(name string, changes []codeEditorChange) {
+	if len(changes) == 0 {
+		return
+	}
+	start := adjustOffsetForChanges(changes, e.selectionStart)
+	if e.HasSelectionRange() && e.selectionStart == e.buffer.LineStart(e.buffer.LineForOffset(e.selectionStart)) {
+		// Keep a selection of whole lines covering them entirely, rather than letting it start after the indent.
+		start = e.selectionStart
+	}
+	end := adjustOffsetForChanges(changes, e.selectionEnd)
+	e.editOnce(name, changes, start, end)
+}
+
+// adjustOffsetForChanges returns where the offset ends up once the changes, which must be non-overlapping and in
+// descending order of offset, have been applied.
+func adjustOffsetForChanges(changes []codeEditorChange, offset int) int {
+	result := offset
+	for _, c := range changes {
+		switch {
+		case offset >= c.offset+len(c.removed):
+			result += len(c.inserted) - len(c.removed)
+		case offset > c.offset:
+			result -= offset - c.offset
+		default:
+		}
+	}
+	return result
+}
+
+// change creates a change that replaces the runes in the range [start, end) with the given runes.
+func (e *CodeEditor) change(start, end int, runes []rune) codeEditorChange {
+	return codeEditorChange{offset: start, removed: e.buffer.Slice(start, end), inserted: runes}
+}
+
+// edit applies the changes, each to the text left by the previous one, selects the range [selStart, selEnd) and records
+// the whole as a single undoable edit.
+func (e *CodeEditor) edit(name string, changes []codeEditorChange, selStart, selEnd int) {
+	before := &codeEditorUndoState{
+		changes:        changes,
+		selectionStart: e.selectionStart,
+		selectionEnd:   e.selectionEnd,
+	}
+	for _, c := range changes {
+		e.replace(c.offset, c.offset+len(c.removed), c.inserted)
+	}
+	e.setSelection(selStart, selEnd, selStart)
+	if mgr := UndoManagerFor(e); mgr != nil {
+		mgr.Add(&UndoEdit[*codeEditorUndoState]{
+			ID:       e.undoID,
+			EditName: name,
+			EditCost: 1,
+			UndoFunc: func(edit *UndoEdit[*codeEditorUndoState]) {
+				for i := len(edit.BeforeData.changes) - 1; i >= 0; i-- {
+					c := edit.BeforeData.changes[i]
+					e.replace(c.offset, c.offset+len(c.inserted), c.removed)
+				}
+				e.restoreSelection(edit.BeforeData)
+			},
+			RedoFunc: func(edit *UndoEdit[*codeEditorUndoState]) {
+				for _, c := range edit.BeforeData.changes {
+					e.replace(c.offset, c.offset+len(c.removed), c.inserted)
+				}
+				e.restoreSelection(edit.AfterData)
+			},
+			AbsorbFunc: func(edit *UndoEdit[*codeEditorUndoState], other Undoable) bool {
+				if o, ok := other.(*UndoEdit[*codeEditorUndoState]); ok && o.ID == edit.ID {
+					edit.BeforeData.changes = append(edit.BeforeData.changes, o.BeforeData.changes...)
+					edit.AfterData = o.AfterData
+					return true
+				}
+				return false
+			},
+			BeforeData: before,
+			AfterData:  &codeEditorUndoState{selectionStart: selStart, selectionEnd: selEnd},
+		})
+	}
+	e.notifyOfModification()
+}
+
+// editOnce is like edit, but the resulting undoable edit never absorbs, nor is absorbed by, any other.
+func (e *CodeEditor) editOnce(name string, changes []codeEditorChange, selStart, selEnd int) {
+	e.undoID = NextUndoID()
+	e.edit(name, changes, selStart, selEnd)
+	e.undoID = NextUndoID()
+}
+
+func (e *CodeEditor) restoreSelection(state *codeEditorUndoState) {
+	e.undoID = NextUndoID()
+	e.setSelection(state.selectionStart, state.selectionEnd, state.selectionStart)
+	e.notifyOfModification()
+}
+
+func (e *CodeEditor) notifyOfModification() {
+	e.MarkForRedraw()
+	if e.ModifiedCallback != nil {
+		SafeCall(e.ModifiedCallback)
+	}
+}
+
+// replace replaces the runes in the range [start, end) with the given runes, without recording an undoable edit or
+// adjusting the selection.
+func (e *CodeEditor) replace(start, end int, runes []rune) {
+	lines := e.buffer.LineCount()
+	line := e.buffer.LineForOffset(start)
+	e.buffer.Replace(start, end, runes)
+	e.states = e.states[:min(len(e.states), line+1)]
+	e.searchMatches = nil
+	columns := e.maxColumns
+	e.updateMaxColumns(line, e.buffer.LineForOffset(start+len(runes)))
+	if lines != e.buffer.LineCount() || columns != e.maxColumns {
+		e.syncSize()
+	}
+	e.MarkForRedraw()
+}
+
+// CanCut returns true if the editor has a selection that can be cut.
+func (e *CodeEditor) CanCut() bool {
+	return e.HasSelectionRange()
+}
+
+// Cut the selected text to the clipboard.
+func (e *CodeEditor) Cut() {
+	if e.HasSelectionRange() {
+		ClipboardSetText(e.SelectedText())
+		e.editOnce(i18n.Text("Cut"), []codeEditorChange{e.change(e.selectionStart, e.selectionEnd, nil)},
+			e.selectionStart, e.selectionStart)
+	}
+}
+
+// CanCopy returns true if the editor has a selection that can be copied.
+func (e *CodeEditor) CanCopy() bool {
+	return e.HasSelectionRange()
+}
+
+// Copy the selected text to the clipboard.
+func (e *CodeEditor) Copy() {
+	if e.HasSelectionRange() {
+		ClipboardSetText(e.SelectedText())
+	}
+}
+
+// CanPaste returns true if the clipboard has content that can be pasted into the editor.
+func (e *CodeEditor) CanPaste() bool {
+	return ClipboardGetText() != ""
+}
+
+// Paste any text on the clipboard into the editor.
+func (e *CodeEditor) Paste() {
+	if text := ClipboardGetText(); text != "" {
+		e.insert(i18n.Text("Paste"), []rune(strings.ReplaceAll(text, "\r\n", "\n")), false)
+	}
+}
+
+// CanDelete returns true if the editor has a selection that can be deleted or a character before the caret.
+func (e *CodeEditor) CanDelete() bool {
+	return e.HasSelectionRange() || e.selectionStart > 0
+}
+
+// Delete removes the currently selected text, if any, or the character before the caret.
+func (e *CodeEditor) Delete() {
+	if !e.CanDelete() {
+		return
+	}
+	start := e.selectionStart
+	if !e.HasSelectionRange() {
+		start--
+	}
+	e.editOnce(i18n.Text("Delete"), []codeEditorChange{e.change(start, e.selectionEnd, nil)}, start, start)
+}
+
+// CanSelectAll returns true if the editor's selection can be expanded.
+func (e *CodeEditor) CanSelectAll() bool {
+	return e.selectionStart != 0 || e.selectionEnd != e.buffer.Len()
+}
+
+// SelectAll selects all of the text in the editor.
+func (e *CodeEditor) SelectAll() {
+	e.undoID = NextUndoID()
+	e.SetSelection(0, e.buffer.Len())
+}
+
+// ReplaceText replaces the runes in the range [start, end) with the text as a single undoable edit, then selects the
+// inserted text.
+func (e *CodeEditor) ReplaceText(start, end int, text string) {
+	length := e.buffer.Len()
+	start = max(min(start, length), 0)
+	end = max(min(end, length), start)
+	runes := []rune(strings.ReplaceAll(text, "\r\n", "\n"))
+	e.editOnce(i18n.Text("Replace"), []codeEditorChange{e.change(start, end, runes)}, start, start+len(runes))
+}
+
+// SelectedText returns the currently selected text.
+func (e *CodeEditor) SelectedText() string {
+	return string(e.buffer.Slice(e.selectionStart, e.selectionEnd))
+}
+
+// HasSelectionRange returns true is a selection range is currently present.
+func (e *CodeEditor) HasSelectionRange() bool {
+	return e.selectionStart < e.selectionEnd
+}
+
+// Selection returns the current start and end selection indexes.
+func (e *CodeEditor) Selection() (start, end int) {
+	return e.selectionStart, e.selectionEnd
+}
+
+// SetSelectionTo moves the caret to the specified index and removes any range that may have been present.
+func (e *CodeEditor) SetSelectionTo(pos int) {
+	e.SetSelection(pos, pos)
+}
+
+// SetSelection sets the start and end range of the selection. Values beyond either end will be constrained to the
+// appropriate end. Likewise, an end value less than the start value will be treated as if the start and end values were
+// the same.
+func (e *CodeEditor) SetSelection(start, end int) {
+	e.setSelection(start, end, start)
+}
+
+// caret returns the moving end of the selection.
+func (e *CodeEditor) caret() int {
+	if e.selectionAnchor == e.selectionStart {
+		return e.selectionEnd
+	}
+	return e.selectionStart
+}
+
+func (e *CodeEditor) moveTo(pos int, extend bool) {
+	e.undoID = NextUndoID()
+	if extend {
+		e.setSelectionFromAnchor(e.selectionAnchor, pos)
+	} else {
+		e.setSelection(pos, pos, pos)
+	}
+}
+
+func (e *CodeEditor) moveVertically(lines int, extend bool) {
+	caret := e.caret()
+	line := e.buffer.LineForOffset(caret)
+	if e.goalX < 0 {
+		e.goalX = e.buildLine(line, nil).position(caret - e.buffer.LineStart(line))
+	}
+	target := line + lines
+	e.keepGoalX = true
+	switch {
+	case target < 0:
+		e.moveTo(0, extend)
+	case target >= e.buffer.LineCount():
+		e.moveTo(e.buffer.Len(), extend)
+	default:
+		e.moveTo(e.buffer.LineStart(target)+e.buildLine(target, nil).runeIndex(e.goalX), extend)
+	}
+	e.keepGoalX = false
+}
+
+func (e *CodeEditor) linesPerPage() int {
+	height := e.ContentRect(false).Height
+	if scroller := e.ScrollRoot(); scroller != nil {
+		height = scroller.ContentView().ContentRect(false).Height
+	}
+	return max(int(height/e.lineHeight())-1, 1)
+}
+
+// homeFor returns the position that Home should move the caret to: the first non-whitespace character of the line, or
+// the start of the line if the caret is already there.
+func (e *CodeEditor) homeFor(pos int) int {
+	line := e.buffer.LineForOffset(pos)
+	start := e.buffer.LineStart(line)
+	end := e.buffer.LineEnd(line)
+	first := start
+	for first < end {
+		if r := e.buffer.RuneAt(first); r != ' ' && r != '\t' {
+			break
+		}
+		first++
+	}
+	if pos == first {
+		return start
+	}
+	return first
+}
+
+func (e *CodeEditor) setSelectionFromAnchor(anchor, pos int) {
+	if pos < anchor {
+		e.setSelection(pos, anchor, anchor)
+	} else {
+		e.setSelection(anchor, pos, anchor)
+	}
+}
+
+func (e *CodeEditor) setSelection(start, end, anchor int) {
+	length := e.buffer.Len()
+	start = max(min(start, length), 0)
+	end = max(min(end, length), start)
+	anchor = max(min(anchor, end), start)
+	if !e.keepGoalX {
+		e.goalX = -1
+	}
+	if e.selectionStart != start || e.selectionEnd != end || e.selectionAnchor != anchor {
+		e.selectionStart = start
+		e.selectionEnd = end
+		e.selectionAnchor = anchor
+		e.forceShowUntil = time.Now().Add(e.BlinkRate)
+		e.showCursor = true
+		e.MarkForRedraw()
+		e.ScrollSelectionIntoView()
+	}
+}
+
+// ScrollSelectionIntoView scrolls the moving end of the selection into view.
+func (e *CodeEditor) ScrollSelectionIntoView() {
+	pt := e.FromSelectionIndex(e.caret())
+	e.ScrollRectIntoView(geom.NewRect(pt.X-codeEditorTextInset, pt.Y, 2*codeEditorTextInset, e.lineHeight()))
+}
+
+// ToSelectionIndex returns the rune index for the coordinates.
+func (e *CodeEditor) ToSelectionIndex(where geom.Point) int {
+	y := where.Y - e.ContentRect(false).Y
+	if y < 0 {
+		return 0
+	}
+	line := int(y / e.lineHeight())
+	if line >= e.buffer.LineCount() {
+		return e.buffer.Len()
+	}
+	return e.buffer.LineStart(line) + e.buildLine(line, nil).runeIndex(where.X-e.textLeft())
+}
+
+// FromSelectionIndex returns the location in local coordinates of the top of the caret for the specified rune index.
+func (e *CodeEditor) FromSelectionIndex(index int) geom.Point {
+	index = max(min(index, e.buffer.Len()), 0)
+	line := e.buffer.LineForOffset(index)
+	x := e.textLeft() + e.buildLine(line, nil).position(index-e.buffer.LineStart(line))
+	return geom.NewPoint(x, e.ContentRect(false).Y+float32(line)*e.lineHeight())
+}
+
+func (e *CodeEditor) isWordPart(pos int) bool {
+	return pos >= 0 && pos < e.buffer.Len() && isFindWordPart(e.buffer.RuneAt(pos))
+}
+
+func (e *CodeEditor) findWordAt(pos int) (start, end int) {
+	start = pos
+	end = pos
+	if !e.isWordPart(pos) {
+		if !e.isWordPart(pos - 1) {
+			return pos, pos
+		}
+		start--
+		end--
+	}
+	for e.isWordPart(start - 1) {
+		start--
+	}
+	for e.isWordPart(end) {
+		end++
+	}
+	return start, end
+}
+
+func (e *CodeEditor) wordStartBefore(pos int) int {
+	pos--
+	for pos > 0 && !e.isWordPart(pos) {
+		pos--
+	}
+	for e.isWordPart(pos - 1) {
+		pos--
+	}
+	return max(pos, 0)
+}
+
+func (e *CodeEditor) wordEndAfter(pos int) int {
+	length := e.buffer.Len()
+	for pos < length && !e.isWordPart(pos) {
+		pos++
+	}
+	for e.isWordPart(pos) {
+		pos++
+	}
+	return pos
+}
+
+var (
+	codeEditorClosingBrackets = map[rune]rune{'(': ')', '[': ']', '{': '}'}
+	codeEditorOpeningBrackets = map[rune]rune{')': '(', ']': '[', '}': '{'}
+)
+
+// MatchingBrackets returns the offsets of the bracket adjacent to the caret and its match, or -1 for both if there is
+// no such pair. The character before the caret is considered first, then the one after it.
+func (e *CodeEditor) MatchingBrackets() (bracket, match int) {
+	if e.HasSelectionRange() {
+		return -1, -1
+	}
+	for _, pos := range []int{e.selectionStart - 1, e.selectionStart} {
+		if pos < 0 || pos >= e.buffer.Len() {
+			continue
+		}
+		r := e.buffer.RuneAt(pos)
+		if closing, ok := codeEditorClosingBrackets[r]; ok {
+			if other := e.scanForBracket(pos, 1, r, closing); other != -1 {
+				return pos, other
+			}
+		} else if opening, ok := codeEditorOpeningBrackets[r]; ok {
+			if other := e.scanForBracket(pos, -1, r, opening); other != -1 {
+				return pos, other
+			}
+		}
+	}
+	return -1, -1
+}
+
+func (e *CodeEditor) scanForBracket(pos, dir int, bracket, match rune) int {
+	depth := 0
+	length := e.buffer.Len()
+	for i := 0; i < codeEditorBracketScanLimit; i++ {
+		pos += dir
+		if pos < 0 || pos >= length {
+			break
+		}
+		switch e.buffer.RuneAt(pos) {
+		case bracket:
+			depth++
+		case match:
+			if depth == 0 {
+				return pos
+			}
+			depth--
+		default:
+		}
+	}
+	return -1
+}
+
+// FindNext selects the next match of the finder following the selection, or the previous one preceding it if backward
+// is true, wrapping around the ends of the text. Returns false if there are no matches.
+func (e *CodeEditor) FindNext(finder *TextFinder, backward bool) bool {
+	target, ok := nextMatch(finder.FindAll(e.buffer.Slice(0, e.buffer.Len())), e.selectionStart, e.selectionEnd,
+		backward)
+	if !ok {
+		return false
+	}
+	e.undoID = NextUndoID()
+	e.SetSelection(target[0], target[1])
+	return true
+}
+
+// ReplaceSelection replaces the selection with the replacement if the selection is a match of the finder, then selects
+// the next match. Returns true if a replacement was made.
+func (e *CodeEditor) ReplaceSelection(finder *TextFinder, replacement string) bool {
+	text := e.buffer.Slice(0, e.buffer.Len())
+	for _, m := range finder.FindAll(text) {
+		if m[0] == e.selectionStart && m[1] == e.selectionEnd {
+			runes := finder.Replacement(text, m, replacement)
+			e.editOnce(i18n.Text("Replace"), []codeEditorChange{e.change(m[0], m[1], runes)}, m[0]+len(runes),
+				m[0]+len(runes))
+			e.FindNext(finder, false)
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceAll replaces every match of the finder with the replacement as a single undoable edit. Returns the number of
+// replacements made.
+func (e *CodeEditor) ReplaceAll(finder *TextFinder, replacement string) int {
+	text := e.buffer.Slice(0, e.buffer.Len())
+	matches := finder.FindAll(text)
+	if len(matches) == 0 {
+		return 0
+	}
+	changes := make([]codeEditorChange, 0, len(matches))
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		changes = append(changes, codeEditorChange{
+			offset:   m[0],
+			removed:  text[m[0]:m[1]],
+			inserted: finder.Replacement(text, m, replacement),
+		})
+	}
+	caret := adjustOffsetForChanges(changes, e.selectionStart)
+	e.editOnce(i18n.Text("Replace All"), changes, caret, caret)
+	return len(matches)
+}
+
+// SearchableText implements Searchable.
+func (e *CodeEditor) SearchableText() []rune {
+	return e.buffer.Slice(0, e.buffer.Len())
+}
+
+// SetSearchHighlights implements Searchable.
+func (e *CodeEditor) SetSearchHighlights(ranges [][2]int) {
+	e.searchMatches = ranges
+	e.MarkForRedraw()
+}
+
+// ReplaceRanges implements EditableSearchable.
+func (e *CodeEditor) ReplaceRanges(name string, ranges [][2]int, replacements [][]rune) {
+	if len(ranges) == 0 {
+		return
+	}
+	changes := make([]codeEditorChange, 0, len(ranges))
+	caret := ranges[len(ranges)-1][1]
+	for i := len(ranges) - 1; i >= 0; i-- {
+		runes := []rune(strings.ReplaceAll(string(replacements[i]), "\r\n", "\n"))
+		changes = append(changes, e.change(ranges[i][0], ranges[i][1], runes))
+		caret += len(runes) - (ranges[i][1] - ranges[i][0])
+	}
+	e.editOnce(name, changes, caret, caret)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// DefaultCodeEditorGutterTheme holds the default CodeEditorGutterTheme values for CodeEditorGutters. Modifying this
+// data will not alter existing CodeEditorGutters, but will alter any CodeEditorGutters created in the future.
+var DefaultCodeEditorGutterTheme = CodeEditorGutterTheme{
+	BackgroundInk:        ThemeSurface,
+	LineNumberInk:        &ThemeColor{Light: RGB(140, 140, 140), Dark: RGB(110, 110, 110)},
+	CurrentLineNumberInk: ThemeOnSurface,
+	DividerInk:           ThemeSurfaceEdge,
+	HPadding:             8,
+	MinimumDigits:        2,
+}
+
+// CodeEditorGutterTheme holds theming data for a CodeEditorGutter.
+type CodeEditorGutterTheme struct {
+	BackgroundInk        Ink
+	LineNumberInk        Ink
+	CurrentLineNumberInk Ink
+	DividerInk           Ink
+	HPadding             float32
+	MinimumDigits        int
+}
+
+// CodeEditorGutter shows the line numbers of a CodeEditor. It is intended to be installed as the row header of the
+// ScrollPanel containing the CodeEditor. Clicking or dragging within it selects whole lines.
+type CodeEditorGutter struct {
+	editor *CodeEditor
+	CodeEditorGutterTheme
+	Panel
+	anchorLine int
+}
+
+// NewCodeEditorGutter creates a new gutter for the CodeEditor.
+func NewCodeEditorGutter(editor *CodeEditor) *CodeEditorGutter {
+	g := &CodeEditorGutter{
+		CodeEditorGutterTheme: DefaultCodeEditorGutterTheme,
+		editor:                editor,
+	}
+	g.Self = g
+	g.SetSizer(g.DefaultSizes)
+	g.DrawCallback = g.DefaultDraw
+	g.MouseDownCallback = g.DefaultMouseDown
+	g.MouseDragCallback = g.DefaultMouseDrag
+	g.UpdateCursorCallback = g.DefaultUpdateCursor
+	editor.gutter = g
+	return g
+}
+
+// Editor returns the CodeEditor this gutter is for.
+func (g *CodeEditorGutter) Editor() *CodeEditor {
+	return g.editor
+}
+
+// DefaultSizes provides the default sizing.
+func (g *CodeEditorGutter) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	digits := max(len(strconv.Itoa(g.editor.LineCount())), g.MinimumDigits)
+	prefSize.Width = xmath.Ceil(float32(digits)*g.editor.columnWidth() + 2*g.HPadding + 1)
+	prefSize.Height = g.editor.FrameRect().Height
+	if border := g.Border(); border != nil {
+		insets := border.Insets().Size()
+		prefSize = prefSize.Add(insets)
+	}
+	return prefSize, prefSize, prefSize
+}
+
+// DefaultDraw provides the default drawing.
+func (g *CodeEditorGutter) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
+	rect := g.ContentRect(true)
+	canvas.DrawRect(rect.Intersect(dirty), g.BackgroundInk.Paint(canvas, rect, paintstyle.Fill))
+	content := g.ContentRect(false)
+	divider := geom.NewRect(content.Right()-1, content.Y, 1, content.Height)
+	canvas.DrawRect(divider, g.DividerInk.Paint(canvas, divider, paintstyle.Fill))
+	top := g.editor.ContentRect(false).Y
+	lineHeight := g.editor.lineHeight()
+	caretLine := g.editor.LineForOffset(g.editor.caret())
+	first := max(int((dirty.Y-top)/lineHeight), 0)
+	last := min(int((dirty.Bottom()-top)/lineHeight), g.editor.LineCount()-1)
+	for i := first; i <= last; i++ {
+		ink := g.LineNumberInk
+		if i == caretLine {
+			ink = g.CurrentLineNumberInk
+		}
+		text := NewText(strconv.Itoa(i+1), &TextDecoration{Font: g.editor.Font, OnBackgroundInk: ink})
+		text.Draw(canvas, geom.NewPoint(content.Right()-(1+g.HPadding+text.Width()),
+			top+float32(i)*lineHeight+text.Baseline()))
+	}
+}
+
+// lineAt returns the line of the editor at the vertical position.
+func (g *CodeEditorGutter) lineAt(y float32) int {
+	line := int((y - g.editor.ContentRect(false).Y) / g.editor.lineHeight())
+	return max(min(line, g.editor.LineCount()-1), 0)
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (g *CodeEditorGutter) DefaultMouseDown(where geom.Point, button, _ int, mods mod.Modifiers) bool {
+	if button != ButtonLeft {
+		return false
+	}
+	g.editor.RequestFocus()
+	line := g.lineAt(where.Y)
+	if mods.ShiftDown() {
+		g.anchorLine = g.editor.LineForOffset(g.editor.selectionAnchor)
+	} else {
+		g.anchorLine = line
+	}
+	g.selectLines(line)
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (g *CodeEditorGutter) DefaultMouseDrag(where geom.Point, button int, _ mod.Modifiers) bool {
+	if button == ButtonLeft {
+		g.selectLines(g.lineAt(where.Y))
+		g.ScrollRectIntoView(geom.NewRect(where.X, where.Y, 1, 1))
+	}
+	return true
+}
+
+// DefaultUpdateCursor provides the default cursor update handling.
+func (g *CodeEditorGutter) DefaultUpdateCursor(_ geom.Point) *Cursor {
+	return ArrowCursor()
+}
+
+// selectLines selects the lines from the anchor line through the line, including the line feed of the last of them.
+func (g *CodeEditorGutter) selectLines(line int) {
+	e := g.editor
+	e.undoID = NextUndoID()
+	if line < g.anchorLine {
+		e.setSelection(e.LineStart(line), e.LineStart(g.anchorLine+1), e.LineStart(g.anchorLine+1))
+	} else {
+		e.setSelection(e.LineStart(g.anchorLine), e.LineStart(line+1), e.LineStart(g.anchorLine))
+	}
+}
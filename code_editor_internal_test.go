@@ -0,0 +1,169 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+func newCodeEditorWithUndo(t *testing.T, text string) (*CodeEditor, *UndoManager) {
+	host := newUndoHost(t)
+	e := NewCodeEditor()
+	host.AddChild(e)
+	e.SetText(text)
+	return e, host.mgr
+}
+
+func TestCodeEditorTypingIsOneUndoableEdit(t *testing.T) {
+	c := check.New(t)
+	e, mgr := newCodeEditorWithUndo(t, "ab")
+	e.SetSelectionTo(1)
+	for _, r := range "xyz" {
+		e.DefaultRuneTyped(r)
+	}
+	c.Equal("axyzb", e.Text())
+	e.Delete()
+	c.Equal("axyb", e.Text())
+	mgr.Undo()
+	c.Equal("axyzb", e.Text())
+	mgr.Undo()
+	c.Equal("ab", e.Text())
+	start, end := e.Selection()
+	c.Equal(1, start)
+	c.Equal(1, end)
+	mgr.Redo()
+	c.Equal("axyzb", e.Text())
+	start, _ = e.Selection()
+	c.Equal(4, start)
+}
+
+func TestCodeEditorAutoIndent(t *testing.T) {
+	c := check.New(t)
+	e := NewCodeEditor()
+	e.SetText("\tif x {}")
+	e.SetSelectionTo(7)
+	e.DefaultKeyDown(KeyReturn, 0, false)
+	c.Equal("\tif x {\n\t\t\n\t}", e.Text())
+	start, _ := e.Selection()
+	c.Equal(10, start)
+
+	e.SetText("  a")
+	e.SetSelectionTo(3)
+	e.DefaultKeyDown(KeyReturn, 0, false)
+	c.Equal("  a\n  ", e.Text())
+}
+
+func TestCodeEditorIndentAndOutdent(t *testing.T) {
+	c := check.New(t)
+	e, mgr := newCodeEditorWithUndo(t, "a\n\nb\nc")
+	e.SetSelection(0, 5)
+	e.DefaultKeyDown(KeyTab, 0, false)
+	c.Equal("\ta\n\n\tb\nc", e.Text(), "empty lines and the line the selection ends at the start of are skipped")
+	start, end := e.Selection()
+	c.Equal(0, start)
+	c.Equal(7, end)
+	e.IndentWithSpaces = true
+	e.Indent()
+	c.Equal("    \ta\n\n    \tb\nc", e.Text())
+	e.DefaultKeyDown(KeyTab, mod.Shift, false)
+	e.DefaultKeyDown(KeyTab, mod.Shift, false)
+	c.Equal("a\n\nb\nc", e.Text())
+	mgr.Undo()
+	mgr.Undo()
+	mgr.Undo()
+	c.Equal("\ta\n\n\tb\nc", e.Text())
+}
+
+func TestCodeEditorMatchingBrackets(t *testing.T) {
+	c := check.New(t)
+	e := NewCodeEditor()
+	e.SetText("f(a[0], (b))")
+	e.SetSelectionTo(2)
+	bracket, match := e.MatchingBrackets()
+	c.Equal(1, bracket)
+	c.Equal(11, match)
+	e.SetSelectionTo(11)
+	bracket, match = e.MatchingBrackets()
+	c.Equal(10, bracket)
+	c.Equal(8, match)
+	e.SetSelectionTo(5)
+	bracket, match = e.MatchingBrackets()
+	c.Equal(5, bracket)
+	c.Equal(3, match)
+	e.SetSelection(1, 2)
+	bracket, _ = e.MatchingBrackets()
+	c.Equal(-1, bracket)
+}
+
+func TestCodeEditorFindAndReplace(t *testing.T) {
+	c := check.New(t)
+	e, mgr := newCodeEditorWithUndo(t, "cat dog cat\ncat")
+	finder, err := NewTextFinder("cat", FindOptions{WholeWord: true})
+	c.NoError(err)
+	c.True(e.FindNext(finder, false))
+	start, end := e.Selection()
+	c.Equal(0, start)
+	c.Equal(3, end)
+	c.True(e.FindNext(finder, false))
+	start, _ = e.Selection()
+	c.Equal(8, start)
+	c.True(e.FindNext(finder, true))
+	start, _ = e.Selection()
+	c.Equal(0, start)
+	c.True(e.FindNext(finder, true), "searching wraps around")
+	start, _ = e.Selection()
+	c.Equal(12, start)
+
+	e.SetSelection(8, 11)
+	c.True(e.ReplaceSelection(finder, "cow"))
+	c.Equal("cat dog cow\ncat", e.Text())
+	start, _ = e.Selection()
+	c.Equal(12, start, "the next match is selected")
+
+	e.SetSelectionTo(5)
+	c.Equal(2, e.ReplaceAll(finder, "bird"))
+	c.Equal("bird dog cow\nbird", e.Text())
+	start, _ = e.Selection()
+	c.Equal(6, start)
+	mgr.Undo()
+	c.Equal("cat dog cow\ncat", e.Text(), "replacing all is a single undoable edit")
+}
+
+func TestCodeEditorClipboard(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	e := NewCodeEditor()
+	e.SetText("one two")
+	e.SetSelection(0, 4)
+	c.True(e.CanPerformCmd(e, CutItemID))
+	e.PerformCmd(e, CutItemID)
+	c.Equal("two", e.Text())
+	e.SetSelectionTo(3)
+	e.PerformCmd(e, PasteItemID)
+	c.Equal("twoone ", e.Text())
+	e.PerformCmd(e, SelectAllItemID)
+	e.PerformCmd(e, CopyItemID)
+	c.Equal("twoone ", ClipboardGetText())
+}
+
+func TestCodeEditorLineLayoutExpandsTabs(t *testing.T) {
+	c := check.New(t)
+	e := NewCodeEditor()
+	e.SetText("a\tb")
+	line := e.buildLine(0, nil)
+	c.Equal("a   b", line.text.String())
+	c.Equal([]int{0, 1, 4, 5}, line.columns)
+	c.Equal(2, line.runeIndex(line.text.PositionForRuneIndex(4)))
+	c.Equal(1, line.runeIndex(line.text.PositionForRuneIndex(1)))
+	c.Equal(5, e.maxColumns)
+}
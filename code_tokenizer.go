@@ -0,0 +1,222 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/unison/enums/tokenkind"
+)
+
+// Token identifies the kind of a range of runes [Start, End) within a line.
+type Token struct {
+	Kind  tokenkind.Enum
+	Start int
+	End   int
+}
+
+// Tokenizer splits lines of text into tokens for syntax highlighting.
+type Tokenizer interface {
+	// Tokenize returns the tokens found in the line, which does not include its line feed. The state is the value
+	// returned as endState from the call for the previous line, or 0 for the first line, and allows constructs such as
+	// block comments to span lines. Runes not covered by a token are treated as tokenkind.Plain.
+	Tokenize(line []rune, state int) (tokens []Token, endState int)
+}
+
+// SimpleTokenizer provides a Tokenizer that handles the lexical structure shared by many C-like languages: words,
+// numbers, quoted strings, line and block comments, operators and punctuation.
+type SimpleTokenizer struct {
+	// Words maps words to the kind of token they should be reported as, typically tokenkind.Keyword, tokenkind.Type or
+	// tokenkind.Constant. Words not found here are reported as tokenkind.Function if directly followed by an opening
+	// parenthesis, and as tokenkind.Identifier otherwise.
+	Words map[string]tokenkind.Enum
+	// LineComment starts a comment that runs to the end of the line.
+	LineComment string
+	// BlockCommentStart and BlockCommentEnd delimit a comment that may span lines.
+	BlockCommentStart string
+	BlockCommentEnd   string
+	// StringDelimiters holds the runes that start and end a string on a single line. A backslash escapes the rune that
+	// follows it.
+	StringDelimiters string
+	// RawStringDelimiters holds the runes that start and end a string which may span lines and has no escapes.
+	RawStringDelimiters string
+	// Operators holds the runes that are reported as tokenkind.Operator. Other non-space symbols are reported as
+	// tokenkind.Punctuation.
+	Operators string
+}
+
+const (
+	simpleTokenizerNormalState = iota
+	simpleTokenizerBlockCommentState
+	// simpleTokenizerRawStringState is added to the index of the delimiter within RawStringDelimiters.
+	simpleTokenizerRawStringState
+)
+
+// NewGoTokenizer creates a new SimpleTokenizer for Go source code.
+func NewGoTokenizer() *SimpleTokenizer {
+	t := &SimpleTokenizer{
+		Words:               make(map[string]tokenkind.Enum),
+		LineComment:         "//",
+		BlockCommentStart:   "/*",
+		BlockCommentEnd:     "*/",
+		StringDelimiters:    `"'`,
+		RawStringDelimiters: "`",
+		Operators:           "+-*/%&|^<>=!:~",
+	}
+	for _, w := range strings.Fields(`break case chan const continue default defer else fallthrough for func go goto if
+		import interface map package range return select struct switch type var`) {
+		t.Words[w] = tokenkind.Keyword
+	}
+	for _, w := range strings.Fields(`any bool byte comparable complex64 complex128 error float32 float64 int int8 int16
+		int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr`) {
+		t.Words[w] = tokenkind.Type
+	}
+	for _, w := range strings.Fields("true false iota nil") {
+		t.Words[w] = tokenkind.Constant
+	}
+	return t
+}
+
+// Tokenize implements Tokenizer.
+func (t *SimpleTokenizer) Tokenize(line []rune, state int) (tokens []Token, endState int) {
+	i := 0
+	rawDelimiters := []rune(t.RawStringDelimiters)
+	switch {
+	case state == simpleTokenizerBlockCommentState:
+		end, found := t.endOfBlockComment(line, 0)
+		tokens = append(tokens, Token{Kind: tokenkind.Comment, Start: 0, End: end})
+		if !found {
+			return tokens, state
+		}
+		i = end
+	case state >= simpleTokenizerRawStringState && state-simpleTokenizerRawStringState < len(rawDelimiters):
+		end, found := endOfRawString(line, 0, rawDelimiters[state-simpleTokenizerRawStringState])
+		tokens = append(tokens, Token{Kind: tokenkind.String, Start: 0, End: end})
+		if !found {
+			return tokens, state
+		}
+		i = end
+	default:
+	}
+	for i < len(line) {
+		r := line[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case t.LineComment != "" && hasRunePrefix(line[i:], t.LineComment):
+			return append(tokens, Token{Kind: tokenkind.Comment, Start: i, End: len(line)}), simpleTokenizerNormalState
+		case t.BlockCommentStart != "" && hasRunePrefix(line[i:], t.BlockCommentStart):
+			end, found := t.endOfBlockComment(line, i+len([]rune(t.BlockCommentStart)))
+			tokens = append(tokens, Token{Kind: tokenkind.Comment, Start: i, End: end})
+			if !found {
+				return tokens, simpleTokenizerBlockCommentState
+			}
+			i = end
+			continue
+		case slices.Contains(rawDelimiters, r):
+			end, found := endOfRawString(line, i+1, r)
+			tokens = append(tokens, Token{Kind: tokenkind.String, Start: i, End: end})
+			if !found {
+				return tokens, simpleTokenizerRawStringState + slices.Index(rawDelimiters, r)
+			}
+			i = end
+			continue
+		case strings.ContainsRune(t.StringDelimiters, r):
+			kind := tokenkind.Invalid
+			for i++; i < len(line); i++ {
+				if line[i] == '\\' {
+					i++
+				} else if line[i] == r {
+					i++
+					kind = tokenkind.String
+					break
+				}
+			}
+			i = min(i, len(line))
+			tokens = append(tokens, Token{Kind: kind, Start: start, End: i})
+			continue
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(line) && unicode.IsDigit(line[i+1])):
+			i++
+			for i < len(line) && (isFindWordPart(line[i]) || line[i] == '.' ||
+				((line[i] == '+' || line[i] == '-') && (line[i-1] == 'e' || line[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: tokenkind.Number, Start: start, End: i})
+			continue
+		case isFindWordPart(r):
+			i++
+			for i < len(line) && isFindWordPart(line[i]) {
+				i++
+			}
+			kind, ok := t.Words[string(line[start:i])]
+			if !ok {
+				kind = tokenkind.Identifier
+				j := i
+				for j < len(line) && unicode.IsSpace(line[j]) {
+					j++
+				}
+				if j < len(line) && line[j] == '(' {
+					kind = tokenkind.Function
+				}
+			}
+			tokens = append(tokens, Token{Kind: kind, Start: start, End: i})
+			continue
+		case strings.ContainsRune(t.Operators, r):
+			// Operators run together, but a comment that follows one directly is not part of it.
+			i++
+			for i < len(line) && strings.ContainsRune(t.Operators, line[i]) &&
+				(t.LineComment == "" || !hasRunePrefix(line[i:], t.LineComment)) &&
+				(t.BlockCommentStart == "" || !hasRunePrefix(line[i:], t.BlockCommentStart)) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: tokenkind.Operator, Start: start, End: i})
+			continue
+		default:
+			i++
+			tokens = append(tokens, Token{Kind: tokenkind.Punctuation, Start: start, End: i})
+		}
+	}
+	return tokens, simpleTokenizerNormalState
+}
+
+// endOfBlockComment returns the index just past the end of the block comment that continues at the index, and whether
+// the end was found on this line.
+func (t *SimpleTokenizer) endOfBlockComment(line []rune, i int) (end int, found bool) {
+	for ; i < len(line); i++ {
+		if hasRunePrefix(line[i:], t.BlockCommentEnd) {
+			return i + len([]rune(t.BlockCommentEnd)), true
+		}
+	}
+	return len(line), false
+}
+
+func endOfRawString(line []rune, i int, delimiter rune) (end int, found bool) {
+	for ; i < len(line); i++ {
+		if line[i] == delimiter {
+			return i + 1, true
+		}
+	}
+	return len(line), false
+}
+
+func hasRunePrefix(runes []rune, prefix string) bool {
+	i := 0
+	for _, r := range prefix {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
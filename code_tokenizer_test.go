@@ -0,0 +1,82 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/tokenkind"
+)
+
+type tokenText struct {
+	text string
+	kind tokenkind.Enum
+}
+
+func tokenize(tokenizer unison.Tokenizer, line string, state int) (tokens []tokenText, endState int) {
+	runes := []rune(line)
+	found, endState := tokenizer.Tokenize(runes, state)
+	for _, token := range found {
+		tokens = append(tokens, tokenText{text: string(runes[token.Start:token.End]), kind: token.Kind})
+	}
+	return tokens, endState
+}
+
+func TestGoTokenizer(t *testing.T) {
+	c := check.New(t)
+	tokenizer := unison.NewGoTokenizer()
+	tokens, state := tokenize(tokenizer, `func f(s string) int { return 0x1F+len(s) // done`, 0)
+	c.Equal([]tokenText{
+		{"func", tokenkind.Keyword},
+		{"f", tokenkind.Function},
+		{"(", tokenkind.Punctuation},
+		{"s", tokenkind.Identifier},
+		{"string", tokenkind.Type},
+		{")", tokenkind.Punctuation},
+		{"int", tokenkind.Type},
+		{"{", tokenkind.Punctuation},
+		{"return", tokenkind.Keyword},
+		{"0x1F", tokenkind.Number},
+		{"+", tokenkind.Operator},
+		{"len", tokenkind.Function},
+		{"(", tokenkind.Punctuation},
+		{"s", tokenkind.Identifier},
+		{")", tokenkind.Punctuation},
+		{"// done", tokenkind.Comment},
+	}, tokens)
+	c.Equal(0, state)
+
+	tokens, _ = tokenize(tokenizer, `x := "a\"b" + 'c' + "open`, 0)
+	c.Equal(tokenText{`"a\"b"`, tokenkind.String}, tokens[2])
+	c.Equal(tokenText{`'c'`, tokenkind.String}, tokens[4])
+	c.Equal(tokenText{`"open`, tokenkind.Invalid}, tokens[6], "unterminated strings are invalid")
+}
+
+func TestGoTokenizerCarriesStateAcrossLines(t *testing.T) {
+	c := check.New(t)
+	tokenizer := unison.NewGoTokenizer()
+	tokens, state := tokenize(tokenizer, "a /* start", 0)
+	c.Equal(tokenText{"/* start", tokenkind.Comment}, tokens[1])
+	c.NotEqual(0, state)
+	tokens, state = tokenize(tokenizer, "middle", state)
+	c.Equal([]tokenText{{"middle", tokenkind.Comment}}, tokens)
+	tokens, state = tokenize(tokenizer, "end */ b := `raw", state)
+	c.Equal([]tokenText{
+		{"end */", tokenkind.Comment},
+		{"b", tokenkind.Identifier},
+		{":=", tokenkind.Operator},
+		{"`raw", tokenkind.String},
+	}, tokens)
+	tokens, state = tokenize(tokenizer, "string` nil", state)
+	c.Equal([]tokenText{{"string`", tokenkind.String}, {"nil", tokenkind.Constant}}, tokens)
+	c.Equal(0, state)
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// ComboBox combines a Field with a dropdown List of suggestions that is filtered as the user types. The suggestions
+// are normally drawn from the items set on it, but a SuggestionProvider may be supplied to produce them instead. The
+// Up and Down keys move through the suggestions, Return or Enter chooses the highlighted one and Escape closes the
+// dropdown.
+type ComboBox[T any] struct {
+	*Field
+	// ItemText returns the text for an item, which is used both in the dropdown and in the field once the item has been
+	// chosen. The default uses fmt.Sprintf("%v", item).
+	ItemText func(item T) string
+	// Matches returns true if the item should be suggested for the text. The default is a case-insensitive substring
+	// match against the item's text.
+	Matches func(item T, text string) bool
+	// SuggestionProvider, if set, is used in place of filtering the items to produce the suggestions for the text. It
+	// is called on the UI thread, but may call suggest from any goroutine at any later time, which permits the work to
+	// be done elsewhere, such as when querying an index. Suggestions for text that is no longer current are discarded.
+	SuggestionProvider func(text string, suggest func(suggestions []T))
+	// ChoiceMadeCallback is called after the user has chosen one of the suggestions.
+	ChoiceMadeCallback func(item T)
+	list               *List[T]
+	scroller           *ScrollPanel
+	items              []T
+	chosen             T
+	// MaxVisibleSuggestions is the maximum number of suggestions visible in the dropdown before it needs to scroll.
+	MaxVisibleSuggestions int
+	generation            int
+	hasChosen             bool
+	freeTextAllowed       bool
+	open                  bool
+	choosing              bool
+}
+
+type comboBoxCellFactory[T any] struct {
+	DefaultCellFactory
+	combo *ComboBox[T]
+}
+
+// NewComboBox creates a new ComboBox that suggests from the specified items. By default, any text may be entered.
+func NewComboBox[T any](items ...T) *ComboBox[T] {
+	c := &ComboBox[T]{
+		Field:                 NewField(),
+		list:                  NewList[T](),
+		scroller:              NewScrollPanel(),
+		items:                 slices.Clone(items),
+		MaxVisibleSuggestions: 8,
+		freeTextAllowed:       true,
+	}
+	c.Self = c
+	UninstallFocusBorders(c, c)
+	c.LostFocusCallback = c.DefaultFocusLost
+	InstallDefaultFieldBorder(c, c)
+	c.ModifiedCallback = c.DefaultModified
+	c.ValidateCallback = c.DefaultValidate
+	c.KeyDownCallback = c.DefaultKeyDown
+	c.list.Factory = &comboBoxCellFactory[T]{combo: c}
+	c.list.SetAllowMultipleSelection(false)
+	c.list.SetFocusable(false)
+	c.list.MouseDownCallback = c.listMouseDown
+	c.list.MouseMoveCallback = c.listMouseMove
+	c.scroller.SetBorder(NewLineBorder(ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
+	c.scroller.SetContent(c.list, behavior.Follow, behavior.Fill)
+	return c
+}
+
+// Items returns the items suggestions are drawn from when there is no SuggestionProvider.
+func (c *ComboBox[T]) Items() []T {
+	return slices.Clone(c.items)
+}
+
+// SetItems sets the items suggestions are drawn from when there is no SuggestionProvider.
+func (c *ComboBox[T]) SetItems(items ...T) {
+	c.items = slices.Clone(items)
+	if c.open {
+		c.updateSuggestions()
+	}
+	c.Validate()
+}
+
+// FreeTextAllowed returns true if any text may be entered. When false, the field is marked as invalid unless its text
+// is empty or matches one of the items or current suggestions.
+func (c *ComboBox[T]) FreeTextAllowed() bool {
+	return c.freeTextAllowed
+}
+
+// SetFreeTextAllowed sets whether any text may be entered. When false, the field is marked as invalid unless its text
+// is empty or matches one of the items or current suggestions.
+func (c *ComboBox[T]) SetFreeTextAllowed(allowed bool) {
+	if c.freeTextAllowed != allowed {
+		c.freeTextAllowed = allowed
+		c.Validate()
+	}
+}
+
+// Selected returns the item whose text matches the text in the field, if any.
+func (c *ComboBox[T]) Selected() (item T, ok bool) {
+	text := c.Text()
+	if c.hasChosen && c.itemText(c.chosen) == text {
+		return c.chosen, true
+	}
+	for _, one := range c.items {
+		if c.itemText(one) == text {
+			return one, true
+		}
+	}
+	for _, one := range c.list.rows {
+		if c.itemText(one) == text {
+			return one, true
+		}
+	}
+	return item, false
+}
+
+// SetSelected sets the text in the field to that of the item, without showing the suggestions.
+func (c *ComboBox[T]) SetSelected(item T) {
+	c.chosen = item
+	c.hasChosen = true
+	c.choosing = true
+	c.SetText(c.itemText(item))
+	c.choosing = false
+	c.Validate()
+}
+
+// Suggestions returns the current suggestions.
+func (c *ComboBox[T]) Suggestions() []T {
+	return slices.Clone(c.list.rows)
+}
+
+// SuggestionsShown returns true if the dropdown of suggestions has been requested. It is only visible while there is
+// at least one suggestion.
+func (c *ComboBox[T]) SuggestionsShown() bool {
+	return c.open
+}
+
+// ShowSuggestions updates the suggestions for the current text and shows the dropdown.
+func (c *ComboBox[T]) ShowSuggestions() {
+	c.open = true
+	c.updateSuggestions()
+}
+
+// HideSuggestions hides the dropdown. Any suggestions still being produced by a SuggestionProvider are discarded.
+func (c *ComboBox[T]) HideSuggestions() {
+	c.open = false
+	c.generation++
+	c.syncDropDown()
+}
+
+func (c *ComboBox[T]) itemText(item T) string {
+	if c.ItemText != nil {
+		return c.ItemText(item)
+	}
+	return fmt.Sprintf("%v", item)
+}
+
+func (c *ComboBox[T]) matches(item T, text string) bool {
+	if c.Matches != nil {
+		return c.Matches(item, text)
+	}
+	return strings.Contains(strings.ToLower(c.itemText(item)), strings.ToLower(text))
+}
+
+func (c *ComboBox[T]) updateSuggestions() {
+	c.generation++
+	text := c.Text()
+	if c.SuggestionProvider != nil {
+		generation := c.generation
+		SafeCall(func() {
+			c.SuggestionProvider(text, func(suggestions []T) {
+				suggestions = slices.Clone(suggestions)
+				InvokeTask(func() {
+					if generation == c.generation {
+						c.setSuggestions(suggestions)
+					}
+				})
+			})
+		})
+		return
+	}
+	suggestions := make([]T, 0, len(c.items))
+	for _, item := range c.items {
+		if c.matches(item, text) {
+			suggestions = append(suggestions, item)
+		}
+	}
+	c.setSuggestions(suggestions)
+}
+
+func (c *ComboBox[T]) setSuggestions(suggestions []T) {
+	c.list.Clear()
+	c.list.Append(suggestions...)
+	c.syncDropDown()
+	if !c.freeTextAllowed {
+		c.Validate()
+	}
+}
+
+// syncDropDown adds, positions or removes the dropdown to match the current state.
+func (c *ComboBox[T]) syncDropDown() {
+	wnd := c.Window()
+	if wnd == nil {
+		return
+	}
+	panel := c.scroller.AsPanel()
+	if !c.open || c.list.Count() == 0 {
+		if wnd.root.dropDownPanel == panel {
+			wnd.root.setDropDown(nil, nil)
+		}
+		return
+	}
+	var insets geom.Insets
+	if border := c.scroller.Border(); border != nil {
+		insets = border.Insets()
+	}
+	visible := min(c.list.Count(), max(c.MaxVisibleSuggestions, 1))
+	size := geom.NewSize(c.ContentRect(true).Width, c.list.RowRect(visible-1).Bottom()+insets.Top+insets.Bottom)
+	if wnd.root.dropDownPanel != panel {
+		wnd.root.setDropDown(panel, c.HideSuggestions)
+	}
+	panel.SetFrameRect(wnd.root.dropDownRect(c.AsPanel(), size))
+	c.scroller.MarkForLayoutAndRedraw()
+}
+
+// moveSuggestionSelection moves the highlighted suggestion by the delta, highlighting the first or last suggestion if
+// none are currently highlighted.
+func (c *ComboBox[T]) moveSuggestionSelection(delta int) {
+	count := c.list.Count()
+	if count == 0 {
+		return
+	}
+	index := c.list.Selection.FirstSet()
+	switch {
+	case index != -1:
+		index = max(min(index+delta, count-1), 0)
+	case delta < 0:
+		index = count - 1
+	default:
+		index = 0
+	}
+	c.list.Select(false, index)
+	c.list.ScrollRectIntoView(c.list.RowRect(index))
+	c.list.MarkForRedraw()
+}
+
+// choose places the text of the suggestion at the index into the field and hides the dropdown.
+func (c *ComboBox[T]) choose(index int) {
+	if index < 0 || index >= c.list.Count() {
+		return
+	}
+	item := c.list.DataAtIndex(index)
+	c.SetSelected(item)
+	c.HideSuggestions()
+	if c.ChoiceMadeCallback != nil {
+		SafeCall(func() { c.ChoiceMadeCallback(item) })
+	}
+}
+
+// DefaultModified is the default implementation for the ModifiedCallback. It updates the suggestions as the user
+// types.
+func (c *ComboBox[T]) DefaultModified(_, _ *FieldState) {
+	if !c.choosing && c.Focused() {
+		c.ShowSuggestions()
+	}
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (c *ComboBox[T]) DefaultValidate() bool {
+	if !c.freeTextAllowed && c.Text() != "" {
+		if _, ok := c.Selected(); !ok {
+			c.Tooltip = NewTooltipWithText(i18n.Text("Must match one of the available choices"))
+			return false
+		}
+	}
+	c.Tooltip = nil
+	return true
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (c *ComboBox[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if mods&mod.NonSticky == 0 {
+		switch keyCode {
+		case KeyDown:
+			if c.open {
+				c.moveSuggestionSelection(1)
+			} else {
+				c.ShowSuggestions()
+			}
+			return true
+		case KeyUp:
+			if c.open {
+				c.moveSuggestionSelection(-1)
+				return true
+			}
+		case KeyReturn, KeyNumPadEnter:
+			if c.open {
+				if index := c.list.Selection.FirstSet(); index != -1 {
+					c.choose(index)
+					return true
+				}
+				c.HideSuggestions()
+			}
+		case KeyEscape:
+			if c.open {
+				c.HideSuggestions()
+				return true
+			}
+		case KeyTab:
+			c.HideSuggestions()
+		}
+	}
+	return c.Field.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (c *ComboBox[T]) DefaultFocusLost() {
+	c.HideSuggestions()
+	c.Field.DefaultFocusLost()
+}
+
+func (c *ComboBox[T]) listMouseDown(where geom.Point, button, _ int, _ mod.Modifiers) bool {
+	// The list never takes the focus, so that the field retains it while the user picks a suggestion
+	if button == ButtonLeft {
+		if index, _ := c.list.rowAt(where.Y); index != -1 {
+			c.choose(index)
+		}
+	}
+	return true
+}
+
+func (c *ComboBox[T]) listMouseMove(where geom.Point, _ mod.Modifiers) bool {
+	if index, _ := c.list.rowAt(where.Y); index != -1 && !c.list.Selection.State(index) {
+		c.list.Select(false, index)
+		c.list.MarkForRedraw()
+	}
+	return true
+}
+
+// CreateCell implements CellFactory.
+func (f *comboBoxCellFactory[T]) CreateCell(owner Paneler, element any, row int, foreground, background Ink, selected, focused bool) Paneler {
+	if item, ok := element.(T); ok {
+		element = f.combo.itemText(item)
+	}
+	return f.DefaultCellFactory.CreateCell(owner, element, row, foreground, background, selected, focused)
+}
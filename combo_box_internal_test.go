@@ -0,0 +1,114 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+func TestComboBoxSuggestions(t *testing.T) {
+	c := check.New(t)
+	combo := NewComboBox("Apple", "Apricot", "Banana", "Cherry")
+	var chosen []string
+	combo.ChoiceMadeCallback = func(item string) { chosen = append(chosen, item) }
+	c.False(combo.SuggestionsShown())
+	c.True(combo.DefaultKeyDown(KeyDown, 0, false), "Down opens the suggestions")
+	c.True(combo.SuggestionsShown())
+	c.Equal([]string{"Apple", "Apricot", "Banana", "Cherry"}, combo.Suggestions())
+
+	combo.SetText("AP")
+	combo.ShowSuggestions()
+	c.Equal([]string{"Apple", "Apricot"}, combo.Suggestions(), "matching is case-insensitive")
+	c.Equal(-1, combo.list.Selection.FirstSet())
+	c.True(combo.DefaultKeyDown(KeyUp, 0, false))
+	c.Equal(1, combo.list.Selection.FirstSet(), "Up starts from the last suggestion")
+	c.True(combo.DefaultKeyDown(KeyDown, 0, false))
+	c.Equal(1, combo.list.Selection.FirstSet(), "movement stops at the ends")
+	c.True(combo.DefaultKeyDown(KeyUp, 0, false))
+	c.True(combo.DefaultKeyDown(KeyReturn, 0, false))
+	c.Equal("Apple", combo.Text())
+	c.False(combo.SuggestionsShown())
+	c.Equal([]string{"Apple"}, chosen)
+	item, ok := combo.Selected()
+	c.True(ok)
+	c.Equal("Apple", item)
+
+	combo.ShowSuggestions()
+	c.True(combo.DefaultKeyDown(KeyEscape, 0, false), "Escape closes the suggestions")
+	c.False(combo.SuggestionsShown())
+	c.False(combo.DefaultKeyDown(KeyEscape, 0, false), "Escape is passed along once closed")
+	combo.DefaultKeyDown(KeyDown, mod.Shift, false)
+	c.False(combo.SuggestionsShown(), "Down with modifiers is left to the field")
+
+	combo.Matches = func(item, text string) bool { return strings.HasPrefix(item, text) }
+	combo.SetText("B")
+	combo.ShowSuggestions()
+	c.Equal([]string{"Banana"}, combo.Suggestions())
+	combo.SetItems("Blueberry", "Cranberry")
+	c.Equal([]string{"Blueberry"}, combo.Suggestions(), "new items are filtered while open")
+}
+
+func TestComboBoxMustMatch(t *testing.T) {
+	c := check.New(t)
+	combo := NewComboBox(1, 2, 3)
+	combo.ItemText = func(item int) string { return []string{"", "One", "Two", "Three"}[item] }
+	combo.SetText("Four")
+	c.False(combo.Invalid(), "free text is allowed by default")
+	c.Nil(combo.Tooltip)
+	combo.SetFreeTextAllowed(false)
+	c.True(combo.Invalid())
+	c.NotNil(combo.Tooltip)
+	combo.SetText("Two")
+	c.False(combo.Invalid())
+	c.Nil(combo.Tooltip)
+	item, ok := combo.Selected()
+	c.True(ok)
+	c.Equal(2, item)
+	combo.SetText("")
+	c.False(combo.Invalid(), "empty text is permitted")
+	combo.SetSelected(3)
+	c.Equal("Three", combo.Text())
+	c.False(combo.SuggestionsShown(), "setting the selection doesn't show the suggestions")
+}
+
+func TestComboBoxSuggestionProvider(t *testing.T) {
+	c := check.New(t)
+	resetTaskQueue()
+	defer resetTaskQueue()
+	combo := NewComboBox[string]()
+	combo.SetFreeTextAllowed(false)
+	var pending []func()
+	combo.SuggestionProvider = func(text string, suggest func([]string)) {
+		pending = append(pending, func() { suggest([]string{text + "1", text + "2"}) })
+	}
+	combo.SetText("a")
+	combo.ShowSuggestions()
+	combo.SetText("ab")
+	combo.ShowSuggestions()
+	c.Equal(2, len(pending))
+	c.Equal(0, combo.list.Count(), "suggestions arrive later")
+
+	pending[1]()
+	pending[0]()
+	drainTasks()
+	c.Equal([]string{"ab1", "ab2"}, combo.Suggestions(), "suggestions for stale text are discarded")
+	combo.SetText("ab2")
+	c.False(combo.Invalid(), "the current suggestions are acceptable matches")
+
+	combo.ShowSuggestions()
+	combo.HideSuggestions()
+	pending[2]()
+	drainTasks()
+	c.Equal([]string{"ab1", "ab2"}, combo.Suggestions(), "suggestions arriving after closing are discarded")
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+// Composing returns true if an input method is currently composing text for this window.
+func (w *Window) Composing() bool {
+	return w.composing
+}
+
+// compositionUpdated is called by the platform when the input method's in-progress text changes. Empty text ends the
+// composition without producing anything.
+func (w *Window) compositionUpdated(text string, caret int) {
+	if !w.okToProcess() {
+		// See the comment in keyPressed.
+		modalStack[len(modalStack)-1].compositionUpdated(text, caret)
+		return
+	}
+	if text == "" {
+		if w.composing {
+			w.endComposition("")
+		}
+		return
+	}
+	if !w.composing {
+		w.composing = true
+		w.compositionPanel = nil
+		w.ClearTooltip()
+		for panel := w.Focus(); panel != nil; panel = panel.parent {
+			if panel.Enabled() && panel.CompositionStartCallback != nil {
+				accepted := false
+				SafeCall(func() { accepted = panel.CompositionStartCallback() })
+				if accepted {
+					w.compositionPanel = panel
+					break
+				}
+			}
+		}
+	}
+	if panel := w.compositionPanel; panel != nil && panel.CompositionUpdateCallback != nil {
+		caret = max(min(caret, utf8.RuneCountInString(text)), 0)
+		SafeCall(func() { panel.CompositionUpdateCallback(text, caret) })
+	}
+}
+
+// compositionCommitted is called by the platform when the input method has produced text. This may happen without a
+// preceding call to compositionUpdated(), as input methods frequently commit text directly.
+func (w *Window) compositionCommitted(text string) {
+	if !w.okToProcess() {
+		// See the comment in keyPressed.
+		modalStack[len(modalStack)-1].compositionCommitted(text)
+		return
+	}
+	w.endComposition(text)
+}
+
+func (w *Window) endComposition(text string) {
+	panel := w.compositionPanel
+	w.composing = false
+	w.compositionPanel = nil
+	consumed := false
+	if panel != nil && panel.CompositionCommitCallback != nil {
+		SafeCall(func() { consumed = panel.CompositionCommitCallback(text) })
+	}
+	if !consumed {
+		for _, ch := range text {
+			w.runeTyped(ch)
+		}
+	}
+}
+
+// cancelComposition abandons the composition in progress, if any, and tells the input method to do the same. This is
+// done whenever the keyboard focus moves, since the in-progress text belongs to the panel that had the focus.
+func (w *Window) cancelComposition() {
+	if w.composing {
+		w.endComposition("")
+		if w.headless == nil {
+			w.apiCancelComposition()
+		}
+	}
+}
+
+// acceptsComposition returns true if the focused panel, or one of its ancestors, is able to take part in composition.
+func (w *Window) acceptsComposition() bool {
+	for panel := w.Focus(); panel != nil; panel = panel.parent {
+		if panel.Enabled() && panel.CompositionStartCallback != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// compositionCaretRect returns the caret location reported by the focused panel, in root coordinates, or an empty rect
+// if no panel reports one.
+func (w *Window) compositionCaretRect() geom.Rect {
+	for panel := w.Focus(); panel != nil; panel = panel.parent {
+		if panel.CompositionCaretRectCallback != nil {
+			var r geom.Rect
+			SafeCall(func() { r = panel.CompositionCaretRectCallback() })
+			if !r.Empty() {
+				return panel.RectToRoot(r)
+			}
+		}
+	}
+	return geom.Rect{}
+}
+
+// updateCompositionCaretRect passes the caret location on to the input method if it has changed. Since the caret can
+// only move visibly as the result of a redraw, this is called after each one.
+func (w *Window) updateCompositionCaretRect() {
+	if w.headless != nil || !w.focused {
+		return
+	}
+	if r := w.compositionCaretRect(); r != w.lastCompositionCaretRect {
+		w.lastCompositionCaretRect = r
+		if !r.Empty() {
+			w.apiSetCompositionCaretRect(r)
+		}
+	}
+}
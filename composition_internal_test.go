@@ -0,0 +1,189 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+type compositionRecorder struct {
+	events []string
+	typed  []rune
+}
+
+func newCompositionTestPanel(rec *compositionRecorder, accept, consume bool) *Panel {
+	p := NewPanel()
+	p.SetFocusable(true)
+	p.SetFrameRect(geom.NewRect(0, 0, 50, 20))
+	p.CompositionStartCallback = func() bool {
+		rec.events = append(rec.events, "start")
+		return accept
+	}
+	p.CompositionUpdateCallback = func(text string, caret int) {
+		rec.events = append(rec.events, "update:"+text+":"+string(rune('0'+caret)))
+	}
+	p.CompositionCommitCallback = func(text string) bool {
+		rec.events = append(rec.events, "commit:"+text)
+		return consume
+	}
+	p.RuneTypedCallback = func(ch rune) bool {
+		rec.typed = append(rec.typed, ch)
+		return true
+	}
+	return p
+}
+
+// TestCompositionRouting verifies that a composition is offered to the focused panel, that updates and the commit go to
+// the panel that accepted it, and that unconsumed text is then typed.
+func TestCompositionRouting(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	var rec compositionRecorder
+	p := newCompositionTestPanel(&rec, true, false)
+	w := newHeadlessTestWindow(t, p)
+	p.RequestFocus()
+
+	c.False(w.Composing())
+	w.SimulateComposition("´", 1)
+	c.True(w.Composing())
+	w.SimulateComposition("´", 5) // The caret is clamped to the text.
+	w.SimulateCompositionCommit("é")
+	c.False(w.Composing())
+	c.Equal([]string{"start", "update:´:1", "update:´:1", "commit:é"}, rec.events)
+	c.Equal([]rune{'é'}, rec.typed)
+
+	// Text committed without a preceding update ends no composition, but is still typed.
+	rec = compositionRecorder{}
+	w.SimulateCompositionCommit("あい")
+	c.Equal(0, len(rec.events))
+	c.Equal([]rune{'あ', 'い'}, rec.typed)
+}
+
+// TestCompositionConsumedAndDeclined verifies that a panel may consume the committed text, and that a composition no
+// panel accepts still produces typed text.
+func TestCompositionConsumedAndDeclined(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+
+	var rec compositionRecorder
+	p := newCompositionTestPanel(&rec, true, true)
+	w := newHeadlessTestWindow(t, p)
+	p.RequestFocus()
+	w.SimulateComposition("n", 1)
+	w.SimulateCompositionCommit("ñ")
+	c.Equal([]string{"start", "update:n:1", "commit:ñ"}, rec.events)
+	c.Equal(0, len(rec.typed))
+
+	rec = compositionRecorder{}
+	p.CompositionStartCallback = func() bool { return false }
+	w.SimulateComposition("n", 1)
+	w.SimulateCompositionCommit("ñ")
+	c.Equal(0, len(rec.events), "a panel that declined the composition must not be told about it")
+	c.Equal([]rune{'ñ'}, rec.typed)
+}
+
+// TestCompositionCancelledOnFocusChange verifies that moving the focus abandons the composition in progress, so that
+// its text cannot land in a different panel.
+func TestCompositionCancelledOnFocusChange(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	var rec1, rec2 compositionRecorder
+	content := NewPanel()
+	p1 := newCompositionTestPanel(&rec1, true, false)
+	p2 := newCompositionTestPanel(&rec2, true, false)
+	p2.SetFrameRect(geom.NewRect(0, 30, 50, 20))
+	content.AddChild(p1)
+	content.AddChild(p2)
+	w := newHeadlessTestWindow(t, content)
+	p1.RequestFocus()
+	w.SimulateComposition("ㅎ", 1)
+	p2.RequestFocus()
+	c.False(w.Composing())
+	c.Equal([]string{"start", "update:ㅎ:1", "commit:"}, rec1.events)
+	c.Equal(0, len(rec1.typed))
+	c.Equal(0, len(rec2.events))
+
+	// Empty text also abandons a composition.
+	w.SimulateComposition("ㅎ", 1)
+	w.SimulateComposition("", 0)
+	c.False(w.Composing())
+	c.Equal([]string{"start", "update:ㅎ:1", "commit:"}, rec2.events)
+	c.Equal(0, len(rec2.typed))
+}
+
+// TestCompositionCaretRect verifies that the caret location is reported in root coordinates, and that a panel without
+// one defers to its parents.
+func TestCompositionCaretRect(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	content.CompositionCaretRectCallback = func() geom.Rect { return geom.NewRect(1, 2, 1, 10) }
+	child := NewPanel()
+	child.SetFocusable(true)
+	child.SetFrameRect(geom.NewRect(20, 30, 40, 20))
+	child.CompositionCaretRectCallback = func() geom.Rect { return geom.NewRect(5, 3, 1, 12) }
+	content.AddChild(child)
+	w := newHeadlessTestWindow(t, content)
+	child.RequestFocus()
+	c.Equal(geom.NewRect(25, 33, 1, 12), w.compositionCaretRect())
+	child.CompositionCaretRectCallback = func() geom.Rect { return geom.Rect{} }
+	c.Equal(geom.NewRect(1, 2, 1, 10), w.compositionCaretRect())
+}
+
+// TestFieldComposition verifies that a field shows the composition without modifying its content, and that the
+// committed text then replaces the selection as typed text does.
+func TestFieldComposition(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	f := NewField()
+	f.SetFrameRect(geom.NewRect(0, 0, 200, 30))
+	w := newHeadlessTestWindow(t, f)
+	f.RequestFocus()
+	f.SetText("ab cd")
+	f.SetSelection(2, 3)
+
+	w.SimulateComposition("にほ", 1)
+	c.Equal("ab cd", f.Text())
+	c.Equal([]rune("にほ"), f.preedit)
+	restore := f.spliceInPreedit()
+	c.Equal("abにほcd", string(f.runes))
+	start, end := f.Selection()
+	c.Equal(3, start)
+	c.Equal(3, end)
+	restore()
+	c.Equal("ab cd", f.Text())
+	start, end = f.Selection()
+	c.Equal(2, start)
+	c.Equal(3, end)
+
+	// Locating the caret lays out the composition, but must not leave those lines behind for the field to use.
+	f.prepareLinesForCurrentWidth()
+	lines := f.lines
+	c.False(f.DefaultCompositionCaretRect().Empty())
+	c.True(len(f.lines) == len(lines) && f.lines[0] == lines[0], "the field's lines were replaced")
+	c.Equal("ab cd", f.lines[0].String())
+
+	w.SimulateCompositionCommit("日本")
+	c.Equal(0, len(f.preedit))
+	c.Equal("ab日本cd", f.Text())
+	start, end = f.Selection()
+	c.Equal(4, start)
+	c.Equal(4, end)
+
+	// Obscured fields do not show the composition, but still receive its text.
+	f.ObscurementRune = '*'
+	w.SimulateComposition("x", 1)
+	c.Equal(0, len(f.preedit))
+	w.SimulateCompositionCommit("y")
+	c.Equal("ab日本ycd", f.Text())
+}
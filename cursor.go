@@ -289,7 +289,7 @@ func cursorInkReplacements(s cursorSettings) map[Color]Ink {
 }
 
 func newThemedCursor(svg *SVG, relativeHotSpot geom.Point, s cursorSettings) *Cursor {
-	return apiNewCursor(&cursorSource{
+	return newPlatformCursor(&cursorSource{
 		svg:     svg,
 		inks:    cursorInkReplacements(s),
 		size:    s.size,
@@ -312,7 +312,7 @@ func NewThemedCursorFromSVG(svg *SVG, relativeHotSpot geom.Point) *Cursor {
 func NewCursorFromSVG(svg *SVG, hotSpot geom.Point, size geom.Size) *Cursor {
 	hotSpot.X = min(max(hotSpot.X, 0), size.Width-1)
 	hotSpot.Y = min(max(hotSpot.Y, 0), size.Height-1)
-	return apiNewCursor(&cursorSource{
+	return newPlatformCursor(&cursorSource{
 		svg:     svg,
 		size:    size,
 		hotSpot: hotSpot,
@@ -329,13 +329,22 @@ func NewCursor(img *Image, hotSpot geom.Point) *Cursor {
 		errs.Log(err)
 		return nil
 	}
-	return apiNewCursor(&cursorSource{
+	return newPlatformCursor(&cursorSource{
 		img:     nrgba,
 		size:    logicalSize,
 		hotSpot: hotSpot,
 	})
 }
 
+// newPlatformCursor creates the cursor with the active backend. The headless backend has nothing to show a cursor on, so
+// its cursors are placeholders that carry no native resources.
+func newPlatformCursor(src *cursorSource) *Cursor {
+	if headlessActive.Load() {
+		return headlessNewCursor()
+	}
+	return apiNewCursor(src)
+}
+
 // Destroy releases the resources associated with the cursor.
 func (c *Cursor) Destroy() {
 	if c == nil {
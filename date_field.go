@@ -0,0 +1,237 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// DateField holds a date that can be edited. The Up and Down keys step the day, month or year under the caret, while
+// Alt/Option+Down shows a CalendarPanel below the field from which a date can be picked.
+type DateField struct {
+	*Field
+	calendar *CalendarPanel
+	layout   string
+	value    time.Time
+	minimum  time.Time
+	maximum  time.Time
+	open     bool
+}
+
+// NewDateField creates a new field that holds a date, presented using DefaultDateTimeFormat.
+func NewDateField(date time.Time) *DateField {
+	f := &DateField{
+		Field:    NewField(),
+		calendar: NewCalendarPanel(date),
+		layout:   DefaultDateTimeFormat.DateLayout,
+		value:    startOfDay(date),
+	}
+	f.Self = f
+	UninstallFocusBorders(f, f)
+	f.LostFocusCallback = f.DefaultFocusLost
+	InstallDefaultFieldBorder(f, f)
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.ValidateCallback = f.DefaultValidate
+	f.calendar.SetFocusable(false)
+	f.calendar.SetBorder(NewCompoundBorder(
+		NewLineBorder(ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false),
+		f.calendar.Border(),
+	))
+	f.calendar.DateChosenCallback = f.calendarDateChosen
+	f.SetText(f.value.Format(f.layout))
+	f.adjustMinimumTextWidth()
+	return f
+}
+
+// Calendar returns the CalendarPanel shown below the field. It may be used to adjust its theme.
+func (f *DateField) Calendar() *CalendarPanel {
+	return f.calendar
+}
+
+// Layout returns the layout used to present the date, in the form accepted by time.Format.
+func (f *DateField) Layout() string {
+	return f.layout
+}
+
+// SetLayout sets the layout used to present the date, in the form accepted by time.Format. See
+// DateTimeFormat.DateLayout for the elements that are supported.
+func (f *DateField) SetLayout(layout string) {
+	if f.layout != layout {
+		value := f.Value()
+		f.layout = layout
+		f.SetText(value.Format(layout))
+		f.adjustMinimumTextWidth()
+	}
+}
+
+// Value returns the date in the field, limited to the allowed range. If the text in the field is not a valid date, the
+// most recent valid one is returned.
+func (f *DateField) Value() time.Time {
+	if date, err := f.parse(); err == nil {
+		return f.constrain(date)
+	}
+	return f.value
+}
+
+// SetValue sets the date in the field.
+func (f *DateField) SetValue(date time.Time) {
+	f.value = f.constrain(startOfDay(date))
+	if text := f.value.Format(f.layout); text != f.Text() {
+		f.SetText(text)
+	}
+}
+
+// Min returns the earliest date allowed. A zero value means there is no limit.
+func (f *DateField) Min() time.Time {
+	return f.minimum
+}
+
+// Max returns the latest date allowed. A zero value means there is no limit.
+func (f *DateField) Max() time.Time {
+	return f.maximum
+}
+
+// SetMinMax sets the earliest and latest dates allowed. A zero value means there is no limit.
+func (f *DateField) SetMinMax(minimum, maximum time.Time) {
+	if !minimum.IsZero() {
+		minimum = startOfDay(minimum)
+	}
+	if !maximum.IsZero() {
+		maximum = startOfDay(maximum)
+	}
+	f.minimum = minimum
+	f.maximum = maximum
+	f.calendar.SetMinMax(minimum, maximum)
+	f.Validate()
+}
+
+// CalendarShown returns true if the CalendarPanel is being shown.
+func (f *DateField) CalendarShown() bool {
+	return f.open
+}
+
+// ShowCalendar shows the CalendarPanel below the field, with the date in the field selected.
+func (f *DateField) ShowCalendar() {
+	f.open = true
+	f.calendar.SetDate(f.Value())
+	if wnd := f.Window(); wnd != nil {
+		panel := f.calendar.AsPanel()
+		_, pref, _ := panel.Sizes(geom.Size{})
+		wnd.root.setDropDown(panel, f.HideCalendar)
+		panel.SetFrameRect(wnd.root.dropDownRect(f.AsPanel(), pref))
+	}
+}
+
+// HideCalendar hides the CalendarPanel.
+func (f *DateField) HideCalendar() {
+	f.open = false
+	if wnd := f.Window(); wnd != nil && wnd.root.dropDownPanel == f.calendar.AsPanel() {
+		wnd.root.setDropDown(nil, nil)
+	}
+}
+
+func (f *DateField) calendarDateChosen(date time.Time) {
+	f.SetValue(date)
+	f.SelectAll()
+	f.HideCalendar()
+}
+
+func (f *DateField) parse() (time.Time, error) {
+	return time.ParseInLocation(f.layout, strings.TrimSpace(f.Text()), time.Local)
+}
+
+func (f *DateField) constrain(date time.Time) time.Time {
+	if !f.minimum.IsZero() && date.Before(f.minimum) {
+		return f.minimum
+	}
+	if !f.maximum.IsZero() && date.After(f.maximum) {
+		return f.maximum
+	}
+	return date
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (f *DateField) DefaultFocusLost() {
+	f.HideCalendar()
+	f.SetValue(f.Value())
+	f.Field.DefaultFocusLost()
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *DateField) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if f.open {
+		switch keyCode {
+		case KeyEscape:
+			f.HideCalendar()
+			return true
+		case KeyTab:
+			f.HideCalendar()
+		case KeyLeft, KeyRight, KeyUp, KeyDown, KeyPageUp, KeyPageDown, KeyReturn, KeyNumPadEnter:
+			if f.calendar.DefaultKeyDown(keyCode, mods, repeat) {
+				return true
+			}
+		}
+	} else if keyCode == KeyDown || keyCode == KeyUp {
+		switch mods & mod.NonSticky {
+		case mod.Option:
+			if keyCode == KeyDown {
+				f.ShowCalendar()
+				return true
+			}
+		case 0:
+			delta := 1
+			if keyCode == KeyDown {
+				delta = -1
+			}
+			if stepDateTimeField(f.Field, f.layout, f.Value(), delta, f.constrain) {
+				return true
+			}
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (f *DateField) DefaultValidate() bool {
+	if text := f.tooltipTextForValidation(); text != "" {
+		f.Tooltip = NewTooltipWithText(text)
+		return false
+	}
+	f.Tooltip = nil
+	return true
+}
+
+func (f *DateField) tooltipTextForValidation() string {
+	date, err := f.parse()
+	if err != nil {
+		example := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.Local).Format(f.layout)
+		return fmt.Sprintf(i18n.Text("Invalid date; expected a date such as %s"), example)
+	}
+	// Remember the most recent valid date, so that it can be restored should the text become invalid
+	f.value = f.constrain(date)
+	if !f.minimum.IsZero() && date.Before(f.minimum) {
+		return fmt.Sprintf(i18n.Text("Date must be no earlier than %s"), f.minimum.Format(f.layout))
+	}
+	if !f.maximum.IsZero() && date.After(f.maximum) {
+		return fmt.Sprintf(i18n.Text("Date must be no later than %s"), f.maximum.Format(f.layout))
+	}
+	return ""
+}
+
+func (f *DateField) adjustMinimumTextWidth() {
+	// A date in late September (the longest English month name) on a Wednesday (the longest English weekday name)
+	f.SetMinimumTextWidthUsing(time.Date(2000, 9, 27, 0, 0, 0, 0, time.Local).Format(f.layout))
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+func TestDateFieldStepping(t *testing.T) {
+	c := check.New(t)
+	f := NewDateField(time.Date(2026, time.January, 31, 10, 0, 0, 0, time.Local))
+	f.SetLayout("01/02/2006")
+	c.Equal("01/31/2026", f.Text())
+	c.Equal(calendarDate(2026, time.January, 31), f.Value())
+
+	f.SetSelectionTo(0)
+	c.True(f.DefaultKeyDown(KeyUp, 0, false))
+	c.Equal("02/28/2026", f.Text(), "Up steps the month under the caret")
+	start, end := f.Selection()
+	c.Equal(0, start)
+	c.Equal(2, end)
+	f.SetSelectionTo(4)
+	c.True(f.DefaultKeyDown(KeyDown, 0, false))
+	c.Equal("02/27/2026", f.Text(), "Down steps the day under the caret")
+	start, end = f.Selection()
+	c.Equal(3, start)
+	c.Equal(5, end)
+	f.SetSelectionToEnd()
+	c.True(f.DefaultKeyDown(KeyUp, 0, false))
+	c.Equal("02/27/2027", f.Text())
+
+	f.SetText("13/45/2026")
+	c.True(f.Invalid())
+	c.NotNil(f.Tooltip)
+	c.Equal(calendarDate(2027, time.February, 27), f.Value(), "the most recent valid date is used")
+	f.DefaultFocusLost()
+	c.Equal("02/27/2027", f.Text(), "invalid text is replaced when focus is lost")
+	c.False(f.Invalid())
+
+	f.SetMinMax(calendarDate(2026, time.January, 1), calendarDate(2026, time.December, 31))
+	c.True(f.Invalid())
+	c.NotNil(f.Tooltip)
+	c.Equal(calendarDate(2026, time.December, 31), f.Value())
+	f.SetSelectionTo(0)
+	c.True(f.DefaultKeyDown(KeyUp, 0, false))
+	c.Equal("12/31/2026", f.Text(), "stepping is limited to the allowed range")
+	c.False(f.Invalid())
+	c.Nil(f.Tooltip)
+}
+
+func TestDateFieldCalendar(t *testing.T) {
+	c := check.New(t)
+	f := NewDateField(calendarDate(2026, time.March, 10))
+	f.SetLayout("2006-01-02")
+	c.False(f.CalendarShown())
+	c.True(f.DefaultKeyDown(KeyDown, mod.Option, false))
+	c.True(f.CalendarShown())
+	c.Equal(calendarDate(2026, time.March, 10), f.Calendar().Date())
+
+	c.True(f.DefaultKeyDown(KeyRight, 0, false), "the arrow keys move within the calendar while it is shown")
+	c.True(f.DefaultKeyDown(KeyDown, 0, false))
+	c.Equal(calendarDate(2026, time.March, 18), f.Calendar().Date())
+	c.Equal("2026-03-10", f.Text(), "the field is unchanged until a date is chosen")
+	c.True(f.DefaultKeyDown(KeyReturn, 0, false))
+	c.False(f.CalendarShown())
+	c.Equal("2026-03-18", f.Text())
+
+	f.ShowCalendar()
+	c.True(f.DefaultKeyDown(KeyPageDown, 0, false))
+	c.True(f.DefaultKeyDown(KeyEscape, 0, false))
+	c.False(f.CalendarShown())
+	c.Equal("2026-03-18", f.Text(), "Escape closes the calendar without choosing a date")
+	c.False(f.DefaultKeyDown(KeyEscape, 0, false))
+}
+
+func TestTimeField(t *testing.T) {
+	c := check.New(t)
+	f := NewTimeField(time.Date(2026, time.May, 4, 23, 30, 15, 0, time.Local))
+	f.SetLayout("3:04 PM")
+	c.Equal("11:30 PM", f.Text())
+	c.Equal(time.Date(0, time.January, 1, 23, 30, 0, 0, time.UTC), f.Value())
+
+	f.SetSelectionTo(0)
+	c.True(f.DefaultKeyDown(KeyUp, 0, false))
+	c.Equal("12:30 AM", f.Text(), "stepping wraps around at midnight")
+	f.SetSelectionToEnd()
+	c.True(f.DefaultKeyDown(KeyDown, 0, false))
+	c.Equal("12:30 PM", f.Text())
+	start, end := f.Selection()
+	c.Equal(6, start)
+	c.Equal(8, end)
+
+	f.SetLayout("15:04:05")
+	c.Equal("12:30:00", f.Text())
+	f.SetText("25:00:00")
+	c.True(f.Invalid())
+	c.NotNil(f.Tooltip)
+	c.Equal(time.Date(0, time.January, 1, 12, 30, 0, 0, time.UTC), f.Value())
+	f.SetText("07:45:30")
+	c.False(f.Invalid())
+	f.SetValue(time.Date(2000, time.June, 1, 6, 5, 4, 0, time.Local))
+	c.Equal("06:05:04", f.Text())
+}
+
+func TestDateRangeField(t *testing.T) {
+	c := check.New(t)
+	f := NewDateRangeField(DateRange{
+		Start: calendarDate(2026, time.April, 1),
+		End:   calendarDate(2026, time.April, 10),
+	})
+	f.Start().SetLayout("2006-01-02")
+	f.End().SetLayout("2006-01-02")
+	var changes int
+	f.RangeChangedCallback = func() { changes++ }
+	start, end := f.End().Calendar().HighlightedRange()
+	c.Equal(calendarDate(2026, time.April, 1), start)
+	c.Equal(calendarDate(2026, time.April, 10), end)
+
+	f.Start().SetText("2026-04-15")
+	c.Equal(1, changes)
+	c.True(f.End().Invalid(), "the end may not be before the start")
+	c.NotNil(f.End().Tooltip)
+	start, _ = f.Start().Calendar().HighlightedRange()
+	c.True(start.IsZero(), "an invalid range isn't highlighted")
+
+	f.End().SetText("2026-04-20")
+	c.Equal(2, changes)
+	c.False(f.End().Invalid())
+	c.Equal(DateRange{Start: calendarDate(2026, time.April, 15), End: calendarDate(2026, time.April, 20)}, f.Value())
+	start, end = f.Start().Calendar().HighlightedRange()
+	c.Equal(calendarDate(2026, time.April, 15), start)
+	c.Equal(calendarDate(2026, time.April, 20), end)
+
+	editor := NewTableDateRangeFieldCellEditor(f)
+	editor.RuneTyped('x')
+	c.Equal("x", f.Start().Text(), "typing to begin editing replaces the start date")
+	c.Equal(f.Value(), editor.Value())
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+// DateRange holds a range of dates, inclusive of both ends.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// DateRangeField holds a range of dates that can be edited, using a pair of DateFields. The end date is marked as
+// invalid if it falls before the start date, and the calendar of each DateField highlights the range.
+type DateRangeField struct {
+	// RangeChangedCallback is called whenever the text of either DateField is modified.
+	RangeChangedCallback func()
+	start                *DateField
+	end                  *DateField
+	Panel
+}
+
+// NewDateRangeField creates a new DateRangeField holding the range.
+func NewDateRangeField(dateRange DateRange) *DateRangeField {
+	f := &DateRangeField{
+		start: NewDateField(dateRange.Start),
+		end:   NewDateField(dateRange.End),
+	}
+	f.Self = f
+	f.SetLayout(&FlexLayout{
+		Columns:  3,
+		HSpacing: StdHSpacing,
+	})
+	separator := NewLabel()
+	separator.SetTitle("–")
+	separator.SetLayoutData(&FlexLayoutData{VAlign: align.Middle})
+	f.AddChild(f.start)
+	f.AddChild(separator)
+	f.AddChild(f.end)
+	f.start.ModifiedCallback = f.modified
+	f.end.ModifiedCallback = f.modified
+	f.end.ValidateCallback = f.validateEnd
+	f.end.Validate()
+	f.syncHighlightedRange()
+	return f
+}
+
+// Start returns the DateField holding the start of the range.
+func (f *DateRangeField) Start() *DateField {
+	return f.start
+}
+
+// End returns the DateField holding the end of the range.
+func (f *DateRangeField) End() *DateField {
+	return f.end
+}
+
+// Value returns the range of dates.
+func (f *DateRangeField) Value() DateRange {
+	return DateRange{
+		Start: f.start.Value(),
+		End:   f.end.Value(),
+	}
+}
+
+// SetValue sets the range of dates.
+func (f *DateRangeField) SetValue(dateRange DateRange) {
+	f.start.SetValue(dateRange.Start)
+	f.end.SetValue(dateRange.End)
+}
+
+func (f *DateRangeField) modified(_, _ *FieldState) {
+	f.end.Validate()
+	f.syncHighlightedRange()
+	if f.RangeChangedCallback != nil {
+		f.RangeChangedCallback()
+	}
+}
+
+func (f *DateRangeField) validateEnd() bool {
+	if !f.end.DefaultValidate() {
+		return false
+	}
+	if f.end.Value().Before(f.start.Value()) {
+		f.end.Tooltip = NewTooltipWithText(i18n.Text("The end date must not be before the start date"))
+		return false
+	}
+	return true
+}
+
+func (f *DateRangeField) syncHighlightedRange() {
+	var start, end time.Time
+	if r := f.Value(); !r.End.Before(r.Start) {
+		start = r.Start
+		end = r.End
+	}
+	f.start.Calendar().SetHighlightedRange(start, end)
+	f.end.Calendar().SetHighlightedRange(start, end)
+}
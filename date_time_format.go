@@ -0,0 +1,309 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// DefaultDateTimeFormat holds the DateTimeFormat used by DateFields, TimeFields and CalendarPanels. It is initialized
+// from the locale found in the LC_ALL, LC_TIME or LANG environment variables. Modifying this data will not alter
+// existing widgets, but will alter any created in the future.
+var DefaultDateTimeFormat = DateTimeFormatForLocale(systemLocale())
+
+// DateTimeFormat holds the conventions used to present dates and times.
+type DateTimeFormat struct {
+	// DateLayout is the layout used for dates, in the form accepted by time.Format, e.g. "01/02/2006". The year, month,
+	// day and weekday elements are supported, along with literal separators between them.
+	DateLayout string
+	// TimeLayout is the layout used for times, in the form accepted by time.Format, e.g. "3:04 PM". The hour, minute,
+	// second and AM/PM elements are supported, along with literal separators between them.
+	TimeLayout string
+	// FirstWeekday is the day each week starts with.
+	FirstWeekday time.Weekday
+}
+
+// DateTimeFormatForLocale returns the DateTimeFormat for the locale, which is expected to be in a form such as "en_US"
+// or "de-DE.UTF-8". An empty or unrecognized locale results in ISO 8601 dates and 24-hour times.
+func DateTimeFormatForLocale(locale string) DateTimeFormat {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	lang, region, _ := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	lang = strings.ToLower(lang)
+	region = strings.ToUpper(region)
+	if lang == "en" && region == "" {
+		region = "US"
+	}
+	f := DateTimeFormat{
+		DateLayout:   "2006-01-02",
+		TimeLayout:   "15:04",
+		FirstWeekday: time.Monday,
+	}
+	switch lang {
+	case "", "c", "posix":
+		return f
+	case "zh", "ja", "ko":
+		f.DateLayout = "2006/01/02"
+	case "sv", "lt", "hu":
+		// These use ISO 8601 dates
+	case "de", "ru", "pl", "cs", "sk", "fi", "nb", "nn", "no", "da", "tr", "uk", "ro", "hr", "sl", "et", "lv":
+		f.DateLayout = "02.01.2006"
+	case "nl":
+		f.DateLayout = "02-01-2006"
+	default:
+		f.DateLayout = "02/01/2006"
+	}
+	switch region {
+	case "US", "PH":
+		f.DateLayout = "01/02/2006"
+	case "CA":
+		f.DateLayout = "2006-01-02"
+	}
+	switch region {
+	case "US", "CA", "AU", "NZ", "IN", "PH":
+		f.TimeLayout = "3:04 PM"
+	}
+	switch region {
+	case "US", "CA", "JP", "BR", "IL", "MX", "PH", "IN":
+		f.FirstWeekday = time.Sunday
+	}
+	return f
+}
+
+func systemLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// monthName returns the localized name of the month.
+func monthName(month time.Month) string {
+	return []string{
+		i18n.Text("January"),
+		i18n.Text("February"),
+		i18n.Text("March"),
+		i18n.Text("April"),
+		i18n.Text("May"),
+		i18n.Text("June"),
+		i18n.Text("July"),
+		i18n.Text("August"),
+		i18n.Text("September"),
+		i18n.Text("October"),
+		i18n.Text("November"),
+		i18n.Text("December"),
+	}[(month+11)%12]
+}
+
+// shortWeekdayName returns the localized abbreviation of the weekday, as used in the column headers of a calendar.
+func shortWeekdayName(day time.Weekday) string {
+	return []string{
+		i18n.Text("Su"),
+		i18n.Text("Mo"),
+		i18n.Text("Tu"),
+		i18n.Text("We"),
+		i18n.Text("Th"),
+		i18n.Text("Fr"),
+		i18n.Text("Sa"),
+	}[(day+7)%7]
+}
+
+type dateTimeSegmentKind byte
+
+const (
+	literalSegment dateTimeSegmentKind = iota
+	yearSegment
+	monthSegment
+	daySegment
+	weekdaySegment
+	hourSegment
+	minuteSegment
+	secondSegment
+	amPMSegment
+)
+
+type dateTimeLayoutPart struct {
+	literal string
+	kind    dateTimeSegmentKind
+	textual bool
+}
+
+// dateTimeLayoutElements holds the layout elements that are recognized, ordered such that longer elements are checked
+// before any shorter ones they start with.
+var dateTimeLayoutElements = []dateTimeLayoutPart{
+	{literal: "January", kind: monthSegment, textual: true},
+	{literal: "Jan", kind: monthSegment, textual: true},
+	{literal: "Monday", kind: weekdaySegment, textual: true},
+	{literal: "Mon", kind: weekdaySegment, textual: true},
+	{literal: "2006", kind: yearSegment},
+	{literal: "01", kind: monthSegment},
+	{literal: "02", kind: daySegment},
+	{literal: "_2", kind: daySegment},
+	{literal: "15", kind: hourSegment},
+	{literal: "03", kind: hourSegment},
+	{literal: "04", kind: minuteSegment},
+	{literal: "05", kind: secondSegment},
+	{literal: "06", kind: yearSegment},
+	{literal: "PM", kind: amPMSegment, textual: true},
+	{literal: "pm", kind: amPMSegment, textual: true},
+	{literal: "1", kind: monthSegment},
+	{literal: "2", kind: daySegment},
+	{literal: "3", kind: hourSegment},
+	{literal: "4", kind: minuteSegment},
+	{literal: "5", kind: secondSegment},
+}
+
+func parseDateTimeLayout(layout string) []dateTimeLayoutPart {
+	var parts []dateTimeLayoutPart
+	for layout != "" {
+		found := false
+		for _, element := range dateTimeLayoutElements {
+			if strings.HasPrefix(layout, element.literal) {
+				parts = append(parts, dateTimeLayoutPart{kind: element.kind, textual: element.textual})
+				layout = layout[len(element.literal):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			_, size := utf8.DecodeRuneInString(layout)
+			if last := len(parts) - 1; last >= 0 && parts[last].kind == literalSegment {
+				parts[last].literal += layout[:size]
+			} else {
+				parts = append(parts, dateTimeLayoutPart{literal: layout[:size]})
+			}
+			layout = layout[size:]
+		}
+	}
+	return parts
+}
+
+// dateTimeSegment identifies the range of runes within text formatted with a layout that holds one of its elements.
+type dateTimeSegment struct {
+	kind  dateTimeSegmentKind
+	start int
+	end   int
+}
+
+// dateTimeSegments returns the segments of the text, or nil if the text does not follow the layout.
+func dateTimeSegments(layout, text string) []dateTimeSegment {
+	runes := []rune(text)
+	var segments []dateTimeSegment
+	pos := 0
+	for _, part := range parseDateTimeLayout(layout) {
+		if part.kind == literalSegment {
+			for _, r := range part.literal {
+				if pos >= len(runes) || runes[pos] != r {
+					return nil
+				}
+				pos++
+			}
+			continue
+		}
+		for pos < len(runes) && runes[pos] == ' ' {
+			pos++
+		}
+		start := pos
+		for pos < len(runes) && ((part.textual && unicode.IsLetter(runes[pos])) ||
+			(!part.textual && unicode.IsDigit(runes[pos]))) {
+			pos++
+		}
+		if start == pos {
+			return nil
+		}
+		segments = append(segments, dateTimeSegment{kind: part.kind, start: start, end: pos})
+	}
+	if pos != len(runes) {
+		return nil
+	}
+	return segments
+}
+
+// segmentIndexAt returns the index of the segment at or following the position, or the last segment if there are none
+// following it. Returns -1 if there are no segments.
+func segmentIndexAt(segments []dateTimeSegment, pos int) int {
+	for i, segment := range segments {
+		if pos <= segment.end {
+			return i
+		}
+	}
+	return len(segments) - 1
+}
+
+// stepDateTime adjusts the element of the value identified by the kind by delta units of that element.
+func stepDateTime(value time.Time, kind dateTimeSegmentKind, delta int) time.Time {
+	switch kind {
+	case yearSegment:
+		return addMonthsClamped(value, delta*12)
+	case monthSegment:
+		return addMonthsClamped(value, delta)
+	case daySegment, weekdaySegment:
+		return value.AddDate(0, 0, delta)
+	case hourSegment:
+		return value.Add(time.Duration(delta) * time.Hour)
+	case minuteSegment:
+		return value.Add(time.Duration(delta) * time.Minute)
+	case secondSegment:
+		return value.Add(time.Duration(delta) * time.Second)
+	case amPMSegment:
+		return value.Add(time.Duration(delta) * 12 * time.Hour)
+	default:
+		return value
+	}
+}
+
+// addMonthsClamped adds the months to the date, limiting the day to the last one of the resulting month rather than
+// overflowing into the month after it.
+func addMonthsClamped(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	hour, minute, second := date.Clock()
+	first := time.Date(year, month+time.Month(months), 1, hour, minute, second, date.Nanosecond(), date.Location())
+	return first.AddDate(0, 0, min(day, daysInMonth(first.Year(), first.Month()))-1)
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// startOfDay returns midnight at the start of the date's day, in the local time zone.
+func startOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// stepDateTimeField adjusts the element of the value identified by the segment of the field's text that holds the start
+// of the selection, then replaces the field's text with the adjusted value and selects that same segment. limit is
+// applied to the adjusted value before it is placed in the field. Returns false if the layout has no segments to
+// adjust.
+func stepDateTimeField(field *Field, layout string, value time.Time, delta int, limit func(time.Time) time.Time) bool {
+	segments := dateTimeSegments(layout, field.Text())
+	if segments == nil {
+		segments = dateTimeSegments(layout, value.Format(layout))
+	}
+	start, _ := field.Selection()
+	i := segmentIndexAt(segments, start)
+	if i == -1 {
+		return false
+	}
+	value = limit(stepDateTime(value, segments[i].kind, delta))
+	field.SetText(value.Format(layout))
+	if segments = dateTimeSegments(layout, field.Text()); i < len(segments) {
+		field.SetSelection(segments[i].start, segments[i].end)
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestDateTimeFormatForLocale(t *testing.T) {
+	c := check.New(t)
+	c.Equal(DateTimeFormat{DateLayout: "01/02/2006", TimeLayout: "3:04 PM", FirstWeekday: time.Sunday},
+		DateTimeFormatForLocale("en_US.UTF-8"))
+	c.Equal(DateTimeFormat{DateLayout: "02/01/2006", TimeLayout: "15:04", FirstWeekday: time.Monday},
+		DateTimeFormatForLocale("en_GB"))
+	c.Equal(DateTimeFormat{DateLayout: "02.01.2006", TimeLayout: "15:04", FirstWeekday: time.Monday},
+		DateTimeFormatForLocale("de-DE"))
+	c.Equal(DateTimeFormat{DateLayout: "2006/01/02", TimeLayout: "15:04", FirstWeekday: time.Sunday},
+		DateTimeFormatForLocale("ja_JP.eucJP"))
+	c.Equal(DateTimeFormat{DateLayout: "2006-01-02", TimeLayout: "3:04 PM", FirstWeekday: time.Sunday},
+		DateTimeFormatForLocale("fr_CA"))
+	iso := DateTimeFormat{DateLayout: "2006-01-02", TimeLayout: "15:04", FirstWeekday: time.Monday}
+	c.Equal(iso, DateTimeFormatForLocale(""))
+	c.Equal(iso, DateTimeFormatForLocale("C"))
+	c.Equal(iso, DateTimeFormatForLocale("sv_SE"))
+}
+
+func TestDateTimeSegments(t *testing.T) {
+	c := check.New(t)
+	c.Equal([]dateTimeSegment{
+		{kind: monthSegment, start: 0, end: 2},
+		{kind: daySegment, start: 3, end: 5},
+		{kind: yearSegment, start: 6, end: 10},
+	}, dateTimeSegments("01/02/2006", "03/15/2026"))
+	c.Equal([]dateTimeSegment{
+		{kind: hourSegment, start: 0, end: 2},
+		{kind: minuteSegment, start: 3, end: 5},
+		{kind: amPMSegment, start: 6, end: 8},
+	}, dateTimeSegments("3:04 PM", "11:30 AM"))
+	c.Equal([]dateTimeSegment{
+		{kind: monthSegment, start: 0, end: 3},
+		{kind: daySegment, start: 5, end: 6},
+		{kind: yearSegment, start: 8, end: 12},
+	}, dateTimeSegments("Jan _2, 2006", "Mar  5, 2026"))
+	c.Nil(dateTimeSegments("01/02/2006", "03/15"), "text that doesn't follow the layout has no segments")
+	c.Nil(dateTimeSegments("01/02/2006", "03-15-2026"))
+
+	segments := dateTimeSegments("01/02/2006", "03/15/2026")
+	c.Equal(0, segmentIndexAt(segments, 0))
+	c.Equal(0, segmentIndexAt(segments, 2))
+	c.Equal(1, segmentIndexAt(segments, 3))
+	c.Equal(2, segmentIndexAt(segments, 10))
+	c.Equal(-1, segmentIndexAt(nil, 0))
+}
+
+func TestStepDateTime(t *testing.T) {
+	c := check.New(t)
+	date := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.Local)
+	c.Equal(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local), stepDateTime(date, monthSegment, 1),
+		"the day is limited to the last one of the month")
+	c.Equal(time.Date(2023, time.December, 31, 0, 0, 0, 0, time.Local), stepDateTime(date, monthSegment, -1))
+	c.Equal(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.Local),
+		stepDateTime(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local), yearSegment, 1))
+	c.Equal(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.Local), stepDateTime(date, daySegment, 1),
+		"days roll over into the next month")
+	moment := time.Date(0, time.January, 1, 23, 59, 30, 0, time.UTC)
+	c.Equal(time.Date(0, time.January, 1, 0, 59, 30, 0, time.UTC), timeOfDay(stepDateTime(moment, hourSegment, 1)))
+	c.Equal(time.Date(0, time.January, 1, 11, 59, 30, 0, time.UTC), timeOfDay(stepDateTime(moment, amPMSegment, 1)))
+	c.Equal(time.Date(0, time.January, 1, 23, 58, 30, 0, time.UTC), stepDateTime(moment, minuteSegment, -1))
+}
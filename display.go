@@ -48,11 +48,17 @@ func displayPPI(pixels, sizeMM float64) int {
 // PrimaryDisplay returns the primary display. This is usually the display where elements like the Windows task bar or
 // the macOS menu bar is located. It may only be called on the UI thread.
 func PrimaryDisplay() *Display {
+	if headlessActive.Load() {
+		return headlessPrimaryDisplay()
+	}
 	return apiPrimaryDisplay()
 }
 
 // AllDisplays returns all currently active displays. It may only be called on the UI thread.
 func AllDisplays() []*Display {
+	if headlessActive.Load() {
+		return []*Display{headlessPrimaryDisplay()}
+	}
 	return apiAllDisplays()
 }
 
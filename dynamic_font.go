@@ -94,3 +94,7 @@ func (f *DynamicFont) TextBlobPosH(glyphs []uint16, positions []float32, y float
 func (f *DynamicFont) canvasFont() *font.Font {
 	return f.resolvedFont().canvasFont()
 }
+
+func (f *DynamicFont) emSize() float32 {
+	return f.resolvedFont().emSize()
+}
@@ -20,5 +20,5 @@ func SetDragTableDataForTest(data any) {
 // AddHitRectForTest injects a disclosure hit rect for the given row, allowing external tests to exercise the table's
 // mouse-down/mouse-up hit rect handling without the draw pass that normally builds the hit rects.
 func (t *Table[T]) AddHitRectForTest(rect geom.Rect, row T) {
-	t.hitRects = append(t.hitRects, t.newTableHitRect(rect, row))
+	t.hitRects = append(t.hitRects, t.newTableHitRect(rect, row, nil))
 }
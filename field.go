@@ -12,15 +12,16 @@ package unison
 import (
 	"math"
 	"slices"
-	"strings"
 	"time"
 	"unicode"
 
 	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
 	"github.com/richardwilkes/unison/enums/pathop"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 type lineEndingType byte
@@ -34,18 +35,21 @@ const (
 // DefaultFieldTheme holds the default FieldTheme values for Fields. Modifying this data will not alter existing Fields,
 // but will alter any Fields created in the future.
 var DefaultFieldTheme = FieldTheme{
-	Font:             FieldFont,
-	BackgroundInk:    ThemeSurface,
-	OnBackgroundInk:  ThemeOnSurface,
-	EditableInk:      ThemeDeepBelowSurface,
-	OnEditableInk:    ThemeOnDeepBelowSurface,
-	SelectionInk:     ThemeFocus,
-	OnSelectionInk:   ThemeOnFocus,
-	ErrorInk:         ThemeError,
-	OnErrorInk:       ThemeOnError,
-	BlinkRate:        560 * time.Millisecond,
-	MinimumTextWidth: 10,
-	HAlign:           align.Start,
+	Font:                  FieldFont,
+	BackgroundInk:         ThemeSurface,
+	OnBackgroundInk:       ThemeOnSurface,
+	EditableInk:           ThemeDeepBelowSurface,
+	OnEditableInk:         ThemeOnDeepBelowSurface,
+	SelectionInk:          ThemeFocus,
+	OnSelectionInk:        ThemeOnFocus,
+	ErrorInk:              ThemeError,
+	OnErrorInk:            ThemeOnError,
+	SearchMatchInk:        ThemeSearchMatch,
+	CurrentSearchMatchInk: ThemeFocus,
+	MisspelledInk:         ThemeError,
+	BlinkRate:             560 * time.Millisecond,
+	MinimumTextWidth:      10,
+	HAlign:                align.Start,
 }
 
 // FieldTheme holds theming data for a Field.
@@ -60,6 +64,9 @@ type FieldTheme struct {
 	OnSelectionInk         Ink
 	ErrorInk               Ink
 	OnErrorInk             Ink
+	SearchMatchInk         Ink
+	CurrentSearchMatchInk  Ink
+	MisspelledInk          Ink
 	BlinkRate              time.Duration
 	MinimumTextWidth       float32
 	HAlign                 align.Enum
@@ -69,15 +76,21 @@ type FieldTheme struct {
 type Field struct {
 	ModifiedCallback   func(before, after *FieldState)
 	ValidateCallback   func() bool
+	startSpellCheck    func(check func())
+	spellChecker       SpellChecker
 	runes              []rune
 	lines              []*Text
+	richText           *richTextStyles
 	endsWithLineFeed   []lineEndingType
+	searchMatches      [][2]int
+	misspelled         [][2]int
 	Watermark          string
 	linesBuiltWithFont FontDescriptor
 	forceShowUntil     time.Time
 	FieldTheme
 	Panel
 	undoID             int64
+	spellCheckGen      int
 	selectionStart     int
 	selectionEnd       int
 	selectionAnchor    int
@@ -85,6 +98,8 @@ type Field struct {
 	linesBuiltFor      float32
 	linesBuiltWithRune rune
 	ObscurementRune    rune
+	preedit            []rune
+	preeditCaret       int
 	AutoScroll         bool
 	NoSelectAllOnFocus bool
 	multiLine          bool
@@ -97,7 +112,9 @@ type Field struct {
 
 // FieldState holds the text and selection data for the field.
 type FieldState struct {
-	Text            string
+	Text string
+	// Styles holds the style of each rune of the text for a RichTextField. It is nil for other fields.
+	Styles          []RichTextStyle
 	SelectionStart  int
 	SelectionEnd    int
 	SelectionAnchor int
@@ -123,6 +140,11 @@ func NewField() *Field {
 	f.UpdateCursorCallback = f.DefaultUpdateCursor
 	f.KeyDownCallback = f.DefaultKeyDown
 	f.RuneTypedCallback = f.DefaultRuneTyped
+	f.CompositionStartCallback = f.DefaultCompositionStart
+	f.CompositionUpdateCallback = f.DefaultCompositionUpdate
+	f.CompositionCommitCallback = f.DefaultCompositionCommit
+	f.CompositionCaretRectCallback = f.DefaultCompositionCaretRect
+	f.AccessibilityCallback = f.DefaultAccessibility
 	f.InstallCmdHandlers(CutItemID, func(_ any) bool { return f.CanCut() }, func(_ any) { f.Cut() })
 	f.InstallCmdHandlers(CopyItemID, func(_ any) bool { return f.CanCopy() }, func(_ any) { f.Copy() })
 	f.InstallCmdHandlers(PasteItemID, func(_ any) bool { return f.CanPaste() }, func(_ any) { f.Paste() })
@@ -234,8 +256,13 @@ func (f *Field) buildLines(wrapWidth float32) (lines []*Text, endsWithLineFeed [
 		decoration := &TextDecoration{Font: f.Font}
 		if f.multiLine {
 			endsWithLineFeed = make([]lineEndingType, 0, 16)
-			for line := range strings.SplitSeq(string(f.runes), "\n") {
-				one := NewText(f.obscureStringIfNeeded(line), decoration)
+			for lineStart := 0; lineStart <= len(f.runes); {
+				lineEnd := len(f.runes)
+				if i := slices.Index(f.runes[lineStart:], '\n'); i != -1 {
+					lineEnd = lineStart + i
+				}
+				one := f.textForRunes(lineStart, lineEnd, decoration)
+				lineStart = lineEnd + 1
 				if f.wrap && wrapWidth > 0 {
 					parts := one.BreakToWidth(wrapWidth)
 					for i, part := range parts {
@@ -254,7 +281,7 @@ func (f *Field) buildLines(wrapWidth float32) (lines []*Text, endsWithLineFeed [
 				}
 			}
 		} else {
-			one := NewTextFromRunes(f.obscureIfNeeded(f.runes), decoration)
+			one := f.textForRunes(0, len(f.runes), decoration)
 			if f.wrap && wrapWidth > 0 {
 				lines = append(lines, one.BreakToWidth(wrapWidth)...)
 			} else {
@@ -266,16 +293,12 @@ func (f *Field) buildLines(wrapWidth float32) (lines []*Text, endsWithLineFeed [
 	return lines, endsWithLineFeed
 }
 
-func (f *Field) obscureStringIfNeeded(in string) string {
-	if f.ObscurementRune == 0 {
-		return in
-	}
-	r := []rune(in)
-	replacement := make([]rune, len(r))
-	for i := range r {
-		replacement[i] = f.ObscurementRune
+// textForRunes returns a Text for the runes in the range [start, end).
+func (f *Field) textForRunes(start, end int, decoration *TextDecoration) *Text {
+	if f.richText != nil {
+		return f.richText.text(f.runes, start, end, decoration)
 	}
-	return string(replacement)
+	return NewTextFromRunes(f.obscureIfNeeded(f.runes[start:end]), decoration)
 }
 
 func (f *Field) obscureIfNeeded(in []rune) []rune {
@@ -309,6 +332,11 @@ func (f *Field) DefaultDraw(canvas *Canvas, _ geom.Rect) {
 	canvas.DrawRect(rect, backgroundPaint)
 	rect = f.ContentRect(false)
 	canvas.ClipRect(rect, pathop.Intersect, false)
+	if len(f.preedit) != 0 {
+		// Deferred calls run in reverse order, so the underline is drawn before the field's state is restored.
+		defer f.spliceInPreedit()()
+		defer f.drawPreeditUnderline(canvas, fg)
+	}
 	f.prepareLines(rect.Width - 2)
 	ink := fg
 	if !enabled {
@@ -351,53 +379,71 @@ func (f *Field) DefaultDraw(canvas *Canvas, _ geom.Rect) {
 			if f.endsWithLineFeed[i] == hardLineEnding {
 				end++
 			}
+			var saved map[*TextDecoration]TextDecoration
+			if f.richText != nil {
+				// Styled runs may carry their own color, so the decorations are put back once the line is drawn.
+				saved = line.AdjustDecorations(func(*TextDecoration) {})
+			}
+			if len(f.searchMatches) != 0 {
+				f.drawSearchMatches(canvas, line, textLeft+f.scrollOffset.X, textTop, textHeight, start, end)
+			}
 			if enabled && focused && hasSelectionRange && f.selectionStart < end && f.selectionEnd > start {
 				left := textLeft + f.scrollOffset.X
 				selStart := max(f.selectionStart, start)
 				selEnd := min(f.selectionEnd, end)
-				if selStart > start {
-					t := NewTextFromRunes(f.obscureIfNeeded(f.runes[start:selStart]), &TextDecoration{
+				if line.complex || f.richText != nil {
+					f.drawBidiSelection(canvas, line, geom.NewPoint(left, textBaseLine), textTop, textHeight,
+						selStart-start, selEnd-start, ink)
+				} else {
+					if selStart > start {
+						t := NewTextFromRunes(f.obscureIfNeeded(f.runes[start:selStart]), &TextDecoration{
+							Font:            f.Font,
+							OnBackgroundInk: ink,
+						})
+						t.Draw(canvas, geom.NewPoint(left, textBaseLine))
+						left += t.Width()
+					}
+					e := selEnd
+					if end == selEnd && f.endsWithLineFeed[i] == hardLineEnding {
+						e--
+					}
+					t := NewTextFromRunes(f.obscureIfNeeded(f.runes[selStart:e]), &TextDecoration{
 						Font:            f.Font,
-						OnBackgroundInk: ink,
+						OnBackgroundInk: f.OnSelectionInk,
 					})
+					right := left + t.Width()
+					selRect := geom.NewRect(left, textTop, right-left, textHeight)
+					selectionPaint := f.SelectionInk.Paint(canvas, selRect, paintstyle.Fill)
+					canvas.DrawRect(selRect, selectionPaint)
 					t.Draw(canvas, geom.NewPoint(left, textBaseLine))
-					left += t.Width()
-				}
-				e := selEnd
-				if end == selEnd && f.endsWithLineFeed[i] == hardLineEnding {
-					e--
-				}
-				t := NewTextFromRunes(f.obscureIfNeeded(f.runes[selStart:e]), &TextDecoration{
-					Font:            f.Font,
-					OnBackgroundInk: f.OnSelectionInk,
-				})
-				right := left + t.Width()
-				selRect := geom.NewRect(left, textTop, right-left, textHeight)
-				selectionPaint := f.SelectionInk.Paint(canvas, selRect, paintstyle.Fill)
-				canvas.DrawRect(selRect, selectionPaint)
-				t.Draw(canvas, geom.NewPoint(left, textBaseLine))
-				if selEnd < end {
-					e = end
-					if f.endsWithLineFeed[i] == hardLineEnding {
-						e--
+					if selEnd < end {
+						e = end
+						if f.endsWithLineFeed[i] == hardLineEnding {
+							e--
+						}
+						NewTextFromRunes(f.obscureIfNeeded(f.runes[selEnd:e]), &TextDecoration{
+							Font:            f.Font,
+							OnBackgroundInk: ink,
+						}).Draw(canvas, geom.NewPoint(right, textBaseLine))
 					}
-					NewTextFromRunes(f.obscureIfNeeded(f.runes[selEnd:e]), &TextDecoration{
-						Font:            f.Font,
-						OnBackgroundInk: ink,
-					}).Draw(canvas, geom.NewPoint(right, textBaseLine))
 				}
 			} else {
-				line.AdjustDecorations(func(decoration *TextDecoration) { decoration.OnBackgroundInk = ink })
+				f.applyInk(line, ink)
 				line.Draw(canvas, geom.NewPoint(textLeft+f.scrollOffset.X, textBaseLine))
 			}
+			if saved != nil {
+				line.RestoreDecorations(saved)
+			}
+			if len(f.misspelled) != 0 && f.ObscurementRune == 0 {
+				f.drawMisspellings(canvas, line, textLeft+f.scrollOffset.X, textBaseLine, start, end,
+					focused && !hasSelectionRange)
+			}
 			if !hasSelectionRange && enabled && focused && f.selectionEnd >= start && (f.selectionEnd < end ||
 				(i == len(f.lines)-1 && f.selectionEnd <= end)) {
 				if f.showCursor {
-					t := NewTextFromRunes(f.obscureIfNeeded(f.runes[start:f.selectionEnd]),
-						&TextDecoration{Font: f.Font})
+					x := textLeft + line.PositionForRuneIndex(f.selectionEnd-start) + f.scrollOffset.X
 					cursorPaint := fg.Paint(canvas, rect, paintstyle.Fill)
-					canvas.DrawRect(geom.NewRect(textLeft+t.Width()+f.scrollOffset.X-0.5, textTop, 1, textHeight),
-						cursorPaint)
+					canvas.DrawRect(geom.NewRect(x-0.5, textTop, 1, textHeight), cursorPaint)
 				}
 				f.scheduleBlink()
 			}
@@ -407,6 +453,67 @@ func (f *Field) DefaultDraw(canvas *Canvas, _ geom.Rect) {
 	}
 }
 
+// drawBidiSelection draws a line whose runes have been reordered for display, highlighting the logical range [selStart,
+// selEnd), which may be split into several visual ranges. The line is drawn whole, then drawn again within each
+// highlighted range using the selection ink, so that shaping isn't disturbed by the selection boundaries. This is also
+// used for lines with mixed styles, for the same reason.
+func (f *Field) drawBidiSelection(canvas *Canvas, line *Text, pt geom.Point, top, height float32, selStart, selEnd int,
+	ink Ink) {
+	f.applyInk(line, ink)
+	ranges := line.RangesForRuneIndexes(selStart, selEnd)
+	for _, r := range ranges {
+		selRect := geom.NewRect(pt.X+r[0], top, r[1]-r[0], height)
+		canvas.DrawRect(selRect, f.SelectionInk.Paint(canvas, selRect, paintstyle.Fill))
+	}
+	line.Draw(canvas, pt)
+	line.AdjustDecorations(func(decoration *TextDecoration) { decoration.OnBackgroundInk = f.OnSelectionInk })
+	for _, r := range ranges {
+		canvas.Save()
+		canvas.ClipRect(geom.NewRect(pt.X+r[0], top, r[1]-r[0], height), pathop.Intersect, false)
+		line.Draw(canvas, pt)
+		canvas.Restore()
+	}
+}
+
+// drawSearchMatches highlights the search matches within the line, which holds the runes [start, end). Since the
+// selection isn't shown while the field lacks the focus, it is outlined instead, as it is usually the current match.
+func (f *Field) drawSearchMatches(canvas *Canvas, line *Text, left, top, height float32, start, end int) {
+	drawSearchMatches(canvas, f.searchMatches, start, end, left, top, height, f.SearchMatchInk, paintstyle.Fill,
+		line.RangesForRuneIndexes)
+	if f.HasSelectionRange() && !f.Focused() {
+		drawSearchMatches(canvas, [][2]int{{f.selectionStart, f.selectionEnd}}, start, end, left, top, height,
+			f.CurrentSearchMatchInk, paintstyle.Stroke, line.RangesForRuneIndexes)
+	}
+}
+
+// drawMisspellings draws a wavy underline beneath the misspelled words within the line, which holds the runes [start,
+// end). When skipCaretWord is true, a word that ends at the caret is left alone, as it is likely still being typed.
+func (f *Field) drawMisspellings(canvas *Canvas, line *Text, left, baseline float32, start, end int,
+	skipCaretWord bool) {
+	var paint *Paint
+	for _, m := range overlappingRanges(f.misspelled, start, end) {
+		if skipCaretWord && m[1] == f.selectionEnd {
+			continue
+		}
+		for _, r := range line.RangesForRuneIndexes(max(m[0], start)-start, min(m[1], end)-start) {
+			if paint == nil {
+				paint = f.MisspelledInk.Paint(canvas, geom.NewRect(left, baseline, r[1]-r[0], 4), paintstyle.Stroke)
+			}
+			drawWavyLine(canvas, left+r[0], left+r[1], baseline+2, paint)
+		}
+	}
+}
+
+// applyInk sets the ink used to draw the line. Runs of a RichTextField that have been given their own color keep it.
+func (f *Field) applyInk(line *Text, ink Ink) {
+	keepColors := f.richText != nil
+	line.AdjustDecorations(func(decoration *TextDecoration) {
+		if !keepColors || decoration.OnBackgroundInk == nil {
+			decoration.OnBackgroundInk = ink
+		}
+	})
+}
+
 // Invalid returns true if the field is currently marked as invalid.
 func (f *Field) Invalid() bool {
 	return f.invalid
@@ -510,10 +617,12 @@ func (f *Field) DefaultMouseUp(where geom.Point, button int, _ mod.Modifiers) bo
 
 // ShowContextMenu displays the context menu for the field at the specified position, which should be in local
 // coordinates. Only the actions that can currently be performed (Cut, Copy, Paste, Select All) are included; if none
-// of them can be performed, no menu is shown.
+// of them can be performed, no menu is shown. When the position is over a word the field's SpellChecker considers to be
+// misspelled, suggested replacements for it and an option to add it to the dictionary are placed first.
 func (f *Field) ShowContextMenu(where geom.Point) {
 	fac := DefaultMenuFactory()
 	cm := fac.NewMenu(PopupMenuTemporaryBaseID|ContextMenuIDFlag, "", nil)
+	f.insertSpellingItems(cm, f.ToSelectionIndex(where))
 	cm.InsertItem(-1, CutAction().NewContextMenuItemFromAction(fac))
 	cm.InsertItem(-1, CopyAction().NewContextMenuItemFromAction(fac))
 	cm.InsertItem(-1, PasteAction().NewContextMenuItemFromAction(fac))
@@ -525,6 +634,60 @@ func (f *Field) ShowContextMenu(where geom.Point) {
 	cm.Dispose()
 }
 
+// insertSpellingItems adds the spelling suggestions for the misspelled word at the given rune index, if any, to the
+// menu.
+func (f *Field) insertSpellingItems(cm Menu, index int) {
+	if f.spellChecker == nil || !f.Enabled() {
+		return
+	}
+	r, ok := f.misspellingAt(index)
+	if !ok {
+		return
+	}
+	fac := cm.Factory()
+	word := string(f.runes[r[0]:r[1]])
+	suggestions := f.spellChecker.Suggest(word, 5)
+	for _, suggestion := range suggestions {
+		cm.InsertItem(-1, fac.NewItem(-1, suggestion, KeyBinding{}, nil, func(MenuItem) {
+			f.replaceMisspelling(r, suggestion)
+		}))
+	}
+	if len(suggestions) == 0 {
+		cm.InsertItem(-1, fac.NewItem(-1, i18n.Text("No Suggestions"), KeyBinding{},
+			func(MenuItem) bool { return false }, nil))
+	}
+	checker := f.spellChecker
+	cm.InsertItem(-1, fac.NewItem(-1, i18n.Text("Add to Dictionary"), KeyBinding{}, nil, func(MenuItem) {
+		checker.Add(word)
+		f.scheduleSpellCheck()
+	}))
+	cm.InsertSeparator(-1, false)
+}
+
+// misspellingAt returns the misspelled range that contains or borders the given rune index.
+func (f *Field) misspellingAt(index int) ([2]int, bool) {
+	for _, r := range f.misspelled {
+		if index >= r[0] && index <= r[1] {
+			return r, true
+		}
+	}
+	return [2]int{}, false
+}
+
+// replaceMisspelling replaces the runes in the range with the replacement, provided the range is still one that was
+// found to be misspelled.
+func (f *Field) replaceMisspelling(r [2]int, replacement string) {
+	if !slices.Contains(f.misspelled, r) {
+		return
+	}
+	f.undoID = NextUndoID()
+	before := f.GetFieldState()
+	runes := f.sanitize([]rune(replacement))
+	f.replaceRunes(r[0], r[1], runes)
+	f.SetSelectionTo(r[0] + len(runes))
+	f.notifyOfModification(before, f.GetFieldState())
+}
+
 // DefaultMouseDrag provides the default mouse drag handling.
 func (f *Field) DefaultMouseDrag(where geom.Point, button int, _ mod.Modifiers) bool {
 	if button != ButtonLeft {
@@ -635,8 +798,7 @@ func (f *Field) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, _repeat bool
 			f.Delete()
 		} else if f.selectionStart < len(f.runes) {
 			before := f.GetFieldState()
-			f.runes = append(f.runes[:f.selectionStart], f.runes[f.selectionStart+1:]...)
-			f.linesBuiltFor = -1
+			f.replaceRunes(f.selectionStart, f.selectionStart+1, nil)
 			f.notifyOfModification(before, f.GetFieldState())
 		}
 		f.MarkForRedraw()
@@ -693,16 +855,137 @@ func (f *Field) DefaultRuneTyped(ch rune) bool {
 		return false
 	}
 	before := f.GetFieldState()
-	if f.HasSelectionRange() {
-		f.runes = append(f.runes[:f.selectionStart], f.runes[f.selectionEnd:]...)
-	}
-	f.runes = append(f.runes[:f.selectionStart], append([]rune{ch}, f.runes[f.selectionStart:]...)...)
-	f.linesBuiltFor = -1
+	f.replaceRunes(f.selectionStart, f.selectionEnd, []rune{ch})
 	f.SetSelectionTo(f.selectionStart + 1)
 	f.notifyOfModification(before, f.GetFieldState())
 	return true
 }
 
+// DefaultAccessibility provides the default accessibility description. The field is named by its watermark, if any.
+func (f *Field) DefaultAccessibility() Accessibility {
+	a := Accessibility{
+		Name:           f.Watermark,
+		Value:          string(f.obscureIfNeeded(f.runes)),
+		ActiveItem:     -1,
+		SelectionStart: f.selectionStart,
+		SelectionEnd:   f.selectionEnd,
+		Role:           role.TextField,
+	}
+	if f.ObscurementRune != 0 {
+		a.Role = role.PasswordField
+	}
+	if f.Enabled() {
+		a.State |= AccessibleEditable
+	} else {
+		a.State |= AccessibleReadOnly
+	}
+	if f.multiLine {
+		a.State |= AccessibleMultiLine
+	}
+	if f.invalid {
+		a.State |= AccessibleInvalid
+	}
+	return a
+}
+
+// DefaultCompositionStart provides the default composition start handling. Obscured fields decline, so that the text
+// being composed is never shown.
+func (f *Field) DefaultCompositionStart() bool {
+	return f.Enabled() && f.ObscurementRune == 0
+}
+
+// DefaultCompositionUpdate provides the default composition update handling.
+func (f *Field) DefaultCompositionUpdate(text string, caret int) {
+	f.preedit = []rune(text)
+	f.preeditCaret = caret
+	f.showCursor = true
+	f.forceShowUntil = time.Now().Add(f.BlinkRate)
+	f.MarkForRedraw()
+}
+
+// DefaultCompositionCommit provides the default composition commit handling. The composed text is not consumed here, so
+// that it is inserted by DefaultRuneTyped and participates in undo and validation like any other typing.
+func (f *Field) DefaultCompositionCommit(_ string) bool {
+	f.preedit = nil
+	f.preeditCaret = 0
+	f.MarkForRedraw()
+	return false
+}
+
+// DefaultCompositionCaretRect provides the default composition caret location. The text being composed is laid out on
+// its own to determine this, leaving the field's lines untouched.
+func (f *Field) DefaultCompositionCaretRect() geom.Rect {
+	if len(f.preedit) != 0 {
+		defer f.spliceInPreedit()()
+	}
+	pt := f.FromSelectionIndex(f.selectionEnd)
+	return geom.NewRect(pt.X, pt.Y, 1, f.Font.LineHeight())
+}
+
+// spliceInPreedit temporarily replaces the selection with the text being composed, placing the caret within it, so that
+// it can be laid out along with the rest of the content. Call the returned function to restore the field's state,
+// including the lines laid out before the splice, so that the lines laid out with the text being composed never
+// outlive it.
+func (f *Field) spliceInPreedit() (restore func()) {
+	runes := f.runes
+	start, end, anchor := f.selectionStart, f.selectionEnd, f.selectionAnchor
+	lines, endsWithLineFeed := f.lines, f.endsWithLineFeed
+	builtFor, builtWithFont, builtWithRune := f.linesBuiltFor, f.linesBuiltWithFont, f.linesBuiltWithRune
+	var styles richTextStyles
+	if f.richText != nil {
+		styles = *f.richText
+		f.richText.styles = slices.Clone(styles.styles)
+		f.richText.splice(start, end, len(f.preedit))
+	}
+	spliced := make([]rune, 0, len(runes)-(end-start)+len(f.preedit))
+	spliced = append(spliced, runes[:start]...)
+	spliced = append(spliced, f.preedit...)
+	f.runes = append(spliced, runes[end:]...)
+	caret := start + max(min(f.preeditCaret, len(f.preedit)), 0)
+	f.selectionStart = caret
+	f.selectionEnd = caret
+	f.selectionAnchor = caret
+	f.linesBuiltFor = -1
+	return func() {
+		f.runes = runes
+		if f.richText != nil {
+			*f.richText = styles
+		}
+		f.selectionStart = start
+		f.selectionEnd = end
+		f.selectionAnchor = anchor
+		f.lines = lines
+		f.endsWithLineFeed = endsWithLineFeed
+		f.linesBuiltFor = builtFor
+		f.linesBuiltWithFont = builtWithFont
+		f.linesBuiltWithRune = builtWithRune
+	}
+}
+
+// drawPreeditUnderline underlines the text being composed. Must be called while the preedit is spliced in.
+func (f *Field) drawPreeditUnderline(canvas *Canvas, ink Ink) {
+	rect := f.ContentRect(false)
+	preeditStart := f.selectionStart - max(min(f.preeditCaret, len(f.preedit)), 0)
+	preeditEnd := preeditStart + len(f.preedit)
+	paint := ink.Paint(canvas, rect, paintstyle.Fill)
+	y := rect.Y + f.scrollOffset.Y
+	start := 0
+	for i, line := range f.lines {
+		end := start + len(line.Runes())
+		if preeditStart < end && preeditEnd > start {
+			left := f.textLeft(line, rect) + f.scrollOffset.X
+			x1 := left + line.PositionForRuneIndex(max(preeditStart, start)-start)
+			x2 := left + line.PositionForRuneIndex(min(preeditEnd, end)-start)
+			canvas.DrawRect(geom.NewRect(x1, y+line.Baseline()+1, x2-x1, 1), paint)
+		}
+		if f.endsWithLineFeed[i] == hardLineEnding {
+			end++
+		}
+		y += max(line.Height(), f.Font.LineHeight())
+		start = end
+	}
+}
+
 func (f *Field) handleHome(lineOnly, extend bool) {
 	f.undoID = NextUndoID()
 	switch {
@@ -973,11 +1256,7 @@ func (f *Field) Paste() {
 		f.undoID = NextUndoID()
 		before := f.GetFieldState()
 		runes := f.sanitize([]rune(text))
-		if f.HasSelectionRange() {
-			f.runes = append(f.runes[:f.selectionStart], f.runes[f.selectionEnd:]...)
-		}
-		f.runes = append(f.runes[:f.selectionStart], append(runes, f.runes[f.selectionStart:]...)...)
-		f.linesBuiltFor = -1
+		f.replaceRunes(f.selectionStart, f.selectionEnd, runes)
 		f.SetSelectionTo(f.selectionStart + len(runes))
 		f.notifyOfModification(before, f.GetFieldState())
 	} else if f.HasSelectionRange() {
@@ -1004,12 +1283,11 @@ func (f *Field) Delete() {
 	if f.CanDelete() {
 		f.undoID = NextUndoID()
 		before := f.GetFieldState()
-		f.linesBuiltFor = -1
 		if f.HasSelectionRange() {
-			f.runes = append(f.runes[:f.selectionStart], f.runes[f.selectionEnd:]...)
+			f.replaceRunes(f.selectionStart, f.selectionEnd, nil)
 			f.SetSelectionTo(f.selectionStart)
 		} else {
-			f.runes = append(f.runes[:f.selectionStart-1], f.runes[f.selectionStart:]...)
+			f.replaceRunes(f.selectionStart-1, f.selectionStart, nil)
 			f.SetSelectionTo(f.selectionStart - 1)
 		}
 		f.notifyOfModification(before, f.GetFieldState())
@@ -1038,13 +1316,122 @@ func (f *Field) SetText(text string) {
 	runes := f.sanitize([]rune(text))
 	if !slices.Equal(runes, f.runes) {
 		before := f.GetFieldState()
-		f.runes = runes
-		f.linesBuiltFor = -1
+		f.replaceRunes(0, len(f.runes), runes)
 		f.SetSelectionToEnd()
 		f.notifyOfModification(before, f.GetFieldState())
 	}
 }
 
+// replaceRunes replaces the runes in the range [start, end) with the given runes.
+func (f *Field) replaceRunes(start, end int, runes []rune) {
+	if f.richText != nil {
+		f.richText.splice(start, end, len(runes))
+	}
+	f.runes = slices.Replace(f.runes, start, end, runes...)
+	f.linesBuiltFor = -1
+	f.searchMatches = nil
+	f.misspelled = adjustRangesForEdit(f.misspelled, start, end, len(runes))
+	f.scheduleSpellCheck()
+}
+
+// SpellChecker returns the spell checker used by the field, if any.
+func (f *Field) SpellChecker() SpellChecker {
+	return f.spellChecker
+}
+
+// SetSpellChecker sets the spell checker used by the field. Pass in nil to disable spell checking. Fields with an
+// ObscurementRune set are never checked.
+func (f *Field) SetSpellChecker(checker SpellChecker) {
+	if f.spellChecker != checker {
+		f.spellChecker = checker
+		f.misspelled = nil
+		f.scheduleSpellCheck()
+		f.MarkForRedraw()
+	}
+}
+
+// MisspelledRanges returns the rune ranges [start, end) of the words that were found to be misspelled the last time
+// the field's content was checked. Checks are made in the background after each change, so this may briefly lag
+// behind the content.
+func (f *Field) MisspelledRanges() [][2]int {
+	return slices.Clone(f.misspelled)
+}
+
+// scheduleSpellCheck checks the content on another goroutine, or via startSpellCheck if it has been set, delivering the
+// results back to the UI thread. Results for content that has since changed are discarded.
+func (f *Field) scheduleSpellCheck() {
+	f.spellCheckGen++
+	if f.spellChecker == nil || f.ObscurementRune != 0 {
+		f.misspelled = nil
+		return
+	}
+	checker := f.spellChecker
+	gen := f.spellCheckGen
+	text := slices.Clone(f.runes)
+	spellCheck := func() {
+		var misspelled [][2]int
+		SafeCall(func() { misspelled = FindMisspelledWords(checker, text) })
+		InvokeTask(func() {
+			if f.spellCheckGen == gen {
+				f.misspelled = misspelled
+				f.MarkForRedraw()
+			}
+		})
+	}
+	if f.startSpellCheck != nil {
+		f.startSpellCheck(spellCheck)
+	} else {
+		go spellCheck()
+	}
+}
+
+// SearchableText implements Searchable.
+func (f *Field) SearchableText() []rune {
+	return f.runes
+}
+
+// SetSearchHighlights implements Searchable.
+func (f *Field) SetSearchHighlights(ranges [][2]int) {
+	f.searchMatches = ranges
+	f.MarkForRedraw()
+}
+
+// ReplaceRanges implements EditableSearchable. The edit is added to the UndoManager returned by UndoManagerFor(), if
+// any.
+func (f *Field) ReplaceRanges(name string, ranges [][2]int, replacements [][]rune) {
+	if len(ranges) == 0 {
+		return
+	}
+	f.undoID = NextUndoID()
+	before := f.GetFieldState()
+	caret := ranges[len(ranges)-1][1]
+	for i := len(ranges) - 1; i >= 0; i-- {
+		runes := f.sanitize(slices.Clone(replacements[i]))
+		f.replaceRunes(ranges[i][0], ranges[i][1], runes)
+		caret += len(runes) - (ranges[i][1] - ranges[i][0])
+	}
+	f.SetSelectionTo(caret)
+	after := f.GetFieldState()
+	if mgr := UndoManagerFor(f); mgr != nil {
+		mgr.Add(&UndoEdit[*FieldState]{
+			ID:         f.undoID,
+			EditName:   name,
+			EditCost:   1,
+			UndoFunc:   func(edit *UndoEdit[*FieldState]) { f.applyUndoState(edit.AfterData, edit.BeforeData) },
+			RedoFunc:   func(edit *UndoEdit[*FieldState]) { f.applyUndoState(edit.BeforeData, edit.AfterData) },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	f.undoID = NextUndoID()
+	f.notifyOfModification(before, after)
+}
+
+func (f *Field) applyUndoState(from, to *FieldState) {
+	f.ApplyFieldState(to)
+	f.notifyOfModification(from, to)
+}
+
 func (f *Field) notifyOfModification(before, after *FieldState) {
 	f.MarkForRedraw()
 	if f.ModifiedCallback != nil {
@@ -1150,6 +1537,9 @@ func (f *Field) setSelection(start, end, anchor int) {
 		f.selectionStart = start
 		f.selectionEnd = end
 		f.selectionAnchor = anchor
+		if f.richText != nil {
+			f.richText.pendingAt = -1
+		}
 		f.forceShowUntil = time.Now().Add(f.BlinkRate)
 		f.showCursor = true
 		f.MarkForRedraw()
@@ -1397,12 +1787,16 @@ func (f *Field) lineIndexForY(y float32) (index, startPos int) {
 func (f *Field) GetFieldState() *FieldState {
 	runes := make([]rune, len(f.runes))
 	copy(runes, f.runes)
-	return &FieldState{
+	state := &FieldState{
 		Text:            string(runes),
 		SelectionStart:  f.selectionStart,
 		SelectionEnd:    f.selectionEnd,
 		SelectionAnchor: f.selectionAnchor,
 	}
+	if f.richText != nil {
+		state.Styles = slices.Clone(f.richText.styles)
+	}
+	return state
 }
 
 // ApplyFieldState sets the underlying field state to match the input and without triggering calls to the modification
@@ -1410,7 +1804,11 @@ func (f *Field) GetFieldState() *FieldState {
 func (f *Field) ApplyFieldState(state *FieldState) {
 	runes := f.sanitize([]rune(state.Text))
 	if !slices.Equal(runes, f.runes) {
-		f.runes = runes
+		f.replaceRunes(0, len(f.runes), runes)
+		f.MarkForRedraw()
+	}
+	if f.richText != nil && len(state.Styles) == len(f.runes) && !slices.Equal(state.Styles, f.richText.styles) {
+		f.richText.styles = slices.Clone(state.Styles)
 		f.linesBuiltFor = -1
 		f.MarkForRedraw()
 	}
@@ -338,3 +338,52 @@ func TestFieldShiftArrowWithoutCrossingAnchorIsUnchanged(t *testing.T) {
 	c.Equal(12, f.selectionEnd)
 	c.Equal(12, f.selectionAnchor)
 }
+
+type testSpellChecker map[string]bool
+
+func (s testSpellChecker) Check(word string) bool           { return s[word] }
+func (s testSpellChecker) Suggest(_ string, _ int) []string { return nil }
+func (s testSpellChecker) Add(word string)                  { s[word] = true }
+
+// awaitMisspellings runs the queued tasks, which deliver the results of the field's pending spell check, then verifies
+// the ranges it found.
+func awaitMisspellings(c check.Checker, f *Field, want [][2]int) {
+	c.Helper()
+	drainTasks()
+	c.Equal(want, f.MisspelledRanges())
+}
+
+// TestFieldSpellCheck verifies that misspelled words are found and delivered to the field through the UI thread, that
+// an edit drops the ranges it touches and shifts the ones after it right away, and that obscured fields aren't checked.
+func TestFieldSpellCheck(t *testing.T) {
+	c := check.New(t)
+	resetTaskQueue()
+	checker := testSpellChecker{"the": true, "cat": true, "sat": true}
+	f := NewField()
+	f.startSpellCheck = func(check func()) { check() } // Queue the results as soon as a check is scheduled
+	f.SetText("teh cat sta")
+	f.SetSpellChecker(checker)
+	awaitMisspellings(c, f, [][2]int{{0, 3}, {8, 11}})
+
+	f.replaceMisspelling([2]int{0, 3}, "the")
+	c.Equal("the cat sta", f.Text())
+	c.Equal([][2]int{{8, 11}}, f.MisspelledRanges())
+	f.replaceMisspelling([2]int{4, 7}, "dog")
+	c.Equal("the cat sta", f.Text(), "ranges that aren't misspelled are never replaced")
+
+	f.SetSelection(0, 0)
+	f.replaceRunes(0, 0, []rune("a "))
+	c.Equal([][2]int{{10, 13}}, f.MisspelledRanges())
+	awaitMisspellings(c, f, [][2]int{{0, 1}, {10, 13}})
+
+	checker.Add("a")
+	checker.Add("sta")
+	f.scheduleSpellCheck()
+	awaitMisspellings(c, f, nil)
+
+	f.ObscurementRune = '*'
+	f.SetText("xyzzy")
+	c.Nil(f.MisspelledRanges())
+	f.SetSpellChecker(nil)
+	c.Nil(f.SpellChecker())
+}
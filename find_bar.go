@@ -0,0 +1,446 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var (
+	_ EditableSearchable = &Field{}
+	_ EditableSearchable = &CodeEditor{}
+	_ Searchable         = &Markdown{}
+)
+
+// Searchable defines the methods a widget must provide for its text to be searched with a FindBar. Offsets are rune
+// indexes into the text returned by SearchableText().
+type Searchable interface {
+	Paneler
+	// SearchableText returns the text to search. The returned slice must not be modified.
+	SearchableText() []rune
+	// Selection returns the current selection, which a FindBar uses as the current match.
+	Selection() (start, end int)
+	// SetSelection sets the selection.
+	SetSelection(start, end int)
+	// ScrollSelectionIntoView scrolls the selection into view.
+	ScrollSelectionIntoView()
+	// SetSearchHighlights sets the ranges to highlight as matches of a search, in ascending order. Pass nil to remove
+	// the highlighting. Widgets whose text can change remove the highlighting themselves when it does.
+	SetSearchHighlights(ranges [][2]int)
+}
+
+// EditableSearchable defines the additional methods a Searchable widget must provide for a FindBar to replace matches.
+type EditableSearchable interface {
+	Searchable
+	// ReplaceRanges replaces each of the ranges, which are in ascending order and do not overlap, with the corresponding
+	// replacement, placing the caret after the last of them. The whole is recorded as a single undoable edit with the
+	// given name.
+	ReplaceRanges(name string, ranges [][2]int, replacements [][]rune)
+}
+
+// FindBar provides a panel for finding, and optionally replacing, text within a Searchable widget. It is typically
+// placed above or below the widget it searches, or the ScrollPanel containing it. The find and replace commands are
+// installed on both the FindBar and its target, so that FindAction(), FindNextAction() and FindPreviousAction() work
+// while either has the focus. When the target is an EditableSearchable, a second row for replacing matches is shown.
+type FindBar struct {
+	// CloseCallback is called when the FindBar is closed, giving the owner a chance to remove it from its parent.
+	CloseCallback     func()
+	target            Searchable
+	finder            *TextFinder
+	findField         *Field
+	replaceField      *Field
+	matchCaseCheckBox *CheckBox
+	wholeWordCheckBox *CheckBox
+	regexCheckBox     *CheckBox
+	statusLabel       *Label
+	replaceRow        *Panel
+	matches           [][2]int
+	Panel
+	patternErr bool
+}
+
+// NewFindBar creates a new FindBar for the target.
+func NewFindBar(target Searchable) *FindBar {
+	b := &FindBar{}
+	b.Self = b
+	b.SetLayout(&FlexLayout{
+		Columns:  1,
+		VSpacing: StdVSpacing,
+	})
+	b.SetBorder(NewEmptyBorder(StdInsets()))
+
+	findRow := NewPanel()
+	b.findField = NewField()
+	b.findField.Watermark = i18n.Text("Find")
+	b.findField.ModifiedCallback = func(_, _ *FieldState) { b.search() }
+	b.findField.ValidateCallback = func() bool { return !b.patternErr }
+	b.findField.KeyDownCallback = b.findFieldKeyDown
+	b.findField.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	findRow.AddChild(b.findField)
+	findRow.AddChild(b.newButton(i18n.Text("Previous"), func() { b.FindPrevious() }))
+	findRow.AddChild(b.newButton(i18n.Text("Next"), func() { b.FindNext() }))
+	b.matchCaseCheckBox = b.newCheckBox(i18n.Text("Match Case"))
+	findRow.AddChild(b.matchCaseCheckBox)
+	b.wholeWordCheckBox = b.newCheckBox(i18n.Text("Whole Word"))
+	findRow.AddChild(b.wholeWordCheckBox)
+	b.regexCheckBox = b.newCheckBox(i18n.Text("Regex"))
+	findRow.AddChild(b.regexCheckBox)
+	b.statusLabel = NewLabel()
+	b.statusLabel.SetLayoutData(&FlexLayoutData{VAlign: align.Middle})
+	findRow.AddChild(b.statusLabel)
+	findRow.AddChild(b.newButton(i18n.Text("Done"), b.Close))
+	findRow.SetLayout(&FlexLayout{
+		Columns:  len(findRow.Children()),
+		HSpacing: StdHSpacing,
+	})
+	findRow.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	b.AddChild(findRow)
+
+	b.replaceRow = NewPanel()
+	b.replaceField = NewField()
+	b.replaceField.Watermark = i18n.Text("Replace")
+	b.replaceField.KeyDownCallback = b.replaceFieldKeyDown
+	b.replaceField.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	b.replaceRow.AddChild(b.replaceField)
+	b.replaceRow.AddChild(b.newButton(i18n.Text("Replace"), func() { b.Replace() }))
+	b.replaceRow.AddChild(b.newButton(i18n.Text("Replace All"), func() { b.ReplaceAll() }))
+	b.replaceRow.SetLayout(&FlexLayout{
+		Columns:  len(b.replaceRow.Children()),
+		HSpacing: StdHSpacing,
+	})
+	b.replaceRow.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	b.installCmdHandlers(b.AsPanel())
+	b.SetTarget(target)
+	return b
+}
+
+func (b *FindBar) newButton(title string, clicked func()) *Button {
+	button := NewButton()
+	button.SetTitle(title)
+	button.ClickCallback = clicked
+	button.SetLayoutData(&FlexLayoutData{VAlign: align.Middle})
+	return button
+}
+
+func (b *FindBar) newCheckBox(title string) *CheckBox {
+	checkBox := NewCheckBox()
+	checkBox.SetTitle(title)
+	checkBox.ClickCallback = b.search
+	checkBox.SetLayoutData(&FlexLayoutData{VAlign: align.Middle})
+	return checkBox
+}
+
+func (b *FindBar) installCmdHandlers(p *Panel) {
+	p.InstallCmdHandlers(FindItemID, AlwaysEnabled, func(_ any) { b.Activate() })
+	p.InstallCmdHandlers(FindNextItemID, b.canFind, func(_ any) { b.FindNext() })
+	p.InstallCmdHandlers(FindPreviousItemID, b.canFind, func(_ any) { b.FindPrevious() })
+}
+
+func (b *FindBar) canFind(_ any) bool {
+	return b.target != nil && b.findField.Text() != ""
+}
+
+// Target returns the widget being searched.
+func (b *FindBar) Target() Searchable {
+	return b.target
+}
+
+// SetTarget sets the widget to search. May be nil.
+func (b *FindBar) SetTarget(target Searchable) {
+	if b.target == target {
+		return
+	}
+	if b.target != nil {
+		b.target.SetSearchHighlights(nil)
+		p := b.target.AsPanel()
+		p.RemoveCmdHandler(FindItemID)
+		p.RemoveCmdHandler(FindNextItemID)
+		p.RemoveCmdHandler(FindPreviousItemID)
+	}
+	b.target = target
+	if target != nil {
+		b.installCmdHandlers(target.AsPanel())
+	}
+	_, editable := target.(EditableSearchable)
+	if editable != (b.replaceRow.Parent() != nil) {
+		if editable {
+			b.AddChild(b.replaceRow)
+		} else {
+			b.replaceRow.RemoveFromParent()
+		}
+		b.MarkForLayoutRecursivelyUpward()
+	}
+	b.search()
+}
+
+// FindField returns the field holding the text to find.
+func (b *FindBar) FindField() *Field {
+	return b.findField
+}
+
+// ReplaceField returns the field holding the replacement text.
+func (b *FindBar) ReplaceField() *Field {
+	return b.replaceField
+}
+
+// Options returns the current search options.
+func (b *FindBar) Options() FindOptions {
+	return FindOptions{
+		MatchCase: b.matchCaseCheckBox.State == check.On,
+		WholeWord: b.wholeWordCheckBox.State == check.On,
+		Regex:     b.regexCheckBox.State == check.On,
+	}
+}
+
+// SetOptions sets the search options.
+func (b *FindBar) SetOptions(options FindOptions) {
+	b.matchCaseCheckBox.State = check.FromBool(options.MatchCase)
+	b.wholeWordCheckBox.State = check.FromBool(options.WholeWord)
+	b.regexCheckBox.State = check.FromBool(options.Regex)
+	b.MarkForRedraw()
+	b.search()
+}
+
+// Activate focuses the find field, first filling it with the target's selection if that is a non-empty range that
+// doesn't span lines.
+func (b *FindBar) Activate() {
+	if b.target != nil {
+		if start, end := b.target.Selection(); start < end {
+			if text := b.target.SearchableText()[start:end]; !slices.Contains(text, '\n') {
+				b.findField.SetText(string(text))
+			}
+		}
+	}
+	b.findField.RequestFocus()
+	b.findField.SelectAll()
+	b.search()
+}
+
+// Matches returns the ranges of the matches found in the target's text when it was last searched.
+func (b *FindBar) Matches() [][2]int {
+	return b.matches
+}
+
+// FindNext selects the next match following the target's selection, wrapping around to the start of the text if
+// needed. Returns false if there are no matches.
+func (b *FindBar) FindNext() bool {
+	return b.find(false)
+}
+
+// FindPrevious selects the previous match preceding the target's selection, wrapping around to the end of the text if
+// needed. Returns false if there are no matches.
+func (b *FindBar) FindPrevious() bool {
+	return b.find(true)
+}
+
+func (b *FindBar) find(backward bool) bool {
+	b.search()
+	if b.target == nil {
+		return false
+	}
+	start, end := b.target.Selection()
+	target, ok := nextMatch(b.matches, start, end, backward)
+	if ok {
+		b.target.SetSelection(target[0], target[1])
+		b.target.ScrollSelectionIntoView()
+	}
+	b.updateStatus()
+	return ok
+}
+
+// Replace replaces the target's selection if it is a match, then selects the next match. If the selection isn't a
+// match, the next match is selected instead. Returns true if a replacement was made.
+func (b *FindBar) Replace() bool {
+	b.search()
+	editable, ok := b.target.(EditableSearchable)
+	if !ok {
+		return false
+	}
+	start, end := editable.Selection()
+	text := editable.SearchableText()
+	for _, m := range b.matches {
+		if m[0] == start && m[1] == end {
+			editable.ReplaceRanges(i18n.Text("Replace"), [][2]int{m},
+				[][]rune{b.finder.Replacement(text, m, b.replaceField.Text())})
+			b.FindNext()
+			return true
+		}
+	}
+	b.FindNext()
+	return false
+}
+
+// ReplaceAll replaces every match within the target's text as a single undoable edit. Returns the number of
+// replacements made.
+func (b *FindBar) ReplaceAll() int {
+	b.search()
+	editable, ok := b.target.(EditableSearchable)
+	if !ok || len(b.matches) == 0 {
+		return 0
+	}
+	matches := b.matches
+	text := editable.SearchableText()
+	replacement := b.replaceField.Text()
+	replacements := make([][]rune, len(matches))
+	for i, m := range matches {
+		replacements[i] = b.finder.Replacement(text, m, replacement)
+	}
+	editable.ReplaceRanges(i18n.Text("Replace All"), matches, replacements)
+	b.search()
+	b.statusLabel.SetTitle(fmt.Sprintf(i18n.Text("Replaced %d"), len(matches)))
+	b.statusLabel.MarkForLayoutRecursivelyUpward()
+	return len(matches)
+}
+
+// Close removes the highlighting from the target, returns the focus to it and then calls the CloseCallback.
+func (b *FindBar) Close() {
+	if b.target != nil {
+		b.target.SetSearchHighlights(nil)
+		b.target.AsPanel().RequestFocus()
+	}
+	if b.CloseCallback != nil {
+		SafeCall(b.CloseCallback)
+	}
+}
+
+// search finds the matches within the target's text and highlights them.
+func (b *FindBar) search() {
+	b.finder = nil
+	b.matches = nil
+	patternErr := false
+	if pattern := b.findField.Text(); pattern != "" {
+		finder, err := NewTextFinder(pattern, b.Options())
+		if err != nil {
+			patternErr = true
+		} else {
+			b.finder = finder
+			if b.target != nil {
+				b.matches = finder.FindAll(b.target.SearchableText())
+			}
+		}
+	}
+	if patternErr != b.patternErr {
+		b.patternErr = patternErr
+		b.findField.Validate()
+	}
+	if b.target != nil {
+		b.target.SetSearchHighlights(b.matches)
+	}
+	b.updateStatus()
+}
+
+func (b *FindBar) updateStatus() {
+	var status string
+	switch {
+	case b.patternErr:
+		status = i18n.Text("Invalid pattern")
+	case b.finder == nil:
+	case len(b.matches) == 0:
+		status = i18n.Text("No matches")
+	default:
+		status = fmt.Sprintf(i18n.Text("%d matches"), len(b.matches))
+		if b.target != nil {
+			start, end := b.target.Selection()
+			if i, found := slices.BinarySearchFunc(b.matches, start, func(m [2]int, target int) int {
+				return m[0] - target
+			}); found && b.matches[i][1] == end {
+				status = fmt.Sprintf(i18n.Text("%d of %d"), i+1, len(b.matches))
+			}
+		}
+	}
+	if b.statusLabel.String() != status {
+		b.statusLabel.SetTitle(status)
+		b.statusLabel.MarkForLayoutRecursivelyUpward()
+	}
+}
+
+func (b *FindBar) findFieldKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	switch keyCode {
+	case KeyReturn, KeyNumPadEnter:
+		if mods.ShiftDown() {
+			b.FindPrevious()
+		} else {
+			b.FindNext()
+		}
+		return true
+	case KeyEscape:
+		b.Close()
+		return true
+	default:
+	}
+	return b.findField.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+func (b *FindBar) replaceFieldKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	switch keyCode {
+	case KeyReturn, KeyNumPadEnter:
+		b.Replace()
+		return true
+	case KeyEscape:
+		b.Close()
+		return true
+	default:
+	}
+	return b.replaceField.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// drawSearchMatches fills the portions of the matches that fall within the line, which holds the runes [lineStart,
+// lineEnd) of the text. extents returns the horizontal extents, relative to left, covered by a range of the line's
+// runes.
+func drawSearchMatches(canvas *Canvas, matches [][2]int, lineStart, lineEnd int, left, top, height float32, ink Ink,
+	style paintstyle.Enum, extents func(start, end int) [][2]float32) {
+	for _, m := range overlappingRanges(matches, lineStart, lineEnd) {
+		for _, r := range extents(max(m[0], lineStart)-lineStart, min(m[1], lineEnd)-lineStart) {
+			rect := geom.NewRect(left+r[0], top, r[1]-r[0], height)
+			if style == paintstyle.Stroke {
+				rect = rect.Inset(geom.NewUniformInsets(0.5))
+			}
+			canvas.DrawRect(rect, ink.Paint(canvas, rect, style))
+		}
+	}
+}
+
+// overlappingRanges returns the portion of the ranges, which are in ascending order, that overlap [start, end).
+func overlappingRanges(ranges [][2]int, start, end int) [][2]int {
+	i, _ := slices.BinarySearchFunc(ranges, start, func(r [2]int, target int) int {
+		if r[1] <= target {
+			return -1
+		}
+		return 1
+	})
+	j := i
+	for j < len(ranges) && ranges[j][0] < end {
+		j++
+	}
+	return ranges[i:j]
+}
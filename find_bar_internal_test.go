@@ -0,0 +1,118 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func newFieldWithUndo(t *testing.T, text string) (*Field, *UndoManager) {
+	host := newUndoHost(t)
+	f := NewMultiLineField()
+	host.AddChild(f)
+	f.SetText(text)
+	f.SetSelectionToStart()
+	return f, host.mgr
+}
+
+func TestFindBarFindsAndHighlights(t *testing.T) {
+	c := check.New(t)
+	f, _ := newFieldWithUndo(t, "cat dog Cat\ncatalog")
+	b := NewFindBar(f)
+	b.FindField().SetText("cat")
+	c.Equal([][2]int{{0, 3}, {8, 11}, {12, 15}}, b.Matches())
+	c.Equal(b.Matches(), f.searchMatches)
+	c.True(b.FindNext())
+	start, end := f.Selection()
+	c.Equal(0, start)
+	c.Equal(3, end)
+	c.Equal("1 of 3", b.statusLabel.String())
+	c.True(b.FindNext())
+	start, _ = f.Selection()
+	c.Equal(8, start)
+	c.True(b.FindPrevious())
+	c.True(b.FindPrevious(), "searching wraps around")
+	start, _ = f.Selection()
+	c.Equal(12, start)
+
+	b.SetOptions(FindOptions{MatchCase: true, WholeWord: true})
+	c.Equal([][2]int{{0, 3}}, b.Matches())
+
+	b.SetOptions(FindOptions{Regex: true})
+	b.FindField().SetText("(")
+	c.Equal(0, len(b.Matches()))
+	c.True(b.FindField().Invalid())
+	c.False(b.FindNext())
+
+	b.Close()
+	c.Equal(0, len(f.searchMatches), "closing removes the highlighting")
+}
+
+func TestFindBarReplace(t *testing.T) {
+	c := check.New(t)
+	f, mgr := newFieldWithUndo(t, "a1 b2 c3")
+	b := NewFindBar(f)
+	c.NotNil(b.replaceRow.Parent(), "the replace row is shown for editable targets")
+	b.SetOptions(FindOptions{Regex: true})
+	b.FindField().SetText(`(\w)(\d)`)
+	b.ReplaceField().SetText("$2$1")
+	c.False(b.Replace(), "the selection is not a match, so the first match is selected instead")
+	c.True(b.Replace())
+	c.Equal("1a b2 c3", f.Text())
+	start, end := f.Selection()
+	c.Equal(3, start, "the next match is selected")
+	c.Equal(5, end)
+
+	c.Equal(2, b.ReplaceAll())
+	c.Equal("1a 2b 3c", f.Text())
+	c.Equal("Replaced 2", b.statusLabel.String())
+	mgr.Undo()
+	c.Equal("1a b2 c3", f.Text(), "replacing all is a single undoable edit")
+	mgr.Undo()
+	c.Equal("a1 b2 c3", f.Text())
+	mgr.Redo()
+	mgr.Redo()
+	c.Equal("1a 2b 3c", f.Text())
+}
+
+func TestFindBarCommands(t *testing.T) {
+	c := check.New(t)
+	e := NewCodeEditor()
+	e.SetText("one two one")
+	b := NewFindBar(e)
+	c.False(e.CanPerformCmd(nil, FindNextItemID), "nothing to find yet")
+	e.SetSelection(0, 3)
+	e.PerformCmd(nil, FindItemID)
+	c.Equal("one", b.FindField().Text(), "the selection is used as the text to find")
+	c.Equal(2, len(e.searchMatches))
+	c.True(b.CanPerformCmd(nil, FindNextItemID))
+	b.PerformCmd(nil, FindNextItemID)
+	start, _ := e.Selection()
+	c.Equal(8, start)
+	e.PerformCmd(nil, FindPreviousItemID)
+	start, _ = e.Selection()
+	c.Equal(0, start)
+
+	m := NewMarkdown(false)
+	m.SetContent("alpha beta\n\n```\nbeta\ngamma\n```\n", 400)
+	b.SetTarget(m)
+	c.False(e.CanPerformCmd(nil, FindNextItemID), "the handlers are removed from the former target")
+	c.Equal(0, len(e.searchMatches))
+	c.Nil(b.replaceRow.Parent(), "the replace row is hidden for targets that can't be edited")
+	c.Equal("alpha beta\nbeta\ngamma", string(m.SearchableText()))
+	b.FindField().SetText("beta")
+	c.Equal([][2]int{{6, 10}, {11, 15}}, b.Matches())
+	c.True(b.FindNext())
+	start, end := m.Selection()
+	c.Equal(6, start)
+	c.Equal(10, end)
+}
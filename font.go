@@ -79,6 +79,8 @@ type Font interface {
 	// Descriptor returns a FontDescriptor for this Font.
 	Descriptor() FontDescriptor
 	canvasFont() *font.Font
+	// emSize returns the size of the em square in logical pixels, as opposed to the cap height returned by Size().
+	emSize() float32
 }
 
 type internalFont struct {
@@ -87,10 +89,11 @@ type internalFont struct {
 }
 
 type fontImpl struct {
-	face    *FontFace
-	font    *font.Font
-	metrics font.Metrics
-	size    float32
+	face     *FontFace
+	font     *font.Font
+	metrics  font.Metrics
+	size     float32
+	fontSize float32
 }
 
 func (f *fontImpl) Face() *FontFace {
@@ -162,6 +165,10 @@ func (f *fontImpl) canvasFont() *font.Font {
 	return f.font
 }
 
+func (f *fontImpl) emSize() float32 {
+	return f.fontSize
+}
+
 func (f *fontImpl) Descriptor() FontDescriptor {
 	w, sp, sl := f.face.Style()
 	return FontDescriptor{
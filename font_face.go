@@ -38,7 +38,8 @@ type faceFallbackCacheKey struct {
 
 // FontFace holds the immutable portions of a font description.
 type FontFace struct {
-	face *font.Typeface
+	face    *font.Typeface
+	shaping fontFaceShaping
 }
 
 func newFace(face *font.Typeface) *FontFace {
@@ -114,7 +115,11 @@ func MatchFontFace(family string, weightValue weight.Enum, spacingValue spacing.
 func CreateFontFace(data []byte) *FontFace {
 	localData := make([]byte, len(data))
 	copy(localData, data)
-	return newFace(fontmgr.Default().MakeFromData(localData, 0))
+	f := newFace(fontmgr.Default().MakeFromData(localData, 0))
+	if f != nil {
+		f.setShapingData(localData)
+	}
+	return f
 }
 
 // Font returns a Font of the given size for this FontFace.
@@ -186,8 +191,9 @@ func (f *FontFace) FallbackForCharacter(ch rune) *FontFace {
 
 func (f *FontFace) createFontWithSize(size float32) *fontImpl {
 	fi := &fontImpl{
-		face: f,
-		font: font.NewFont(f.face, size, 1, 0),
+		face:     f,
+		font:     font.NewFont(f.face, size, 1, 0),
+		fontSize: size,
 	}
 	fi.font.SetSubpixel(true)
 	fi.font.SetForceAutoHinting(true)
@@ -0,0 +1,156 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	gotext "github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/fontscan"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/unison/enums/slant"
+)
+
+var (
+	systemFontMapOnce  sync.Once
+	systemFontMapLock  sync.Mutex
+	systemFontMap      *fontscan.FontMap
+	systemFontMapReady atomic.Bool
+)
+
+// shapingSampleRunes are used to verify that a face located for shaping maps characters to the same glyphs as the face
+// used for rendering.
+var shapingSampleRunes = []rune("Aa0 אاकก")
+
+// fontFaceShaping holds the state needed to shape text with a FontFace.
+type fontFaceShaping struct {
+	lock     sync.Mutex
+	data     []byte
+	face     *gotext.Face
+	resolved bool
+}
+
+// scanSystemFonts starts scanning the system fonts used for shaping on a background goroutine, if that hasn't already
+// been done. The first scan examines every installed font and can take several seconds. Until it completes, text that
+// needs a system font for shaping is laid out without shaping; once it completes, that text is laid out again and all
+// windows are redrawn.
+func scanSystemFonts() {
+	systemFontMapOnce.Do(func() {
+		go func() {
+			fm := fontscan.NewFontMap(log.New(io.Discard, "", 0))
+			dir, err := os.UserCacheDir()
+			if err != nil {
+				dir = os.TempDir()
+			}
+			if err = fm.UseSystemFonts(filepath.Join(dir, "unison", "fonts")); err != nil {
+				errs.Log(errs.NewWithCause("unable to scan system fonts for shaping", err))
+				fm = nil
+			}
+			InvokeTask(func() { systemFontsScanned(fm) })
+		}()
+	})
+}
+
+// systemFontsScanned is called on the UI thread once the system fonts have been scanned.
+func systemFontsScanned(fm *fontscan.FontMap) {
+	systemFontMapLock.Lock()
+	systemFontMap = fm
+	systemFontMapLock.Unlock()
+	systemFontMapReady.Store(true)
+	for _, wnd := range Windows() {
+		if wnd.IsValid() {
+			wnd.root.MarkForLayoutRecursively()
+			wnd.MarkForRedraw()
+		}
+	}
+}
+
+// setShapingData records the font data this FontFace was created from, so that it can be used for shaping.
+func (f *FontFace) setShapingData(data []byte) {
+	faceCacheLock.Lock()
+	if f.shaping.data == nil {
+		f.shaping.data = data
+	}
+	faceCacheLock.Unlock()
+}
+
+// shapingFace returns the face used for complex-script shaping, or nil if one isn't available. Faces created from font
+// data are parsed directly. Faces provided by the system are located by family and style, and the result is only used
+// if its character mapping agrees with the one used for rendering, since the glyphs it produces will be drawn with this
+// FontFace. Returns false if the system fonts haven't been scanned yet, in which case a face may become available
+// later.
+func (f *FontFace) shapingFace() (*gotext.Face, bool) {
+	f.shaping.lock.Lock()
+	defer f.shaping.lock.Unlock()
+	if !f.shaping.resolved {
+		faceCacheLock.RLock()
+		data := f.shaping.data
+		faceCacheLock.RUnlock()
+		var face *gotext.Face
+		if data != nil {
+			var err error
+			if face, err = gotext.ParseTTF(bytes.NewReader(data)); err != nil {
+				errs.Log(errs.NewWithCause("unable to parse font for shaping", err), "family", f.Family())
+			}
+		} else if !systemFontMapReady.Load() {
+			return nil, false
+		} else {
+			face = f.locateSystemShapingFace()
+		}
+		if face != nil && f.matchesShapingFace(face) {
+			f.shaping.face = face
+		}
+		f.shaping.resolved = true
+	}
+	return f.shaping.face, true
+}
+
+func (f *FontFace) locateSystemShapingFace() *gotext.Face {
+	w, _, sl := f.Style()
+	aspect := gotext.Aspect{Style: gotext.StyleNormal, Weight: gotext.Weight(w)}
+	if sl != slant.Upright {
+		aspect.Style = gotext.StyleItalic
+	}
+	systemFontMapLock.Lock()
+	defer systemFontMapLock.Unlock()
+	if systemFontMap == nil {
+		return nil
+	}
+	systemFontMap.SetQuery(fontscan.Query{Families: []string{f.Family()}, Aspect: aspect})
+	fi := f.createFontWithSize(12)
+	for _, r := range shapingSampleRunes {
+		if fi.RuneToGlyph(r) != 0 {
+			return systemFontMap.ResolveFace(r)
+		}
+	}
+	return nil
+}
+
+func (f *FontFace) matchesShapingFace(face *gotext.Face) bool {
+	fi := f.createFontWithSize(12)
+	matched := false
+	for _, r := range shapingSampleRunes {
+		glyph := fi.RuneToGlyph(r)
+		gid, _ := face.NominalGlyph(r)
+		if uint16(gid) != glyph {
+			return false
+		}
+		if glyph != 0 {
+			matched = true
+		}
+	}
+	return matched
+}
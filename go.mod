@@ -5,6 +5,7 @@ go 1.27.0
 require (
 	github.com/OpenPrinting/goipp v1.2.0
 	github.com/ebitengine/purego v0.11.0-alpha.9
+	github.com/go-text/typesetting v0.3.4
 	github.com/grandcat/zeroconf v1.0.0
 	github.com/richardwilkes/canvas v0.3.0
 	github.com/richardwilkes/toolbox/v2 v2.17.0
@@ -19,7 +20,6 @@ require (
 require (
 	github.com/HugoSmits86/nativewebp v1.3.0 // indirect
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
-	github.com/go-text/typesetting v0.3.4 // indirect
 	github.com/klauspost/cpuid/v2 v2.4.0 // indirect
 	github.com/miekg/dns v1.1.73 // indirect
 	golang.org/x/term v0.45.0 // indirect
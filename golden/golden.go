@@ -0,0 +1,241 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package golden provides golden-image snapshot testing for panels. A panel is laid out and rendered on the CPU, then
+// compared against a PNG stored alongside the tests. When the comparison fails, the rendered image and an image
+// highlighting the differences are written next to the golden file for inspection.
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison"
+)
+
+// UpdateEnvKey names the environment variable that switches to "update goldens" mode. When it is set to a true value,
+// as understood by strconv.ParseBool (e.g. "1"), Check() writes the rendered image as the new golden file rather than
+// comparing against it.
+const UpdateEnvKey = "UNISON_UPDATE_GOLDENS"
+
+// DefaultDir is the directory, relative to the package being tested, that golden files are stored in when Config.Dir
+// is empty.
+const DefaultDir = "testdata/golden"
+
+// Suffixes appended to a golden file's base name for the files written when a comparison fails.
+const (
+	ActualSuffix = ".actual.png"
+	DiffSuffix   = ".diff.png"
+)
+
+// Config holds the settings used when comparing a panel against its golden image.
+type Config struct {
+	// Dir is the directory the golden files are stored in. If empty, DefaultDir is used.
+	Dir string
+	// Size is the size to lay out the panel at. If empty, the panel's preferred size is used.
+	Size geom.Size
+	// Scale is the scale the panel is rendered at. Values less than or equal to zero are treated as 1.
+	Scale float32
+	// Tolerance is the maximum amount any one color channel of a pixel may differ from the golden image before the
+	// pixel is considered mismatched.
+	Tolerance uint8
+	// MaxMismatched is the number of mismatched pixels that may be present before the comparison fails.
+	MaxMismatched int
+}
+
+// Result holds the outcome of comparing two images.
+type Result struct {
+	// Diff highlights the mismatched pixels in red over a faded copy of the expected image. It is nil if the images
+	// could not be compared because their dimensions differ.
+	Diff *image.NRGBA
+	// Mismatched is the number of pixels that differ by more than the tolerance.
+	Mismatched int
+	// MaxChannelDelta is the largest difference seen in any single color channel.
+	MaxChannelDelta uint8
+	// SizeMismatch is true if the images have different dimensions.
+	SizeMismatch bool
+}
+
+// UpdateRequested returns true if UpdateEnvKey asks for golden files to be rewritten.
+func UpdateRequested() bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(UpdateEnvKey)))
+	return err == nil && on
+}
+
+// Check renders the panel using the settings in cfg and compares the result against the golden file for name,
+// reporting a test error if they do not match. Returns true if they match. In update mode (see UpdateEnvKey) or if the
+// golden file does not exist yet, the golden file is written instead; a missing golden file is still reported as an
+// error outside of update mode, so that a forgotten golden cannot silently pass in CI.
+func (cfg Config) Check(t testing.TB, name string, paneler unison.Paneler) bool {
+	t.Helper()
+	scale := cfg.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	img, err := unison.NewImageFromPanel(paneler, cfg.Size, scale)
+	if err != nil {
+		t.Errorf("golden %q: unable to render panel: %v", name, err)
+		return false
+	}
+	defer img.Dispose()
+	got, err := img.ToNRGBA()
+	if err != nil {
+		t.Errorf("golden %q: unable to read rendered pixels: %v", name, err)
+		return false
+	}
+	return cfg.CheckImage(t, name, got)
+}
+
+// CheckImage compares the image against the golden file for name, following the same rules as Check().
+func (cfg Config) CheckImage(t testing.TB, name string, got *image.NRGBA) bool {
+	t.Helper()
+	path := cfg.path(name)
+	update := UpdateRequested()
+	if _, err := os.Stat(path); update || os.IsNotExist(err) {
+		if err = Save(path, got); err != nil {
+			t.Errorf("golden %q: %v", name, err)
+			return false
+		}
+		cfg.removeFailureArtifacts(path)
+		if !update {
+			t.Errorf("golden %q: golden file did not exist and has been created at %s", name, path)
+			return false
+		}
+		return true
+	}
+	want, err := Load(path)
+	if err != nil {
+		t.Errorf("golden %q: %v", name, err)
+		return false
+	}
+	result := Compare(got, want, cfg.Tolerance)
+	if !result.SizeMismatch && result.Mismatched <= cfg.MaxMismatched {
+		cfg.removeFailureArtifacts(path)
+		return true
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	if err = Save(base+ActualSuffix, got); err != nil {
+		t.Logf("golden %q: %v", name, err)
+	}
+	if result.SizeMismatch {
+		t.Errorf("golden %q: size mismatch: got %dx%d, want %dx%d (actual written to %s)", name, got.Rect.Dx(),
+			got.Rect.Dy(), want.Rect.Dx(), want.Rect.Dy(), base+ActualSuffix)
+		return false
+	}
+	if err = Save(base+DiffSuffix, result.Diff); err != nil {
+		t.Logf("golden %q: %v", name, err)
+	}
+	t.Errorf("golden %q: %d pixels differ by more than %d (max channel delta %d); actual written to %s, diff to %s",
+		name, result.Mismatched, cfg.Tolerance, result.MaxChannelDelta, base+ActualSuffix, base+DiffSuffix)
+	return false
+}
+
+func (cfg Config) path(name string) string {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if !strings.HasSuffix(strings.ToLower(name), ".png") {
+		name += ".png"
+	}
+	return filepath.Join(dir, name)
+}
+
+func (cfg Config) removeFailureArtifacts(path string) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, p := range []string{base + ActualSuffix, base + DiffSuffix} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			errs.Log(err, "path", p)
+		}
+	}
+}
+
+// Compare compares two images, treating pixels whose color channels all differ by no more than tolerance as matching.
+func Compare(got, want *image.NRGBA, tolerance uint8) Result {
+	var result Result
+	if got.Rect.Dx() != want.Rect.Dx() || got.Rect.Dy() != want.Rect.Dy() {
+		result.SizeMismatch = true
+		return result
+	}
+	width := want.Rect.Dx()
+	height := want.Rect.Dy()
+	result.Diff = image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			g := got.NRGBAAt(got.Rect.Min.X+x, got.Rect.Min.Y+y)
+			w := want.NRGBAAt(want.Rect.Min.X+x, want.Rect.Min.Y+y)
+			delta := max(channelDelta(g.R, w.R), channelDelta(g.G, w.G), channelDelta(g.B, w.B),
+				channelDelta(g.A, w.A))
+			result.MaxChannelDelta = max(result.MaxChannelDelta, delta)
+			if delta > tolerance {
+				result.Mismatched++
+				result.Diff.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				// Fade the expected pixel, so the mismatches stand out while the content remains recognizable.
+				result.Diff.SetNRGBA(x, y, color.NRGBA{R: w.R, G: w.G, B: w.B, A: w.A / 4})
+			}
+		}
+	}
+	return result
+}
+
+func channelDelta(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// Load reads a PNG file and returns its content as an image.NRGBA.
+func Load(path string) (*image.NRGBA, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.NewWithCause(path, err)
+	}
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba, nil
+	}
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := range bounds.Dy() {
+		for x := range bounds.Dx() {
+			nrgba.Set(x, y, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return nrgba, nil
+}
+
+// Save writes the image to a PNG file, creating any missing parent directories.
+func Save(path string, img *image.NRGBA) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errs.Wrap(err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return errs.NewWithCause(fmt.Sprintf("unable to encode %s", path), err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
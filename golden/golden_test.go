@@ -0,0 +1,149 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package golden_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/golden"
+)
+
+// recordingTB captures reported errors rather than failing the enclosing test, so that the failure paths can be
+// verified.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Logf(_ string, _ ...any) {}
+
+func solidImage(width, height int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareHonorsTolerance(t *testing.T) {
+	c := check.New(t)
+	want := solidImage(4, 4, color.NRGBA{R: 100, G: 100, B: 100, A: 255})
+	got := solidImage(4, 4, color.NRGBA{R: 103, G: 100, B: 98, A: 255})
+	got.SetNRGBA(1, 2, color.NRGBA{R: 200, G: 100, B: 100, A: 255})
+	result := golden.Compare(got, want, 3)
+	c.False(result.SizeMismatch)
+	c.Equal(1, result.Mismatched)
+	c.Equal(uint8(100), result.MaxChannelDelta)
+	c.Equal(color.NRGBA{R: 255, A: 255}, result.Diff.NRGBAAt(1, 2))
+	result = golden.Compare(got, want, 100)
+	c.Equal(0, result.Mismatched)
+	result = golden.Compare(got, want, 0)
+	c.Equal(16, result.Mismatched)
+}
+
+func TestCompareDetectsSizeMismatch(t *testing.T) {
+	c := check.New(t)
+	result := golden.Compare(solidImage(4, 4, color.NRGBA{}), solidImage(4, 5, color.NRGBA{}), 255)
+	c.True(result.SizeMismatch)
+	c.Nil(result.Diff)
+}
+
+func TestCheckImageCreatesMissingGoldenButFails(t *testing.T) {
+	c := check.New(t)
+	t.Setenv(golden.UpdateEnvKey, "")
+	cfg := golden.Config{Dir: t.TempDir()}
+	rec := &recordingTB{TB: t}
+	img := solidImage(3, 3, color.NRGBA{G: 255, A: 255})
+	c.False(cfg.CheckImage(rec, "missing", img))
+	c.Equal(1, len(rec.errors))
+	saved, err := golden.Load(filepath.Join(cfg.Dir, "missing.png"))
+	c.NoError(err)
+	c.Equal(img.Pix, saved.Pix)
+	rec.errors = nil
+	c.True(cfg.CheckImage(rec, "missing", img), "the newly created golden should now match")
+	c.Equal(0, len(rec.errors))
+}
+
+func TestCheckImageWritesArtifactsOnMismatch(t *testing.T) {
+	c := check.New(t)
+	t.Setenv(golden.UpdateEnvKey, "")
+	cfg := golden.Config{Dir: t.TempDir(), Tolerance: 2}
+	c.NoError(golden.Save(filepath.Join(cfg.Dir, "panel.png"), solidImage(3, 3, color.NRGBA{B: 255, A: 255})))
+	rec := &recordingTB{TB: t}
+	c.False(cfg.CheckImage(rec, "panel", solidImage(3, 3, color.NRGBA{R: 255, A: 255})))
+	c.Equal(1, len(rec.errors))
+	_, err := os.Stat(filepath.Join(cfg.Dir, "panel"+golden.ActualSuffix))
+	c.NoError(err)
+	diff, err := golden.Load(filepath.Join(cfg.Dir, "panel"+golden.DiffSuffix))
+	c.NoError(err)
+	c.Equal(color.NRGBA{R: 255, A: 255}, diff.NRGBAAt(0, 0))
+
+	// A subsequent successful comparison removes the stale artifacts.
+	rec.errors = nil
+	c.True(cfg.CheckImage(rec, "panel", solidImage(3, 3, color.NRGBA{G: 1, B: 254, A: 255})))
+	_, err = os.Stat(filepath.Join(cfg.Dir, "panel"+golden.ActualSuffix))
+	c.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cfg.Dir, "panel"+golden.DiffSuffix))
+	c.True(os.IsNotExist(err))
+}
+
+func TestCheckImageUpdateModeRewritesGolden(t *testing.T) {
+	c := check.New(t)
+	t.Setenv(golden.UpdateEnvKey, "1")
+	c.True(golden.UpdateRequested())
+	cfg := golden.Config{Dir: t.TempDir()}
+	path := filepath.Join(cfg.Dir, "panel.png")
+	c.NoError(golden.Save(path, solidImage(2, 2, color.NRGBA{B: 255, A: 255})))
+	rec := &recordingTB{TB: t}
+	replacement := solidImage(5, 1, color.NRGBA{R: 255, A: 255})
+	c.True(cfg.CheckImage(rec, "panel", replacement))
+	c.Equal(0, len(rec.errors))
+	saved, err := golden.Load(path)
+	c.NoError(err)
+	c.Equal(replacement.Pix, saved.Pix)
+}
+
+func TestCheckRendersPanel(t *testing.T) {
+	c := check.New(t)
+	t.Setenv(golden.UpdateEnvKey, "")
+	panel := unison.NewPanel()
+	panel.DrawCallback = func(gc *unison.Canvas, _ geom.Rect) {
+		gc.DrawRect(geom.NewRect(0, 0, 4, 4), unison.RGB(255, 0, 0).Paint(gc, geom.Rect{}, paintstyle.Fill))
+	}
+	dir := t.TempDir()
+	rendered, err := unison.NewImageFromPanel(panel, geom.NewSize(4, 4), 2)
+	c.NoError(err)
+	defer rendered.Dispose()
+	c.Equal(geom.NewSize(8, 8), rendered.Size())
+	nrgba, err := rendered.ToNRGBA()
+	c.NoError(err)
+	c.Equal(color.NRGBA{R: 255, A: 255}, nrgba.NRGBAAt(7, 7))
+	c.NoError(golden.Save(filepath.Join(dir, "red.png"), nrgba))
+	rec := &recordingTB{TB: t}
+	c.True(golden.Config{Dir: dir, Size: geom.NewSize(4, 4), Scale: 2}.Check(rec, "red", panel))
+	c.Equal(0, len(rec.errors))
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// HeadlessEnvKey names the environment variable that selects the headless backend. When it is set to a true value, as
+// understood by strconv.ParseBool (e.g. "1"), Start() never connects to the platform's windowing system: windows exist
+// only in memory, are always rendered on the CPU, and only receive input through the Simulate* methods on Window.
+//
+// This exists so that applications can be exercised end-to-end on machines without a display, such as CI servers. Tests
+// that cannot hand their main goroutine over to Start() should call StartHeadless() instead.
+const HeadlessEnvKey = "UNISON_HEADLESS"
+
+var (
+	// headlessActive is atomic because InvokeTask(), which may be called from any goroutine, consults it to decide how
+	// to wake the event loop. It is only ever set during startup.
+	headlessActive atomic.Bool
+	// headlessWake is signaled to wake the headless event loop, playing the role the platform's empty event posting
+	// plays for the other backends. It is buffered so that a wake-up posted while the loop is busy is not lost.
+	headlessWake = make(chan struct{}, 1)
+	// headlessClipboard holds the content of the in-memory clipboard used by the headless backend.
+	headlessClipboard []drag.Data
+	// headlessFocusedWindow is the window that currently holds the simulated platform focus.
+	headlessFocusedWindow *Window
+	// headlessDisplayFrame is the bounds of the single display the headless backend reports.
+	headlessDisplayFrame = geom.NewRect(0, 0, 1920, 1080)
+)
+
+// headlessWindow holds the state the platform would normally track for a window when the headless backend is active.
+type headlessWindow struct {
+	contentRect geom.Rect
+	mouse       geom.Point
+	visible     bool
+}
+
+// IsHeadlessActive returns true if the headless backend is in use, either because HeadlessEnvKey asked for it or
+// because the application was started with StartHeadless().
+func IsHeadlessActive() bool {
+	return headlessActive.Load()
+}
+
+// StartHeadless initializes the application using the headless backend and then returns, rather than servicing an
+// event loop the way Start() does. The StartupFinishedCallback, if any, is called before this function returns.
+//
+// Since no event loop is running, the caller takes its place: after performing actions that queue tasks or mark
+// windows for redraw, call ProcessHeadlessEvents() to have them run. The caller is also responsible for confining its
+// use of unison to a single goroutine at a time, just as it would be on the UI thread. InvokeTaskAndWait() runs its
+// function directly on the calling goroutine.
+//
+// Unless a QuitAfterLastWindowClosedCallback is supplied, closing the last window does not terminate the process, since
+// that would end a test run prematurely. AttemptQuit() still does.
+func StartHeadless(options ...StartupOption) error {
+	for _, option := range options {
+		if err := option(startupOption{}); err != nil {
+			return err
+		}
+	}
+	enableHeadless()
+	if err := start(); err != nil {
+		return err
+	}
+	if quitAfterLastWindowClosedCallback == nil {
+		quitAfterLastWindowClosedCallback = func() bool { return false }
+	}
+	finishStartup()
+	ProcessHeadlessEvents()
+	return nil
+}
+
+// ProcessHeadlessEvents runs the queued tasks, including any they queue in turn, and draws the windows that have been
+// marked for redraw, returning once there is nothing left to do. Tasks scheduled with InvokeTaskAfter() are only run
+// once their delay has elapsed. Does nothing if the headless backend is not active.
+func ProcessHeadlessEvents() {
+	if !headlessActive.Load() {
+		return
+	}
+	for {
+		select {
+		case <-headlessWake:
+		default:
+		}
+		finishProcessingEvents()
+		if !hasPendingTasks() {
+			return
+		}
+	}
+}
+
+// ProcessHeadlessEventsFor repeatedly calls ProcessHeadlessEvents() until the duration has elapsed, allowing tasks
+// scheduled with InvokeTaskAfter() within that window, such as tooltip display, to run. Does nothing if the headless
+// backend is not active.
+func ProcessHeadlessEventsFor(duration time.Duration) {
+	if !headlessActive.Load() {
+		return
+	}
+	deadline := time.Now().Add(duration)
+	for {
+		ProcessHeadlessEvents()
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+		select {
+		case <-headlessWake:
+		case <-time.After(remaining):
+		}
+	}
+}
+
+// applyHeadlessEnvRequest turns on the headless backend if HeadlessEnvKey asks for it. Like the CPU rendering request,
+// this runs during startup, before any window can exist.
+func applyHeadlessEnvRequest() {
+	v, ok := os.LookupEnv(HeadlessEnvKey)
+	if !ok {
+		return
+	}
+	if on, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil && on && !headlessActive.Load() {
+		enableHeadless()
+		slog.Info("headless mode was requested via the environment; no windowing system will be used", "var",
+			HeadlessEnvKey)
+	}
+}
+
+// enableHeadless switches the process to the headless backend. There is no OpenGL without a windowing system, so CPU
+// rendering is forced on, and the platform's global menu bar and file dialogs are replaced with their in-window
+// equivalents, which are made of panels and therefore work headless.
+func enableHeadless() {
+	headlessActive.Store(true)
+	cpuRenderingActive = true
+	noGlobalMenuBar = true
+	noPlatformFileDialogs = true
+}
+
+// postEmptyEvent wakes the event loop so that it will run pending tasks and redraws. It may be called from any
+// goroutine.
+func postEmptyEvent() {
+	if headlessActive.Load() {
+		select {
+		case headlessWake <- struct{}{}:
+		default:
+		}
+		return
+	}
+	apiPostEmptyEvent()
+}
+
+// waitEvents blocks until the event loop has been woken and, for the platform backends, processes the events that woke
+// it.
+func waitEvents() {
+	if headlessActive.Load() {
+		<-headlessWake
+		return
+	}
+	apiWaitEvents()
+}
+
+func headlessPrimaryDisplay() *Display {
+	return &Display{
+		Frame:   headlessDisplayFrame,
+		Usable:  headlessDisplayFrame,
+		Scale:   geom.NewPoint(1, 1),
+		PPI:     defaultDisplayPPI,
+		Primary: true,
+	}
+}
+
+func headlessNewCursor() *Cursor {
+	c := &Cursor{}
+	cursorList = append(cursorList, c)
+	return c
+}
+
+func headlessClipboardAvailableDataTypes() []string {
+	types := make([]string, 0, len(headlessClipboard))
+	for _, one := range headlessClipboard {
+		types = append(types, one.Type.UTI)
+	}
+	return types
+}
+
+func headlessClipboardGetData(dataType *uti.DataType) []byte {
+	for _, one := range headlessClipboard {
+		if one.Type.UTI == dataType.UTI {
+			return slices.Clone(one.Data)
+		}
+	}
+	return nil
+}
+
+func headlessClipboardSetData(data ...drag.Data) {
+	headlessClipboard = make([]drag.Data, 0, len(data))
+	for _, one := range data {
+		if one.Type != nil {
+			headlessClipboard = append(headlessClipboard, drag.Data{Type: one.Type, Data: slices.Clone(one.Data)})
+		}
+	}
+}
+
+func (w *Window) headlessSetContentRect(rect geom.Rect) {
+	old := w.headless.contentRect
+	w.headless.contentRect = rect
+	if old.Size != rect.Size {
+		w.resized()
+		w.MarkForRedraw()
+	}
+	if old.Point != rect.Point {
+		w.moved()
+	}
+}
+
+func (w *Window) headlessShow() {
+	if !w.headless.visible {
+		w.headless.visible = true
+		w.MarkForRedraw()
+	}
+}
+
+func (w *Window) headlessHide() {
+	if w.headless.visible {
+		w.headless.visible = false
+		if headlessFocusedWindow == w {
+			headlessFocusedWindow = nil
+			w.lostFocus()
+		}
+	}
+}
+
+// headlessAcquireFocus delivers the focus change synchronously, since there is no window manager to report it back.
+func (w *Window) headlessAcquireFocus() {
+	if headlessFocusedWindow == w {
+		return
+	}
+	if previous := headlessFocusedWindow; previous.IsValid() {
+		previous.lostFocus()
+	}
+	headlessFocusedWindow = w
+	w.gainedFocus()
+}
+
+func (w *Window) headlessDestroy() {
+	if headlessFocusedWindow == w {
+		headlessFocusedWindow = nil
+	}
+	w.headless.visible = false
+}
+
+// CaptureImage renders the current content of the window on the CPU and returns it as an image at the window's backing
+// scale. This works with any backend, though it is primarily intended for verifying the output of windows driven by the
+// headless backend.
+func (w *Window) CaptureImage() (*Image, error) {
+	if !w.IsValid() {
+		return nil, errs.New("window is not valid")
+	}
+	size := w.ContentRect().Size
+	return newImageFromDrawingAtScale(int(size.Width), int(size.Height), w.BackingScale().X, w.Draw)
+}
+
+// SimulateMouseMove delivers a synthetic mouse movement to the window. If a mouse button is down, this is delivered as
+// a drag. 'where' is in the window's root coordinate space.
+func (w *Window) SimulateMouseMove(where geom.Point, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.simulateMouseLocation(where)
+		w.mouseMovedOrDragged(where, mods)
+	}
+}
+
+// SimulateMouseDown delivers a synthetic mouse button press to the window. 'where' is in the window's root coordinate
+// space.
+func (w *Window) SimulateMouseDown(where geom.Point, button int, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.simulateMouseLocation(where)
+		w.mouseDown(where, button, mods)
+	}
+}
+
+// SimulateMouseUp delivers a synthetic mouse button release to the window. 'where' is in the window's root coordinate
+// space.
+func (w *Window) SimulateMouseUp(where geom.Point, button int, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.simulateMouseLocation(where)
+		w.mouseUp(where, button, mods)
+	}
+}
+
+// SimulateClick delivers a synthetic mouse button press followed by its release to the window. Calling it again before
+// the system's double-click interval has elapsed produces a double-click. 'where' is in the window's root coordinate
+// space.
+func (w *Window) SimulateClick(where geom.Point, button int, mods mod.Modifiers) {
+	w.SimulateMouseDown(where, button, mods)
+	w.SimulateMouseUp(where, button, mods)
+}
+
+// SimulateMouseWheel delivers a synthetic mouse wheel rotation to the window. 'where' is in the window's root
+// coordinate space.
+func (w *Window) SimulateMouseWheel(where, delta geom.Point, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.simulateMouseLocation(where)
+		w.mouseWheel(where, delta, mods)
+	}
+}
+
+// SimulateKeyDown delivers a synthetic key press to the window.
+func (w *Window) SimulateKeyDown(keyCode KeyCode, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.keyPressed(keyCode, mods)
+	}
+}
+
+// SimulateKeyUp delivers a synthetic key release to the window.
+func (w *Window) SimulateKeyUp(keyCode KeyCode, mods mod.Modifiers) {
+	if w.IsValid() {
+		w.keyReleased(keyCode, mods)
+	}
+}
+
+// SimulateKeyStroke delivers a synthetic key press followed by its release to the window.
+func (w *Window) SimulateKeyStroke(keyCode KeyCode, mods mod.Modifiers) {
+	w.SimulateKeyDown(keyCode, mods)
+	w.SimulateKeyUp(keyCode, mods)
+}
+
+// SimulateRuneTyped delivers a synthetic typed rune to the window.
+func (w *Window) SimulateRuneTyped(ch rune) {
+	if w.IsValid() {
+		w.runeTyped(ch)
+	}
+}
+
+// SimulateTyping delivers each rune of the text to the window as if it had been typed.
+func (w *Window) SimulateTyping(text string) {
+	for _, ch := range text {
+		w.SimulateRuneTyped(ch)
+	}
+}
+
+// SimulateComposition delivers a synthetic update of an input method's in-progress text to the window, as happens
+// while a dead key sequence or a phonetic spelling is being typed. caret is the caret position within the text, in
+// runes. Empty text abandons the composition.
+func (w *Window) SimulateComposition(text string, caret int) {
+	if w.IsValid() {
+		w.compositionUpdated(text, caret)
+	}
+}
+
+// SimulateCompositionCommit delivers synthetic text produced by an input method to the window, ending any composition
+// in progress.
+func (w *Window) SimulateCompositionCommit(text string) {
+	if w.IsValid() {
+		w.compositionCommitted(text)
+	}
+}
+
+// simulateMouseLocation records the location of a synthetic mouse event, so that later queries for the mouse location
+// observe it. Only the headless backend has a mouse that can be moved this way.
+func (w *Window) simulateMouseLocation(where geom.Point) {
+	if w.headless != nil {
+		w.headless.mouse = where
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// enableHeadlessForTest switches the process to the headless backend for the duration of the test, restoring the
+// previous global state when the test completes. StartHeadless() is deliberately not used, since it may only be called
+// once per process and would also run the full startup sequence. The pending redraw set and task queue are swapped out
+// as well, so that leftovers from other tests, which may refer to windows that have no platform resources, are never
+// processed here.
+func enableHeadlessForTest(t *testing.T) {
+	t.Helper()
+	savedHeadless := headlessActive.Load()
+	savedCPU := cpuRenderingActive
+	savedNoGlobalMenuBar := noGlobalMenuBar
+	savedNoPlatformFileDialogs := noPlatformFileDialogs
+	savedClipboard := headlessClipboard
+	savedFocused := headlessFocusedWindow
+	savedQuitAfter := quitAfterLastWindowClosedCallback
+	savedRedrawSet := redrawSet
+	taskQueueLock.Lock()
+	savedTaskQueue := taskQueue
+	savedTaskQueueHead := taskQueueHead
+	taskQueue = nil
+	taskQueueHead = 0
+	taskQueueLock.Unlock()
+	redrawSet = make(map[*Window]struct{})
+	quitAfterLastWindowClosedCallback = func() bool { return false }
+	enableHeadless()
+	t.Cleanup(func() {
+		headlessActive.Store(savedHeadless)
+		cpuRenderingActive = savedCPU
+		noGlobalMenuBar = savedNoGlobalMenuBar
+		noPlatformFileDialogs = savedNoPlatformFileDialogs
+		headlessClipboard = savedClipboard
+		headlessFocusedWindow = savedFocused
+		quitAfterLastWindowClosedCallback = savedQuitAfter
+		redrawSet = savedRedrawSet
+		taskQueueLock.Lock()
+		taskQueue = savedTaskQueue
+		taskQueueHead = savedTaskQueueHead
+		taskQueueLock.Unlock()
+	})
+}
+
+// newHeadlessTestWindow creates a visible, focused headless window with the given content, sized to 100x100.
+func newHeadlessTestWindow(t *testing.T, content Paneler) *Window {
+	t.Helper()
+	w, err := NewWindow("headless")
+	check.New(t).NoError(err)
+	t.Cleanup(w.Dispose)
+	w.SetContent(content)
+	w.SetContentRect(geom.NewRect(10, 10, 100, 100))
+	w.ToFront()
+	return w
+}
+
+func TestHeadlessWindowHasNoPlatformResources(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	w := newHeadlessTestWindow(t, NewPanel())
+	c.NotNil(w.headless)
+	c.True(w.IsVisible())
+	c.True(w.Focused())
+	c.Equal(w, ActiveWindow())
+	c.Equal(geom.NewRect(10, 10, 100, 100), w.ContentRect())
+	c.Equal(w.ContentRect(), w.FrameRect())
+	c.Equal(geom.NewPoint(1, 1), w.BackingScale())
+	w.Hide()
+	c.False(w.IsVisible())
+	c.False(w.Focused())
+}
+
+func TestHeadlessResizeNotifiesWindow(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	w := newHeadlessTestWindow(t, NewPanel())
+	var resized, moved int
+	w.ResizedCallback = func() { resized++ }
+	w.MovedCallback = func() { moved++ }
+	w.SetContentRect(geom.NewRect(10, 10, 200, 150))
+	c.Equal(1, resized)
+	c.Equal(0, moved)
+	c.Equal(geom.NewSize(200, 150), w.Content().FrameRect().Size)
+	w.SetContentRect(geom.NewRect(20, 30, 200, 150))
+	c.Equal(1, resized)
+	c.Equal(1, moved)
+}
+
+func TestHeadlessSimulatedMouseReachesPanel(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	child := NewPanel()
+	child.SetFrameRect(geom.NewRect(20, 20, 40, 40))
+	content.AddChild(child)
+	var downs []geom.Point
+	var counts []int
+	var ups, wheels int
+	child.MouseDownCallback = func(where geom.Point, _, clickCount int, _ mod.Modifiers) bool {
+		downs = append(downs, where)
+		counts = append(counts, clickCount)
+		return true
+	}
+	child.MouseUpCallback = func(_ geom.Point, _ int, _ mod.Modifiers) bool {
+		ups++
+		return true
+	}
+	child.MouseWheelCallback = func(_, _ geom.Point, _ mod.Modifiers) bool {
+		wheels++
+		return true
+	}
+	w := newHeadlessTestWindow(t, content)
+	w.SimulateClick(geom.NewPoint(25, 30), ButtonLeft, 0)
+	w.SimulateClick(geom.NewPoint(25, 30), ButtonLeft, 0)
+	c.Equal([]geom.Point{geom.NewPoint(5, 10), geom.NewPoint(5, 10)}, downs)
+	c.Equal([]int{1, 2}, counts, "a second click within the interval must register as a double-click")
+	c.Equal(2, ups)
+	c.Equal(geom.NewPoint(25, 30), w.MouseLocation())
+	w.SimulateMouseWheel(geom.NewPoint(30, 30), geom.NewPoint(0, -1), 0)
+	c.Equal(1, wheels)
+	// Outside the child, so nothing should be delivered to it.
+	w.SimulateClick(geom.NewPoint(90, 90), ButtonLeft, 0)
+	c.Equal(2, len(downs))
+}
+
+func TestHeadlessSimulatedKeysReachFocus(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	content.SetFocusable(true)
+	var keys []KeyCode
+	var typed []rune
+	var released int
+	content.KeyDownCallback = func(keyCode KeyCode, _ mod.Modifiers, _ bool) bool {
+		keys = append(keys, keyCode)
+		return true
+	}
+	content.KeyUpCallback = func(_ KeyCode, _ mod.Modifiers) bool {
+		released++
+		return true
+	}
+	content.RuneTypedCallback = func(ch rune) bool {
+		typed = append(typed, ch)
+		return true
+	}
+	w := newHeadlessTestWindow(t, content)
+	content.RequestFocus()
+	w.SimulateKeyStroke(KeyA, mod.Shift)
+	w.SimulateTyping("hé")
+	c.Equal([]KeyCode{KeyA}, keys)
+	c.Equal(1, released)
+	c.Equal([]rune{'h', 'é'}, typed)
+	c.Equal(mod.Shift, w.CurrentKeyModifiers())
+}
+
+func TestHeadlessFocusMovesBetweenWindows(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	first := newHeadlessTestWindow(t, NewPanel())
+	second := newHeadlessTestWindow(t, NewPanel())
+	c.False(first.Focused())
+	c.True(second.Focused())
+	c.Equal(second, ActiveWindow())
+	first.ToFront()
+	c.True(first.Focused())
+	c.False(second.Focused())
+	c.Equal(first, ActiveWindow())
+}
+
+func TestHeadlessInvokeTaskRunsOnProcess(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	var order []int
+	InvokeTask(func() {
+		order = append(order, 1)
+		InvokeTask(func() { order = append(order, 3) })
+	})
+	InvokeTask(func() { order = append(order, 2) })
+	c.Equal(0, len(order))
+	ProcessHeadlessEvents()
+	c.Equal([]int{1, 2, 3}, order)
+	c.False(hasPendingTasks())
+}
+
+func TestHeadlessProcessDrawsMarkedWindows(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	var draws int
+	content.DrawCallback = func(_ *Canvas, _ geom.Rect) { draws++ }
+	w := newHeadlessTestWindow(t, content)
+	ProcessHeadlessEvents()
+	c.Equal(1, draws)
+	w.MarkForRedraw()
+	ProcessHeadlessEvents()
+	c.Equal(2, draws)
+	ProcessHeadlessEvents()
+	c.Equal(2, draws, "nothing was marked, so nothing should be drawn")
+}
+
+func TestHeadlessClipboardIsInMemory(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	ClipboardSetText("hello")
+	c.True(ClipboardHasText())
+	c.Equal("hello", ClipboardGetText())
+	ClipboardSetData()
+	c.False(ClipboardHasText())
+	c.Equal("", ClipboardGetText())
+}
+
+func TestHeadlessCaptureImage(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	content := NewPanel()
+	content.DrawCallback = func(gc *Canvas, _ geom.Rect) {
+		gc.DrawRect(geom.NewRect(0, 0, 50, 100), RGB(255, 0, 0).Paint(gc, geom.Rect{}, paintstyle.Fill))
+	}
+	w := newHeadlessTestWindow(t, content)
+	img, err := w.CaptureImage()
+	c.NoError(err)
+	defer img.Dispose()
+	c.Equal(geom.NewSize(100, 100), img.Size())
+	nrgba, err := img.ToNRGBA()
+	c.NoError(err)
+	left := nrgba.NRGBAAt(10, 50)
+	c.Equal(uint8(255), left.R)
+	c.Equal(uint8(0), left.G)
+	c.Equal(uint8(0), left.B)
+	right := nrgba.NRGBAAt(90, 50)
+	c.False(right.R == 255 && right.G == 0 && right.B == 0, "the right half must not have been painted red")
+}
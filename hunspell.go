@@ -0,0 +1,501 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+var _ SpellChecker = &HunspellDictionary{}
+
+// defaultHunspellTry holds the characters tried when making suggestions for a dictionary that doesn't specify its own.
+const defaultHunspellTry = "etaoinshrdlcumwfgypbvkjxqzETAOINSHRDLCUMWFGYPBVKJXQZ'"
+
+// HunspellDictionary is a SpellChecker that uses a dictionary in the Hunspell format, which consists of an affix file
+// (.aff) and a word list (.dic). Such dictionaries are freely available for most languages. The commonly used subset
+// of the format is supported: prefix and suffix rules, including their combination; the NOSUGGEST, FORBIDDENWORD and
+// NEEDAFFIX flags; the TRY and REP suggestion hints; each of the FLAG types; and the UTF-8 and ISO8859-1 encodings.
+// Compound words and affixes that permit further affixes are not supported.
+type HunspellDictionary struct {
+	words     map[string][]string
+	added     map[string]bool
+	prefixes  map[string][]*hunspellAffix
+	suffixes  map[string][]*hunspellAffix
+	rep       [][2]string
+	try       []rune
+	flagType  string
+	noSuggest string
+	forbidden string
+	needAffix string
+	lock      sync.RWMutex
+}
+
+type hunspellAffix struct {
+	flag      string
+	strip     string
+	condition []hunspellCondition
+	cross     bool
+}
+
+// hunspellCondition matches a single rune of an affix condition.
+type hunspellCondition struct {
+	runes  []rune
+	any    bool
+	negate bool
+}
+
+// LoadHunspellDictionary loads a Hunspell dictionary from its affix (.aff) and word list (.dic) files.
+func LoadHunspellDictionary(affPath, dicPath string) (*HunspellDictionary, error) {
+	aff, err := os.Open(affPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer func() { _ = aff.Close() }() //nolint:errcheck // Only reading, so nothing useful can be done with an error
+	var dic *os.File
+	if dic, err = os.Open(dicPath); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer func() { _ = dic.Close() }() //nolint:errcheck // Only reading, so nothing useful can be done with an error
+	return NewHunspellDictionary(aff, dic)
+}
+
+// NewHunspellDictionary creates a new Hunspell dictionary from the content of its affix (.aff) and word list (.dic)
+// files.
+func NewHunspellDictionary(aff, dic io.Reader) (*HunspellDictionary, error) {
+	affData, err := io.ReadAll(aff)
+	if err != nil {
+		return nil, errs.NewWithCause("unable to read affix data", err)
+	}
+	var dicData []byte
+	if dicData, err = io.ReadAll(dic); err != nil {
+		return nil, errs.NewWithCause("unable to read word list", err)
+	}
+	var decode func([]byte) string
+	if decode, err = hunspellDecoder(affData); err != nil {
+		return nil, err
+	}
+	d := &HunspellDictionary{
+		words:    make(map[string][]string),
+		added:    make(map[string]bool),
+		prefixes: make(map[string][]*hunspellAffix),
+		suffixes: make(map[string][]*hunspellAffix),
+	}
+	d.parseAffixes(decode(affData))
+	d.parseWords(decode(dicData))
+	if len(d.try) == 0 {
+		d.try = []rune(defaultHunspellTry)
+	}
+	return d, nil
+}
+
+// hunspellDecoder returns a function that converts the dictionary's text to a string, based on the encoding named by
+// the SET directive of the affix data.
+func hunspellDecoder(affData []byte) (func([]byte) string, error) {
+	encoding := "UTF8"
+	for line := range bytes.Lines(affData) {
+		if fields := bytes.Fields(line); len(fields) > 1 && string(fields[0]) == "SET" {
+			encoding = strings.NewReplacer("-", "", "_", "").Replace(strings.ToUpper(string(fields[1])))
+			break
+		}
+	}
+	switch encoding {
+	case "UTF8":
+		return func(data []byte) string { return string(bytes.TrimPrefix(data, []byte("\uFEFF"))) }, nil
+	case "ISO88591":
+		return func(data []byte) string {
+			runes := make([]rune, len(data))
+			for i, b := range data {
+				runes[i] = rune(b)
+			}
+			return string(runes)
+		}, nil
+	default:
+		return nil, errs.Newf("unsupported dictionary encoding: %s", encoding)
+	}
+}
+
+func (d *HunspellDictionary) parseAffixes(data string) {
+	cross := make(map[string]bool)
+	for line := range strings.Lines(data) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG":
+			d.flagType = fields[1]
+		case "TRY":
+			d.try = []rune(fields[1])
+		case "NOSUGGEST":
+			d.noSuggest = d.firstFlag(fields[1])
+		case "FORBIDDENWORD":
+			d.forbidden = d.firstFlag(fields[1])
+		case "NEEDAFFIX":
+			d.needAffix = d.firstFlag(fields[1])
+		case "REP":
+			if len(fields) > 2 {
+				d.rep = append(d.rep, [2]string{
+					strings.ReplaceAll(fields[1], "_", " "),
+					strings.ReplaceAll(fields[2], "_", " "),
+				})
+			}
+		case "PFX", "SFX":
+			if len(fields) < 4 {
+				continue
+			}
+			if _, err := strconv.Atoi(fields[3]); err == nil && (fields[2] == "Y" || fields[2] == "N") {
+				cross[fields[1]] = fields[2] == "Y"
+				continue
+			}
+			affix := &hunspellAffix{
+				flag:  fields[1],
+				strip: hunspellZero(fields[2]),
+				cross: cross[fields[1]],
+			}
+			text, _, _ := strings.Cut(fields[3], "/")
+			text = hunspellZero(text)
+			if len(fields) > 4 {
+				affix.condition = parseHunspellCondition(fields[4])
+			}
+			if fields[0] == "PFX" {
+				d.prefixes[text] = append(d.prefixes[text], affix)
+			} else {
+				d.suffixes[text] = append(d.suffixes[text], affix)
+			}
+		default:
+		}
+	}
+}
+
+func hunspellZero(s string) string {
+	if s == "0" {
+		return ""
+	}
+	return s
+}
+
+func parseHunspellCondition(pattern string) []hunspellCondition {
+	if pattern == "." {
+		return nil
+	}
+	var conditions []hunspellCondition
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			conditions = append(conditions, hunspellCondition{any: true})
+		case '[':
+			var c hunspellCondition
+			i++
+			if i < len(runes) && runes[i] == '^' {
+				c.negate = true
+				i++
+			}
+			for i < len(runes) && runes[i] != ']' {
+				c.runes = append(c.runes, runes[i])
+				i++
+			}
+			conditions = append(conditions, c)
+		default:
+			conditions = append(conditions, hunspellCondition{runes: []rune{runes[i]}})
+		}
+	}
+	return conditions
+}
+
+func (c hunspellCondition) matches(r rune) bool {
+	return c.any || slices.Contains(c.runes, r) != c.negate
+}
+
+// matches returns true if the stem satisfies the affix's condition, which applies to the start of the stem for a
+// prefix and to the end of it for a suffix.
+func (a *hunspellAffix) matches(stem string, prefix bool) bool {
+	if len(a.condition) == 0 {
+		return true
+	}
+	runes := []rune(stem)
+	if len(runes) < len(a.condition) {
+		return false
+	}
+	if !prefix {
+		runes = runes[len(runes)-len(a.condition):]
+	}
+	for i, c := range a.condition {
+		if !c.matches(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *HunspellDictionary) parseWords(data string) {
+	first := true
+	for line := range strings.Lines(data) {
+		line = strings.TrimSpace(line)
+		if first {
+			first = false
+			if _, err := strconv.Atoi(line); err == nil {
+				continue
+			}
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		entry := fields[0]
+		var word, flags string
+		for i := 0; i < len(entry); i++ {
+			if entry[i] == '\\' && i+1 < len(entry) && entry[i+1] == '/' {
+				i++
+			} else if entry[i] == '/' {
+				word = entry[:i]
+				flags = entry[i+1:]
+				break
+			}
+		}
+		if word == "" {
+			word = entry
+		}
+		word = strings.ReplaceAll(word, `\/`, "/")
+		for _, flag := range d.parseFlags(flags) {
+			if !slices.Contains(d.words[word], flag) {
+				d.words[word] = append(d.words[word], flag)
+			}
+		}
+		if _, exists := d.words[word]; !exists {
+			d.words[word] = nil
+		}
+	}
+}
+
+func (d *HunspellDictionary) parseFlags(flags string) []string {
+	if flags == "" {
+		return nil
+	}
+	var result []string
+	switch d.flagType {
+	case "long":
+		runes := []rune(flags)
+		for i := 0; i+1 < len(runes); i += 2 {
+			result = append(result, string(runes[i:i+2]))
+		}
+	case "num":
+		for flag := range strings.SplitSeq(flags, ",") {
+			result = append(result, strings.TrimSpace(flag))
+		}
+	default:
+		for _, r := range flags {
+			result = append(result, string(r))
+		}
+	}
+	return result
+}
+
+func (d *HunspellDictionary) firstFlag(flags string) string {
+	if parsed := d.parseFlags(flags); len(parsed) != 0 {
+		return parsed[0]
+	}
+	return ""
+}
+
+// Check implements SpellChecker. Capitalized and upper-case words are also accepted if the dictionary has them in
+// lower case, as are upper-case words the dictionary has capitalized.
+func (d *HunspellDictionary) Check(word string) bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.check(word)
+}
+
+func (d *HunspellDictionary) check(word string) bool {
+	word = strings.ReplaceAll(word, "’", "'")
+	if d.checkWord(word) {
+		return true
+	}
+	lower := strings.ToLower(word)
+	if lower == word {
+		return false
+	}
+	if strings.ToUpper(word) == word {
+		if d.checkWord(lower) {
+			return true
+		}
+		r, size := utf8.DecodeRuneInString(lower)
+		return d.checkWord(string(unicode.ToUpper(r)) + lower[size:])
+	}
+	_, size := utf8.DecodeRuneInString(word)
+	return strings.ToLower(word[size:]) == word[size:] && d.checkWord(lower)
+}
+
+// checkWord checks the word exactly as given, both as it appears in the dictionary and with affixes removed.
+func (d *HunspellDictionary) checkWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	if d.added[word] {
+		return true
+	}
+	if flags, ok := d.words[word]; ok {
+		if d.forbidden != "" && slices.Contains(flags, d.forbidden) {
+			return false
+		}
+		if d.needAffix == "" || !slices.Contains(flags, d.needAffix) {
+			return true
+		}
+	}
+	for i := len(word); i > 0; i-- {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, sfx := range d.suffixes[word[i:]] {
+			stem := word[:i] + sfx.strip
+			if !sfx.matches(stem, false) {
+				continue
+			}
+			if d.hasFlags(stem, sfx.flag, "") || (sfx.cross && d.checkPrefixed(stem, sfx)) {
+				return true
+			}
+		}
+	}
+	return d.checkPrefixed(word, nil)
+}
+
+// checkPrefixed checks the word with a prefix removed. If sfx isn't nil, a suffix has already been removed and both
+// it and the prefix must be permitted to combine.
+func (d *HunspellDictionary) checkPrefixed(word string, sfx *hunspellAffix) bool {
+	for i := range word {
+		for _, pfx := range d.prefixes[word[:i]] {
+			if sfx != nil && !pfx.cross {
+				continue
+			}
+			stem := pfx.strip + word[i:]
+			if !pfx.matches(stem, true) {
+				continue
+			}
+			suffixFlag := ""
+			if sfx != nil {
+				suffixFlag = sfx.flag
+			}
+			if d.hasFlags(stem, pfx.flag, suffixFlag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasFlags returns true if the stem is in the dictionary with the given flags, which are ignored when empty, and isn't
+// forbidden.
+func (d *HunspellDictionary) hasFlags(stem, flag1, flag2 string) bool {
+	flags, ok := d.words[stem]
+	if !ok || (d.forbidden != "" && slices.Contains(flags, d.forbidden)) {
+		return false
+	}
+	return (flag1 == "" || slices.Contains(flags, flag1)) && (flag2 == "" || slices.Contains(flags, flag2))
+}
+
+// Suggest implements SpellChecker. Candidates are formed by applying the dictionary's REP replacements, swapping
+// adjacent characters, removing, replacing or inserting a character, and splitting the word in two.
+func (d *HunspellDictionary) Suggest(word string, limit int) []string {
+	if limit < 1 {
+		return nil
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	var result []string
+	seen := map[string]bool{word: true}
+	consider := func(candidate string) bool {
+		if !seen[candidate] {
+			seen[candidate] = true
+			if d.suggestible(candidate) {
+				result = append(result, candidate)
+			}
+		}
+		return len(result) >= limit
+	}
+	for _, rep := range d.rep {
+		for i := 0; ; {
+			j := strings.Index(word[i:], rep[0])
+			if j < 0 {
+				break
+			}
+			i += j
+			if consider(word[:i] + rep[1] + word[i+len(rep[0]):]) {
+				return result
+			}
+			i += len(rep[0])
+		}
+	}
+	runes := []rune(word)
+	for i := 0; i+1 < len(runes); i++ {
+		candidate := slices.Clone(runes)
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		if consider(string(candidate)) {
+			return result
+		}
+	}
+	for i := range runes {
+		if consider(string(runes[:i]) + string(runes[i+1:])) {
+			return result
+		}
+	}
+	for i := range runes {
+		for _, r := range d.try {
+			if r != runes[i] && consider(string(runes[:i])+string(r)+string(runes[i+1:])) {
+				return result
+			}
+		}
+	}
+	for i := 0; i <= len(runes); i++ {
+		for _, r := range d.try {
+			if consider(string(runes[:i]) + string(r) + string(runes[i:])) {
+				return result
+			}
+		}
+	}
+	for i := 1; i < len(runes); i++ {
+		if consider(string(runes[:i]) + " " + string(runes[i:])) {
+			return result
+		}
+	}
+	return result
+}
+
+// suggestible returns true if each of the space-separated words of the candidate is spelled correctly and none of them
+// has been marked as one that shouldn't be suggested.
+func (d *HunspellDictionary) suggestible(candidate string) bool {
+	for part := range strings.SplitSeq(candidate, " ") {
+		if !d.check(part) {
+			return false
+		}
+		if d.noSuggest != "" {
+			if flags, ok := d.words[part]; ok && slices.Contains(flags, d.noSuggest) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Add implements SpellChecker. Words added this way are kept in memory only.
+func (d *HunspellDictionary) Add(word string) {
+	d.lock.Lock()
+	d.added[strings.ReplaceAll(word, "’", "'")] = true
+	d.lock.Unlock()
+}
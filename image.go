@@ -28,6 +28,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/xhash"
 	"github.com/richardwilkes/toolbox/v2/xhttp"
 	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/paintstyle"
 	"github.com/zeebo/xxh3"
 )
 
@@ -141,6 +142,30 @@ func NewImageFromDrawing(width, height, ppi int, draw func(*Canvas)) (*Image, er
 	return newImageFromDrawingAtScale(width, height, float32(ppi)/72, draw)
 }
 
+// NewImageFromPanel lays out the panel at the given size and then draws it, along with its children, into a new image
+// at the given scale, rendering on the CPU. If size is empty, the panel's preferred size is used instead. The panel's
+// background is first filled with ThemeSurface, just as a window does, so the result matches what would be shown on
+// screen. The panel's frame is changed to the requested size, so this should not be used on a panel that is currently
+// part of a window.
+func NewImageFromPanel(paneler Paneler, size geom.Size, scale float32) (*Image, error) {
+	if scale <= 0 {
+		return nil, errs.New("invalid scale")
+	}
+	p := paneler.AsPanel()
+	if size.Width <= 0 || size.Height <= 0 {
+		_, size, _ = p.Sizes(geom.Size{})
+	}
+	size = size.Ceil()
+	RebuildDynamicColors()
+	p.SetFrameRect(geom.Rect{Size: size})
+	p.ValidateLayout()
+	return newImageFromDrawingAtScale(int(size.Width), int(size.Height), scale, func(c *Canvas) {
+		r := geom.Rect{Size: size}
+		c.DrawRect(r, ThemeSurface.Paint(c, r, paintstyle.Fill))
+		p.Draw(c, p.ContentRect(true))
+	})
+}
+
 // newImageFromDrawingAtScale creates a new image by drawing into it at the given scale. This exists so that callers
 // which know their exact scaling factor, which may be fractional (e.g. per-monitor DPI scaling), can rasterize at that
 // scale rather than having it quantized by passing it through the integer ppi parameter of NewImageFromDrawing.
@@ -84,3 +84,7 @@ func (f *IndirectFont) TextBlobPosH(glyphs []uint16, positions []float32, y floa
 func (f *IndirectFont) canvasFont() *font.Font {
 	return f.Font.canvasFont()
 }
+
+func (f *IndirectFont) emSize() float32 {
+	return f.Font.emSize()
+}
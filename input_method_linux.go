@@ -0,0 +1,177 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/internal/x11"
+)
+
+// Text composition on X11 comes from one of two sources. If the user has an input method framework (IBus or Fcitx)
+// running, every key destined for a panel that accepts composition is first offered to it and it takes care of
+// everything, including compose sequences. Otherwise, compose and dead key sequences are handled locally by
+// x11Composer. All of this state is UI-thread-only.
+var (
+	x11InputMethod      *x11.InputMethod
+	x11InputMethodFocus *Window
+	x11Composer         x11.Composer
+)
+
+// x11StartInputMethod connects to the input method framework in the background, since doing so involves several D-Bus
+// round trips. Until the connection is made, keys are handled as if there were no input method.
+func x11StartInputMethod() {
+	if !x11.InputMethodRequested() {
+		return
+	}
+	go func() {
+		im, err := x11.OpenInputMethod(xos.AppName, func() { InvokeTask(x11ProcessInputMethodEvents) })
+		if err != nil {
+			slog.Info("unable to connect to the input method; only compose sequences will be available", "error", err)
+			return
+		}
+		InvokeTask(func() {
+			if x11Conn == nil {
+				im.Close()
+				return
+			}
+			x11InputMethod = im
+			if w := ActiveWindow(); w != nil && w.focused {
+				x11InputMethodFocusIn(w)
+			}
+		})
+	}()
+}
+
+func x11StopInputMethod() {
+	if x11InputMethod != nil {
+		x11InputMethod.Close()
+		x11InputMethod = nil
+	}
+	x11InputMethodFocus = nil
+}
+
+func x11InputMethodFocusIn(w *Window) {
+	x11Composer.Reset()
+	if x11InputMethod != nil && x11InputMethodFocus != w {
+		x11InputMethodFocus = w
+		x11InputMethod.FocusIn()
+		if r := w.lastCompositionCaretRect; !r.Empty() {
+			w.apiSetCompositionCaretRect(r)
+		}
+	}
+}
+
+func x11InputMethodFocusOut(w *Window) {
+	x11Composer.Reset()
+	if x11InputMethodFocus == w {
+		x11InputMethodFocus = nil
+		if x11InputMethod != nil {
+			x11InputMethod.FocusOut()
+		}
+	}
+}
+
+// x11ProcessInputMethodEvents delivers the events the input method has sent to the window that has its focus.
+func x11ProcessInputMethodEvents() {
+	if x11InputMethod == nil {
+		return
+	}
+	for _, event := range x11InputMethod.TakeEvents() {
+		w := x11InputMethodFocus
+		if !w.IsValid() {
+			continue
+		}
+		switch event.Kind {
+		case x11.InputMethodPreedit:
+			w.compositionUpdated(event.Text, event.Caret)
+		case x11.InputMethodCommit:
+			w.compositionCommitted(event.Text)
+		case x11.InputMethodForwardKey, x11.InputMethodUnhandledKey:
+			w.x11DeliverKey(byte(event.KeyCode), uint16(event.State), event.KeySym, event.Release)
+		}
+	}
+}
+
+// x11HandleKey processes a key event from the X server, giving the input method or the compose machinery first crack
+// at it. The input method is only consulted when the focused panel accepts composition, and its answer isn't waited
+// for: a key it doesn't consume comes back through x11ProcessInputMethodEvents(), in order with the text it produces.
+func (w *Window) x11HandleKey(detail byte, state uint16, release bool) {
+	mods := x11TranslateModifierState(state)
+	keySym := x11ScanCodeToKeySym(uint16(detail), mods, state&x11Mod5Mask != 0)
+	if x11InputMethod != nil && x11InputMethodFocus == w && (w.composing || w.acceptsComposition()) {
+		if x11InputMethod.ProcessKey(keySym, uint32(detail), uint32(state), release) {
+			return
+		}
+	} else if !release && w.x11Compose(keySym, mods) {
+		return
+	}
+	w.x11DeliverKey(detail, state, keySym, release)
+}
+
+// x11Compose feeds a key press to the compose machinery, returning true if it was consumed.
+func (w *Window) x11Compose(keySym uint32, mods mod.Modifiers) bool {
+	if !x11Composer.Composing() && mods&(mod.Control|mod.Option|mod.Command) != 0 {
+		return false
+	}
+	ch := x11KeySymToUnicode(keySym)
+	if ch == utf8.RuneError {
+		ch = 0
+	}
+	status, text := x11Composer.Feed(keySym, ch)
+	switch status {
+	case x11.ComposeInProgress:
+		preedit := x11Composer.Preedit()
+		w.compositionUpdated(preedit, utf8.RuneCountInString(preedit))
+	case x11.ComposeFinished:
+		w.compositionCommitted(text)
+	case x11.ComposeCancelled:
+		w.compositionCommitted("")
+	default:
+		return false
+	}
+	return true
+}
+
+func (w *Window) x11DeliverKey(detail byte, state uint16, keySym uint32, release bool) {
+	mods := x11TranslateModifierState(state)
+	keyCode := rawScanCodeToKeyCodeMap[uint16(detail)]
+	if release {
+		w.keyReleased(keyCode, mods)
+		return
+	}
+	w.keyPressed(keyCode, mods)
+	if mods&(mod.Control|mod.Option|mod.Command) == 0 {
+		if ch := x11KeySymToUnicode(keySym); ch != utf8.RuneError {
+			w.runeTyped(ch)
+		}
+	}
+}
+
+func (w *Window) apiSetCompositionCaretRect(rect geom.Rect) {
+	if x11InputMethod == nil || x11InputMethodFocus != w {
+		return
+	}
+	content := w.ContentRect()
+	scale := w.BackingScale()
+	x11InputMethod.SetCursorLocation(int32((content.X+rect.X)*scale.X), int32((content.Y+rect.Y)*scale.Y),
+		int32(rect.Width*scale.X), int32(rect.Height*scale.Y))
+}
+
+func (w *Window) apiCancelComposition() {
+	x11Composer.Reset()
+	if x11InputMethod != nil && x11InputMethodFocus == w {
+		x11InputMethod.Reset()
+	}
+}
@@ -0,0 +1,1091 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"errors"
+	"math/bits"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file implements the application side of AT-SPI2, the D-Bus protocol through which screen readers such as Orca
+// inspect and follow applications on Linux. The application connects to a dedicated accessibility bus, embeds its root
+// object with the registry and then answers calls made against the object paths of its accessible objects, emitting
+// signals as they change. The objects themselves are described by an AccessibleProvider.
+
+// Object paths used by the bridge. Every accessible object must have a path that starts with AccessiblePathPrefix.
+const (
+	AccessiblePathPrefix = "/org/a11y/atspi/accessible/"
+	AccessibleRootPath   = AccessiblePathPrefix + "root"
+)
+
+const (
+	atspiNullPath                = "/org/a11y/atspi/null"
+	atspiRegistryService         = "org.a11y.atspi.Registry"
+	atspiAccessibleInterface     = "org.a11y.atspi.Accessible"
+	atspiActionInterface         = "org.a11y.atspi.Action"
+	atspiApplicationInterface    = "org.a11y.atspi.Application"
+	atspiComponentInterface      = "org.a11y.atspi.Component"
+	atspiTextInterface           = "org.a11y.atspi.Text"
+	atspiValueInterface          = "org.a11y.atspi.Value"
+	atspiObjectEventInterface    = "org.a11y.atspi.Event.Object"
+	atspiWindowEventInterface    = "org.a11y.atspi.Event.Window"
+	atspiVersion                 = "2.1"
+	atspiEventSignature          = "siiva{sv}"
+	atspiLayerWidget             = 3
+	atspiCoordTypeWindow         = 1
+	atspiCoordTypeParent         = 2
+	atspiCallTimeout             = 2 * time.Second
+	dbusPropertiesInterface      = "org.freedesktop.DBus.Properties"
+	dbusErrorUnknownMethod       = "org.freedesktop.DBus.Error.UnknownMethod"
+	dbusErrorUnknownObject       = "org.freedesktop.DBus.Error.UnknownObject"
+	dbusErrorUnknownProperty     = "org.freedesktop.DBus.Error.UnknownProperty"
+	dbusErrorInvalidArgs         = "org.freedesktop.DBus.Error.InvalidArgs"
+	atspiToolkitName             = "Unison"
+	atspiTextBoundaryChar        = 0
+	atspiTextBoundaryWordEnd     = 2
+	atspiTextBoundarySentenceEnd = 4
+	atspiTextGranularityChar     = 0
+	atspiTextGranularityWord     = 1
+	atspiTextGranularitySentence = 2
+	atspiTextGranularityLine     = 3
+)
+
+// AccessibleRole identifies the kind of an accessible object, using the values defined by AT-SPI.
+type AccessibleRole uint32
+
+// Possible AccessibleRole values. Only the roles Unison makes use of are defined.
+const (
+	AccessibleRoleCalendar          AccessibleRole = 5
+	AccessibleRoleCheckBox          AccessibleRole = 7
+	AccessibleRoleComboBox          AccessibleRole = 11
+	AccessibleRoleDateEditor        AccessibleRole = 12
+	AccessibleRoleDialog            AccessibleRole = 16
+	AccessibleRoleFrame             AccessibleRole = 23
+	AccessibleRoleImage             AccessibleRole = 27
+	AccessibleRoleLabel             AccessibleRole = 29
+	AccessibleRoleListItem          AccessibleRole = 32
+	AccessibleRoleMenu              AccessibleRole = 33
+	AccessibleRoleMenuBar           AccessibleRole = 34
+	AccessibleRoleMenuItem          AccessibleRole = 35
+	AccessibleRolePageTab           AccessibleRole = 37
+	AccessibleRolePageTabList       AccessibleRole = 38
+	AccessibleRolePanel             AccessibleRole = 39
+	AccessibleRolePasswordText      AccessibleRole = 40
+	AccessibleRoleProgressBar       AccessibleRole = 42
+	AccessibleRolePushButton        AccessibleRole = 43
+	AccessibleRoleRadioButton       AccessibleRole = 44
+	AccessibleRoleScrollBar         AccessibleRole = 48
+	AccessibleRoleScrollPane        AccessibleRole = 49
+	AccessibleRoleSeparator         AccessibleRole = 50
+	AccessibleRoleSlider            AccessibleRole = 51
+	AccessibleRoleSpinButton        AccessibleRole = 52
+	AccessibleRoleSplitPane         AccessibleRole = 53
+	AccessibleRoleStatusBar         AccessibleRole = 54
+	AccessibleRoleTable             AccessibleRole = 55
+	AccessibleRoleTableCell         AccessibleRole = 56
+	AccessibleRoleTableColumnHeader AccessibleRole = 57
+	AccessibleRoleToggleButton      AccessibleRole = 62
+	AccessibleRoleToolBar           AccessibleRole = 63
+	AccessibleRoleToolTip           AccessibleRole = 64
+	AccessibleRoleTreeTable         AccessibleRole = 66
+	AccessibleRoleParagraph         AccessibleRole = 73
+	AccessibleRoleApplication       AccessibleRole = 75
+	AccessibleRoleEntry             AccessibleRole = 79
+	AccessibleRoleDocumentFrame     AccessibleRole = 82
+	AccessibleRoleHeading           AccessibleRole = 83
+	AccessibleRoleLink              AccessibleRole = 88
+	AccessibleRoleTableRow          AccessibleRole = 90
+	AccessibleRoleListBox           AccessibleRole = 98
+)
+
+var atspiRoleNames = map[AccessibleRole]string{
+	AccessibleRoleCalendar:          "calendar",
+	AccessibleRoleCheckBox:          "check box",
+	AccessibleRoleComboBox:          "combo box",
+	AccessibleRoleDateEditor:        "date editor",
+	AccessibleRoleDialog:            "dialog",
+	AccessibleRoleFrame:             "frame",
+	AccessibleRoleImage:             "image",
+	AccessibleRoleLabel:             "label",
+	AccessibleRoleListItem:          "list item",
+	AccessibleRoleMenu:              "menu",
+	AccessibleRoleMenuBar:           "menu bar",
+	AccessibleRoleMenuItem:          "menu item",
+	AccessibleRolePageTab:           "page tab",
+	AccessibleRolePageTabList:       "page tab list",
+	AccessibleRolePanel:             "panel",
+	AccessibleRolePasswordText:      "password text",
+	AccessibleRoleProgressBar:       "progress bar",
+	AccessibleRolePushButton:        "push button",
+	AccessibleRoleRadioButton:       "radio button",
+	AccessibleRoleScrollBar:         "scroll bar",
+	AccessibleRoleScrollPane:        "scroll pane",
+	AccessibleRoleSeparator:         "separator",
+	AccessibleRoleSlider:            "slider",
+	AccessibleRoleSpinButton:        "spin button",
+	AccessibleRoleSplitPane:         "split pane",
+	AccessibleRoleStatusBar:         "status bar",
+	AccessibleRoleTable:             "table",
+	AccessibleRoleTableCell:         "table cell",
+	AccessibleRoleTableColumnHeader: "table column header",
+	AccessibleRoleToggleButton:      "toggle button",
+	AccessibleRoleToolBar:           "tool bar",
+	AccessibleRoleToolTip:           "tool tip",
+	AccessibleRoleTreeTable:         "tree table",
+	AccessibleRoleParagraph:         "paragraph",
+	AccessibleRoleApplication:       "application",
+	AccessibleRoleEntry:             "entry",
+	AccessibleRoleDocumentFrame:     "document frame",
+	AccessibleRoleHeading:           "heading",
+	AccessibleRoleLink:              "link",
+	AccessibleRoleTableRow:          "table row",
+	AccessibleRoleListBox:           "list box",
+}
+
+// AccessibleStates is a set of AT-SPI states. Each state is represented by the bit whose index is the AT-SPI value of
+// the state.
+type AccessibleStates uint64
+
+// Possible AccessibleStates values. Only the states Unison makes use of are defined.
+const (
+	AccessibleStateActive          AccessibleStates = 1 << 1
+	AccessibleStateChecked         AccessibleStates = 1 << 4
+	AccessibleStateCollapsed       AccessibleStates = 1 << 5
+	AccessibleStateEditable        AccessibleStates = 1 << 7
+	AccessibleStateEnabled         AccessibleStates = 1 << 8
+	AccessibleStateExpandable      AccessibleStates = 1 << 9
+	AccessibleStateExpanded        AccessibleStates = 1 << 10
+	AccessibleStateFocusable       AccessibleStates = 1 << 11
+	AccessibleStateFocused         AccessibleStates = 1 << 12
+	AccessibleStateModal           AccessibleStates = 1 << 16
+	AccessibleStateMultiLine       AccessibleStates = 1 << 17
+	AccessibleStateMultiSelectable AccessibleStates = 1 << 18
+	AccessibleStatePressed         AccessibleStates = 1 << 20
+	AccessibleStateResizable       AccessibleStates = 1 << 21
+	AccessibleStateSelectable      AccessibleStates = 1 << 22
+	AccessibleStateSelected        AccessibleStates = 1 << 23
+	AccessibleStateSensitive       AccessibleStates = 1 << 24
+	AccessibleStateShowing         AccessibleStates = 1 << 25
+	AccessibleStateSingleLine      AccessibleStates = 1 << 26
+	AccessibleStateVisible         AccessibleStates = 1 << 30
+	AccessibleStateIndeterminate   AccessibleStates = 1 << 32
+	AccessibleStateInvalidEntry    AccessibleStates = 1 << 36
+	AccessibleStateSelectableText  AccessibleStates = 1 << 38
+	AccessibleStateIsDefault       AccessibleStates = 1 << 39
+	AccessibleStateCheckable       AccessibleStates = 1 << 41
+	AccessibleStateHasPopup        AccessibleStates = 1 << 42
+	AccessibleStateReadOnly        AccessibleStates = 1 << 43
+)
+
+// atspiStateNames holds the names used for states in StateChanged events.
+var atspiStateNames = map[AccessibleStates]string{
+	AccessibleStateActive:          "active",
+	AccessibleStateChecked:         "checked",
+	AccessibleStateCollapsed:       "collapsed",
+	AccessibleStateEditable:        "editable",
+	AccessibleStateEnabled:         "enabled",
+	AccessibleStateExpandable:      "expandable",
+	AccessibleStateExpanded:        "expanded",
+	AccessibleStateFocusable:       "focusable",
+	AccessibleStateFocused:         "focused",
+	AccessibleStateModal:           "modal",
+	AccessibleStateMultiLine:       "multi-line",
+	AccessibleStateMultiSelectable: "multiselectable",
+	AccessibleStatePressed:         "pressed",
+	AccessibleStateResizable:       "resizable",
+	AccessibleStateSelectable:      "selectable",
+	AccessibleStateSelected:        "selected",
+	AccessibleStateSensitive:       "sensitive",
+	AccessibleStateShowing:         "showing",
+	AccessibleStateSingleLine:      "single-line",
+	AccessibleStateVisible:         "visible",
+	AccessibleStateIndeterminate:   "indeterminate",
+	AccessibleStateInvalidEntry:    "invalid-entry",
+	AccessibleStateSelectableText:  "selectable-text",
+	AccessibleStateIsDefault:       "is-default",
+	AccessibleStateCheckable:       "checkable",
+	AccessibleStateHasPopup:        "has-popup",
+	AccessibleStateReadOnly:        "read-only",
+}
+
+// AccessibleRect holds a rectangle in screen pixels.
+type AccessibleRect struct {
+	X      int32
+	Y      int32
+	Width  int32
+	Height int32
+}
+
+// Contains returns true if the point is within the rectangle.
+func (r AccessibleRect) Contains(x, y int32) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.Width && y < r.Y+r.Height
+}
+
+// AccessibleNode describes an accessible object.
+type AccessibleNode struct {
+	Name        string
+	Description string
+	// Text is the text content of a node with HasText set.
+	Text string
+	// Parent is the path of the parent node. Ignored for the root, whose parent is the desktop.
+	Parent string
+	// Children holds the paths of the child nodes.
+	Children []string
+	// Actions holds the names of the actions that may be performed on the node, such as "click".
+	Actions []string
+	// Bounds is the area the node covers and Window is the area of the content of the window containing it.
+	Bounds AccessibleRect
+	Window AccessibleRect
+	// Minimum, Maximum and Current are the numeric value of a node with HasValue set.
+	Minimum float64
+	Maximum float64
+	Current float64
+	States  AccessibleStates
+	Role    AccessibleRole
+	// IndexInParent is the index of this node within the children of its parent.
+	IndexInParent int32
+	// CaretOffset, SelectionStart and SelectionEnd are rune offsets into Text. SelectionStart and SelectionEnd are the
+	// same when there is no selection.
+	CaretOffset    int32
+	SelectionStart int32
+	SelectionEnd   int32
+	HasText        bool
+	HasValue       bool
+}
+
+// AccessibleProvider supplies the accessible objects served by an AccessibilityBridge. Its methods are called from the
+// bridge's own goroutine.
+type AccessibleProvider interface {
+	// AccessibleNode returns the node with the path, or false if there is no such node. The root node represents the
+	// application and its children are its windows.
+	AccessibleNode(path string) (AccessibleNode, bool)
+	// AccessibleNodeAt returns the path of the deepest descendant of the node with the path that contains the point,
+	// which is in screen pixels, or an empty string if there is none.
+	AccessibleNodeAt(path string, x, y int32) string
+	// DoAccessibleAction performs the action at the index in the Actions of the node with the path, returning true if
+	// it was performed.
+	DoAccessibleAction(path string, index int) bool
+	// GrabAccessibleFocus gives the keyboard focus to the node with the path, returning true if it now has the focus.
+	GrabAccessibleFocus(path string) bool
+}
+
+// AccessibilityBridge is a connection to the accessibility bus that serves the objects of an AccessibleProvider.
+type AccessibilityBridge struct {
+	conn     *dbusConn
+	provider AccessibleProvider
+	replies  map[uint32]chan *dbusMessage
+	desktop  string // The bus name of the registry's desktop object, the parent of our root.
+	lock     sync.Mutex
+	appID    int32
+	closed   bool
+}
+
+// AccessibilityRequested returns false if the user has asked that applications not connect to the accessibility bus,
+// which is signaled the same way for applications of every toolkit.
+func AccessibilityRequested() bool {
+	return os.Getenv("NO_AT_BRIDGE") != "1"
+}
+
+// OpenAccessibilityBridge connects to the accessibility bus and registers the application with it. This blocks while
+// the connection is established, so it should be called from a goroutine other than the UI thread.
+func OpenAccessibilityBridge(provider AccessibleProvider) (*AccessibilityBridge, error) {
+	addr, err := atspiBusAddress()
+	if err != nil {
+		return nil, err
+	}
+	c, err := dialDBusAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.hello(); err != nil {
+		c.close()
+		return nil, err
+	}
+	b := newAccessibilityBridge(c, provider)
+	go b.readLoop()
+	if err = b.embed(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func newAccessibilityBridge(c *dbusConn, provider AccessibleProvider) *AccessibilityBridge {
+	return &AccessibilityBridge{
+		conn:     c,
+		provider: provider,
+		replies:  make(map[uint32]chan *dbusMessage),
+		desktop:  atspiRegistryService,
+	}
+}
+
+// atspiBusAddress returns the address of the accessibility bus, which is separate from the session bus and must be
+// asked for.
+func atspiBusAddress() (string, error) {
+	if addr := os.Getenv("AT_SPI_BUS_ADDRESS"); addr != "" {
+		return addr, nil
+	}
+	c, err := dialDBus()
+	if err != nil {
+		return "", err
+	}
+	defer c.close()
+	if err = c.hello(); err != nil {
+		return "", err
+	}
+	if err = c.send(opMethodCall, "org.a11y.Bus", "/org/a11y/bus", "org.a11y.Bus", "GetAddress", "", nil); err != nil {
+		return "", err
+	}
+	msg, err := c.receiveReply()
+	if err != nil {
+		return "", err
+	}
+	r := dbusReader{data: msg.body}
+	addr, ok := r.str()
+	if msg.typ != dbusTypeMethodReturn || !ok || addr == "" {
+		return "", errors.New("unable to obtain the accessibility bus address")
+	}
+	return addr, nil
+}
+
+// embed registers our root object with the registry, which makes the application visible to assistive technologies.
+// The registry calls back into the application while doing so, which is why the read loop must already be running.
+func (b *AccessibilityBridge) embed() error {
+	var body dbusBuf
+	b.appendRef(&body, AccessibleRootPath)
+	b.lock.Lock()
+	ch := b.call(atspiRegistryService, AccessibleRootPath, "org.a11y.atspi.Socket", "Embed", "(so)", body.b)
+	b.lock.Unlock()
+	if ch == nil {
+		return errors.New("unable to register with the accessibility registry")
+	}
+	select {
+	case msg := <-ch:
+		if msg == nil || msg.typ != dbusTypeMethodReturn {
+			return errors.New("the accessibility registry refused the application")
+		}
+		r := dbusReader{data: msg.body}
+		r.align(8)
+		if name, ok := r.str(); ok && name != "" {
+			b.lock.Lock()
+			b.desktop = name
+			b.lock.Unlock()
+		}
+		return nil
+	case <-time.After(atspiCallTimeout):
+		return errors.New("timed out registering with the accessibility registry")
+	}
+}
+
+// Close disconnects from the accessibility bus.
+func (b *AccessibilityBridge) Close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.closed {
+		b.closed = true
+		b.conn.close()
+	}
+}
+
+// call sends a method call, returning a channel on which the reply will be delivered, or nil if the call could not be
+// sent. Must be called with the lock held.
+func (b *AccessibilityBridge) call(dest, path, iface, member, signature string, body []byte) chan *dbusMessage {
+	if b.closed {
+		return nil
+	}
+	if err := b.conn.send(opMethodCall, dest, path, iface, member, signature, body); err != nil {
+		return nil
+	}
+	ch := make(chan *dbusMessage, 1)
+	b.replies[b.conn.serial] = ch
+	return ch
+}
+
+func (b *AccessibilityBridge) readLoop() {
+	defer func() {
+		b.lock.Lock()
+		b.closed = true
+		for serial, ch := range b.replies {
+			delete(b.replies, serial)
+			close(ch)
+		}
+		b.lock.Unlock()
+	}()
+	for {
+		msg, err := b.conn.readMessage(0)
+		if err != nil {
+			return
+		}
+		switch msg.typ {
+		case dbusTypeMethodReturn, dbusTypeError:
+			b.lock.Lock()
+			ch := b.replies[msg.replySerial]
+			delete(b.replies, msg.replySerial)
+			b.lock.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+		case dbusTypeMethodCall:
+			b.handle(msg)
+		}
+	}
+}
+
+// handle answers a method call made against one of our objects.
+func (b *AccessibilityBridge) handle(msg *dbusMessage) {
+	var body dbusBuf
+	signature, errorName := b.dispatch(msg, &body)
+	if errorName != "" {
+		body = dbusBuf{}
+		body.str(msg.iface + "." + msg.member)
+		signature = "s"
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.closed {
+		b.conn.sendReply(msg.sender, msg.serial, errorName, signature, body.b) //nolint:errcheck // See emit()
+	}
+}
+
+// dispatch writes the reply to a method call into w, returning the reply's signature, or the name of the error to
+// reply with instead.
+func (b *AccessibilityBridge) dispatch(msg *dbusMessage, w *dbusBuf) (signature, errorName string) {
+	if !strings.HasPrefix(msg.path, AccessiblePathPrefix) {
+		return "", dbusErrorUnknownObject
+	}
+	node, ok := b.provider.AccessibleNode(msg.path)
+	if !ok {
+		return "", dbusErrorUnknownObject
+	}
+	r := &dbusReader{data: msg.body}
+	if msg.iface == dbusPropertiesInterface {
+		return b.properties(msg, &node, r, w)
+	}
+	if !b.implements(msg.path, &node, msg.iface) {
+		return "", dbusErrorUnknownMethod
+	}
+	switch msg.iface {
+	case atspiAccessibleInterface:
+		return b.accessible(msg, &node, r, w)
+	case atspiApplicationInterface:
+		if msg.member == "GetLocale" {
+			w.str(os.Getenv("LANG"))
+			return "s", ""
+		}
+	case atspiComponentInterface:
+		return b.component(msg, &node, r, w)
+	case atspiActionInterface:
+		return b.action(msg, &node, r, w)
+	case atspiTextInterface:
+		return atspiText(msg, &node, r, w)
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+// interfaces returns the interfaces implemented by the node.
+func (b *AccessibilityBridge) interfaces(path string, node *AccessibleNode) []string {
+	list := []string{atspiAccessibleInterface}
+	if path == AccessibleRootPath {
+		return append(list, atspiApplicationInterface)
+	}
+	list = append(list, atspiComponentInterface)
+	if len(node.Actions) != 0 {
+		list = append(list, atspiActionInterface)
+	}
+	if node.HasText {
+		list = append(list, atspiTextInterface)
+	}
+	if node.HasValue {
+		list = append(list, atspiValueInterface)
+	}
+	return list
+}
+
+func (b *AccessibilityBridge) implements(path string, node *AccessibleNode, iface string) bool {
+	for _, one := range b.interfaces(path, node) {
+		if one == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// appendRef appends an object reference, the (so) struct AT-SPI uses to identify an object on the bus.
+func (b *AccessibilityBridge) appendRef(w *dbusBuf, path string) {
+	w.align(8)
+	if path == "" {
+		path = atspiNullPath
+	}
+	w.str(b.conn.name)
+	w.str(path)
+}
+
+func (b *AccessibilityBridge) appendParentRef(w *dbusBuf, path string, node *AccessibleNode) {
+	if path != AccessibleRootPath {
+		b.appendRef(w, node.Parent)
+		return
+	}
+	b.lock.Lock()
+	desktop := b.desktop
+	b.lock.Unlock()
+	w.align(8)
+	w.str(desktop)
+	w.str(AccessibleRootPath)
+}
+
+func (b *AccessibilityBridge) accessible(msg *dbusMessage, node *AccessibleNode, r *dbusReader,
+	w *dbusBuf) (signature, errorName string) {
+	switch msg.member {
+	case "GetChildAtIndex":
+		index, ok := r.int32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		var child string
+		if index >= 0 && int(index) < len(node.Children) {
+			child = node.Children[index]
+		}
+		b.appendRef(w, child)
+		return "(so)", ""
+	case "GetChildren":
+		w.array(8, func() {
+			for _, child := range node.Children {
+				b.appendRef(w, child)
+			}
+		})
+		return "a(so)", ""
+	case "GetIndexInParent":
+		w.i32(node.IndexInParent)
+		return "i", ""
+	case "GetRelationSet":
+		w.array(8, func() {})
+		return "a(ua(so))", ""
+	case "GetRole":
+		w.u32(uint32(node.Role))
+		return "u", ""
+	case "GetRoleName", "GetLocalizedRoleName":
+		w.str(atspiRoleNames[node.Role])
+		return "s", ""
+	case "GetState":
+		w.array(4, func() {
+			w.u32(uint32(node.States))
+			w.u32(uint32(node.States >> 32))
+		})
+		return "au", ""
+	case "GetAttributes":
+		w.array(8, func() {
+			w.align(8)
+			w.str("toolkit")
+			w.str(atspiToolkitName)
+		})
+		return "a{ss}", ""
+	case "GetApplication":
+		b.appendRef(w, AccessibleRootPath)
+		return "(so)", ""
+	case "GetInterfaces":
+		w.array(4, func() {
+			for _, iface := range b.interfaces(msg.path, node) {
+				w.str(iface)
+			}
+		})
+		return "as", ""
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+// origin returns the location, in screen pixels, of the origin of an AT-SPI coordinate type for the node.
+func (b *AccessibilityBridge) origin(node *AccessibleNode, coordType uint32) (x, y int32) {
+	switch coordType {
+	case atspiCoordTypeWindow:
+		return node.Window.X, node.Window.Y
+	case atspiCoordTypeParent:
+		if parent, ok := b.provider.AccessibleNode(node.Parent); ok {
+			return parent.Bounds.X, parent.Bounds.Y
+		}
+	}
+	return 0, 0
+}
+
+// readPoint reads the (x, y, coordType) arguments that many Component methods take, returning the point in screen
+// pixels.
+func (b *AccessibilityBridge) readPoint(node *AccessibleNode, r *dbusReader) (x, y int32, ok bool) {
+	if x, ok = r.int32(); !ok {
+		return 0, 0, false
+	}
+	if y, ok = r.int32(); !ok {
+		return 0, 0, false
+	}
+	var coordType uint32
+	if coordType, ok = r.uint32(); !ok {
+		return 0, 0, false
+	}
+	ox, oy := b.origin(node, coordType)
+	return x + ox, y + oy, true
+}
+
+func (b *AccessibilityBridge) component(msg *dbusMessage, node *AccessibleNode, r *dbusReader,
+	w *dbusBuf) (signature, errorName string) {
+	switch msg.member {
+	case "Contains":
+		x, y, ok := b.readPoint(node, r)
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		w.boolean(node.Bounds.Contains(x, y))
+		return "b", ""
+	case "GetAccessibleAtPoint":
+		x, y, ok := b.readPoint(node, r)
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		b.appendRef(w, b.provider.AccessibleNodeAt(msg.path, x, y))
+		return "(so)", ""
+	case "GetExtents", "GetPosition":
+		coordType, ok := r.uint32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		ox, oy := b.origin(node, coordType)
+		if msg.member == "GetPosition" {
+			w.i32(node.Bounds.X - ox)
+			w.i32(node.Bounds.Y - oy)
+			return "ii", ""
+		}
+		w.align(8)
+		w.i32(node.Bounds.X - ox)
+		w.i32(node.Bounds.Y - oy)
+		w.i32(node.Bounds.Width)
+		w.i32(node.Bounds.Height)
+		return "(iiii)", ""
+	case "GetSize":
+		w.i32(node.Bounds.Width)
+		w.i32(node.Bounds.Height)
+		return "ii", ""
+	case "GetLayer":
+		w.u32(atspiLayerWidget)
+		return "u", ""
+	case "GetMDIZOrder":
+		w.i16(0)
+		return "n", ""
+	case "GetAlpha":
+		w.double(1)
+		return "d", ""
+	case "GrabFocus":
+		w.boolean(b.provider.GrabAccessibleFocus(msg.path))
+		return "b", ""
+	case "ScrollTo", "ScrollToPoint":
+		w.boolean(false)
+		return "b", ""
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+func (b *AccessibilityBridge) action(msg *dbusMessage, node *AccessibleNode, r *dbusReader,
+	w *dbusBuf) (signature, errorName string) {
+	if msg.member == "GetActions" {
+		w.array(8, func() {
+			for _, name := range node.Actions {
+				w.align(8)
+				w.str(name)
+				w.str(name)
+				w.str("")
+			}
+		})
+		return "a(sss)", ""
+	}
+	index, ok := r.int32()
+	if !ok || index < 0 || int(index) >= len(node.Actions) {
+		return "", dbusErrorInvalidArgs
+	}
+	switch msg.member {
+	case "GetName", "GetLocalizedName":
+		w.str(node.Actions[index])
+		return "s", ""
+	case "GetDescription", "GetKeyBinding":
+		w.str("")
+		return "s", ""
+	case "DoAction":
+		w.boolean(b.provider.DoAccessibleAction(msg.path, int(index)))
+		return "b", ""
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+func atspiText(msg *dbusMessage, node *AccessibleNode, r *dbusReader, w *dbusBuf) (signature, errorName string) {
+	text := []rune(node.Text)
+	length := int32(len(text))
+	clamp := func(offset int32) int32 { return max(min(offset, length), 0) }
+	switch msg.member {
+	case "GetText":
+		start, ok := r.int32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		end, ok := r.int32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		if end < 0 {
+			end = length
+		}
+		start = clamp(start)
+		w.str(string(text[start:max(clamp(end), start)]))
+		return "s", ""
+	case "GetCharacterAtOffset":
+		offset, ok := r.int32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		var ch int32
+		if offset >= 0 && offset < length {
+			ch = text[offset]
+		}
+		w.i32(ch)
+		return "i", ""
+	case "GetTextAtOffset", "GetStringAtOffset":
+		offset, ok := r.int32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		kind, ok := r.uint32()
+		if !ok {
+			return "", dbusErrorInvalidArgs
+		}
+		// GetTextAtOffset takes an older boundary type rather than a granularity; map it onto the granularities.
+		if msg.member == "GetTextAtOffset" {
+			switch {
+			case kind == atspiTextBoundaryChar:
+				kind = atspiTextGranularityChar
+			case kind <= atspiTextBoundaryWordEnd:
+				kind = atspiTextGranularityWord
+			case kind <= atspiTextBoundarySentenceEnd:
+				kind = atspiTextGranularitySentence
+			default:
+				kind = atspiTextGranularityLine
+			}
+		}
+		start, end := atspiTextRange(text, clamp(offset), kind)
+		w.str(string(text[start:end]))
+		w.i32(start)
+		w.i32(end)
+		return "sii", ""
+	case "GetNSelections":
+		if node.SelectionStart != node.SelectionEnd {
+			w.i32(1)
+		} else {
+			w.i32(0)
+		}
+		return "i", ""
+	case "GetSelection":
+		w.i32(node.SelectionStart)
+		w.i32(node.SelectionEnd)
+		return "ii", ""
+	case "GetCharacterExtents", "GetRangeExtents":
+		// We don't know where individual characters are, so report the extents of the whole node.
+		w.i32(node.Bounds.X)
+		w.i32(node.Bounds.Y)
+		w.i32(node.Bounds.Width)
+		w.i32(node.Bounds.Height)
+		return "iiii", ""
+	case "GetOffsetAtPoint":
+		w.i32(-1)
+		return "i", ""
+	case "GetDefaultAttributes":
+		w.array(8, func() {})
+		return "a{ss}", ""
+	case "GetAttributes", "GetAttributeRun":
+		w.array(8, func() {})
+		w.i32(0)
+		w.i32(length)
+		return "a{ss}ii", ""
+	case "SetCaretOffset", "SetSelection", "AddSelection", "RemoveSelection":
+		w.boolean(false)
+		return "b", ""
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+// atspiTextRange returns the range of the character, word or line that contains the offset. Sentences are treated as
+// words and paragraphs as lines, which is as much as can be determined from plain text without knowing the language.
+func atspiTextRange(text []rune, offset int32, granularity uint32) (start, end int32) {
+	length := int32(len(text))
+	switch granularity {
+	case atspiTextGranularityChar:
+		return offset, min(offset+1, length)
+	case atspiTextGranularityWord, atspiTextGranularitySentence:
+		start = offset
+		for start < length && unicode.IsSpace(text[start]) {
+			start++
+		}
+		for start > 0 && !unicode.IsSpace(text[start-1]) {
+			start--
+		}
+		end = start
+		for end < length && !unicode.IsSpace(text[end]) {
+			end++
+		}
+		return start, end
+	default:
+		start = offset
+		for start > 0 && text[start-1] != '\n' {
+			start--
+		}
+		end = offset
+		for end < length && text[end] != '\n' {
+			end++
+		}
+		if end < length {
+			end++ // Include the line ending, as AT-SPI expects.
+		}
+		return start, end
+	}
+}
+
+// properties handles the org.freedesktop.DBus.Properties interface.
+func (b *AccessibilityBridge) properties(msg *dbusMessage, node *AccessibleNode, r *dbusReader,
+	w *dbusBuf) (signature, errorName string) {
+	iface, ok := r.str()
+	if !ok {
+		return "", dbusErrorInvalidArgs
+	}
+	if !b.implements(msg.path, node, iface) {
+		return "", dbusErrorUnknownProperty
+	}
+	switch msg.member {
+	case "Get":
+		name, sok := r.str()
+		if !sok {
+			return "", dbusErrorInvalidArgs
+		}
+		if !b.appendProperty(w, msg.path, node, iface, name) {
+			return "", dbusErrorUnknownProperty
+		}
+		return "v", ""
+	case "GetAll":
+		w.array(8, func() {
+			for _, name := range atspiProperties[iface] {
+				w.align(8)
+				w.str(name)
+				b.appendProperty(w, msg.path, node, iface, name)
+			}
+		})
+		return "a{sv}", ""
+	case "Set":
+		name, sok := r.str()
+		if !sok {
+			return "", dbusErrorInvalidArgs
+		}
+		sig, sok := r.sig()
+		if !sok {
+			return "", dbusErrorInvalidArgs
+		}
+		// The only writable property is the identifier the registry assigns as part of registration. Values are
+		// read-only, as the accessibility model has no way to set them.
+		if iface != atspiApplicationInterface || name != "Id" || sig != "i" {
+			return "", dbusErrorInvalidArgs
+		}
+		id, iok := r.int32()
+		if !iok {
+			return "", dbusErrorInvalidArgs
+		}
+		b.lock.Lock()
+		b.appID = id
+		b.lock.Unlock()
+		return "", ""
+	}
+	return "", dbusErrorUnknownMethod
+}
+
+// atspiProperties holds the properties of each interface, as returned by GetAll.
+var atspiProperties = map[string][]string{
+	atspiAccessibleInterface:  {"Name", "Description", "Parent", "ChildCount", "Locale", "AccessibleId"},
+	atspiApplicationInterface: {"ToolkitName", "Version", "AtspiVersion", "Id"},
+	atspiActionInterface:      {"NActions"},
+	atspiTextInterface:        {"CharacterCount", "CaretOffset"},
+	atspiValueInterface:       {"MinimumValue", "MaximumValue", "MinimumIncrement", "CurrentValue", "Text"},
+}
+
+// appendProperty appends the value of a property as a variant, returning false if there is no such property.
+func (b *AccessibilityBridge) appendProperty(w *dbusBuf, path string, node *AccessibleNode, iface, name string) bool {
+	switch iface + "." + name {
+	case atspiAccessibleInterface + ".Name":
+		w.sig("s")
+		w.str(node.Name)
+	case atspiAccessibleInterface + ".Description":
+		w.sig("s")
+		w.str(node.Description)
+	case atspiAccessibleInterface + ".Parent":
+		w.sig("(so)")
+		b.appendParentRef(w, path, node)
+	case atspiAccessibleInterface + ".ChildCount":
+		w.sig("i")
+		w.i32(int32(len(node.Children)))
+	case atspiAccessibleInterface + ".Locale":
+		w.sig("s")
+		w.str(os.Getenv("LANG"))
+	case atspiAccessibleInterface + ".AccessibleId":
+		w.sig("s")
+		w.str(strings.TrimPrefix(path, AccessiblePathPrefix))
+	case atspiApplicationInterface + ".ToolkitName":
+		w.sig("s")
+		w.str(atspiToolkitName)
+	case atspiApplicationInterface + ".Version":
+		w.sig("s")
+		w.str("")
+	case atspiApplicationInterface + ".AtspiVersion":
+		w.sig("s")
+		w.str(atspiVersion)
+	case atspiApplicationInterface + ".Id":
+		b.lock.Lock()
+		id := b.appID
+		b.lock.Unlock()
+		w.sig("i")
+		w.i32(id)
+	case atspiActionInterface + ".NActions":
+		w.sig("i")
+		w.i32(int32(len(node.Actions)))
+	case atspiTextInterface + ".CharacterCount":
+		w.sig("i")
+		w.i32(int32(utf8.RuneCountInString(node.Text)))
+	case atspiTextInterface + ".CaretOffset":
+		w.sig("i")
+		w.i32(node.CaretOffset)
+	case atspiValueInterface + ".MinimumValue":
+		w.sig("d")
+		w.double(node.Minimum)
+	case atspiValueInterface + ".MaximumValue":
+		w.sig("d")
+		w.double(node.Maximum)
+	case atspiValueInterface + ".MinimumIncrement":
+		w.sig("d")
+		w.double(0)
+	case atspiValueInterface + ".CurrentValue":
+		w.sig("d")
+		w.double(node.Current)
+	case atspiValueInterface + ".Text":
+		w.sig("s")
+		w.str(node.Text)
+	default:
+		return false
+	}
+	return true
+}
+
+// emit sends an AT-SPI event signal. data appends the event's variant argument, including its signature.
+func (b *AccessibilityBridge) emit(path, iface, member, detail string, detail1, detail2 int32, data func(w *dbusBuf)) {
+	var body dbusBuf
+	body.str(detail)
+	body.i32(detail1)
+	body.i32(detail2)
+	data(&body)
+	body.array(8, func() {})
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.closed {
+		// A failure here means the connection is broken, which the read loop will notice.
+		b.conn.send(dbusTypeSignal, "", path, iface, member, atspiEventSignature, body.b) //nolint:errcheck // See above
+	}
+}
+
+func atspiNoData(w *dbusBuf) {
+	w.sig("i")
+	w.i32(0)
+}
+
+func atspiStringData(s string) func(w *dbusBuf) {
+	return func(w *dbusBuf) {
+		w.sig("s")
+		w.str(s)
+	}
+}
+
+func (b *AccessibilityBridge) refData(path string) func(w *dbusBuf) {
+	return func(w *dbusBuf) {
+		w.sig("(so)")
+		b.appendRef(w, path)
+	}
+}
+
+// EmitStatesChanged tells assistive technologies about the states that differ between before and after for the node
+// with the path.
+func (b *AccessibilityBridge) EmitStatesChanged(path string, before, after AccessibleStates) {
+	for changed := before ^ after; changed != 0; changed &= changed - 1 {
+		state := AccessibleStates(1) << bits.TrailingZeros64(uint64(changed))
+		if name, ok := atspiStateNames[state]; ok {
+			var on int32
+			if after&state != 0 {
+				on = 1
+			}
+			b.emit(path, atspiObjectEventInterface, "StateChanged", name, on, 0, atspiNoData)
+		}
+	}
+}
+
+// EmitNameChanged tells assistive technologies that the name of the node with the path has changed.
+func (b *AccessibilityBridge) EmitNameChanged(path, name string) {
+	b.emit(path, atspiObjectEventInterface, "PropertyChange", "accessible-name", 0, 0, atspiStringData(name))
+}
+
+// EmitValueChanged tells assistive technologies that the numeric value of the node with the path has changed.
+func (b *AccessibilityBridge) EmitValueChanged(path string, value float64) {
+	b.emit(path, atspiObjectEventInterface, "PropertyChange", "accessible-value", 0, 0, func(w *dbusBuf) {
+		w.sig("d")
+		w.double(value)
+	})
+}
+
+// EmitTextChanged tells assistive technologies that text was inserted into, or deleted from, the text of the node with
+// the path at the rune offset.
+func (b *AccessibilityBridge) EmitTextChanged(path string, inserted bool, offset int32, text string) {
+	detail := "delete"
+	if inserted {
+		detail = "insert"
+	}
+	b.emit(path, atspiObjectEventInterface, "TextChanged", detail, offset, int32(utf8.RuneCountInString(text)),
+		atspiStringData(text))
+}
+
+// EmitCaretMoved tells assistive technologies that the caret within the text of the node with the path has moved to the
+// rune offset.
+func (b *AccessibilityBridge) EmitCaretMoved(path string, offset int32) {
+	b.emit(path, atspiObjectEventInterface, "TextCaretMoved", "", offset, 0, atspiNoData)
+}
+
+// EmitActiveDescendantChanged tells assistive technologies that the focused item within the node with the path is now
+// the node with the child path.
+func (b *AccessibilityBridge) EmitActiveDescendantChanged(path, child string) {
+	b.emit(path, atspiObjectEventInterface, "ActiveDescendantChanged", "", 0, 0, b.refData(child))
+}
+
+// EmitChildrenChanged tells assistive technologies that the node with the child path was added to, or removed from, the
+// children of the node with the path at the index.
+func (b *AccessibilityBridge) EmitChildrenChanged(path string, added bool, index int32, child string) {
+	detail := "remove"
+	if added {
+		detail = "add"
+	}
+	b.emit(path, atspiObjectEventInterface, "ChildrenChanged", detail, index, 0, b.refData(child))
+}
+
+// EmitWindowActivated tells assistive technologies that the window whose root node has the path has become, or is no
+// longer, the active window.
+func (b *AccessibilityBridge) EmitWindowActivated(path string, active bool) {
+	member := "Deactivate"
+	if active {
+		member = "Activate"
+	}
+	b.emit(path, atspiWindowEventInterface, member, "", 0, 0, atspiStringData(""))
+}
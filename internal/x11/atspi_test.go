@@ -0,0 +1,338 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const (
+	testButtonPath = AccessiblePathPrefix + "1"
+	testFieldPath  = AccessiblePathPrefix + "2"
+)
+
+type testAccessibleProvider struct {
+	nodes   map[string]AccessibleNode
+	actions []string
+}
+
+func (p *testAccessibleProvider) AccessibleNode(path string) (AccessibleNode, bool) {
+	node, ok := p.nodes[path]
+	return node, ok
+}
+
+func (p *testAccessibleProvider) AccessibleNodeAt(path string, x, y int32) string {
+	for _, child := range p.nodes[path].Children {
+		if p.nodes[child].Bounds.Contains(x, y) {
+			return child
+		}
+	}
+	return ""
+}
+
+func (p *testAccessibleProvider) DoAccessibleAction(path string, index int) bool {
+	p.actions = append(p.actions, p.nodes[path].Actions[index])
+	return true
+}
+
+func (p *testAccessibleProvider) GrabAccessibleFocus(_ string) bool {
+	return false
+}
+
+func newTestAccessibilityBridge(t *testing.T) (b *AccessibilityBridge, server *dbusConn,
+	provider *testAccessibleProvider) {
+	t.Helper()
+	window := AccessibleRect{X: 100, Y: 50, Width: 400, Height: 300}
+	provider = &testAccessibleProvider{nodes: map[string]AccessibleNode{
+		AccessibleRootPath: {
+			Name:     "app",
+			Children: []string{AccessiblePathPrefix + "0"},
+			Role:     AccessibleRoleApplication,
+		},
+		AccessiblePathPrefix + "0": {
+			Name:     "Window",
+			Parent:   AccessibleRootPath,
+			Children: []string{testButtonPath, testFieldPath},
+			Bounds:   window,
+			Window:   window,
+			Role:     AccessibleRoleFrame,
+			States:   AccessibleStateActive | AccessibleStateEnabled,
+		},
+		testButtonPath: {
+			Name:    "OK",
+			Parent:  AccessiblePathPrefix + "0",
+			Actions: []string{"click"},
+			Bounds:  AccessibleRect{X: 110, Y: 60, Width: 80, Height: 20},
+			Window:  window,
+			Role:    AccessibleRolePushButton,
+			States:  AccessibleStateEnabled | AccessibleStateFocusable | AccessibleStateIndeterminate,
+		},
+		testFieldPath: {
+			Text:           "one two\nthree",
+			Parent:         AccessiblePathPrefix + "0",
+			Bounds:         AccessibleRect{X: 110, Y: 90, Width: 200, Height: 20},
+			Window:         window,
+			Role:           AccessibleRoleEntry,
+			IndexInParent:  1,
+			CaretOffset:    5,
+			SelectionStart: 4,
+			SelectionEnd:   7,
+			HasText:        true,
+		},
+	}}
+	clientEnd, serverEnd := net.Pipe()
+	b = newAccessibilityBridge(&dbusConn{conn: clientEnd, name: ":1.42"}, provider)
+	go b.readLoop()
+	server = &dbusConn{conn: serverEnd}
+	t.Cleanup(func() {
+		b.Close()
+		server.close()
+	})
+	return b, server, provider
+}
+
+// callTestBridge makes a method call against the bridge as an assistive technology would, returning the reply.
+func callTestBridge(t *testing.T, server *dbusConn, path, iface, member, signature string, body []byte) *dbusMessage {
+	t.Helper()
+	c := check.New(t)
+	c.NoError(server.send(opMethodCall, "", path, iface, member, signature, body))
+	msg, err := server.readMessage(time.Second)
+	c.NoError(err)
+	c.Equal(server.serial, msg.replySerial)
+	return msg
+}
+
+func readTestRef(r *dbusReader) string {
+	r.align(8)
+	r.str() //nolint:errcheck // The bus name is not of interest
+	path, _ := r.str()
+	return path
+}
+
+// TestAccessibilityBridgeTree verifies that the tree can be walked, and that the root's parent is the desktop.
+func TestAccessibilityBridgeTree(t *testing.T) {
+	c := check.New(t)
+	_, server, _ := newTestAccessibilityBridge(t)
+
+	msg := callTestBridge(t, server, AccessiblePathPrefix+"0", atspiAccessibleInterface, "GetChildren", "", nil)
+	c.Equal(byte(dbusTypeMethodReturn), msg.typ)
+	c.Equal("a(so)", msg.signature)
+	r := dbusReader{data: msg.body}
+	_, ok := r.uint32()
+	c.True(ok)
+	c.Equal(testButtonPath, readTestRef(&r))
+	c.Equal(testFieldPath, readTestRef(&r))
+
+	var body dbusBuf
+	body.i32(5)
+	msg = callTestBridge(t, server, AccessibleRootPath, atspiAccessibleInterface, "GetChildAtIndex", "i", body.b)
+	r = dbusReader{data: msg.body}
+	c.Equal(atspiNullPath, readTestRef(&r))
+
+	msg = callTestBridge(t, server, testButtonPath, atspiAccessibleInterface, "GetRole", "", nil)
+	r = dbusReader{data: msg.body}
+	v, _ := r.uint32()
+	c.Equal(uint32(AccessibleRolePushButton), v)
+
+	msg = callTestBridge(t, server, testButtonPath, atspiAccessibleInterface, "GetState", "", nil)
+	r = dbusReader{data: msg.body}
+	n, _ := r.uint32()
+	c.Equal(uint32(8), n)
+	low, _ := r.uint32()
+	high, _ := r.uint32()
+	c.Equal(uint32(AccessibleStateEnabled|AccessibleStateFocusable), low)
+	c.Equal(uint32(AccessibleStateIndeterminate>>32), high)
+
+	body = dbusBuf{}
+	body.str(atspiAccessibleInterface)
+	body.str("Parent")
+	msg = callTestBridge(t, server, AccessibleRootPath, dbusPropertiesInterface, "Get", "ss", body.b)
+	r = dbusReader{data: msg.body}
+	sig, _ := r.sig()
+	c.Equal("(so)", sig)
+	r.align(8)
+	name, _ := r.str()
+	c.Equal(atspiRegistryService, name)
+
+	msg = callTestBridge(t, server, AccessiblePathPrefix+"99", atspiAccessibleInterface, "GetRole", "", nil)
+	c.Equal(byte(dbusTypeError), msg.typ)
+}
+
+// TestAccessibilityBridgeProperties verifies property access, including setting the Id the registry assigns.
+func TestAccessibilityBridgeProperties(t *testing.T) {
+	c := check.New(t)
+	b, server, _ := newTestAccessibilityBridge(t)
+
+	var body dbusBuf
+	body.str(atspiAccessibleInterface)
+	msg := callTestBridge(t, server, testButtonPath, dbusPropertiesInterface, "GetAll", "s", body.b)
+	c.Equal("a{sv}", msg.signature)
+	r := dbusReader{data: msg.body}
+	r.uint32() //nolint:errcheck // Array length
+	r.align(8)
+	key, _ := r.str()
+	c.Equal("Name", key)
+	sig, _ := r.sig()
+	c.Equal("s", sig)
+	value, _ := r.str()
+	c.Equal("OK", value)
+
+	body = dbusBuf{}
+	body.str(atspiApplicationInterface)
+	body.str("Id")
+	body.sig("i")
+	body.i32(17)
+	msg = callTestBridge(t, server, AccessibleRootPath, dbusPropertiesInterface, "Set", "ssv", body.b)
+	c.Equal(byte(dbusTypeMethodReturn), msg.typ)
+	b.lock.Lock()
+	c.Equal(int32(17), b.appID)
+	b.lock.Unlock()
+
+	// The button does not implement the Text interface, so it has none of its properties.
+	body = dbusBuf{}
+	body.str(atspiTextInterface)
+	body.str("CharacterCount")
+	msg = callTestBridge(t, server, testButtonPath, dbusPropertiesInterface, "Get", "ss", body.b)
+	c.Equal(byte(dbusTypeError), msg.typ)
+
+	msg = callTestBridge(t, server, testFieldPath, dbusPropertiesInterface, "Get", "ss", body.b)
+	r = dbusReader{data: msg.body}
+	r.sig() //nolint:errcheck // Checked by the value
+	count, _ := r.int32()
+	c.Equal(int32(13), count)
+}
+
+// TestAccessibilityBridgeComponentAndAction verifies that extents honor the requested coordinate type and that actions
+// are passed on to the provider.
+func TestAccessibilityBridgeComponentAndAction(t *testing.T) {
+	c := check.New(t)
+	_, server, provider := newTestAccessibilityBridge(t)
+
+	var body dbusBuf
+	body.u32(atspiCoordTypeWindow)
+	msg := callTestBridge(t, server, testButtonPath, atspiComponentInterface, "GetExtents", "u", body.b)
+	r := dbusReader{data: msg.body}
+	var extents [4]int32
+	for i := range extents {
+		extents[i], _ = r.int32()
+	}
+	c.Equal([4]int32{10, 10, 80, 20}, extents)
+
+	body = dbusBuf{}
+	body.i32(20)
+	body.i32(45)
+	body.u32(atspiCoordTypeWindow)
+	msg = callTestBridge(t, server, AccessiblePathPrefix+"0", atspiComponentInterface, "GetAccessibleAtPoint", "iiu",
+		body.b)
+	r = dbusReader{data: msg.body}
+	c.Equal(testFieldPath, readTestRef(&r))
+
+	body = dbusBuf{}
+	body.i32(0)
+	msg = callTestBridge(t, server, testButtonPath, atspiActionInterface, "DoAction", "i", body.b)
+	r = dbusReader{data: msg.body}
+	done, _ := r.boolean()
+	c.True(done)
+	c.Equal([]string{"click"}, provider.actions)
+
+	// The field has no actions, so it does not implement the interface.
+	msg = callTestBridge(t, server, testFieldPath, atspiActionInterface, "DoAction", "i", body.b)
+	c.Equal(byte(dbusTypeError), msg.typ)
+}
+
+// TestAccessibilityBridgeText verifies the Text interface methods screen readers use to read and review text.
+func TestAccessibilityBridgeText(t *testing.T) {
+	c := check.New(t)
+	_, server, _ := newTestAccessibilityBridge(t)
+
+	var body dbusBuf
+	body.i32(4)
+	body.i32(-1)
+	msg := callTestBridge(t, server, testFieldPath, atspiTextInterface, "GetText", "ii", body.b)
+	r := dbusReader{data: msg.body}
+	text, _ := r.str()
+	c.Equal("two\nthree", text)
+
+	for _, one := range []struct {
+		text        string
+		granularity uint32
+		start, end  int32
+	}{
+		{"w", atspiTextGranularityChar, 5, 6},
+		{"two", atspiTextGranularityWord, 4, 7},
+		{"one two\n", atspiTextGranularityLine, 0, 8},
+	} {
+		body = dbusBuf{}
+		body.i32(5)
+		body.u32(one.granularity)
+		msg = callTestBridge(t, server, testFieldPath, atspiTextInterface, "GetStringAtOffset", "iu", body.b)
+		r = dbusReader{data: msg.body}
+		text, _ = r.str()
+		start, _ := r.int32()
+		end, _ := r.int32()
+		c.Equal(one.text, text)
+		c.Equal(one.start, start)
+		c.Equal(one.end, end)
+	}
+
+	body = dbusBuf{}
+	body.i32(0)
+	msg = callTestBridge(t, server, testFieldPath, atspiTextInterface, "GetSelection", "i", body.b)
+	r = dbusReader{data: msg.body}
+	start, _ := r.int32()
+	end, _ := r.int32()
+	c.Equal(int32(4), start)
+	c.Equal(int32(7), end)
+}
+
+// TestAccessibilityBridgeEvents verifies the signals sent when state changes.
+func TestAccessibilityBridgeEvents(t *testing.T) {
+	c := check.New(t)
+	b, server, _ := newTestAccessibilityBridge(t)
+
+	go b.EmitStatesChanged(testButtonPath, AccessibleStateChecked, AccessibleStateFocused)
+	for _, expected := range []struct {
+		name string
+		on   int32
+	}{{"checked", 0}, {"focused", 1}} {
+		msg, err := server.readMessage(time.Second)
+		c.NoError(err)
+		c.Equal(byte(dbusTypeSignal), msg.typ)
+		c.Equal(testButtonPath, msg.path)
+		c.Equal(atspiObjectEventInterface, msg.iface)
+		c.Equal("StateChanged", msg.member)
+		c.Equal(atspiEventSignature, msg.signature)
+		r := dbusReader{data: msg.body}
+		detail, _ := r.str()
+		on, _ := r.int32()
+		c.Equal(expected.name, detail)
+		c.Equal(expected.on, on)
+	}
+
+	go b.EmitTextChanged(testFieldPath, true, 3, "héllo")
+	msg, err := server.readMessage(time.Second)
+	c.NoError(err)
+	c.Equal("TextChanged", msg.member)
+	r := dbusReader{data: msg.body}
+	detail, _ := r.str()
+	offset, _ := r.int32()
+	length, _ := r.int32()
+	sig, _ := r.sig()
+	text, _ := r.str()
+	c.Equal("insert", detail)
+	c.Equal(int32(3), offset)
+	c.Equal(int32(5), length)
+	c.Equal("s", sig)
+	c.Equal("héllo", text)
+}
@@ -0,0 +1,590 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// This file implements compose-key and dead-key handling, the part of Xlib's input method that turns sequences of key
+// presses (e.g. Multi_key, apostrophe, e or dead_acute, e) into text. The sequences are read from the same Compose
+// files Xlib uses (https://www.x.org/releases/current/doc/man/man5/Compose.5.xhtml), so user customizations in
+// ~/.XCompose are honored. Dead keys that have no entry in the table fall back to combining the accent with the next
+// character via Unicode normalization, so they remain useful even when no Compose file can be found.
+
+// Special keysyms used by the compose machinery.
+const (
+	KeySymMultiKey  = 0xff20
+	keySymDeadFirst = 0xfe50
+	keySymDeadLast  = 0xfe6f
+	keySymSpace     = 0x0020
+)
+
+const (
+	composeMaxIncludeDepth = 8
+	composeMultiKeyPreedit = '·'
+)
+
+// ComposeStatus describes what a Composer did with a key.
+type ComposeStatus byte
+
+// Possible ComposeStatus values.
+const (
+	// ComposeIgnored means the key is not part of a compose sequence and should be processed normally.
+	ComposeIgnored ComposeStatus = iota
+	// ComposeInProgress means the key was consumed and the sequence is not yet complete.
+	ComposeInProgress
+	// ComposeFinished means the key was consumed and completed a sequence, producing text.
+	ComposeFinished
+	// ComposeCancelled means the key was consumed and did not continue any known sequence, so the sequence was
+	// discarded.
+	ComposeCancelled
+)
+
+// ComposeTable holds the compose sequences loaded from a Compose file.
+type ComposeTable struct {
+	root composeNode
+}
+
+type composeNode struct {
+	children map[uint32]*composeNode
+	result   string
+}
+
+// Composer tracks the progress of a compose sequence.
+type Composer struct {
+	// Table holds the compose sequences. If nil, it is loaded via LoadComposeTable() the first time a key that can
+	// start a sequence is seen.
+	Table   *ComposeTable
+	node    *composeNode
+	pending []uint32
+	preedit []rune
+}
+
+// Composing returns true if a compose sequence is in progress.
+func (c *Composer) Composing() bool {
+	return len(c.pending) != 0
+}
+
+// Preedit returns the text to display while a compose sequence is in progress.
+func (c *Composer) Preedit() string {
+	return string(c.preedit)
+}
+
+// Reset discards any compose sequence in progress.
+func (c *Composer) Reset() {
+	c.node = nil
+	c.pending = c.pending[:0]
+	c.preedit = c.preedit[:0]
+}
+
+// Feed processes a key press. ch is the character the key would normally type, or 0 if it doesn't type one. The text
+// return value is only meaningful when the status is ComposeFinished.
+func (c *Composer) Feed(keySym uint32, ch rune) (status ComposeStatus, text string) {
+	if IsModifierKeySym(keySym) {
+		return ComposeIgnored, ""
+	}
+	if !c.Composing() {
+		if keySym != KeySymMultiKey && !IsDeadKeySym(keySym) {
+			return ComposeIgnored, ""
+		}
+		if c.Table == nil {
+			c.Table = LoadComposeTable()
+		}
+		c.node = &c.Table.root
+	}
+	var next *composeNode
+	if c.node != nil {
+		next = c.node.children[keySym]
+	}
+	if next != nil {
+		if len(next.children) == 0 {
+			c.Reset()
+			return ComposeFinished, next.result
+		}
+		c.push(next, keySym, ch)
+		return ComposeInProgress, ""
+	}
+	if !c.Composing() {
+		if IsDeadKeySym(keySym) {
+			c.push(nil, keySym, ch)
+			return ComposeInProgress, ""
+		}
+		// A Multi_key the table has no sequences for.
+		return ComposeIgnored, ""
+	}
+	if text, ok := c.deadKeyFallback(keySym, ch); ok {
+		c.Reset()
+		return ComposeFinished, text
+	}
+	if IsDeadKeySym(keySym) && c.onlyDeadKeysPending() {
+		c.push(nil, keySym, ch)
+		return ComposeInProgress, ""
+	}
+	c.Reset()
+	return ComposeCancelled, ""
+}
+
+func (c *Composer) push(node *composeNode, keySym uint32, ch rune) {
+	c.node = node
+	c.pending = append(c.pending, keySym)
+	switch {
+	case keySym == KeySymMultiKey:
+		c.preedit = append(c.preedit, composeMultiKeyPreedit)
+	case IsDeadKeySym(keySym):
+		if spacing := deadKeys[keySym-keySymDeadFirst].spacing; spacing != 0 {
+			c.preedit = append(c.preedit, spacing)
+		} else {
+			c.preedit = append(c.preedit, ' ', deadKeys[keySym-keySymDeadFirst].combining)
+		}
+	case ch != 0:
+		c.preedit = append(c.preedit, ch)
+	}
+}
+
+func (c *Composer) onlyDeadKeysPending() bool {
+	for _, one := range c.pending {
+		if !IsDeadKeySym(one) {
+			return false
+		}
+	}
+	return true
+}
+
+// deadKeyFallback composes a sequence made up solely of dead keys that the table doesn't know about. As with other
+// toolkits, pressing the dead key again or following it with a space produces the accent on its own.
+func (c *Composer) deadKeyFallback(keySym uint32, ch rune) (string, bool) {
+	if !c.onlyDeadKeysPending() {
+		return "", false
+	}
+	if len(c.pending) == 1 && (keySym == keySymSpace || keySym == c.pending[0]) {
+		info := deadKeys[c.pending[0]-keySymDeadFirst]
+		if info.spacing != 0 {
+			return string(info.spacing), true
+		}
+		return string(info.combining), true
+	}
+	if ch == 0 || !unicode.IsGraphic(ch) || unicode.IsSpace(ch) {
+		return "", false
+	}
+	runes := make([]rune, 0, len(c.pending)+1)
+	runes = append(runes, ch)
+	for _, one := range c.pending {
+		if combining := deadKeys[one-keySymDeadFirst].combining; combining != 0 {
+			runes = append(runes, combining)
+		}
+	}
+	return norm.NFC.String(string(runes)), true
+}
+
+// IsDeadKeySym returns true if the keysym is one of the dead keys.
+func IsDeadKeySym(keySym uint32) bool {
+	return keySym >= keySymDeadFirst && keySym <= keySymDeadLast
+}
+
+// IsModifierKeySym returns true if the keysym is a modifier key, such as Shift or AltGr, which never affects a compose
+// sequence on its own.
+func IsModifierKeySym(keySym uint32) bool {
+	return (keySym >= 0xffe1 && keySym <= 0xffee) || // Shift_L through Hyper_R
+		(keySym >= 0xfe01 && keySym <= 0xfe13) || // ISO_Lock through ISO_Level5_Lock
+		keySym == 0xff7e || // Mode_switch
+		keySym == 0xff7f // Num_Lock
+}
+
+// LoadComposeTable loads the compose sequences the user would get from Xlib: the file named by $XCOMPOSEFILE, else the
+// user's ~/.config/XCompose or ~/.XCompose, else the system file for the current locale. An empty table is returned if
+// none of them can be read, in which case only dead keys will compose.
+func LoadComposeTable() *ComposeTable {
+	t := &ComposeTable{}
+	for _, path := range composeFileCandidates() {
+		if data, err := os.ReadFile(path); err == nil {
+			t.parse(data, filepath.Dir(path), 0)
+			break
+		}
+	}
+	return t
+}
+
+// ParseComposeTable builds a table from the content of a Compose file. Include directives are resolved relative to
+// dir.
+func ParseComposeTable(data []byte, dir string) *ComposeTable {
+	t := &ComposeTable{}
+	t.parse(data, dir, 0)
+	return t
+}
+
+func composeFileCandidates() []string {
+	var list []string
+	if p := os.Getenv("XCOMPOSEFILE"); p != "" {
+		list = append(list, p)
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	home, err := os.UserHomeDir()
+	if err == nil && configDir == "" {
+		configDir = filepath.Join(home, ".config")
+	}
+	if configDir != "" {
+		list = append(list, filepath.Join(configDir, "XCompose"))
+	}
+	if err == nil {
+		list = append(list, filepath.Join(home, ".XCompose"))
+	}
+	if p := systemComposeFile(); p != "" {
+		list = append(list, p)
+	}
+	return list
+}
+
+func systemLocaleDir() string {
+	if dir := os.Getenv("XLOCALEDIR"); dir != "" {
+		return dir
+	}
+	for _, dir := range []string{"/usr/share/X11/locale", "/usr/lib/X11/locale", "/usr/X11R6/lib/X11/locale"} {
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			return dir
+		}
+	}
+	return "/usr/share/X11/locale"
+}
+
+func currentLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return "C"
+}
+
+// systemComposeFile returns the path to the system Compose file for the current locale, as listed in compose.dir.
+func systemComposeFile() string {
+	dir := systemLocaleDir()
+	data, err := os.ReadFile(filepath.Join(dir, "compose.dir"))
+	if err != nil {
+		return ""
+	}
+	locale, _, _ := strings.Cut(currentLocale(), "@")
+	wanted := []string{locale}
+	if lang, charset, found := strings.Cut(locale, "."); found {
+		// Charsets are spelled inconsistently (utf8 vs. UTF-8), so also try the canonical spelling compose.dir uses.
+		if strings.EqualFold(strings.ReplaceAll(charset, "-", ""), "utf8") {
+			wanted = append(wanted, lang+".UTF-8")
+		}
+	}
+	wanted = append(wanted, "en_US.UTF-8")
+	files := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		file, name, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		if name = strings.TrimSpace(name); files[name] == "" {
+			files[name] = strings.TrimSpace(file)
+		}
+	}
+	for _, one := range wanted {
+		if file := files[one]; file != "" {
+			return filepath.Join(dir, file)
+		}
+	}
+	return ""
+}
+
+func (t *ComposeTable) parse(data []byte, dir string, depth int) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "include"); ok {
+			if depth < composeMaxIncludeDepth {
+				if path := composeIncludePath(rest, dir); path != "" {
+					if included, err := os.ReadFile(path); err == nil {
+						t.parse(included, filepath.Dir(path), depth+1)
+					}
+				}
+			}
+			continue
+		}
+		seq, result, ok := parseComposeLine(line)
+		if ok {
+			t.add(seq, result)
+		}
+	}
+}
+
+func composeIncludePath(directive, dir string) string {
+	s, ok := parseComposeString(strings.TrimSpace(directive))
+	if !ok {
+		return ""
+	}
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i == len(s)-1 {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'H':
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			buf.WriteString(home)
+		case 'L':
+			buf.WriteString(systemComposeFile())
+		case 'S':
+			buf.WriteString(systemLocaleDir())
+		case '%':
+			buf.WriteByte('%')
+		default:
+			return ""
+		}
+	}
+	path := buf.String()
+	if path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
+
+// parseComposeLine parses a line of the form: <Multi_key> <e> <apostrophe> : "é" eacute
+// Lines that use modifiers, name keysyms this package doesn't know or produce text that isn't valid UTF-8 are rejected.
+func parseComposeLine(line string) (seq []uint32, result string, ok bool) {
+	left, right, found := strings.Cut(line, ":")
+	if !found {
+		return nil, "", false
+	}
+	for field := range strings.FieldsSeq(left) {
+		if len(field) < 3 || field[0] != '<' || field[len(field)-1] != '>' {
+			return nil, "", false
+		}
+		keySym, known := KeySymFromName(field[1 : len(field)-1])
+		if !known {
+			return nil, "", false
+		}
+		seq = append(seq, keySym)
+	}
+	if len(seq) == 0 {
+		return nil, "", false
+	}
+	if result, ok = parseComposeString(strings.TrimSpace(right)); !ok || result == "" || !utf8.ValidString(result) {
+		return nil, "", false
+	}
+	return seq, result, true
+}
+
+// parseComposeString parses a quoted string at the start of s, which may contain C-style escapes.
+func parseComposeString(s string) (string, bool) {
+	if s == "" || s[0] != '"' {
+		return "", false
+	}
+	var buf strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return buf.String(), true
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", false
+			}
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'x', 'X':
+				j := i + 1
+				for j < len(s) && j < i+3 && isHexDigit(s[j]) {
+					j++
+				}
+				v, err := strconv.ParseUint(s[i+1:j], 16, 8)
+				if err != nil {
+					return "", false
+				}
+				buf.WriteByte(byte(v))
+				i = j - 1
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				j := i
+				for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+					j++
+				}
+				v, err := strconv.ParseUint(s[i:j], 8, 8)
+				if err != nil {
+					return "", false
+				}
+				buf.WriteByte(byte(v))
+				i = j - 1
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return "", false
+}
+
+func isHexDigit(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func (t *ComposeTable) add(seq []uint32, result string) {
+	node := &t.root
+	for _, keySym := range seq {
+		if node.children == nil {
+			node.children = make(map[uint32]*composeNode)
+		}
+		next := node.children[keySym]
+		if next == nil {
+			next = &composeNode{}
+			node.children[keySym] = next
+		}
+		node = next
+	}
+	// As with Xlib, a later definition replaces an earlier one, even if that means discarding longer sequences which
+	// started with this one.
+	node.children = nil
+	node.result = result
+}
+
+// KeySymFromName returns the keysym for the name used in Compose files and keysymdef.h, e.g. "eacute" or "U00E9". Only
+// the names relevant to text composition are known: the Latin-1 characters, the dead keys, Multi_key and the "U+hex"
+// Unicode form.
+func KeySymFromName(name string) (uint32, bool) {
+	if keySym, ok := keySymNames[name]; ok {
+		return keySym, true
+	}
+	if hexDigits, ok := strings.CutPrefix(name, "U"); ok && len(hexDigits) >= 4 && len(hexDigits) <= 6 {
+		if v, err := strconv.ParseUint(hexDigits, 16, 32); err == nil && v <= unicode.MaxRune {
+			if (v >= 0x20 && v <= 0x7e) || (v >= 0xa0 && v <= 0xff) {
+				return uint32(v), true
+			}
+			return 0x01000000 | uint32(v), true
+		}
+	}
+	if len(name) == 1 && name[0] >= '0' && name[0] <= '9' {
+		return uint32(name[0]), true
+	}
+	return 0, false
+}
+
+type deadKeyInfo struct {
+	name      string
+	combining rune
+	spacing   rune
+}
+
+// deadKeys is indexed by keysym - keySymDeadFirst.
+var deadKeys = [keySymDeadLast - keySymDeadFirst + 1]deadKeyInfo{
+	{"dead_grave", 0x0300, '`'},
+	{"dead_acute", 0x0301, 0x00b4},
+	{"dead_circumflex", 0x0302, '^'},
+	{"dead_tilde", 0x0303, '~'},
+	{"dead_macron", 0x0304, 0x00af},
+	{"dead_breve", 0x0306, 0x02d8},
+	{"dead_abovedot", 0x0307, 0x02d9},
+	{"dead_diaeresis", 0x0308, 0x00a8},
+	{"dead_abovering", 0x030a, 0x02da},
+	{"dead_doubleacute", 0x030b, 0x02dd},
+	{"dead_caron", 0x030c, 0x02c7},
+	{"dead_cedilla", 0x0327, 0x00b8},
+	{"dead_ogonek", 0x0328, 0x02db},
+	{"dead_iota", 0x0345, 0x037a},
+	{"dead_voiced_sound", 0x3099, 0x309b},
+	{"dead_semivoiced_sound", 0x309a, 0x309c},
+	{"dead_belowdot", 0x0323, 0},
+	{"dead_hook", 0x0309, 0},
+	{"dead_horn", 0x031b, 0},
+	{"dead_stroke", 0x0338, 0},
+	{"dead_abovecomma", 0x0313, 0},
+	{"dead_abovereversedcomma", 0x0314, 0},
+	{"dead_doublegrave", 0x030f, 0},
+	{"dead_belowring", 0x0325, 0},
+	{"dead_belowmacron", 0x0331, 0},
+	{"dead_belowcircumflex", 0x032d, 0},
+	{"dead_belowtilde", 0x0330, 0},
+	{"dead_belowbreve", 0x032e, 0},
+	{"dead_belowdiaeresis", 0x0324, 0},
+	{"dead_invertedbreve", 0x0311, 0},
+	{"dead_belowcomma", 0x0326, 0},
+	{"dead_currency", 0, 0x00a4},
+}
+
+// asciiKeySymNames holds the keysym names for 0x20 through 0x7e, in order.
+var asciiKeySymNames = [...]string{
+	"space", "exclam", "quotedbl", "numbersign", "dollar", "percent", "ampersand", "apostrophe", "parenleft",
+	"parenright", "asterisk", "plus", "comma", "minus", "period", "slash", "0", "1", "2", "3", "4", "5", "6", "7",
+	"8", "9", "colon", "semicolon", "less", "equal", "greater", "question", "at", "A", "B", "C", "D", "E", "F", "G",
+	"H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "bracketleft",
+	"backslash", "bracketright", "asciicircum", "underscore", "grave", "a", "b", "c", "d", "e", "f", "g", "h", "i",
+	"j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z", "braceleft", "bar",
+	"braceright", "asciitilde",
+}
+
+// latin1KeySymNames holds the keysym names for 0xa0 through 0xff, in order.
+var latin1KeySymNames = [...]string{
+	"nobreakspace", "exclamdown", "cent", "sterling", "currency", "yen", "brokenbar", "section", "diaeresis",
+	"copyright", "ordfeminine", "guillemotleft", "notsign", "hyphen", "registered", "macron", "degree", "plusminus",
+	"twosuperior", "threesuperior", "acute", "mu", "paragraph", "periodcentered", "cedilla", "onesuperior",
+	"masculine", "guillemotright", "onequarter", "onehalf", "threequarters", "questiondown", "Agrave", "Aacute",
+	"Acircumflex", "Atilde", "Adiaeresis", "Aring", "AE", "Ccedilla", "Egrave", "Eacute", "Ecircumflex",
+	"Ediaeresis", "Igrave", "Iacute", "Icircumflex", "Idiaeresis", "ETH", "Ntilde", "Ograve", "Oacute",
+	"Ocircumflex", "Otilde", "Odiaeresis", "multiply", "Oslash", "Ugrave", "Uacute", "Ucircumflex", "Udiaeresis",
+	"Yacute", "THORN", "ssharp", "agrave", "aacute", "acircumflex", "atilde", "adiaeresis", "aring", "ae",
+	"ccedilla", "egrave", "eacute", "ecircumflex", "ediaeresis", "igrave", "iacute", "icircumflex", "idiaeresis",
+	"eth", "ntilde", "ograve", "oacute", "ocircumflex", "otilde", "odiaeresis", "division", "oslash", "ugrave",
+	"uacute", "ucircumflex", "udiaeresis", "yacute", "thorn", "ydiaeresis",
+}
+
+var keySymNames = func() map[string]uint32 {
+	m := make(map[string]uint32, len(asciiKeySymNames)+len(latin1KeySymNames)+len(deadKeys)+16)
+	for i, name := range asciiKeySymNames {
+		m[name] = uint32(0x20 + i)
+	}
+	for i, name := range latin1KeySymNames {
+		m[name] = uint32(0xa0 + i)
+	}
+	for i, info := range deadKeys {
+		m[info.name] = uint32(keySymDeadFirst + i)
+	}
+	// Aliases defined by keysymdef.h.
+	m["guillemetleft"] = 0xab
+	m["guillemetright"] = 0xbb
+	m["ordmasculine"] = 0xba
+	m["Eth"] = 0xd0
+	m["Ooblique"] = 0xd8
+	m["Thorn"] = 0xde
+	m["ooblique"] = 0xf8
+	m["dead_perispomeni"] = 0xfe53
+	m["dead_psili"] = 0xfe64
+	m["dead_dasia"] = 0xfe65
+	m["Multi_key"] = KeySymMultiKey
+	return m
+}()
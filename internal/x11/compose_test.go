@@ -0,0 +1,204 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const (
+	testKeySymApostrophe = 0x27
+	testKeySymE          = 0x65
+	testKeySymO          = 0x6f
+	testKeySymC          = 0x63
+	testKeySymQ          = 0x71
+	testKeySymShiftL     = 0xffe1
+	testKeySymBackSpace  = 0xff08
+	testKeySymDeadAcute  = 0xfe51
+	testKeySymDeadCirc   = 0xfe52
+	testKeySymDeadDot    = 0xfe60
+)
+
+const testComposeFile = `# A comment
+<Multi_key> <apostrophe> <e>	: "é"	eacute
+<Multi_key> <o> <c>		: "©"	copyright
+<dead_acute> <e>		: "\303\251"
+<Multi_key> <U2203> <x>		: "\"x\""
+<Multi_key> <q> <q>		: "first"
+<Multi_key> <q>			: "replaced"
+<Multi_key> <x> <x>		: "\351"
+~Ctrl <Multi_key> <a>		: "ignored"
+<Multi_key> <unknown_name> <a>	: "ignored"
+`
+
+func feedAll(c *Composer, keys ...uint32) (status ComposeStatus, text string) {
+	for _, key := range keys {
+		var ch rune
+		if key < 0x100 {
+			ch = rune(key)
+		}
+		status, text = c.Feed(key, ch)
+	}
+	return status, text
+}
+
+// TestComposeTableSequences verifies that Multi_key sequences from a Compose file produce their text, that modifier
+// keys pressed partway through don't disturb the sequence, and that a key which doesn't continue the sequence cancels
+// it rather than typing anything.
+func TestComposeTableSequences(t *testing.T) {
+	c := check.New(t)
+	composer := &Composer{Table: ParseComposeTable([]byte(testComposeFile), "")}
+
+	status, _ := composer.Feed(KeySymMultiKey, 0)
+	c.Equal(ComposeInProgress, status)
+	c.True(composer.Composing())
+	c.Equal("·", composer.Preedit())
+	status, _ = composer.Feed(testKeySymApostrophe, '\'')
+	c.Equal(ComposeInProgress, status)
+	c.Equal("·'", composer.Preedit())
+	status, _ = composer.Feed(testKeySymShiftL, 0)
+	c.Equal(ComposeIgnored, status)
+	c.True(composer.Composing())
+	status, text := composer.Feed(testKeySymE, 'e')
+	c.Equal(ComposeFinished, status)
+	c.Equal("é", text)
+	c.False(composer.Composing())
+	c.Equal("", composer.Preedit())
+
+	status, text = feedAll(composer, KeySymMultiKey, testKeySymO, testKeySymC)
+	c.Equal(ComposeFinished, status)
+	c.Equal("©", text)
+
+	status, text = feedAll(composer, KeySymMultiKey, 0x01002203, 0x78)
+	c.Equal(ComposeFinished, status)
+	c.Equal(`"x"`, text)
+
+	status, _ = feedAll(composer, KeySymMultiKey, testKeySymO, testKeySymE)
+	c.Equal(ComposeCancelled, status)
+	c.False(composer.Composing())
+
+	// The later, shorter definition replaces the longer one.
+	status, text = feedAll(composer, KeySymMultiKey, testKeySymQ)
+	c.Equal(ComposeFinished, status)
+	c.Equal("replaced", text)
+
+	// The result must be valid UTF-8, so a Latin-1 octal escape is rejected.
+	status, _ = feedAll(composer, KeySymMultiKey, 0x78)
+	c.Equal(ComposeCancelled, status)
+
+	status, _ = composer.Feed(testKeySymE, 'e')
+	c.Equal(ComposeIgnored, status, "keys outside of a sequence must be left alone")
+}
+
+// TestComposeDeadKeys verifies dead key handling, both when the table has an entry for the sequence and when it must
+// fall back to combining the accent with the following character.
+func TestComposeDeadKeys(t *testing.T) {
+	c := check.New(t)
+	composer := &Composer{Table: ParseComposeTable([]byte(testComposeFile), "")}
+
+	status, _ := composer.Feed(testKeySymDeadAcute, 0)
+	c.Equal(ComposeInProgress, status)
+	c.Equal("´", composer.Preedit())
+	status, text := composer.Feed(testKeySymE, 'e')
+	c.Equal(ComposeFinished, status)
+	c.Equal("é", text)
+
+	// No table entry, so the accent is combined with the character and normalized.
+	status, text = feedAll(composer, testKeySymDeadCirc, testKeySymO)
+	c.Equal(ComposeFinished, status)
+	c.Equal("ô", text)
+
+	// Combinations without a precomposed form still produce the decomposed text.
+	status, text = feedAll(composer, testKeySymDeadAcute, testKeySymQ)
+	c.Equal(ComposeFinished, status)
+	c.Equal("q́", text)
+
+	// Stacked dead keys apply in the order they were typed.
+	status, text = feedAll(composer, testKeySymDeadCirc, testKeySymDeadAcute, testKeySymE)
+	c.Equal(ComposeFinished, status)
+	c.Equal("ế", text)
+
+	// Pressing the dead key twice, or following it with a space, produces the accent itself.
+	status, text = feedAll(composer, testKeySymDeadAcute, testKeySymDeadAcute)
+	c.Equal(ComposeFinished, status)
+	c.Equal("´", text)
+	status, text = feedAll(composer, testKeySymDeadCirc, keySymSpace)
+	c.Equal(ComposeFinished, status)
+	c.Equal("^", text)
+
+	// Dead keys without a spacing form show the combining mark over a space while pending.
+	status, _ = composer.Feed(testKeySymDeadDot, 0)
+	c.Equal(ComposeInProgress, status)
+	c.Equal(" ̣", composer.Preedit())
+	status, _ = composer.Feed(testKeySymBackSpace, 0)
+	c.Equal(ComposeCancelled, status)
+	c.False(composer.Composing())
+}
+
+// TestComposeEmptyTable verifies that dead keys still work when no Compose file could be found, and that Multi_key is
+// then passed through untouched.
+func TestComposeEmptyTable(t *testing.T) {
+	c := check.New(t)
+	composer := &Composer{Table: &ComposeTable{}}
+	status, _ := composer.Feed(KeySymMultiKey, 0)
+	c.Equal(ComposeIgnored, status)
+	c.False(composer.Composing())
+	status, text := feedAll(composer, testKeySymDeadAcute, testKeySymE)
+	c.Equal(ComposeFinished, status)
+	c.Equal("é", text)
+}
+
+// TestComposeInclude verifies that include directives are followed, with %H expanding to the home directory.
+func TestComposeInclude(t *testing.T) {
+	c := check.New(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	c.NoError(os.WriteFile(filepath.Join(home, "extra"), []byte(`<Multi_key> <e> <e> : "ə"`), 0o600))
+	table := ParseComposeTable([]byte("include \"%H/extra\"\n<Multi_key> <o> <o> : \"°\"\n"), home)
+	composer := &Composer{Table: table}
+	status, text := feedAll(composer, KeySymMultiKey, testKeySymE, testKeySymE)
+	c.Equal(ComposeFinished, status)
+	c.Equal("ə", text)
+	status, text = feedAll(composer, KeySymMultiKey, testKeySymO, testKeySymO)
+	c.Equal(ComposeFinished, status)
+	c.Equal("°", text)
+}
+
+func TestKeySymFromName(t *testing.T) {
+	c := check.New(t)
+	for _, tc := range []struct {
+		name   string
+		keySym uint32
+	}{
+		{"space", 0x20},
+		{"7", 0x37},
+		{"Z", 0x5a},
+		{"asciitilde", 0x7e},
+		{"nobreakspace", 0xa0},
+		{"eacute", 0xe9},
+		{"ydiaeresis", 0xff},
+		{"guillemetleft", 0xab},
+		{"dead_grave", 0xfe50},
+		{"dead_currency", 0xfe6f},
+		{"Multi_key", KeySymMultiKey},
+		{"U00E9", 0xe9},
+		{"U2203", 0x01002203},
+	} {
+		keySym, ok := KeySymFromName(tc.name)
+		c.True(ok, tc.name)
+		c.Equal(tc.keySym, keySym, tc.name)
+	}
+	_, ok := KeySymFromName("no_such_keysym")
+	c.False(ok)
+}
@@ -14,6 +14,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"io"
+	"math"
 	"net"
 	"os"
 	"strconv"
@@ -24,17 +25,23 @@ import (
 )
 
 // This file implements just enough of the D-Bus protocol (https://dbus.freedesktop.org/doc/dbus-specification.html) to
-// query and watch the XDG Desktop Portal's "color-scheme" appearance setting. It deliberately avoids pulling in a
-// full D-Bus dependency. All encoding is little-endian, which matches every platform Unison runs on.
+// query and watch the XDG Desktop Portal's "color-scheme" appearance setting, to talk to the input method framework
+// (see ibus.go) and to serve the accessibility tree (see atspi.go). It deliberately avoids pulling in a full D-Bus
+// dependency. All encoding is little-endian, which matches every platform Unison runs on.
 
 const (
+	dbusTypeMethodCall   = 1
 	dbusTypeMethodReturn = 2
+	dbusTypeError        = 3
 	dbusTypeSignal       = 4
 
 	dbusFieldPath        = 1
 	dbusFieldInterface   = 2
 	dbusFieldMember      = 3
+	dbusFieldErrorName   = 4
+	dbusFieldReplySerial = 5
 	dbusFieldDestination = 6
+	dbusFieldSender      = 7
 	dbusFieldSignature   = 8
 
 	dbusColorSchemeNamespace = "org.freedesktop.appearance"
@@ -119,14 +126,20 @@ func WatchColorScheme(onChange func(value uint32)) {
 
 const opMethodCall = 1
 
-// dbusConn is a minimal authenticated connection to the session bus.
+// dbusConn is a minimal authenticated connection to a bus.
 type dbusConn struct {
 	conn   net.Conn
+	name   string // The unique name assigned by the bus, once hello() has been called.
 	serial uint32
 }
 
+// dialDBus connects to the session bus.
 func dialDBus() (*dbusConn, error) {
-	path, err := dbusSocketPath(os.Getenv("DBUS_SESSION_BUS_ADDRESS"))
+	return dialDBusAddress(os.Getenv("DBUS_SESSION_BUS_ADDRESS"))
+}
+
+func dialDBusAddress(addr string) (*dbusConn, error) {
+	path, err := dbusSocketPath(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -214,20 +227,23 @@ func (c *dbusConn) readLine() (string, error) {
 	}
 }
 
-// hello performs the mandatory org.freedesktop.DBus.Hello handshake.
+// hello performs the mandatory org.freedesktop.DBus.Hello handshake, recording the unique name the bus assigns.
 func (c *dbusConn) hello() error {
 	if err := c.send(opMethodCall, "org.freedesktop.DBus", "/org/freedesktop/DBus",
 		"org.freedesktop.DBus", "Hello", "", nil); err != nil {
 		return err
 	}
-	_, err := c.receiveReply()
-	return err
+	msg, err := c.receiveReply()
+	if err != nil {
+		return err
+	}
+	r := dbusReader{data: msg.body}
+	c.name, _ = r.str()
+	return nil
 }
 
-// send marshals and writes a method call. body must already be encoded to match signature.
+// send marshals and writes a method call or signal. body must already be encoded to match signature.
 func (c *dbusConn) send(typ byte, dest, path, iface, member, signature string, body []byte) error {
-	c.serial++
-
 	// Header fields are an array of (byte, variant) structs, written at message offset 16, which is 8-aligned, so the
 	// buffer-relative alignment used here matches the absolute alignment the protocol requires.
 	var f dbusBuf
@@ -242,7 +258,33 @@ func (c *dbusConn) send(typ byte, dest, path, iface, member, signature string, b
 	if signature != "" {
 		dbusField(&f, dbusFieldSignature, 'g', signature)
 	}
+	return c.write(typ, &f, body)
+}
 
+// sendReply marshals and writes the reply to a method call: a method return, or an error if errorName is not empty.
+func (c *dbusConn) sendReply(dest string, replySerial uint32, errorName, signature string, body []byte) error {
+	var f dbusBuf
+	typ := byte(dbusTypeMethodReturn)
+	if errorName != "" {
+		typ = dbusTypeError
+		dbusField(&f, dbusFieldErrorName, 's', errorName)
+	}
+	f.align(8)
+	f.byte(dbusFieldReplySerial)
+	f.sig("u")
+	f.u32(replySerial)
+	if dest != "" {
+		dbusField(&f, dbusFieldDestination, 's', dest)
+	}
+	if signature != "" {
+		dbusField(&f, dbusFieldSignature, 'g', signature)
+	}
+	return c.write(typ, &f, body)
+}
+
+// write assigns the next serial number to a message with the given header fields and body and writes it.
+func (c *dbusConn) write(typ byte, f *dbusBuf, body []byte) error {
+	c.serial++
 	var m dbusBuf
 	m.byte('l') // Little-endian byte order.
 	m.byte(typ)
@@ -261,9 +303,15 @@ func (c *dbusConn) send(typ byte, dest, path, iface, member, signature string, b
 
 // dbusMessage is a decoded incoming message; only the fields we need are retained.
 type dbusMessage struct {
-	member string
-	body   []byte
-	typ    byte
+	path        string
+	iface       string
+	member      string
+	sender      string
+	signature   string
+	body        []byte
+	serial      uint32
+	replySerial uint32
+	typ         byte
 }
 
 // receiveReply reads messages until a non-signal (method return or error) arrives.
@@ -321,45 +369,61 @@ func (c *dbusConn) readMessage(timeout time.Duration) (*dbusMessage, error) {
 	if _, err := io.ReadFull(c.conn, body); err != nil {
 		return nil, err
 	}
-	return &dbusMessage{typ: fixed[1], member: dbusParseMember(fields), body: body}, nil
+	msg := &dbusMessage{typ: fixed[1], serial: binary.LittleEndian.Uint32(fixed[8:12]), body: body}
+	dbusParseHeader(fields, msg)
+	return msg, nil
 }
 
-// dbusParseMember walks the header field array and returns the MEMBER field, if present.
-func dbusParseMember(fields []byte) string {
+// dbusParseHeader walks the header field array and fills in the PATH, INTERFACE, MEMBER, SENDER, SIGNATURE and
+// REPLY_SERIAL fields of the message, if present.
+func dbusParseHeader(fields []byte, msg *dbusMessage) {
 	r := dbusReader{data: fields}
-	var member string
 	for r.remaining() > 0 {
 		r.align(8) // Each (byte, variant) struct is 8-aligned.
 		code, ok := r.byte()
 		if !ok {
-			break
+			return
 		}
 		sig, ok := r.sig()
 		if !ok {
-			break
+			return
 		}
 		switch sig {
 		case "s", "o":
 			v, vok := r.str()
 			if !vok {
-				return member
+				return
 			}
-			if code == dbusFieldMember {
-				member = v
+			switch code {
+			case dbusFieldPath:
+				msg.path = v
+			case dbusFieldInterface:
+				msg.iface = v
+			case dbusFieldMember:
+				msg.member = v
+			case dbusFieldSender:
+				msg.sender = v
 			}
 		case "g":
-			if _, gok := r.sig(); !gok {
-				return member
+			v, gok := r.sig()
+			if !gok {
+				return
+			}
+			if code == dbusFieldSignature {
+				msg.signature = v
 			}
 		case "u":
-			if _, uok := r.uint32(); !uok {
-				return member
+			v, uok := r.uint32()
+			if !uok {
+				return
+			}
+			if code == dbusFieldReplySerial {
+				msg.replySerial = v
 			}
 		default:
-			return member // Unknown field type; we cannot safely skip it.
+			return // Unknown field type; we cannot safely skip it.
 		}
 	}
-	return member
 }
 
 // dbusBuf accumulates a little-endian D-Bus message body or header with the protocol's alignment rules.
@@ -382,6 +446,39 @@ func (w *dbusBuf) u32(v uint32) {
 	w.b = binary.LittleEndian.AppendUint32(w.b, v)
 }
 
+func (w *dbusBuf) i32(v int32) {
+	w.u32(uint32(v))
+}
+
+func (w *dbusBuf) i16(v int16) {
+	w.align(2)
+	w.b = binary.LittleEndian.AppendUint16(w.b, uint16(v))
+}
+
+func (w *dbusBuf) boolean(v bool) {
+	if v {
+		w.u32(1)
+	} else {
+		w.u32(0)
+	}
+}
+
+func (w *dbusBuf) double(v float64) {
+	w.align(8)
+	w.b = binary.LittleEndian.AppendUint64(w.b, math.Float64bits(v))
+}
+
+// array writes an ARRAY whose elements have the given alignment, calling fill to write the elements.
+func (w *dbusBuf) array(elementAlignment int, fill func()) {
+	w.align(4)
+	lengthAt := len(w.b)
+	w.b = append(w.b, 0, 0, 0, 0)
+	w.align(elementAlignment) // The padding before the first element is not included in the length.
+	start := len(w.b)
+	fill()
+	binary.LittleEndian.PutUint32(w.b[lengthAt:], uint32(len(w.b)-start))
+}
+
 // str writes a STRING/OBJECT_PATH: a 4-byte length, the bytes, and a trailing NUL.
 func (w *dbusBuf) str(s string) {
 	w.u32(uint32(len(s)))
@@ -445,6 +542,30 @@ func (r *dbusReader) uint32() (uint32, bool) {
 	return v, true
 }
 
+func (r *dbusReader) boolean() (bool, bool) {
+	v, ok := r.uint32()
+	return v != 0, ok
+}
+
+func (r *dbusReader) int32() (int32, bool) {
+	v, ok := r.uint32()
+	return int32(v), ok
+}
+
+// skipArray skips over an ARRAY whose elements have the given alignment.
+func (r *dbusReader) skipArray(elementAlignment int) bool {
+	n, ok := r.uint32()
+	if !ok {
+		return false
+	}
+	r.align(elementAlignment) // The padding before the first element is not included in the length.
+	if r.remaining() < int(n) {
+		return false
+	}
+	r.pos += int(n)
+	return true
+}
+
 // sig reads a SIGNATURE (single-byte length, bytes, NUL).
 func (r *dbusReader) sig() (string, bool) {
 	n, ok := r.byte()
@@ -0,0 +1,386 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements a client for the input method frameworks used to type languages such as Chinese, Japanese and
+// Korean. Rather than the legacy XIM protocol, it speaks the IBus input context D-Bus interface, which is what GTK and
+// Qt use today. The interface is reached through the org.freedesktop.portal.IBus service on the session bus, which both
+// IBus and Fcitx 5 provide, so a single implementation covers both.
+
+const (
+	ibusPortalService         = "org.freedesktop.portal.IBus"
+	ibusPortalPath            = "/org/freedesktop/IBus"
+	ibusPortalInterface       = "org.freedesktop.IBus.Portal"
+	ibusInputContextInterface = "org.freedesktop.IBus.InputContext"
+	ibusCapPreeditText        = 1 << 0
+	ibusCapFocus              = 1 << 3
+	ibusReleaseMask           = 1 << 30
+	ibusKeyCodeOffset         = 8 // IBus key codes are evdev codes, which are X key codes less 8.
+	ibusKeyTimeout            = 250 * time.Millisecond
+	ibusTextSignature         = "(sa{sv}sv)"
+	ibusAttrListSignature     = "(sa{sv}av)"
+)
+
+// InputMethodEventKind identifies the kind of an InputMethodEvent.
+type InputMethodEventKind byte
+
+// Possible InputMethodEventKind values.
+const (
+	// InputMethodPreedit reports a change to the text being composed. Empty text means the composition was abandoned.
+	InputMethodPreedit InputMethodEventKind = iota
+	// InputMethodCommit reports text that the input method has finished composing.
+	InputMethodCommit
+	// InputMethodForwardKey asks the application to process a key event itself.
+	InputMethodForwardKey
+	// InputMethodUnhandledKey returns a key offered to the input method by ProcessKey() that it didn't consume, so that
+	// the application can process it itself.
+	InputMethodUnhandledKey
+)
+
+// InputMethodEvent holds an event sent by the input method.
+type InputMethodEvent struct {
+	// Text is the preedit or committed text.
+	Text string
+	// Caret is the caret position within a preedit Text, in runes.
+	Caret int
+	// KeySym, KeyCode and State describe a forwarded or unhandled key. KeyCode is an X key code and State is an X event
+	// state mask.
+	KeySym  uint32
+	KeyCode uint32
+	State   uint32
+	// Release is true if a forwarded or unhandled key was released rather than pressed.
+	Release bool
+	Kind    InputMethodEventKind
+}
+
+// InputMethod is a connection to an input context of the input method framework.
+type InputMethod struct {
+	conn    *dbusConn
+	notify  func()
+	replies map[uint32]func(msg *dbusMessage)
+	path    string
+	events  []InputMethodEvent
+	lock    sync.Mutex
+	closed  bool
+}
+
+// InputMethodRequested returns true if the environment indicates the user has an input method framework set up, which
+// is signaled the same way for X11 applications of every toolkit.
+func InputMethodRequested() bool {
+	for _, name := range []string{"XMODIFIERS", "GTK_IM_MODULE", "QT_IM_MODULE"} {
+		v := strings.ToLower(os.Getenv(name))
+		if strings.Contains(v, "ibus") || strings.Contains(v, "fcitx") {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenInputMethod connects to the input method framework and creates an input context for the application. notify is
+// called, from an arbitrary goroutine, whenever new events are available via TakeEvents(). This blocks while the
+// connection is established, so it should be called from a goroutine other than the UI thread.
+func OpenInputMethod(clientName string, notify func()) (*InputMethod, error) {
+	c, err := dialDBus()
+	if err != nil {
+		return nil, err
+	}
+	im := &InputMethod{conn: c, notify: notify, replies: make(map[uint32]func(msg *dbusMessage))}
+	if err = im.setup(clientName); err != nil {
+		c.close()
+		return nil, err
+	}
+	go im.readLoop()
+	return im, nil
+}
+
+func (im *InputMethod) setup(clientName string) error {
+	c := im.conn
+	if err := c.hello(); err != nil {
+		return err
+	}
+	var body dbusBuf
+	body.str(clientName)
+	if err := c.send(opMethodCall, ibusPortalService, ibusPortalPath, ibusPortalInterface, "CreateInputContext", "s",
+		body.b); err != nil {
+		return err
+	}
+	msg, err := c.receiveReply()
+	if err != nil {
+		return err
+	}
+	if msg.typ != dbusTypeMethodReturn {
+		return errors.New("unable to create an input context")
+	}
+	r := dbusReader{data: msg.body}
+	var ok bool
+	if im.path, ok = r.str(); !ok {
+		return errors.New("invalid input context path")
+	}
+	body = dbusBuf{}
+	body.str("type='signal',interface='" + ibusInputContextInterface + "',path='" + im.path + "'")
+	if err = c.send(opMethodCall, "org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "AddMatch",
+		"s", body.b); err != nil {
+		return err
+	}
+	if _, err = c.receiveReply(); err != nil {
+		return err
+	}
+	body = dbusBuf{}
+	body.u32(ibusCapPreeditText | ibusCapFocus)
+	return c.send(opMethodCall, ibusPortalService, im.path, ibusInputContextInterface, "SetCapabilities", "u", body.b)
+}
+
+// Close disconnects from the input method framework.
+func (im *InputMethod) Close() {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	if !im.closed {
+		im.closed = true
+		im.conn.close()
+	}
+}
+
+// FocusIn tells the input method that the application now has the keyboard focus.
+func (im *InputMethod) FocusIn() {
+	im.call("FocusIn", "", nil, nil)
+}
+
+// FocusOut tells the input method that the application no longer has the keyboard focus.
+func (im *InputMethod) FocusOut() {
+	im.call("FocusOut", "", nil, nil)
+}
+
+// Reset tells the input method to discard any composition in progress.
+func (im *InputMethod) Reset() {
+	im.call("Reset", "", nil, nil)
+}
+
+// SetCursorLocation tells the input method where the caret is, in root window coordinates, so that it can position its
+// candidate window next to it.
+func (im *InputMethod) SetCursorLocation(x, y, width, height int32) {
+	var body dbusBuf
+	body.i32(x)
+	body.i32(y)
+	body.i32(width)
+	body.i32(height)
+	im.call("SetCursorLocation", "iiii", body.b, nil)
+}
+
+// ProcessKey offers a key event to the input method. keyCode is an X key code and state is an X event state mask. This
+// doesn't wait for the input method to decide whether it wants the key: if it doesn't consume it, or fails to respond
+// in a timely manner, an InputMethodUnhandledKey event for the key is queued for TakeEvents(), following any events the
+// input method generated in response to it. Returns false if the key could not be offered, in which case the
+// application should process it immediately.
+func (im *InputMethod) ProcessKey(keySym, keyCode, state uint32, release bool) bool {
+	if keyCode < ibusKeyCodeOffset {
+		return false
+	}
+	var body dbusBuf
+	body.u32(keySym)
+	body.u32(keyCode - ibusKeyCodeOffset)
+	if release {
+		body.u32(state | ibusReleaseMask)
+	} else {
+		body.u32(state)
+	}
+	var once sync.Once
+	respond := func(msg *dbusMessage) {
+		once.Do(func() {
+			if msg != nil && msg.typ == dbusTypeMethodReturn {
+				r := dbusReader{data: msg.body}
+				if handled, ok := r.boolean(); ok && handled {
+					return
+				}
+			}
+			im.queue(InputMethodEvent{
+				Kind:    InputMethodUnhandledKey,
+				KeySym:  keySym,
+				KeyCode: keyCode,
+				State:   state,
+				Release: release,
+			})
+		})
+	}
+	serial, ok := im.call("ProcessKeyEvent", "uuu", body.b, respond)
+	if !ok {
+		return false
+	}
+	time.AfterFunc(ibusKeyTimeout, func() {
+		im.lock.Lock()
+		delete(im.replies, serial)
+		im.lock.Unlock()
+		respond(nil)
+	})
+	return true
+}
+
+// TakeEvents returns the events received since the last call.
+func (im *InputMethod) TakeEvents() []InputMethodEvent {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	events := im.events
+	im.events = nil
+	return events
+}
+
+// queue adds the event to those returned by TakeEvents() and notifies the application of it.
+func (im *InputMethod) queue(event InputMethodEvent) {
+	im.lock.Lock()
+	im.events = append(im.events, event)
+	im.lock.Unlock()
+	if im.notify != nil {
+		im.notify()
+	}
+}
+
+// call sends a method call to the input context, returning its serial number and true if it was sent. If reply isn't
+// nil, it is called from the read loop with the reply once it arrives, or with nil if the connection is lost first.
+func (im *InputMethod) call(member, signature string, body []byte, reply func(msg *dbusMessage)) (uint32, bool) {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	if im.closed {
+		return 0, false
+	}
+	if err := im.conn.send(opMethodCall, ibusPortalService, im.path, ibusInputContextInterface, member, signature,
+		body); err != nil {
+		return 0, false
+	}
+	if reply != nil {
+		im.replies[im.conn.serial] = reply
+	}
+	return im.conn.serial, true
+}
+
+func (im *InputMethod) readLoop() {
+	defer func() {
+		im.lock.Lock()
+		im.closed = true
+		pending := make([]func(msg *dbusMessage), 0, len(im.replies))
+		for serial, reply := range im.replies {
+			delete(im.replies, serial)
+			pending = append(pending, reply)
+		}
+		im.lock.Unlock()
+		for _, reply := range pending {
+			reply(nil)
+		}
+	}()
+	for {
+		msg, err := im.conn.readMessage(0)
+		if err != nil {
+			return
+		}
+		switch msg.typ {
+		case dbusTypeMethodReturn, dbusTypeError:
+			im.lock.Lock()
+			reply := im.replies[msg.replySerial]
+			delete(im.replies, msg.replySerial)
+			im.lock.Unlock()
+			if reply != nil {
+				reply(msg)
+			}
+		case dbusTypeSignal:
+			if msg.path == im.path && msg.iface == ibusInputContextInterface {
+				if event, ok := ibusDecodeSignal(msg); ok {
+					im.queue(event)
+				}
+			}
+		}
+	}
+}
+
+func ibusDecodeSignal(msg *dbusMessage) (InputMethodEvent, bool) {
+	r := dbusReader{data: msg.body}
+	switch msg.member {
+	case "CommitText":
+		text, ok := ibusReadText(&r)
+		return InputMethodEvent{Kind: InputMethodCommit, Text: text}, ok
+	case "UpdatePreeditText":
+		text, ok := ibusReadText(&r)
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		caret, ok := r.uint32()
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		visible, ok := r.boolean()
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		if !visible {
+			text = ""
+			caret = 0
+		}
+		return InputMethodEvent{Kind: InputMethodPreedit, Text: text, Caret: int(caret)}, true
+	case "HidePreeditText":
+		return InputMethodEvent{Kind: InputMethodPreedit}, true
+	case "ForwardKeyEvent":
+		keySym, ok := r.uint32()
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		keyCode, ok := r.uint32()
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		state, ok := r.uint32()
+		if !ok {
+			return InputMethodEvent{}, false
+		}
+		return InputMethodEvent{
+			Kind:    InputMethodForwardKey,
+			KeySym:  keySym,
+			KeyCode: keyCode + ibusKeyCodeOffset,
+			State:   state &^ ibusReleaseMask,
+			Release: state&ibusReleaseMask != 0,
+		}, true
+	default:
+		return InputMethodEvent{}, false
+	}
+}
+
+// ibusReadText reads an IBusText, which is serialized as a variant holding a struct of the type name, a dictionary of
+// attachments, the text itself and a variant holding the text's attribute list. Only the text is of interest; the
+// underline and highlight attributes are not needed, since the preedit text is always drawn underlined.
+func ibusReadText(r *dbusReader) (string, bool) {
+	sig, ok := r.sig()
+	if !ok || sig != ibusTextSignature {
+		return "", false
+	}
+	r.align(8)
+	if _, ok = r.str(); !ok {
+		return "", false
+	}
+	if !r.skipArray(8) {
+		return "", false
+	}
+	text, ok := r.str()
+	if !ok {
+		return "", false
+	}
+	// Skip the attribute list, so that any arguments that follow can be read.
+	if sig, ok = r.sig(); !ok || sig != ibusAttrListSignature {
+		return "", false
+	}
+	r.align(8)
+	if _, ok = r.str(); !ok || !r.skipArray(8) || !r.skipArray(1) {
+		return "", false
+	}
+	return text, true
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package x11
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const testInputContextPath = "/org/freedesktop/IBus/InputContext_1"
+
+// appendTestIBusText appends an IBusText variant, with an empty attribute list, as IBus serializes it.
+func appendTestIBusText(b *dbusBuf, text string) {
+	b.sig(ibusTextSignature)
+	b.align(8)
+	b.str("IBusText")
+	b.u32(0) // Empty attachments dictionary...
+	b.align(8)
+	b.str(text)
+	b.sig(ibusAttrListSignature) // ...and the attribute list variant.
+	b.align(8)
+	b.str("IBusAttrList")
+	b.u32(0)
+	b.align(8)
+	b.u32(0)
+}
+
+// writeTestMessage writes a message as the bus would deliver it, including the REPLY_SERIAL header field that the
+// client never sends itself.
+func writeTestMessage(t *testing.T, conn net.Conn, typ byte, member string, replySerial uint32, signature string,
+	body []byte) {
+	t.Helper()
+	var f dbusBuf
+	if typ == dbusTypeSignal {
+		dbusField(&f, dbusFieldPath, 'o', testInputContextPath)
+		dbusField(&f, dbusFieldInterface, 's', ibusInputContextInterface)
+		dbusField(&f, dbusFieldMember, 's', member)
+	} else {
+		f.align(8)
+		f.byte(dbusFieldReplySerial)
+		f.sig("u")
+		f.u32(replySerial)
+	}
+	if signature != "" {
+		dbusField(&f, dbusFieldSignature, 'g', signature)
+	}
+	var m dbusBuf
+	m.byte('l')
+	m.byte(typ)
+	m.byte(0)
+	m.byte(1)
+	m.b = binary.LittleEndian.AppendUint32(m.b, uint32(len(body)))
+	m.b = binary.LittleEndian.AppendUint32(m.b, 1000+replySerial)
+	m.b = binary.LittleEndian.AppendUint32(m.b, uint32(len(f.b)))
+	m.b = append(m.b, f.b...)
+	m.align(8)
+	m.b = append(m.b, body...)
+	_, err := conn.Write(m.b)
+	check.New(t).NoError(err)
+}
+
+func newTestInputMethod(t *testing.T) (im *InputMethod, server *dbusConn, notified chan struct{}) {
+	t.Helper()
+	clientEnd, serverEnd := net.Pipe()
+	notified = make(chan struct{}, 16)
+	im = &InputMethod{
+		conn:    &dbusConn{conn: clientEnd},
+		path:    testInputContextPath,
+		replies: make(map[uint32]func(msg *dbusMessage)),
+		notify:  func() { notified <- struct{}{} },
+	}
+	go im.readLoop()
+	server = &dbusConn{conn: serverEnd}
+	t.Cleanup(func() {
+		im.Close()
+		server.close()
+	})
+	return im, server, notified
+}
+
+// awaitNotifications waits for the input method to report that new events are available the given number of times.
+func awaitNotifications(t *testing.T, notified chan struct{}, count int) {
+	t.Helper()
+	for range count {
+		select {
+		case <-notified:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the input method's events")
+		}
+	}
+}
+
+// TestInputMethodProcessKey verifies that a key event is sent to the input context with an evdev key code without
+// waiting for the reply, that nothing more is reported for a key the input method consumes, and that a key it doesn't
+// consume is returned after any text it committed in response.
+func TestInputMethodProcessKey(t *testing.T) {
+	c := check.New(t)
+	im, server, notified := newTestInputMethod(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, handled := range []uint32{1, 0} {
+			msg, err := server.readMessage(time.Second)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if msg.member != "ProcessKeyEvent" || msg.path != testInputContextPath {
+				t.Errorf("unexpected call %s on %s", msg.member, msg.path)
+				return
+			}
+			r := dbusReader{data: msg.body}
+			keySym, _ := r.uint32()
+			keyCode, _ := r.uint32()
+			state, _ := r.uint32()
+			if handled == 1 && (keySym != 0x61 || keyCode != 30 || state != 1) {
+				t.Errorf("unexpected key arguments %x %d %x", keySym, keyCode, state)
+			}
+			var body dbusBuf
+			appendTestIBusText(&body, "あ")
+			writeTestMessage(t, server.conn, dbusTypeSignal, "CommitText", 0, "v", body.b)
+			body = dbusBuf{}
+			body.u32(handled)
+			writeTestMessage(t, server.conn, dbusTypeMethodReturn, "", msg.serial, "b", body.b)
+		}
+	}()
+	c.True(im.ProcessKey(0x61, 38, 1, false))
+	c.True(im.ProcessKey(0x62, 56, 0, false))
+	<-done
+	awaitNotifications(t, notified, 3)
+	c.Equal([]InputMethodEvent{
+		{Kind: InputMethodCommit, Text: "あ"},
+		{Kind: InputMethodCommit, Text: "あ"},
+		{Kind: InputMethodUnhandledKey, KeySym: 0x62, KeyCode: 56},
+	}, im.TakeEvents())
+	c.Equal(0, len(im.TakeEvents()))
+}
+
+// TestInputMethodProcessKeyNoReply verifies that a key is returned as unhandled when the input method does not answer
+// in time, so that typing keeps working if the input method hangs.
+func TestInputMethodProcessKeyNoReply(t *testing.T) {
+	c := check.New(t)
+	im, server, notified := newTestInputMethod(t)
+	go func() {
+		_, _ = server.readMessage(time.Second) //nolint:errcheck // Deliberately never answered
+	}()
+	c.True(im.ProcessKey(0x61, 38, 0, true))
+	awaitNotifications(t, notified, 1)
+	c.Equal([]InputMethodEvent{{Kind: InputMethodUnhandledKey, KeySym: 0x61, KeyCode: 38, Release: true}},
+		im.TakeEvents())
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	c.Equal(0, len(im.replies), "the abandoned reply must not be left registered")
+}
+
+func TestIBusDecodeSignal(t *testing.T) {
+	c := check.New(t)
+
+	var body dbusBuf
+	appendTestIBusText(&body, "nǐ hǎo")
+	body.u32(3)
+	body.u32(1)
+	event, ok := ibusDecodeSignal(&dbusMessage{member: "UpdatePreeditText", body: body.b})
+	c.True(ok)
+	c.Equal(InputMethodEvent{Kind: InputMethodPreedit, Text: "nǐ hǎo", Caret: 3}, event)
+
+	// A hidden preedit is reported as empty.
+	body = dbusBuf{}
+	appendTestIBusText(&body, "nǐ")
+	body.u32(2)
+	body.u32(0)
+	event, ok = ibusDecodeSignal(&dbusMessage{member: "UpdatePreeditText", body: body.b})
+	c.True(ok)
+	c.Equal(InputMethodEvent{Kind: InputMethodPreedit}, event)
+
+	event, ok = ibusDecodeSignal(&dbusMessage{member: "HidePreeditText"})
+	c.True(ok)
+	c.Equal(InputMethodEvent{Kind: InputMethodPreedit}, event)
+
+	body = dbusBuf{}
+	body.u32(0xff0d)
+	body.u32(28)
+	body.u32(ibusReleaseMask | 1)
+	event, ok = ibusDecodeSignal(&dbusMessage{member: "ForwardKeyEvent", body: body.b})
+	c.True(ok)
+	c.Equal(InputMethodEvent{Kind: InputMethodForwardKey, KeySym: 0xff0d, KeyCode: 36, State: 1, Release: true}, event)
+
+	// Truncated or unexpected content is rejected rather than misread.
+	body = dbusBuf{}
+	body.sig("s")
+	body.str("not an IBusText")
+	_, ok = ibusDecodeSignal(&dbusMessage{member: "CommitText", body: body.b})
+	c.False(ok)
+	_, ok = ibusDecodeSignal(&dbusMessage{member: "ForwardKeyEvent", body: []byte{1, 0}})
+	c.False(ok)
+	_, ok = ibusDecodeSignal(&dbusMessage{member: "SomethingElse"})
+	c.False(ok)
+}
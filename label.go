@@ -16,6 +16,7 @@ import (
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/paintstyle"
 	"github.com/richardwilkes/unison/enums/pathop"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/side"
 )
 
@@ -55,6 +56,7 @@ func NewLabel() *Label {
 	l.Self = l
 	l.SetSizer(l.DefaultSizes)
 	l.DrawCallback = l.DefaultDraw
+	l.AccessibilityCallback = l.DefaultAccessibility
 	return l
 }
 
@@ -72,6 +74,19 @@ func (l *Label) SetTitle(text string) {
 	l.Text = NewText(text, &l.TextDecoration)
 }
 
+// DefaultAccessibility provides the default accessibility description. Labels with neither text nor a drawable, which
+// are typically used as spacers, are not exposed.
+func (l *Label) DefaultAccessibility() Accessibility {
+	a := Accessibility{Name: l.Text.String(), ActiveItem: -1}
+	switch {
+	case a.Name != "":
+		a.Role = role.Label
+	case l.Drawable != nil:
+		a.Role = role.Image
+	}
+	return a
+}
+
 // DefaultSizes provides the default sizing.
 func (l *Label) DefaultSizes(hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
 	prefSize, _ = LabelContentSizes(l.Text, l.Drawable, l.Font, l.Side, l.Gap)
@@ -14,6 +14,7 @@ import (
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/side"
 )
 
@@ -53,6 +54,14 @@ func NewLink(title, tooltip, target string, theme *LinkTheme, clickHandler func(
 	if tooltip != "" {
 		link.Tooltip = NewTooltipWithText(tooltip)
 	}
+	link.AccessibilityCallback = func() Accessibility {
+		a := link.DefaultAccessibility()
+		a.Role = role.Link
+		if clickHandler != nil {
+			a.Action = func() { clickHandler(link, target) }
+		}
+		return a
+	}
 	link.UpdateCursorCallback = func(_ geom.Point) *Cursor {
 		if link.Enabled() {
 			return PointingCursor()
@@ -12,12 +12,14 @@ package unison
 import (
 	"slices"
 	"time"
+	"unicode"
 
 	"github.com/richardwilkes/toolbox/v2/collection/bitset"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/xmath"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 // DefaultListTheme holds the default ListTheme values for Lists. Modifying this data will not alter existing Lists,
@@ -32,6 +34,7 @@ var DefaultListTheme = ListTheme{
 	InactiveSelectionInk:   ThemeDeepFocus,
 	OnInactiveSelectionInk: ThemeOnDeepFocus,
 	FlashAnimationTime:     100 * time.Millisecond,
+	TypeToSelectTimeout:    DefaultTypeToSelectTimeout,
 }
 
 // ListTheme holds theming data for a List.
@@ -45,20 +48,25 @@ type ListTheme struct {
 	InactiveSelectionInk   Ink
 	OnInactiveSelectionInk Ink
 	FlashAnimationTime     time.Duration
+	TypeToSelectTimeout    time.Duration
 }
 
 // List provides a control that allows the user to select from a list of items, represented by cells.
 type List[T any] struct {
-	DoubleClickCallback  func()
-	NewSelectionCallback func()
-	Factory              CellFactory
-	Selection            *bitset.BitSet
-	savedSelection       *bitset.BitSet
-	rows                 []T
+	DoubleClickCallback      func()
+	NewSelectionCallback     func()
+	DragRemovedItemsCallback func() // Called whenever a drag removes one or more items from the list, but only if the source and destination lists were different.
+	DropOccurredCallback     func() // Called whenever a drop occurs that modifies the list.
+	Factory                  CellFactory
+	Selection                *bitset.BitSet
+	savedSelection           *bitset.BitSet
+	rows                     []T
 	ListTheme
 	Panel
+	typeToSelect      typeToSelect
 	anchor            int
 	lastSel           int
+	pressedIndex      int
 	allowMultiple     bool
 	pressed           bool
 	suppressSelection bool
@@ -75,6 +83,7 @@ func NewList[T any]() *List[T] {
 		savedSelection: &bitset.BitSet{},
 		anchor:         -1,
 		lastSel:        -1,
+		pressedIndex:   -1,
 		allowMultiple:  true,
 	}
 	l.Self = l
@@ -86,6 +95,8 @@ func NewList[T any]() *List[T] {
 	l.MouseDragCallback = l.DefaultMouseDrag
 	l.MouseUpCallback = l.DefaultMouseUp
 	l.KeyDownCallback = l.DefaultKeyDown
+	l.RuneTypedCallback = l.DefaultRuneTyped
+	l.AccessibilityCallback = l.DefaultAccessibility
 	l.InstallCmdHandlers(SelectAllItemID, func(_ any) bool { return l.CanSelectAll() }, func(_ any) { l.SelectAll() })
 	return l
 }
@@ -246,6 +257,48 @@ func (l *List[T]) DefaultFocusGained() {
 	l.MarkForRedraw()
 }
 
+// DefaultAccessibility provides the default accessibility description. Each row is exposed as an item named by the text
+// of its cell.
+func (l *List[T]) DefaultAccessibility() Accessibility {
+	a := Accessibility{
+		Item:       l.accessibleItem,
+		ItemCount:  len(l.rows),
+		ActiveItem: -1,
+		Role:       role.List,
+	}
+	if l.Selection.Count() != 0 {
+		a.ActiveItem = l.Selection.LastSet()
+	}
+	if l.allowMultiple {
+		a.State |= AccessibleMultiSelectable
+	}
+	return a
+}
+
+func (l *List[T]) accessibleItem(index int) Accessibility {
+	if index < 0 || index >= len(l.rows) {
+		return Accessibility{ActiveItem: -1}
+	}
+	a := Accessibility{
+		Action: func() {
+			if index < len(l.rows) {
+				l.Select(false, index)
+				SafeCall(l.NewSelectionCallback)
+				l.ScrollRectIntoView(l.RowRect(index))
+			}
+		},
+		Name:       AccessibleText(l.cell(index)),
+		Bounds:     l.RowRect(index),
+		ActiveItem: -1,
+		Role:       role.ListItem,
+		State:      AccessibleSelectable,
+	}
+	if l.Selection.State(index) {
+		a.State |= AccessibleSelected
+	}
+	return a
+}
+
 func (l *List[T]) cellParams(row int) (fg, bg Ink, selected, focused bool) {
 	focused = l.Focused()
 	if !l.suppressSelection {
@@ -342,7 +395,9 @@ func (l *List[T]) DefaultMouseDown(where geom.Point, _, clickCount int, mods mod
 	l.savedSelection = l.Selection.Clone()
 	l.lastSel = -1
 	l.wasDragged = false
-	if index, _ := l.rowAt(where.Y); index >= 0 {
+	index, _ := l.rowAt(where.Y)
+	l.pressedIndex = index
+	if index >= 0 {
 		switch {
 		case mods.DiscontiguousSelectionDown():
 			if l.allowMultiple {
@@ -478,6 +533,22 @@ func (l *List[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, _repeat bo
 	return true
 }
 
+// DefaultRuneTyped provides the default rune typed handling, which selects the next row whose text begins with the
+// runes typed in quick succession.
+func (l *List[T]) DefaultRuneTyped(ch rune) bool {
+	if unicode.IsControl(ch) || (unicode.IsSpace(ch) && !l.typeToSelect.active(l.TypeToSelectTimeout)) {
+		return false
+	}
+	index := l.typeToSelect.find(ch, l.TypeToSelectTimeout, len(l.rows), l.Selection.FirstSet(),
+		func(index int) string { return AccessibleText(l.cell(index)) })
+	if index != -1 {
+		l.Select(false, index)
+		SafeCall(l.NewSelectionCallback)
+		l.ScrollRectIntoView(l.RowRect(index))
+	}
+	return true
+}
+
 // CanSelectAll returns true if the list's selection can be expanded.
 func (l *List[T]) CanSelectAll() bool {
 	return l.Selection.Count() < len(l.rows)
@@ -0,0 +1,123 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// dragListData is actually a *ListDragData[T], but cannot be stored with the originating list since it must be
+// accessible from the drag data. As with dragTableData, all access occurs on the UI thread.
+var dragListData any
+
+// ListDragData holds the data from a drag originating from a List.
+type ListDragData[T any] struct {
+	List    *List[T]
+	Indexes []int // The indexes of the items within the List at the time the drag started, in ascending order
+	Items   []T
+}
+
+// InstallDragSupport installs default drag support into the list. Dragging from a selected item drags all of the
+// selected items. The encoder may be nil; if not, it provides the data placed in the drag for the data type, which is
+// only needed when drop targets other than those installed by List.InstallDropSupport() should be able to accept it.
+// This will chain a function to any existing MouseDragCallback, which will not be called while dragging items.
+func (l *List[T]) InstallDragSupport(svg *SVG, dataType *uti.DataType, singularName, pluralName string,
+	encoder func(items []T) []byte) {
+	orig := l.MouseDragCallback
+	l.MouseDragCallback = func(where geom.Point, button int, mods mod.Modifiers) bool {
+		if button == ButtonLeft && l.pressed && l.pressedIndex >= 0 && l.pressedIndex < len(l.rows) &&
+			l.Selection.State(l.pressedIndex) && !mods.ShiftDown() && !mods.DiscontiguousSelectionDown() {
+			if dragListData == nil && l.IsDragGesture(where) {
+				l.startDrag(where, svg, dataType, singularName, pluralName, encoder)
+			}
+			return true
+		}
+		if orig != nil {
+			return orig(where, button, mods)
+		}
+		return false
+	}
+}
+
+func (l *List[T]) startDrag(where geom.Point, svg *SVG, dataType *uti.DataType, singularName, pluralName string,
+	encoder func(items []T) []byte) {
+	data := l.selectedDragData()
+	drawable := NewListDragDrawable(data, svg, singularName, pluralName)
+	size := drawable.LogicalSize()
+	img, err := NewImageFromDrawing(int(size.Width), int(size.Height), 144, func(c *Canvas) {
+		drawable.DrawInRect(c, geom.Rect{Size: size}, nil, nil)
+	})
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	var buffer []byte
+	if encoder != nil {
+		buffer = encoder(data.Items)
+	}
+	if len(buffer) == 0 {
+		buffer = []byte{0}
+	}
+	where.X -= size.Width / 2
+	where.Y -= size.Height / 2
+	l.wasDragged = true
+	dragListData = data
+	l.StartDrag(img, where, func() { dragListData = nil }, drag.Copy|drag.Move, drag.Data{
+		Type: dataType,
+		Data: buffer,
+	})
+}
+
+// selectedDragData returns the drag data for the currently selected items.
+func (l *List[T]) selectedDragData() *ListDragData[T] {
+	data := &ListDragData[T]{List: l}
+	for i := l.Selection.FirstSet(); i != -1 && i < len(l.rows); i = l.Selection.NextSet(i + 1) {
+		data.Indexes = append(data.Indexes, i)
+		data.Items = append(data.Items, l.rows[i])
+	}
+	return data
+}
+
+// NewListDragDrawable creates a new drawable for a list item drag.
+func NewListDragDrawable[T any](data *ListDragData[T], svg *SVG, singularName, pluralName string) Drawable {
+	return newDragDrawable(data.List.SelectionInk, data.List.OnSelectionInk, len(data.Items), svg, singularName,
+		pluralName)
+}
+
+// InstallDropSupport installs default drop support into this list, allowing items dragged from it or from another List
+// with the same type of items to be dropped into it. This will replace any existing CanAcceptDropCallback,
+// DragEnteredCallback, DragUpdatedCallback, DragExitedCallback, and DropCallback functions. It will also chain a
+// function to any existing DrawOverCallback. The shouldMoveDataCallback is called when a drop is about to occur to
+// determine if the data should be moved (i.e. removed from the source) or copied to the destination. The
+// willDropCallback is called before the actual data changes are made, giving an opportunity to start an undo event,
+// which should be returned. The didDropCallback is called after data changes are made and is passed the undo event (if
+// any) returned by the willDropCallback, so that the undo event can be completed and posted. If willDropCallback is
+// nil, an undo event that restores the items of both lists is instead posted to the list's UndoManager, if it has one.
+func (l *List[T]) InstallDropSupport[U any](dataType *uti.DataType, shouldMoveDataCallback func(from, to *List[T]) bool, willDropCallback func(from, to *List[T], move bool) *UndoEdit[U], didDropCallback func(undo *UndoEdit[U], from, to *List[T], move bool)) *ListDrop[T, U] {
+	drop := &ListDrop[T, U]{
+		List:                   l,
+		DataType:               dataType,
+		originalDrawOver:       l.DrawOverCallback,
+		shouldMoveDataCallback: shouldMoveDataCallback,
+		willDropCallback:       willDropCallback,
+		didDropCallback:        didDropCallback,
+	}
+	l.DrawOverCallback = drop.DrawOverCallback
+	l.CanAcceptDropCallback = drop.CanAcceptDropCallback
+	l.DragEnteredCallback = drop.DragEnterCallback
+	l.DragUpdatedCallback = drop.DragUpdatedCallback
+	l.DragExitedCallback = drop.DragExitCallback
+	l.DropCallback = drop.DropCallback
+	return drop
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/collection/bitset"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// ListDrop provides default support for dropping data into a list. This should only be instantiated by a call to
+// List.InstallDropSupport().
+type ListDrop[T any, U any] struct {
+	List                   *List[T]
+	DataType               *uti.DataType
+	CopyItem               func(item T) T // Used to duplicate items that are copied rather than moved, if set
+	originalDrawOver       func(*Canvas, geom.Rect)
+	shouldMoveDataCallback func(from, to *List[T]) bool
+	willDropCallback       func(from, to *List[T], move bool) *UndoEdit[U]
+	didDropCallback        func(undo *UndoEdit[U], from, to *List[T], move bool)
+	TargetIndex            int
+	top                    float32
+	inDragOver             bool
+}
+
+// listDropUndoState holds the items and selection of the lists involved in a drop.
+type listDropUndoState[T any] struct {
+	from          *List[T]
+	to            *List[T]
+	fromRows      []T
+	toRows        []T
+	fromSelection *bitset.BitSet
+	toSelection   *bitset.BitSet
+}
+
+// DrawOverCallback handles drawing the drop zone feedback.
+func (d *ListDrop[T, U]) DrawOverCallback(gc *Canvas, rect geom.Rect) {
+	if d.originalDrawOver != nil {
+		d.originalDrawOver(gc, rect)
+	}
+	if d.inDragOver {
+		r := d.List.ContentRect(false).Inset(geom.NewUniformInsets(1))
+		paint := ThemeWarning.Paint(gc, r, paintstyle.Stroke)
+		paint.SetStrokeWidth(2)
+		paint.SetColorFilter(Alpha30Filter())
+		gc.DrawRect(r, paint)
+		paint.SetColorFilter(nil)
+		paint.SetPathEffect(DashEffect())
+		gc.DrawLine(geom.NewPoint(r.X, d.top), geom.NewPoint(r.Right(), d.top), paint)
+	}
+}
+
+// CanAcceptDropCallback reports whether this list is a candidate for the given drag, independent of pointer position.
+func (d *ListDrop[T, U]) CanAcceptDropCallback(di drag.Info) bool {
+	if dragListData == nil || !d.List.Enabled() || !di.HasDataType(d.DataType.UTI) {
+		return false
+	}
+	_, ok := dragListData.(*ListDragData[T])
+	return ok
+}
+
+// DragEnterCallback provides the drag enter handling.
+func (d *ListDrop[T, U]) DragEnterCallback(di drag.Info, where geom.Point, mods mod.Modifiers) drag.Op {
+	var op drag.Op
+	SafeCall(func() { op = d.DragUpdatedCallback(di, where, mods) })
+	return op
+}
+
+// DragUpdatedCallback provides the drag updated handling.
+func (d *ListDrop[T, U]) DragUpdatedCallback(di drag.Info, where geom.Point, _ mod.Modifiers) drag.Op {
+	d.inDragOver = false
+	accept := false
+	SafeCall(func() { accept = d.CanAcceptDropCallback(di) })
+	if !accept {
+		return drag.None
+	}
+	data, ok := dragListData.(*ListDragData[T])
+	if !ok {
+		return drag.None
+	}
+	var op drag.Op
+	SafeCall(func() {
+		if d.shouldMoveDataCallback(data.List, d.List) {
+			op = drag.Move
+		} else {
+			op = drag.Copy
+		}
+	})
+	d.inDragOver = true
+	contentRect := d.List.ContentRect(false)
+	if index, _ := d.List.rowAt(where.Y); index != -1 && where.Y < contentRect.Bottom()-2 {
+		rect := d.List.RowRect(index)
+		if where.Y >= rect.CenterY() {
+			// Over lower half of item; insert after it
+			d.TargetIndex = index + 1
+			d.top = min(rect.Bottom(), contentRect.Bottom()-1)
+		} else {
+			// Over upper half of item; insert before it
+			d.TargetIndex = index
+			d.top = max(rect.Y, contentRect.Y+1)
+		}
+	} else {
+		// Not over any item, or over the bottom edge; add to the end
+		d.TargetIndex = len(d.List.rows)
+		d.top = min(d.List.RowRect(len(d.List.rows)-1).Bottom(), contentRect.Bottom()-1)
+		if d.TargetIndex == 0 {
+			d.top = contentRect.Y + 1
+		}
+	}
+	d.List.MarkForRedraw()
+	d.List.FlushDrawing()
+	return op
+}
+
+// DropCallback handles processing a drop.
+func (d *ListDrop[T, U]) DropCallback(di drag.Info, where geom.Point, mods mod.Modifiers) bool {
+	defer func() { SafeCall(d.DragExitCallback) }()
+	var op drag.Op
+	SafeCall(func() { op = d.DragUpdatedCallback(di, where, mods) })
+	if op == drag.None {
+		return false
+	}
+	data, ok := dragListData.(*ListDragData[T])
+	if !ok {
+		return false
+	}
+	var savedScrollX, savedScrollY float32
+	if scroller := d.List.ScrollRoot(); scroller != nil {
+		savedScrollX, savedScrollY = scroller.Position()
+		defer func() {
+			scroller.SetPosition(savedScrollX, savedScrollY)
+		}()
+	}
+	d.inDragOver = false
+	move := false
+	SafeCall(func() { move = d.shouldMoveDataCallback(data.List, d.List) })
+	var undo *UndoEdit[U]
+	var before *listDropUndoState[T]
+	if d.willDropCallback != nil {
+		SafeCall(func() { undo = d.willDropCallback(data.List, d.List, move) })
+	} else {
+		before = newListDropUndoState(data.List, d.List)
+	}
+	items := slices.Clone(data.Items)
+	target := max(min(d.TargetIndex, len(d.List.rows)), 0)
+	if move {
+		// Remove the dragged items from their original places
+		moving := make(map[int]bool, len(data.Indexes))
+		for _, index := range data.Indexes {
+			moving[index] = true
+		}
+		remaining := make([]T, 0, len(data.List.rows))
+		for i, item := range data.List.rows {
+			if !moving[i] {
+				remaining = append(remaining, item)
+			} else if data.List == d.List && i < target {
+				target--
+			}
+		}
+		data.List.setItems(remaining, nil)
+		if d.List != data.List && data.List.DragRemovedItemsCallback != nil {
+			SafeCall(data.List.DragRemovedItemsCallback)
+		}
+	} else if d.CopyItem != nil {
+		for i, item := range items {
+			items[i] = d.CopyItem(item)
+		}
+	}
+
+	// Insert the items into their new location and select them
+	var selection bitset.BitSet
+	if len(items) != 0 {
+		selection.SetRange(target, target+len(items)-1)
+	}
+	d.List.setItems(slices.Insert(slices.Clone(d.List.rows), target, items...), &selection)
+
+	// Notify the destination list
+	SafeCall(d.List.DropOccurredCallback)
+
+	if d.willDropCallback != nil {
+		if d.didDropCallback != nil {
+			SafeCall(func() { d.didDropCallback(undo, data.List, d.List, move) })
+		}
+	} else if mgr := UndoManagerFor(d.List); mgr != nil {
+		name := i18n.Text("Copy Items")
+		if move {
+			name = i18n.Text("Move Items")
+		}
+		mgr.Add(&UndoEdit[*listDropUndoState[T]]{
+			ID:         NextUndoID(),
+			EditName:   name,
+			EditCost:   1,
+			UndoFunc:   func(e *UndoEdit[*listDropUndoState[T]]) { e.BeforeData.apply() },
+			RedoFunc:   func(e *UndoEdit[*listDropUndoState[T]]) { e.AfterData.apply() },
+			BeforeData: before,
+			AfterData:  newListDropUndoState(data.List, d.List),
+		})
+	}
+
+	d.List.MarkForRedraw()
+	return true
+}
+
+// DragExitCallback handles resetting the state when a drag is no longer of interest.
+func (d *ListDrop[T, U]) DragExitCallback() {
+	d.inDragOver = false
+	d.List.MarkForRedraw()
+	d.List.FlushDrawing()
+}
+
+// setItems replaces the items of the list, along with its selection, which may be nil to clear it.
+func (l *List[T]) setItems(items []T, selection *bitset.BitSet) {
+	l.rows = items
+	l.Selection.Reset()
+	l.anchor = -1
+	if selection != nil {
+		l.Selection.Copy(selection)
+		l.anchor = l.Selection.FirstSet()
+	}
+	l.MarkForLayoutAndRedraw()
+}
+
+func newListDropUndoState[T any](from, to *List[T]) *listDropUndoState[T] {
+	return &listDropUndoState[T]{
+		from:          from,
+		to:            to,
+		fromRows:      slices.Clone(from.rows),
+		toRows:        slices.Clone(to.rows),
+		fromSelection: from.Selection.Clone(),
+		toSelection:   to.Selection.Clone(),
+	}
+}
+
+func (s *listDropUndoState[T]) apply() {
+	if s.from != s.to {
+		s.from.setItems(slices.Clone(s.fromRows), s.fromSelection)
+	}
+	s.to.setItems(slices.Clone(s.toRows), s.toSelection)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/drag"
+)
+
+var listDropTestDataType = CreatePrivateDataType("unison.test.list-drop")
+
+// listDropTestDragInfo is a minimal drag.Info carrying the list drop test data type.
+type listDropTestDragInfo struct{}
+
+func (d *listDropTestDragInfo) SourceDragOpMask() drag.Op  { return drag.Copy | drag.Move }
+func (d *listDropTestDragInfo) DataTypes() []string        { return []string{listDropTestDataType.UTI} }
+func (d *listDropTestDragInfo) HasString() bool            { return false }
+func (d *listDropTestDragInfo) HasFilePaths() bool         { return false }
+func (d *listDropTestDragInfo) HasURLs() bool              { return false }
+func (d *listDropTestDragInfo) HasDataType(dt string) bool { return dt == listDropTestDataType.UTI }
+func (d *listDropTestDragInfo) Text() string               { return "" }
+func (d *listDropTestDragInfo) FilePaths() []string        { return nil }
+func (d *listDropTestDragInfo) URLs() []*url.URL           { return nil }
+func (d *listDropTestDragInfo) Data(_ string) []byte       { return nil }
+
+// newDropTestList returns a list of the values, with rows 20 pixels tall, that accepts drops, moving the items when
+// they come from the same list and copying them otherwise.
+func newDropTestList(values ...string) (*List[string], *ListDrop[string, any]) {
+	l := NewList[string]()
+	l.Factory = &DefaultCellFactory{Height: 20}
+	l.Append(values...)
+	l.SetFrameRect(geom.NewRect(0, 0, 100, 200))
+	drop := l.InstallDropSupport[any](listDropTestDataType,
+		func(from, to *List[string]) bool { return from == to }, nil, nil)
+	return l, drop
+}
+
+// startListDragForTest registers a drag of the selected items of the list.
+func startListDragForTest(t *testing.T, l *List[string]) {
+	t.Helper()
+	dragListData = l.selectedDragData()
+	t.Cleanup(func() { dragListData = nil })
+}
+
+func listItems(l *List[string]) string {
+	return strings.Join(l.rows, "")
+}
+
+func TestListDropReordersAndIsUndoable(t *testing.T) {
+	c := check.New(t)
+	host := newUndoHost(t)
+	l, drop := newDropTestList("a", "b", "c", "d")
+	host.AddChild(l)
+	l.Select(false, 0, 1)
+	startListDragForTest(t, l)
+	di := &listDropTestDragInfo{}
+
+	c.Equal(drag.Move, l.DragUpdatedCallback(di, geom.NewPoint(10, 45), 0))
+	c.Equal(2, drop.TargetIndex, "the upper half of an item inserts before it")
+	c.Equal(drag.Move, l.DragUpdatedCallback(di, geom.NewPoint(10, 55), 0))
+	c.Equal(3, drop.TargetIndex, "the lower half of an item inserts after it")
+	c.Equal(drag.None, l.DragUpdatedCallback(&dropTargetTestDragInfo{}, geom.NewPoint(10, 55), 0))
+
+	c.True(l.DropCallback(di, geom.NewPoint(10, 55), 0))
+	c.Equal("cabd", listItems(l))
+	c.Equal([]int{1, 2}, l.selectedDragData().Indexes, "the dropped items are selected")
+	c.Equal("Undo Move Items", host.mgr.UndoTitle())
+
+	host.mgr.Undo()
+	c.Equal("abcd", listItems(l))
+	c.Equal([]int{0, 1}, l.selectedDragData().Indexes, "the selection is restored")
+	host.mgr.Redo()
+	c.Equal("cabd", listItems(l))
+}
+
+func TestListDropBetweenLists(t *testing.T) {
+	c := check.New(t)
+	src, _ := newDropTestList("a", "b", "c")
+	dst, drop := newDropTestList("x", "y")
+	drop.CopyItem = strings.ToUpper
+	src.Select(false, 0, 2)
+	startListDragForTest(t, src)
+	di := &listDropTestDragInfo{}
+
+	c.Equal(drag.Copy, dst.DragUpdatedCallback(di, geom.NewPoint(10, 150), 0))
+	c.Equal(2, drop.TargetIndex, "below the last item adds to the end")
+	c.True(dst.DropCallback(di, geom.NewPoint(10, 150), 0))
+	c.Equal("xyAC", listItems(dst))
+	c.Equal("abc", listItems(src), "copying leaves the source alone")
+
+	var removed int
+	src.DragRemovedItemsCallback = func() { removed++ }
+	dst.InstallDropSupport[any](listDropTestDataType, func(_, _ *List[string]) bool { return true }, nil, nil)
+	c.True(dst.DropCallback(di, geom.NewPoint(10, 5), 0))
+	c.Equal("acxyAC", listItems(dst))
+	c.Equal("b", listItems(src))
+	c.Equal(1, removed)
+}
+
+func TestListDragSupportKeepsSelection(t *testing.T) {
+	c := check.New(t)
+	l, _ := newDropTestList("a", "b", "c", "d")
+	c.True(l.MouseDownCallback(geom.NewPoint(10, 25), ButtonLeft, 1, 0))
+	c.True(l.MouseDragCallback(geom.NewPoint(10, 65), ButtonLeft, 0))
+	c.Equal([]int{1, 2, 3}, l.selectedDragData().Indexes, "without drag support, dragging extends the selection")
+	l.MouseUpCallback(geom.NewPoint(10, 65), ButtonLeft, 0)
+
+	l.InstallDragSupport(nil, listDropTestDataType, "item", "items", nil)
+	c.True(l.MouseDownCallback(geom.NewPoint(10, 25), ButtonLeft, 1, 0))
+	c.True(l.MouseDragCallback(geom.NewPoint(10, 65), ButtonLeft, 0))
+	c.Equal([]int{1, 2, 3}, l.selectedDragData().Indexes, "dragging from a selected item drags the selection")
+	l.MouseUpCallback(geom.NewPoint(10, 65), ButtonLeft, 0)
+
+	c.True(l.MouseDownCallback(geom.NewPoint(10, 5), ButtonLeft, 1, 0))
+	c.True(l.MouseDragCallback(geom.NewPoint(10, 25), ButtonLeft, 0))
+	data := l.selectedDragData()
+	c.Equal([]int{0}, data.Indexes)
+	c.Equal([]string{"a"}, data.Items)
+}
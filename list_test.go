@@ -11,6 +11,7 @@ package unison_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/richardwilkes/toolbox/v2/check"
 	"github.com/richardwilkes/unison"
@@ -198,3 +199,25 @@ func TestListSetAllowMultipleSelectionCollapses(t *testing.T) {
 	l.Select(false, 0, 2)
 	c.Equal([]int{2}, selectedIndexes(l))
 }
+
+func TestListTypeToSelect(t *testing.T) {
+	c := check.New(t)
+	l := newTestList("apple", "Banana", "blueberry", "bluebell", "cherry")
+	l.TypeToSelectTimeout = time.Hour
+	c.False(l.DefaultRuneTyped(' '), "a space doesn't begin a search")
+	c.True(l.DefaultRuneTyped('b'))
+	c.Equal([]int{1}, selectedIndexes(l), "matching ignores case")
+	for _, ch := range "luebel" {
+		c.True(l.DefaultRuneTyped(ch))
+	}
+	c.Equal([]int{3}, selectedIndexes(l))
+
+	l.TypeToSelectTimeout = 0
+	c.True(l.DefaultRuneTyped('b'))
+	c.Equal([]int{1}, selectedIndexes(l), "once the timeout passes, a new search begins")
+	l.TypeToSelectTimeout = time.Hour
+	c.True(l.DefaultRuneTyped('b'))
+	c.Equal([]int{2}, selectedIndexes(l), "typing the same rune again moves to the next match")
+	c.True(l.DefaultRuneTyped('b'))
+	c.Equal([]int{3}, selectedIndexes(l))
+}
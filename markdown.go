@@ -34,6 +34,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/xstrings"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/slant"
 	"github.com/richardwilkes/unison/enums/weight"
 	"github.com/yuin/goldmark"
@@ -80,6 +81,8 @@ func init() {
 		LinkInk:                DefaultLinkTheme.OnBackgroundInk,
 		LinkOnPressedInk:       DefaultLinkTheme.OnPressedInk,
 		LinkHandler:            DefaultMarkdownLinkHandler,
+		SearchMatchInk:         ThemeSearchMatch,
+		CurrentSearchMatchInk:  ThemeFocus,
 		QuoteBarThickness:      2,
 		CodeAndQuotePadding:    6,
 		Slop:                   4,
@@ -141,6 +144,8 @@ type MarkdownTheme struct {
 	QuoteBarCautionColor   Ink
 	LinkInk                Ink
 	LinkOnPressedInk       Ink
+	SearchMatchInk         Ink
+	CurrentSearchMatchInk  Ink
 	LinkHandler            func(Paneler, string)
 	WorkingDirProvider     func(Paneler) string
 	AltLinkPrefixes        []string
@@ -179,6 +184,7 @@ type Markdown struct {
 	columnWidths               []int
 	drawableCache              map[string]*drawableCacheEntry
 	anchors                    map[string]*Panel
+	search                     *markdownSearch
 	MarkdownTheme
 	Panel
 	drawableCacheLock sync.Mutex
@@ -202,6 +208,7 @@ func NewMarkdown(autoSizingFromParent bool) *Markdown {
 	}
 	m.SetLayout(&FlexLayout{Columns: 1})
 	m.Self = m
+	m.AccessibilityCallback = func() Accessibility { return Accessibility{ActiveItem: -1, Role: role.Document} }
 	if autoSizingFromParent {
 		m.ParentChangedCallback = m.adjustSizeOnParentChange
 	}
@@ -265,6 +272,7 @@ func (m *Markdown) SetContentBytes(content []byte, maxWidth float32) {
 	m.index = 0
 	m.ordered = false
 	m.anchors = make(map[string]*Panel)
+	m.search = nil
 	m.node = goldmark.New(goldmark.WithExtensions(extension.GFM),
 		goldmark.WithParserOptions(parser.WithAutoHeadingID(), parser.WithHeadingAttribute())).
 		Parser().Parse(text.NewReader(m.content))
@@ -1313,12 +1321,13 @@ func (m *Markdown) flushText() {
 		if remaining := m.prepareToFlushText(m.decoration.Font.SimpleWidth("W")); remaining < m.text.Width() {
 			// Remaining space isn't large enough for the text we have, so put a chunk that will fit on this line, then
 			// go to the next line
-			part := m.text.BreakToWidth(remaining)[0]
+			part := m.text.BreakToWidthWithOptions(remaining, LineBreakOptions{SoftHyphens: true})[0]
 			m.text = m.text.Slice(len(part.Runes()), len(m.text.Runes()))
 			m.addLabelToTextRow(part)
 			m.issueLineBreak()
 			// Now break the remaining text up to the max width size and add each line
-			if parts := m.text.BreakToWidth(m.maxLineWidth); len(parts) != 0 {
+			if parts := m.text.BreakToWidthWithOptions(m.maxLineWidth,
+				LineBreakOptions{SoftHyphens: true}); len(parts) != 0 {
 				for i := 0; i < len(parts)-1; i++ {
 					m.addLabelToTextRow(parts[i])
 					m.issueLineBreak()
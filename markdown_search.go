@@ -0,0 +1,144 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// markdownSearch holds the text of a Markdown's labels, in the order they appear, so that it can be searched.
+type markdownSearch struct {
+	text      []rune
+	labels    []*Label
+	starts    []int
+	matches   [][2]int
+	selection [2]int
+}
+
+// searchData returns the searchable text of the Markdown, collecting it from its labels if that hasn't been done since
+// the content was last set. Labels that share a row of text are joined directly; others are separated by a line feed.
+func (m *Markdown) searchData() *markdownSearch {
+	if m.search != nil {
+		return m.search
+	}
+	s := &markdownSearch{}
+	var last *Label
+	var collect func(p *Panel)
+	collect = func(p *Panel) {
+		for _, child := range p.Children() {
+			label, ok := child.Self.(*Label)
+			if !ok {
+				collect(child)
+				continue
+			}
+			if label.Text.Empty() {
+				continue
+			}
+			if last != nil {
+				if _, inRow := child.Parent().Layout().(*FlowLayout); !inRow || last.Parent() != child.Parent() {
+					s.text = append(s.text, '\n')
+				}
+			}
+			start := len(s.text)
+			s.labels = append(s.labels, label)
+			s.starts = append(s.starts, start)
+			s.text = append(s.text, label.Text.Runes()...)
+			label.DrawOverCallback = func(canvas *Canvas, _ geom.Rect) { m.drawSearchMatches(canvas, label, start) }
+			last = label
+		}
+	}
+	collect(m.AsPanel())
+	m.search = s
+	return s
+}
+
+// SearchableText implements Searchable. The text is that of the Markdown as displayed, rather than its source.
+func (m *Markdown) SearchableText() []rune {
+	return m.searchData().text
+}
+
+// SetSearchHighlights implements Searchable.
+func (m *Markdown) SetSearchHighlights(ranges [][2]int) {
+	m.searchData().matches = ranges
+	m.MarkForRedraw()
+}
+
+// Selection returns the range of the displayed text that is selected. The selection can only be set programmatically,
+// as is done by a FindBar to indicate the current match.
+func (m *Markdown) Selection() (start, end int) {
+	s := m.searchData()
+	return s.selection[0], s.selection[1]
+}
+
+// SetSelection sets the range of the displayed text that is selected.
+func (m *Markdown) SetSelection(start, end int) {
+	s := m.searchData()
+	start = max(min(start, len(s.text)), 0)
+	end = max(min(end, len(s.text)), start)
+	if s.selection[0] != start || s.selection[1] != end {
+		s.selection = [2]int{start, end}
+		m.MarkForRedraw()
+	}
+}
+
+// ScrollSelectionIntoView scrolls the start of the selection into view.
+func (m *Markdown) ScrollSelectionIntoView() {
+	s := m.searchData()
+	if len(s.labels) == 0 {
+		return
+	}
+	i, found := slices.BinarySearch(s.starts, s.selection[0])
+	if !found {
+		i = max(i-1, 0)
+	}
+	label := s.labels[i]
+	left, rect := m.searchTextBounds(label)
+	if r := label.Text.RangesForRuneIndexes(s.selection[0]-s.starts[i], s.selection[1]-s.starts[i]); len(r) != 0 {
+		rect.X = left + r[0][0]
+		rect.Width = r[len(r)-1][1] - r[0][0]
+	}
+	label.ScrollRectIntoView(rect)
+}
+
+// searchTextBounds returns the left edge of the label's text and the content area of the label.
+func (m *Markdown) searchTextBounds(label *Label) (left float32, rect geom.Rect) {
+	rect = label.ContentRect(false)
+	left = rect.X
+	switch label.HAlign {
+	case align.Middle, align.Fill:
+		left = xmath.Floor(left + (rect.Width-label.Text.Width())/2)
+	case align.End:
+		left += rect.Width - label.Text.Width()
+	default:
+	}
+	return left, rect
+}
+
+// drawSearchMatches draws the search highlighting over the label, whose text starts at the given offset within the
+// searchable text.
+func (m *Markdown) drawSearchMatches(canvas *Canvas, label *Label, start int) {
+	s := m.search
+	if s == nil || (len(s.matches) == 0 && s.selection[0] == s.selection[1]) {
+		return
+	}
+	end := start + len(label.Text.Runes())
+	left, rect := m.searchTextBounds(label)
+	drawSearchMatches(canvas, s.matches, start, end, left, rect.Y, rect.Height, m.SearchMatchInk, paintstyle.Fill,
+		label.Text.RangesForRuneIndexes)
+	if s.selection[0] != s.selection[1] {
+		drawSearchMatches(canvas, [][2]int{s.selection}, start, end, left, rect.Y, rect.Height,
+			m.CurrentSearchMatchInk, paintstyle.Stroke, label.Text.RangesForRuneIndexes)
+	}
+}
@@ -49,6 +49,14 @@ const (
 	maxWindowsListed         = 100
 )
 
+// Pre-defined menu IDs for the find items, which are only present when added with InsertFindItems(). These are
+// reserved from the top of the pre-defined range so that the IDs above keep their values.
+const (
+	FindItemID = UserBaseID - 3 + iota
+	FindNextItemID
+	FindPreviousItemID
+)
+
 // InsertStdMenus adds the standard menus to the menu bar.
 func InsertStdMenus(m Menu, aboutHandler, prefsHandler func(MenuItem), updater func(Menu)) {
 	f := m.Factory()
@@ -127,6 +135,19 @@ func NewEditMenu(f MenuFactory, prefsHandler func(MenuItem), updater func(Menu))
 	return m
 }
 
+// InsertFindItems creates the "Find…", "Find Next" and "Find Previous" menu items, which issue the commands handled by
+// a FindBar. These aren't part of the standard 'Edit' menu, so apps that use a FindBar should add them, typically after
+// a separator at the end of that menu.
+func InsertFindItems(m Menu, atIndex int) {
+	f := m.Factory()
+	for _, action := range []*Action{FindAction(), FindNextAction(), FindPreviousAction()} {
+		m.InsertItem(atIndex, action.NewMenuItem(f))
+		if atIndex >= 0 {
+			atIndex++
+		}
+	}
+}
+
 // InsertPreferencesItem creates the standard "Preferences…" menu item that will call the provided handler when chosen.
 func InsertPreferencesItem(m Menu, atIndex int, prefsHandler func(MenuItem)) {
 	m.InsertItem(atIndex, m.Factory().NewItem(PreferencesItemID, i18n.Text("Preferences…"),
@@ -14,35 +14,53 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
 )
 
-// NumericField holds a numeric value that can be edited.
+// NumericField holds a numeric value that can be edited. Once a Step has been set, the value may also be stepped up or
+// down with the Up and Down keys, the mouse wheel while the field has the focus, a Stepper created with
+// NewNumericFieldStepper() or by dragging over a panel passed to InstallScrubbing().
 type NumericField[T xmath.Integer | xmath.Float] struct {
 	*Field
 	Format     func(T) string
 	Extract    func(s string) (T, error)
 	Prototypes func(minimum, maximum T) []T
-	minimum    T
-	maximum    T
+	// Step is the amount the value changes by with each step. Stepping is disabled while this is zero.
+	Step T
+	// LargeStepMultiplier is applied to Step while the Shift key is down.
+	LargeStepMultiplier float64
+	// SmallStepMultiplier is applied to Step while the Alt/Option key is down. Integer values never step by less than
+	// one.
+	SmallStepMultiplier float64
+	// ScrubDistance is the horizontal distance the mouse must be dragged to take each step when scrubbing.
+	ScrubDistance float32
+	minimum       T
+	maximum       T
 }
 
 // NewNumericField creates a new field that holds a numeric value and limits its input to a specific range of values.
 // The format and extract functions allow the field to be presented as something other than numbers.
 func NewNumericField[T xmath.Integer | xmath.Float](current, minimum, maximum T, format func(T) string, extract func(s string) (T, error), prototypes func(minimum, maximum T) []T) *NumericField[T] {
 	f := &NumericField[T]{
-		Field:      NewField(),
-		Prototypes: prototypes,
-		Format:     format,
-		Extract:    extract,
-		minimum:    minimum,
-		maximum:    maximum,
+		Field:               NewField(),
+		Prototypes:          prototypes,
+		Format:              format,
+		Extract:             extract,
+		LargeStepMultiplier: 10,
+		SmallStepMultiplier: 0.1,
+		ScrubDistance:       4,
+		minimum:             minimum,
+		maximum:             maximum,
 	}
 	f.Self = f
 	UninstallFocusBorders(f, f)
 	f.LostFocusCallback = f.DefaultFocusLost
 	InstallDefaultFieldBorder(f, f)
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.MouseWheelCallback = f.DefaultMouseWheel
 	f.RuneTypedCallback = f.DefaultRuneTyped
 	f.ValidateCallback = f.DefaultValidate
 	f.SetText(f.Format(current))
@@ -74,6 +92,113 @@ func (f *NumericField[T]) Max() T {
 	return f.maximum
 }
 
+// StepValue changes the value by the number of steps, which may be negative, limited to the allowed range. The
+// modifier keys select the multiplier applied to Step. Does nothing if Step is zero.
+func (f *NumericField[T]) StepValue(steps int, mods mod.Modifiers) {
+	if f.Step != 0 {
+		f.SetValue(f.steppedValue(f.Value(), steps, mods))
+	}
+}
+
+func (f *NumericField[T]) stepSize(mods mod.Modifiers) T {
+	multiplier := 1.0
+	if mods.ShiftDown() {
+		multiplier *= f.LargeStepMultiplier
+	}
+	if mods.OptionDown() {
+		multiplier *= f.SmallStepMultiplier
+	}
+	step := T(float64(f.Step) * multiplier)
+	if step == 0 {
+		// Only possible for integer values, which can't step by a fraction
+		step = 1
+	}
+	return step
+}
+
+// steppedValue returns the value after taking the number of steps from it, limited to the allowed range.
+func (f *NumericField[T]) steppedValue(value T, steps int, mods mod.Modifiers) T {
+	if steps == 0 {
+		return min(max(value, f.minimum), f.maximum)
+	}
+	step := f.stepSize(mods)
+	// The range check is done with floating point values to avoid overflowing integer types near their limits
+	target := float64(value) + float64(step)*float64(steps)
+	if target >= float64(f.maximum) {
+		return f.maximum
+	}
+	if target <= float64(f.minimum) {
+		return f.minimum
+	}
+	return value + step*T(steps)
+}
+
+// InstallScrubbing permits the value to be changed by dragging horizontally over the panel, which is typically the
+// Label for the field. Each ScrubDistance the mouse is dragged takes one step, with the multiplier applied to Step
+// selected by the modifier keys that are down. Does nothing while Step is zero.
+func (f *NumericField[T]) InstallScrubbing(panel Paneler) {
+	p := panel.AsPanel()
+	var startX float32
+	var startValue T
+	p.MouseDownCallback = func(where geom.Point, button, _ int, _ mod.Modifiers) bool {
+		if f.Step == 0 || button != ButtonLeft || !f.Enabled() {
+			return false
+		}
+		startX = where.X
+		startValue = f.Value()
+		return true
+	}
+	p.MouseDragCallback = func(where geom.Point, _ int, mods mod.Modifiers) bool {
+		steps := int((where.X - startX) / max(f.ScrubDistance, 1))
+		f.SetValue(f.steppedValue(startValue, steps, mods))
+		return true
+	}
+	p.UpdateCursorCallback = func(_ geom.Point) *Cursor {
+		if f.Step == 0 || !f.Enabled() {
+			return ArrowCursor()
+		}
+		return ResizeHorizontalCursor()
+	}
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *NumericField[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if f.Step != 0 && mods&(mod.Control|mod.Command) == 0 {
+		switch keyCode {
+		case KeyUp:
+			f.StepValue(1, mods)
+			return true
+		case KeyDown:
+			f.StepValue(-1, mods)
+			return true
+		default:
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// DefaultMouseWheel is the default implementation for the MouseWheelCallback. The value is only stepped while the
+// field has the focus, so that scrolling past it doesn't alter it.
+func (f *NumericField[T]) DefaultMouseWheel(_, delta geom.Point, mods mod.Modifiers) bool {
+	if f.Step == 0 || !f.Focused() {
+		return false
+	}
+	// Some platforms turn vertical scrolling into horizontal scrolling while the Shift key is down
+	d := delta.Y
+	if d == 0 {
+		d = delta.X
+	}
+	switch {
+	case d > 0:
+		f.StepValue(1, mods)
+	case d < 0:
+		f.StepValue(-1, mods)
+	default:
+		return false
+	}
+	return true
+}
+
 // DefaultFocusLost is the default implementation for the LostFocusCallback.
 func (f *NumericField[T]) DefaultFocusLost() {
 	f.SetText(f.Format(f.Value()))
@@ -14,7 +14,9 @@ import (
 	"testing"
 
 	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/mod"
 )
 
 func TestNumericFieldFocusBorderRestoredOnFocusLoss(t *testing.T) {
@@ -48,3 +50,49 @@ func TestNumericFieldFocusLossStillNormalizesText(t *testing.T) {
 	f.LostFocusCallback()
 	c.Equal("100", f.Text())
 }
+
+func TestNumericFieldStepping(t *testing.T) {
+	c := check.New(t)
+	f := unison.NewNumericField(50, 0, 100, strconv.Itoa, strconv.Atoi, nil)
+	f.DefaultKeyDown(unison.KeyUp, 0, false)
+	c.Equal(50, f.Value(), "stepping is disabled until a step is set")
+
+	f.Step = 2
+	c.True(f.DefaultKeyDown(unison.KeyUp, 0, false))
+	c.Equal(52, f.Value())
+	c.True(f.DefaultKeyDown(unison.KeyDown, mod.Shift, false))
+	c.Equal(32, f.Value(), "Shift multiplies the step by ten")
+	c.True(f.DefaultKeyDown(unison.KeyUp, mod.Option, false))
+	c.Equal(33, f.Value(), "integer steps are never less than one")
+	f.StepValue(-100, 0)
+	c.Equal(0, f.Value(), "steps are limited to the minimum")
+	f.StepValue(1000, mod.Shift)
+	c.Equal(100, f.Value(), "steps are limited to the maximum")
+	c.False(f.DefaultMouseWheel(geom.Point{}, geom.NewPoint(0, 1), 0), "the mouse wheel is ignored without the focus")
+
+	ff := unison.NewNumericField(1.0, 0, 2, func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }, nil)
+	ff.Step = 0.5
+	ff.StepValue(1, mod.Option)
+	c.Equal(1.05, ff.Value())
+	ff.StepValue(-1, 0)
+	c.Equal(0.55, ff.Value())
+}
+
+func TestNumericFieldScrubbing(t *testing.T) {
+	c := check.New(t)
+	f := unison.NewNumericField(50, 0, 100, strconv.Itoa, strconv.Atoi, nil)
+	label := unison.NewLabel()
+	f.InstallScrubbing(label)
+	c.False(label.MouseDownCallback(geom.NewPoint(10, 5), unison.ButtonLeft, 1, 0),
+		"scrubbing is disabled until a step is set")
+
+	f.Step = 2
+	c.True(label.MouseDownCallback(geom.NewPoint(10, 5), unison.ButtonLeft, 1, 0))
+	label.MouseDragCallback(geom.NewPoint(22, 5), unison.ButtonLeft, 0)
+	c.Equal(56, f.Value(), "each four pixels dragged takes a step")
+	label.MouseDragCallback(geom.NewPoint(2, 5), unison.ButtonLeft, 0)
+	c.Equal(46, f.Value(), "steps are measured from where the drag started")
+	label.MouseDragCallback(geom.NewPoint(50, 5), unison.ButtonLeft, mod.Shift)
+	c.Equal(100, f.Value())
+}
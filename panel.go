@@ -48,6 +48,7 @@ type Panel struct {
 	ScrollRectIntoViewCallback          func(rect geom.Rect) bool
 	ParentChangedCallback               func()
 	FocusChangeInHierarchyCallback      func(from, to *Panel)
+	AccessibilityCallback               func() Accessibility
 	Tooltip                             *Panel
 	parent                              *Panel
 	canPerformMap                       map[int]func(any) bool
@@ -55,6 +56,7 @@ type Panel struct {
 	data                                map[string]any
 	RefKey                              string
 	children                            []*Panel
+	accessibleID                        uint64
 	frame                               geom.Rect
 	scale                               geom.Point
 	NeedsLayout                         bool
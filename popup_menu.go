@@ -19,6 +19,7 @@ import (
 	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/slant"
 )
 
@@ -86,6 +87,7 @@ func NewPopupMenu[T comparable]() *PopupMenu[T] {
 	p.MouseUpCallback = p.DefaultMouseUp
 	p.KeyDownCallback = p.DefaultKeyDown
 	p.UpdateCursorCallback = p.DefaultUpdateCursor
+	p.AccessibilityCallback = p.DefaultAccessibility
 	p.ChoiceMadeCallback = func(popup *PopupMenu[T], index int, _ T) { popup.SelectIndex(index) }
 	return p
 }
@@ -179,6 +181,17 @@ func (p *PopupMenu[T]) textObj() *Text {
 	}
 }
 
+// DefaultAccessibility provides the default accessibility description.
+func (p *PopupMenu[T]) DefaultAccessibility() Accessibility {
+	return Accessibility{
+		Action:     p.Click,
+		Value:      p.Text(),
+		ActiveItem: -1,
+		Role:       role.ComboBox,
+		State:      AccessibleHasPopup,
+	}
+}
+
 // Text the currently shown text.
 func (p *PopupMenu[T]) Text() string {
 	indexes := p.SelectedIndexes()
@@ -15,6 +15,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 	"github.com/richardwilkes/unison/enums/side"
 )
 
@@ -67,9 +68,19 @@ func NewRadioButton() *RadioButton {
 	r.commonInit()
 	r.updateState = func() { r.group.Select(&r) }
 	r.drawMark = r.drawRadio
+	r.AccessibilityCallback = r.DefaultAccessibility
 	return &r
 }
 
+// DefaultAccessibility provides the default accessibility description.
+func (r *RadioButton) DefaultAccessibility() Accessibility {
+	var state AccessibleState
+	if r.group.Selected(r) {
+		state = AccessibleChecked
+	}
+	return r.accessibility(role.RadioButton, state)
+}
+
 // Group returns the group that this button is a part of.
 func (r *RadioButton) Group() *Group {
 	return r.group
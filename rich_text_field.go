@@ -0,0 +1,431 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/slant"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var (
+	// RichTextDataType is the data type placed on the clipboard by a RichTextField, in addition to plain text and HTML,
+	// so that styles survive being copied and pasted within the application.
+	RichTextDataType     = CreatePrivateDataType("unison.richtext")
+	richTextHTMLDataType = htmlDataType()
+)
+
+func htmlDataType() *uti.DataType {
+	if dt := uti.ByUTI("public.html"); dt != nil {
+		return dt
+	}
+	return uti.Register(&uti.DataType{UTI: "public.html"})
+}
+
+// RichTextStyle holds the styling applied to a character of a RichTextField. The zero value is unstyled text.
+type RichTextStyle struct {
+	// Color is the color of the text. If zero, the field's ink is used.
+	Color Color `json:"color,omitzero"`
+	// Size is the font size of the text. If zero, the size of the field's font is used.
+	Size          float32 `json:"size,omitzero"`
+	Bold          bool    `json:"bold,omitzero"`
+	Italic        bool    `json:"italic,omitzero"`
+	Underline     bool    `json:"underline,omitzero"`
+	StrikeThrough bool    `json:"strikethrough,omitzero"`
+}
+
+// FontDescriptor returns the FontDescriptor to use for this style, given that of the field's font.
+func (s RichTextStyle) FontDescriptor(base FontDescriptor) FontDescriptor {
+	if s.Bold {
+		base.Weight = weight.Bold
+	}
+	if s.Italic {
+		base.Slant = slant.Italic
+	}
+	if s.Size > 0 {
+		base.Size = s.Size
+	}
+	return base
+}
+
+// richTextStyles holds the per-rune styles of a RichTextField. Field keeps them in step with its runes.
+type richTextStyles struct {
+	styles    []RichTextStyle
+	fonts     map[FontDescriptor]Font
+	pending   RichTextStyle
+	pendingAt int
+}
+
+// insertionStyle returns the style given to runes that replace those in the range [start, end). Replacing a selection
+// takes on the style of its first rune, while inserting at a caret continues the style of the rune before it.
+func (s *richTextStyles) insertionStyle(start, end int) RichTextStyle {
+	switch {
+	case start == end && start == s.pendingAt:
+		return s.pending
+	case start < end:
+		return s.styles[start]
+	case start > 0:
+		return s.styles[start-1]
+	case len(s.styles) != 0:
+		return s.styles[0]
+	default:
+		return RichTextStyle{}
+	}
+}
+
+// splice replaces the styles in the range [start, end) with count copies of the insertion style.
+func (s *richTextStyles) splice(start, end, count int) {
+	style := s.insertionStyle(start, end)
+	inserted := make([]RichTextStyle, count)
+	for i := range inserted {
+		inserted[i] = style
+	}
+	s.styles = slices.Replace(s.styles, start, end, inserted...)
+	s.pendingAt = -1
+}
+
+// text returns a Text for the runes in the range [start, end), decorated according to their styles.
+func (s *richTextStyles) text(runes []rune, start, end int, decoration *TextDecoration) *Text {
+	t := NewTextFromRunes(nil, decoration)
+	for start < end {
+		style := s.styles[start]
+		next := start + 1
+		for next < end && s.styles[next] == style {
+			next++
+		}
+		t.AddRunes(runes[start:next], s.decoration(style, decoration))
+		start = next
+	}
+	return t
+}
+
+func (s *richTextStyles) decoration(style RichTextStyle, base *TextDecoration) *TextDecoration {
+	d := base.Clone()
+	if style.Bold || style.Italic || style.Size > 0 {
+		fd := style.FontDescriptor(base.Font.Descriptor())
+		var exists bool
+		if d.Font, exists = s.fonts[fd]; !exists {
+			d.Font = fd.Font()
+			if s.fonts == nil {
+				s.fonts = make(map[FontDescriptor]Font)
+			}
+			s.fonts[fd] = d.Font
+		}
+	}
+	if style.Color != 0 {
+		d.OnBackgroundInk = style.Color
+	}
+	d.Underline = style.Underline
+	d.StrikeThrough = style.StrikeThrough
+	return d
+}
+
+// richTextClip is the form RichTextField content takes on the clipboard.
+type richTextClip struct {
+	Text string            `json:"text"`
+	Runs []richTextClipRun `json:"runs"`
+}
+
+type richTextClipRun struct {
+	Style  RichTextStyle `json:"style"`
+	Length int           `json:"length"`
+}
+
+// RichTextField provides a multi-line text input control whose characters may each be styled. Styles are carried in
+// the FieldState passed to the ModifiedCallback, so undo support built on GetFieldState() and ApplyFieldState()
+// restores them along with the text. Obscurement is not supported.
+type RichTextField struct {
+	*Field
+}
+
+// NewRichTextField creates a new, empty, rich text field.
+func NewRichTextField() *RichTextField {
+	f := &RichTextField{Field: NewMultiLineField()}
+	f.Self = f
+	f.richText = &richTextStyles{pendingAt: -1}
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.InstallCmdHandlers(CutItemID, func(_ any) bool { return f.CanCut() }, func(_ any) { f.Cut() })
+	f.InstallCmdHandlers(CopyItemID, func(_ any) bool { return f.CanCopy() }, func(_ any) { f.Copy() })
+	f.InstallCmdHandlers(PasteItemID, func(_ any) bool { return f.CanPaste() }, func(_ any) { f.Paste() })
+	return f
+}
+
+// DefaultKeyDown provides the default key down handling, adding shortcuts for bold, italic and underline.
+func (f *RichTextField) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if mods&mod.NonSticky == mod.OSMenuCommand() {
+		switch keyCode {
+		case KeyB:
+			f.ToggleBold()
+			return true
+		case KeyI:
+			f.ToggleItalic()
+			return true
+		case KeyU:
+			f.ToggleUnderline()
+			return true
+		default:
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// StyleAt returns the style of the character at the given index.
+func (f *RichTextField) StyleAt(index int) RichTextStyle {
+	if index < 0 || index >= len(f.richText.styles) {
+		return RichTextStyle{}
+	}
+	return f.richText.styles[index]
+}
+
+// Styles returns the style of each character of the content.
+func (f *RichTextField) Styles() []RichTextStyle {
+	return slices.Clone(f.richText.styles)
+}
+
+// TypingStyle returns the style that will be given to the next character typed.
+func (f *RichTextField) TypingStyle() RichTextStyle {
+	return f.richText.insertionStyle(f.selectionStart, f.selectionEnd)
+}
+
+// SetStyledText sets the content of the field, with each character taking on the style at the same index in styles.
+// Characters without a corresponding style are unstyled.
+func (f *RichTextField) SetStyledText(text string, styles []RichTextStyle) {
+	runes := []rune(text)
+	all := make([]RichTextStyle, len(runes))
+	copy(all, styles)
+	i := 0
+	for j, ch := range runes {
+		if ch >= ' ' || ch == '\t' || ch == '\n' {
+			runes[i] = ch
+			all[i] = all[j]
+			i++
+		}
+	}
+	runes = runes[:i]
+	all = all[:i]
+	if slices.Equal(runes, f.runes) && slices.Equal(all, f.richText.styles) {
+		return
+	}
+	before := f.GetFieldState()
+	f.replaceRunes(0, len(f.runes), runes)
+	f.richText.styles = all
+	f.SetSelectionToEnd()
+	f.notifyOfModification(before, f.GetFieldState())
+}
+
+// ApplyStyle calls the adjuster with the style of each character in the range [start, end), allowing it to be
+// modified.
+func (f *RichTextField) ApplyStyle(start, end int, adjuster func(style *RichTextStyle)) {
+	start = max(start, 0)
+	end = min(end, len(f.runes))
+	if start >= end {
+		return
+	}
+	before := f.GetFieldState()
+	changed := false
+	for i := start; i < end; i++ {
+		style := f.richText.styles[i]
+		adjuster(&style)
+		if style != f.richText.styles[i] {
+			f.richText.styles[i] = style
+			changed = true
+		}
+	}
+	if changed {
+		f.undoID = NextUndoID()
+		f.linesBuiltFor = -1
+		f.MarkForLayoutAndRedraw()
+		f.notifyOfModification(before, f.GetFieldState())
+	}
+}
+
+// AdjustSelectionStyle applies the adjuster to the selected characters. If there is no selection, the adjuster is
+// applied to the style the next character typed will have instead.
+func (f *RichTextField) AdjustSelectionStyle(adjuster func(style *RichTextStyle)) {
+	if f.HasSelectionRange() {
+		f.ApplyStyle(f.selectionStart, f.selectionEnd, adjuster)
+		return
+	}
+	style := f.TypingStyle()
+	adjuster(&style)
+	f.richText.pending = style
+	f.richText.pendingAt = f.selectionStart
+}
+
+// selectionHas returns true if all of the selected characters satisfy the predicate. If there is no selection, the
+// style the next character typed will have is checked instead.
+func (f *RichTextField) selectionHas(predicate func(style RichTextStyle) bool) bool {
+	if !f.HasSelectionRange() {
+		return predicate(f.TypingStyle())
+	}
+	for _, style := range f.richText.styles[f.selectionStart:f.selectionEnd] {
+		if !predicate(style) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToggleBold makes the selection bold, unless it already is, in which case bold is removed.
+func (f *RichTextField) ToggleBold() {
+	bold := !f.selectionHas(func(style RichTextStyle) bool { return style.Bold })
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.Bold = bold })
+}
+
+// ToggleItalic makes the selection italic, unless it already is, in which case italic is removed.
+func (f *RichTextField) ToggleItalic() {
+	italic := !f.selectionHas(func(style RichTextStyle) bool { return style.Italic })
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.Italic = italic })
+}
+
+// ToggleUnderline underlines the selection, unless it already is, in which case the underline is removed.
+func (f *RichTextField) ToggleUnderline() {
+	underline := !f.selectionHas(func(style RichTextStyle) bool { return style.Underline })
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.Underline = underline })
+}
+
+// ToggleStrikeThrough strikes through the selection, unless it already is, in which case the strikethrough is removed.
+func (f *RichTextField) ToggleStrikeThrough() {
+	strike := !f.selectionHas(func(style RichTextStyle) bool { return style.StrikeThrough })
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.StrikeThrough = strike })
+}
+
+// SetSelectionColor sets the color of the selection. A zero color restores the field's ink.
+func (f *RichTextField) SetSelectionColor(color Color) {
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.Color = color })
+}
+
+// SetSelectionFontSize sets the font size of the selection. A size of zero restores the size of the field's font.
+func (f *RichTextField) SetSelectionFontSize(size float32) {
+	f.AdjustSelectionStyle(func(style *RichTextStyle) { style.Size = max(size, 0) })
+}
+
+// Cut the selected text to the clipboard.
+func (f *RichTextField) Cut() {
+	if f.HasSelectionRange() {
+		f.Copy()
+		f.Delete()
+	}
+}
+
+// Copy the selected text to the clipboard, along with its styles and an HTML rendition of it.
+func (f *RichTextField) Copy() {
+	if !f.HasSelectionRange() {
+		return
+	}
+	runes := f.runes[f.selectionStart:f.selectionEnd]
+	styles := f.richText.styles[f.selectionStart:f.selectionEnd]
+	clip := richTextClip{Text: string(runes)}
+	for i := 0; i < len(styles); {
+		j := i + 1
+		for j < len(styles) && styles[j] == styles[i] {
+			j++
+		}
+		clip.Runs = append(clip.Runs, richTextClipRun{Style: styles[i], Length: j - i})
+		i = j
+	}
+	data := []drag.Data{
+		{Type: uti.UTF8PlainText, Data: []byte(clip.Text)},
+		{Type: richTextHTMLDataType, Data: []byte(richTextHTML(runes, styles))},
+	}
+	if buffer, err := json.Marshal(&clip); err != nil {
+		errs.Log(errs.NewWithCause("unable to encode rich text for the clipboard", err))
+	} else {
+		data = append(data, drag.Data{Type: RichTextDataType, Data: buffer})
+	}
+	ClipboardSetData(data...)
+}
+
+// Paste the clipboard content into the field. Content copied from a RichTextField retains its styles. Failing that,
+// HTML placed on the clipboard by other applications retains its bold, italic, underline, strikethrough, color and font
+// size, layered on the style of the text it is pasted into. Other text simply takes on the style of the text it is
+// pasted into.
+func (f *RichTextField) Paste() {
+	runes, styles, ok := f.styledClipboardContent()
+	if !ok {
+		f.Field.Paste()
+		return
+	}
+	f.undoID = NextUndoID()
+	before := f.GetFieldState()
+	start := f.selectionStart
+	f.replaceRunes(start, f.selectionEnd, runes)
+	copy(f.richText.styles[start:], styles)
+	f.SetSelectionTo(start + len(runes))
+	f.notifyOfModification(before, f.GetFieldState())
+}
+
+func (f *RichTextField) styledClipboardContent() (runes []rune, styles []RichTextStyle, ok bool) {
+	switch {
+	case ClipboardHasDataType(RichTextDataType):
+		var clip richTextClip
+		if err := json.Unmarshal(ClipboardGetData(RichTextDataType), &clip); err != nil {
+			errs.Log(errs.NewWithCause("unable to decode rich text from the clipboard", err))
+			return nil, nil, false
+		}
+		all := []rune(clip.Text)
+		for _, run := range clip.Runs {
+			if run.Length < 0 || run.Length > len(all) {
+				return nil, nil, false
+			}
+			for range run.Length {
+				styles = append(styles, run.Style)
+			}
+			runes = append(runes, all[:run.Length]...)
+			all = all[run.Length:]
+		}
+	case slices.Contains(clipboardAvailableDataTypes(), richTextHTMLDataType.UTI):
+		// The HTML type is checked for directly, since ClipboardHasDataType() would also accept plain text for it.
+		runes, styles = richTextFromHTML(ClipboardGetData(richTextHTMLDataType),
+			f.richText.insertionStyle(f.selectionStart, f.selectionEnd))
+	default:
+		return nil, nil, false
+	}
+	runes, styles = f.sanitizeStyled(runes, styles)
+	return runes, styles, len(runes) != 0
+}
+
+// sanitizeStyled sanitizes the runes one run of identically styled runes at a time, so that the styles remain aligned
+// with the runes that survive.
+func (f *RichTextField) sanitizeStyled(runes []rune, styles []RichTextStyle) (sanitizedRunes []rune,
+	sanitizedStyles []RichTextStyle) {
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && styles[end] == styles[start] {
+			end++
+		}
+		for _, ch := range f.sanitize(slices.Clone(runes[start:end])) {
+			sanitizedRunes = append(sanitizedRunes, ch)
+			sanitizedStyles = append(sanitizedStyles, styles[start])
+		}
+		start = end
+	}
+	return sanitizedRunes, sanitizedStyles
+}
+
+// Markdown returns the content of the field as Markdown. Each line becomes a paragraph. Bold, italic and
+// strikethrough use Markdown syntax where possible, while underline, color and font size use inline HTML.
+func (f *RichTextField) Markdown() string {
+	return richTextMarkdown(f.runes, f.richText.styles)
+}
+
+// SetMarkdown sets the content of the field from Markdown. Inline HTML tags for bold, italic, underline and
+// strikethrough are recognized, as are span tags setting the color and font-size. Other markup is dropped, retaining
+// its text, and each block becomes a line.
+func (f *RichTextField) SetMarkdown(markdown string) {
+	runes, styles := richTextFromMarkdown([]byte(markdown))
+	f.SetStyledText(string(runes), styles)
+}
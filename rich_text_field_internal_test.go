@@ -0,0 +1,224 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+)
+
+func richTextFrom(parts ...any) (runes []rune, styles []RichTextStyle) {
+	for i := 0; i < len(parts); i += 2 {
+		style, ok := parts[i+1].(RichTextStyle)
+		if !ok {
+			panic("expected a RichTextStyle")
+		}
+		for _, r := range parts[i].(string) {
+			runes = append(runes, r)
+			styles = append(styles, style)
+		}
+	}
+	return runes, styles
+}
+
+func TestRichTextFieldToggleBoldAppliesToSelection(t *testing.T) {
+	c := check.New(t)
+	f := NewRichTextField()
+	f.SetText("hello world")
+	f.SetSelection(0, 5)
+	f.ToggleBold()
+	for i := range 5 {
+		c.True(f.StyleAt(i).Bold, "rune %d", i)
+	}
+	c.False(f.StyleAt(5).Bold)
+
+	// A selection that is entirely bold has its bold removed.
+	f.ToggleBold()
+	c.False(f.StyleAt(0).Bold)
+
+	// A partially bold selection becomes entirely bold.
+	f.SetSelection(2, 3)
+	f.ToggleBold()
+	f.SetSelection(0, 5)
+	f.ToggleBold()
+	for i := range 5 {
+		c.True(f.StyleAt(i).Bold, "rune %d", i)
+	}
+}
+
+func TestRichTextFieldTypingContinuesStyle(t *testing.T) {
+	c := check.New(t)
+	f := NewRichTextField()
+	f.SetText("ab")
+	f.ToggleItalic()
+	c.False(f.StyleAt(1).Italic, "toggling without a selection only affects what is typed next")
+	c.True(f.TypingStyle().Italic)
+	f.DefaultRuneTyped('c')
+	f.DefaultRuneTyped('d')
+	c.Equal("abcd", f.Text())
+	c.False(f.StyleAt(1).Italic)
+	c.True(f.StyleAt(2).Italic)
+	c.True(f.StyleAt(3).Italic)
+
+	// Moving the caret away discards the pending style.
+	f.SetSelectionTo(1)
+	f.ToggleUnderline()
+	f.SetSelectionTo(2)
+	f.SetSelectionTo(1)
+	f.DefaultRuneTyped('x')
+	c.False(f.StyleAt(1).Underline)
+}
+
+func TestRichTextFieldEditsKeepStylesAligned(t *testing.T) {
+	c := check.New(t)
+	f := NewRichTextField()
+	f.SetText("abcdef")
+	f.SetSelection(4, 6)
+	f.SetSelectionColor(RGB(255, 0, 0))
+	f.SetSelection(1, 3)
+	f.Delete()
+	c.Equal("adef", f.Text())
+	c.Equal(Color(0), f.StyleAt(1).Color)
+	c.Equal(RGB(255, 0, 0), f.StyleAt(2).Color)
+	c.Equal(RGB(255, 0, 0), f.StyleAt(3).Color)
+	c.Equal(4, len(f.Styles()))
+}
+
+func TestRichTextFieldUndoRestoresStyles(t *testing.T) {
+	c := check.New(t)
+	mgr := NewUndoManager(100, func(err error) { t.Error(err) })
+	f := NewRichTextField()
+	f.SetText("hello")
+	f.ModifiedCallback = func(before, after *FieldState) {
+		mgr.Add(&UndoEdit[*FieldState]{
+			ID:         f.CurrentUndoID(),
+			EditName:   "Edit",
+			UndoFunc:   func(e *UndoEdit[*FieldState]) { f.ApplyFieldState(e.BeforeData) },
+			RedoFunc:   func(e *UndoEdit[*FieldState]) { f.ApplyFieldState(e.AfterData) },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	f.SetSelection(1, 4)
+	f.SetSelectionFontSize(20)
+	c.Equal(float32(20), f.StyleAt(2).Size)
+	mgr.Undo()
+	c.Equal(float32(0), f.StyleAt(2).Size)
+	mgr.Redo()
+	c.Equal(float32(20), f.StyleAt(2).Size)
+}
+
+func TestRichTextFieldClipboardRetainsStyles(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	f := NewRichTextField()
+	f.SetText("plain bold")
+	f.SetSelection(6, 10)
+	f.ToggleBold()
+	f.SetSelection(4, 10)
+	f.Copy()
+	c.Equal("n bold", ClipboardGetText())
+	c.True(strings.Contains(string(ClipboardGetData(richTextHTMLDataType)), "<b>bold</b>"))
+
+	other := NewRichTextField()
+	other.SetText("[]")
+	other.SetSelectionTo(1)
+	other.Paste()
+	c.Equal("[n bold]", other.Text())
+	c.False(other.StyleAt(2).Bold)
+	c.True(other.StyleAt(3).Bold)
+	c.False(other.StyleAt(7).Bold)
+
+	// Plain text from elsewhere takes on the style of the text it is pasted into.
+	ClipboardSetText("xy")
+	other.SetSelectionTo(4)
+	other.Paste()
+	c.Equal("[n bxyold]", other.Text())
+	c.True(other.StyleAt(4).Bold)
+}
+
+func TestRichTextFieldPastesHTML(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	ClipboardSetData(
+		drag.Data{Type: uti.UTF8PlainText, Data: []byte("Hi there\nnext")},
+		drag.Data{Type: richTextHTMLDataType, Data: []byte("<html><head><style>p { margin: 0 }</style></head><body>" +
+			"<!--StartFragment--><p>Hi   <b>there</b></p>\n<p><span style=\"font-style: italic\">next</span></p>" +
+			"<!--EndFragment--></body></html>")},
+	)
+	f := NewRichTextField()
+	f.SetText("[]")
+	f.SetSelectionTo(1)
+	f.Paste()
+	c.Equal("[Hi there\nnext]", f.Text())
+	c.False(f.StyleAt(1).Bold)
+	c.True(f.StyleAt(4).Bold)
+	c.True(f.StyleAt(10).Italic)
+	c.False(f.StyleAt(10).Bold)
+
+	// HTML without styles of its own takes on the style of the text it is pasted into.
+	ClipboardSetData(drag.Data{Type: richTextHTMLDataType, Data: []byte("a&amp;b<br class=\"x\">")})
+	f.SetSelectionTo(6)
+	f.Paste()
+	c.Equal("[Hi tha&bere\nnext]", f.Text())
+	c.True(f.StyleAt(7).Bold)
+}
+
+func TestRichTextMarkdownRoundTrip(t *testing.T) {
+	c := check.New(t)
+	bold := RichTextStyle{Bold: true}
+	italic := RichTextStyle{Italic: true}
+	for i, parts := range [][]any{
+		{"plain", RichTextStyle{}},
+		{"a ", RichTextStyle{}, "bold", bold, " and ", RichTextStyle{}, "it", italic, "x", RichTextStyle{
+			Bold:   true,
+			Italic: true,
+		}},
+		{"ab", bold, "cd", italic, "(e)", bold, "!", RichTextStyle{}},
+		{"  *x* & &#32; 1. - #\n\n\nnext  ", RichTextStyle{}},
+		{"red", RichTextStyle{Color: RGB(255, 0, 0), Size: 18, Underline: true}, "\n", RichTextStyle{}},
+		{"struck", RichTextStyle{StrikeThrough: true}, "<tag>", RichTextStyle{}},
+	} {
+		runes, styles := richTextFrom(parts...)
+		markdown := richTextMarkdown(runes, styles)
+		gotRunes, gotStyles := richTextFromMarkdown([]byte(markdown))
+		c.Equal(string(runes), string(gotRunes), "case %d: %q", i, markdown)
+		c.Equal(styles, gotStyles, "case %d: %q", i, markdown)
+	}
+}
+
+func TestRichTextFromMarkdownFlattensBlocks(t *testing.T) {
+	c := check.New(t)
+	runes, styles := richTextFromMarkdown([]byte("# Title\n\n- one\n- <u>two</u>\n\n```\ncode\n```\nsoft\nwrap"))
+	c.Equal("Title\none\ntwo\ncode\nsoft wrap", string(runes))
+	c.True(styles[0].Bold, "headings are bold")
+	c.True(styles[10].Underline)
+	c.Equal(RichTextStyle{}, styles[len(styles)-1])
+}
+
+func TestRichTextFieldMarkdown(t *testing.T) {
+	c := check.New(t)
+	f := NewRichTextField()
+	f.SetMarkdown("Some **bold** text\n\n<br>\n\n<span style=\"color:#00FF00\">green</span>")
+	c.Equal("Some bold text\n\ngreen", f.Text())
+	c.True(f.StyleAt(5).Bold)
+	c.Equal(RGB(0, 255, 0), f.StyleAt(16).Color)
+	c.Equal("Some **bold** text\n\n<br>\n\n<span style=\"color:"+RGB(0, 255, 0).String()+"\">green</span>\n", f.Markdown())
+}
+
+func TestUnescapeMarkdownText(t *testing.T) {
+	c := check.New(t)
+	c.Equal(`*a* & b`, unescapeMarkdownText([]byte(`\*a\* &amp; b`)))
+	c.Equal("&#32; x", unescapeMarkdownText([]byte(`\&\#32; &#120;`)))
+}
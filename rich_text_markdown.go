@@ -0,0 +1,490 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	astex "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	xhtml "golang.org/x/net/html"
+)
+
+// Each line of a RichTextField is written to Markdown as its own paragraph. An empty line is written as a paragraph
+// holding just this tag, since Markdown would otherwise collapse it.
+const richTextEmptyLine = "<br>"
+
+// richTextMarkdown returns the runes, styled as described by the styles, as Markdown. Bold, italic and strikethrough
+// are written using Markdown's own syntax where it is unambiguous, with inline HTML used for everything else.
+func richTextMarkdown(runes []rune, styles []RichTextStyle) string {
+	if len(runes) == 0 {
+		return ""
+	}
+	var buffer strings.Builder
+	for lineStart := 0; lineStart <= len(runes); {
+		lineEnd := len(runes)
+		if i := slices.Index(runes[lineStart:], '\n'); i != -1 {
+			lineEnd = lineStart + i
+		}
+		if lineStart != 0 {
+			buffer.WriteString("\n\n")
+		}
+		if lineStart == lineEnd {
+			buffer.WriteString(richTextEmptyLine)
+		} else {
+			writeRichTextMarkdownLine(&buffer, runes[lineStart:lineEnd], styles[lineStart:lineEnd])
+		}
+		lineStart = lineEnd + 1
+	}
+	buffer.WriteByte('\n')
+	return buffer.String()
+}
+
+func writeRichTextMarkdownLine(buffer *strings.Builder, runes []rune, styles []RichTextStyle) {
+	// Markdown strips whitespace from the start and end of a paragraph, so it is written as character references.
+	leading := 0
+	for leading < len(runes) && unicode.IsSpace(runes[leading]) {
+		leading++
+	}
+	trailing := len(runes)
+	for trailing > leading && unicode.IsSpace(runes[trailing-1]) {
+		trailing--
+	}
+	previousUsedDelimiters := false
+	for start := 0; start < len(runes); {
+		style := styles[start]
+		end := start + 1
+		for end < len(runes) && styles[end] == style {
+			end++
+		}
+		// Emphasis delimiters are only recognized when they are next to a letter or digit on the inside, and two runs
+		// written with delimiters back-to-back would merge, so HTML tags are used in those cases instead.
+		useDelimiters := !previousUsedDelimiters && isRichTextWordRune(runes[start]) && isRichTextWordRune(runes[end-1])
+		open, closing := richTextTags(style, useDelimiters)
+		buffer.WriteString(open)
+		for i := start; i < end; i++ {
+			r := runes[i]
+			switch {
+			case i < leading || i >= trailing:
+				fmt.Fprintf(buffer, "&#%d;", r)
+			case r < unicode.MaxASCII && util.IsPunct(byte(r)):
+				buffer.WriteByte('\\')
+				buffer.WriteRune(r)
+			default:
+				buffer.WriteRune(r)
+			}
+		}
+		buffer.WriteString(closing)
+		previousUsedDelimiters = useDelimiters && (style.Bold || style.Italic || style.StrikeThrough)
+		start = end
+	}
+}
+
+func isRichTextWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// richTextTags returns the markup that should surround a run with the given style. If useDelimiters is true, bold,
+// italic and strikethrough use Markdown syntax rather than HTML.
+func richTextTags(style RichTextStyle, useDelimiters bool) (open, closing string) {
+	var opening []string
+	var closings []string
+	add := func(o, c string) {
+		opening = append(opening, o)
+		closings = append(closings, c)
+	}
+	var css []string
+	if style.Color != 0 {
+		css = append(css, "color:"+style.Color.String())
+	}
+	if style.Size > 0 {
+		css = append(css, fmt.Sprintf("font-size:%vpt", style.Size))
+	}
+	if len(css) != 0 {
+		add(`<span style="`+strings.Join(css, ";")+`">`, "</span>")
+	}
+	if style.Underline {
+		add("<u>", "</u>")
+	}
+	if useDelimiters {
+		if style.StrikeThrough {
+			add("~~", "~~")
+		}
+		if style.Bold {
+			add("**", "**")
+		}
+		if style.Italic {
+			add("*", "*")
+		}
+	} else {
+		if style.StrikeThrough {
+			add("<s>", "</s>")
+		}
+		if style.Bold {
+			add("<b>", "</b>")
+		}
+		if style.Italic {
+			add("<i>", "</i>")
+		}
+	}
+	slices.Reverse(closings)
+	return strings.Join(opening, ""), strings.Join(closings, "")
+}
+
+// richTextHTML returns the runes, styled as described by the styles, as an HTML fragment.
+func richTextHTML(runes []rune, styles []RichTextStyle) string {
+	var buffer strings.Builder
+	for start := 0; start < len(runes); {
+		if runes[start] == '\n' {
+			buffer.WriteString("<br>")
+			start++
+			continue
+		}
+		style := styles[start]
+		end := start + 1
+		for end < len(runes) && styles[end] == style && runes[end] != '\n' {
+			end++
+		}
+		open, closing := richTextTags(style, false)
+		buffer.WriteString(open)
+		buffer.WriteString(html.EscapeString(string(runes[start:end])))
+		buffer.WriteString(closing)
+		start = end
+	}
+	return buffer.String()
+}
+
+// richTextMarkdownReader converts Markdown, or HTML, into runes and their styles. Markup that has no equivalent is
+// dropped, with its text retained.
+type richTextMarkdownReader struct {
+	content []byte
+	runes   []rune
+	styles  []RichTextStyle
+	tags    []richTextOpenTag
+	style   RichTextStyle
+	blocks  int
+}
+
+type richTextOpenTag struct {
+	name     string
+	previous RichTextStyle
+}
+
+// richTextFromMarkdown returns the runes and styles described by the Markdown content.
+func richTextFromMarkdown(content []byte) (runes []rune, styles []RichTextStyle) {
+	r := &richTextMarkdownReader{content: content}
+	r.walk(goldmark.New(goldmark.WithExtensions(extension.Strikethrough)).Parser().Parse(text.NewReader(content)))
+	return r.runes, r.styles
+}
+
+// richTextFromHTML returns the runes and styles described by the HTML content, such as that placed on the clipboard by
+// other applications. If the content marks a fragment with StartFragment and EndFragment comments, as the Windows
+// clipboard does, only that fragment is used. Text is given the base style, adjusted by the tags surrounding it.
+func richTextFromHTML(content []byte, base RichTextStyle) (runes []rune, styles []RichTextStyle) {
+	if i := bytes.Index(content, []byte("<!--StartFragment-->")); i != -1 {
+		content = content[i+len("<!--StartFragment-->"):]
+		if i = bytes.Index(content, []byte("<!--EndFragment-->")); i != -1 {
+			content = content[:i]
+		}
+	}
+	r := &richTextMarkdownReader{style: base}
+	hidden := 0
+	z := xhtml.NewTokenizer(bytes.NewReader(content))
+	for {
+		tokenType := z.Next()
+		switch tokenType {
+		case xhtml.ErrorToken:
+			end := len(r.runes)
+			for end > 0 && r.runes[end-1] == '\n' {
+				end--
+			}
+			return r.runes[:end], r.styles[:end]
+		case xhtml.TextToken:
+			if hidden == 0 {
+				r.addHTMLText(string(z.Text()))
+			}
+		case xhtml.StartTagToken, xhtml.EndTagToken, xhtml.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "head", "script", "style", "title":
+				if tokenType == xhtml.StartTagToken {
+					hidden++
+				} else if tokenType == xhtml.EndTagToken {
+					hidden = max(hidden-1, 0)
+				}
+			case "br":
+				r.add("\n")
+			case "p", "div", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "pre":
+				if n := len(r.runes); n != 0 && r.runes[n-1] != '\n' {
+					r.add("\n")
+				}
+			default:
+				r.processTag(string(z.Raw()))
+			}
+		default:
+		}
+	}
+}
+
+// addHTMLText adds text from HTML, in which each run of whitespace is displayed as a single space, with none displayed
+// at the start of a line.
+func (r *richTextMarkdownReader) addHTMLText(s string) {
+	for _, ch := range s {
+		switch ch {
+		case ' ', '\t', '\n', '\r', '\f':
+			if n := len(r.runes); n == 0 || r.runes[n-1] == ' ' || r.runes[n-1] == '\n' {
+				continue
+			}
+			ch = ' '
+		default:
+		}
+		r.runes = append(r.runes, ch)
+		r.styles = append(r.styles, r.style)
+	}
+}
+
+func (r *richTextMarkdownReader) add(s string) {
+	for _, ch := range s {
+		r.runes = append(r.runes, ch)
+		r.styles = append(r.styles, r.style)
+	}
+}
+
+// startLine begins a new line for a block.
+func (r *richTextMarkdownReader) startLine() {
+	if r.blocks != 0 {
+		save := r.style
+		r.style = RichTextStyle{}
+		r.add("\n")
+		r.style = save
+	}
+	r.blocks++
+}
+
+func (r *richTextMarkdownReader) walkChildren(node ast.Node) {
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		r.walk(child)
+	}
+}
+
+func (r *richTextMarkdownReader) walkStyled(node ast.Node, adjuster func(style *RichTextStyle)) {
+	save := r.style
+	adjuster(&r.style)
+	r.walkChildren(node)
+	r.style = save
+}
+
+func (r *richTextMarkdownReader) walk(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		r.startLine()
+		r.walkChildren(n)
+	case *ast.Heading:
+		r.startLine()
+		r.walkStyled(n, func(style *RichTextStyle) { style.Bold = true })
+	case *ast.CodeBlock, *ast.FencedCodeBlock:
+		r.startLine()
+		lines := n.Lines()
+		for i := range lines.Len() {
+			if i != 0 {
+				r.add("\n")
+			}
+			segment := lines.At(i)
+			r.add(strings.TrimRight(string(segment.Value(r.content)), "\r\n"))
+		}
+	case *ast.HTMLBlock:
+		lines := n.Lines()
+		var buffer bytes.Buffer
+		for i := range lines.Len() {
+			segment := lines.At(i)
+			buffer.Write(segment.Value(r.content))
+		}
+		if isRichTextLineBreakTag(buffer.String()) {
+			r.startLine()
+		}
+	case *ast.ThematicBreak:
+		r.startLine()
+	case *ast.Text:
+		r.add(unescapeMarkdownText(n.Value(r.content)))
+		switch {
+		case n.HardLineBreak():
+			r.add("\n")
+		case n.SoftLineBreak():
+			r.add(" ")
+		default:
+		}
+	case *ast.String:
+		r.add(unescapeMarkdownText(n.Value))
+	case *ast.Emphasis:
+		r.walkStyled(n, func(style *RichTextStyle) {
+			if n.Level == 1 {
+				style.Italic = true
+			} else {
+				style.Bold = true
+			}
+		})
+	case *astex.Strikethrough:
+		r.walkStyled(n, func(style *RichTextStyle) { style.StrikeThrough = true })
+	case *ast.AutoLink:
+		r.add(string(n.Label(r.content)))
+	case *ast.RawHTML:
+		var buffer bytes.Buffer
+		for i := range n.Segments.Len() {
+			segment := n.Segments.At(i)
+			buffer.Write(segment.Value(r.content))
+		}
+		r.processTag(buffer.String())
+	default:
+		r.walkChildren(n)
+	}
+}
+
+func isRichTextLineBreakTag(tag string) bool {
+	switch strings.Join(strings.Fields(strings.ToLower(tag)), " ") {
+	case "<br>", "<br/>", "<br />":
+		return true
+	default:
+		return false
+	}
+}
+
+// processTag applies an inline HTML tag to the current style.
+func (r *richTextMarkdownReader) processTag(tag string) {
+	if isRichTextLineBreakTag(tag) {
+		r.add("\n")
+		return
+	}
+	tag = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(tag), "<"), ">")
+	if name, closing := strings.CutPrefix(tag, "/"); closing {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for i := len(r.tags) - 1; i >= 0; i-- {
+			if r.tags[i].name == name {
+				r.style = r.tags[i].previous
+				r.tags = r.tags[:i]
+				break
+			}
+		}
+		return
+	}
+	name, attributes, _ := strings.Cut(tag, " ")
+	name = strings.ToLower(name)
+	style := r.style
+	switch name {
+	case "b", "strong":
+		style.Bold = true
+	case "i", "em":
+		style.Italic = true
+	case "u", "ins":
+		style.Underline = true
+	case "s", "del", "strike":
+		style.StrikeThrough = true
+	case "span", "font":
+		applyRichTextCSS(&style, richTextStyleAttribute(attributes))
+	default:
+		return
+	}
+	r.tags = append(r.tags, richTextOpenTag{name: name, previous: r.style})
+	r.style = style
+}
+
+// richTextStyleAttribute returns the value of the style attribute within the attributes of an HTML tag.
+func richTextStyleAttribute(attributes string) string {
+	i := strings.Index(strings.ToLower(attributes), "style=")
+	if i == -1 {
+		return ""
+	}
+	value := attributes[i+len("style="):]
+	if value == "" {
+		return ""
+	}
+	if quote := value[0]; quote == '"' || quote == '\'' {
+		if end := strings.IndexByte(value[1:], quote); end != -1 {
+			return html.UnescapeString(value[1 : end+1])
+		}
+		return ""
+	}
+	value, _, _ = strings.Cut(value, " ")
+	return value
+}
+
+// applyRichTextCSS applies the color, font-size, font-weight, font-style and text-decoration properties of the CSS
+// declarations to the style.
+func applyRichTextCSS(style *RichTextStyle, css string) {
+	for declaration := range strings.SplitSeq(css, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(property)) {
+		case "color":
+			if c, err := ColorDecode(value); err == nil {
+				style.Color = c
+			}
+		case "font-size":
+			value = strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(value), "pt"), "px")
+			if size, err := strconv.ParseFloat(strings.TrimSpace(value), 32); err == nil && size > 0 {
+				style.Size = float32(size)
+			}
+		case "font-weight":
+			value = strings.ToLower(value)
+			if weight, err := strconv.Atoi(value); err == nil {
+				style.Bold = weight >= 600
+			} else {
+				style.Bold = value == "bold" || value == "bolder"
+			}
+		case "font-style":
+			value = strings.ToLower(value)
+			style.Italic = value == "italic" || strings.HasPrefix(value, "oblique")
+		case "text-decoration", "text-decoration-line":
+			value = strings.ToLower(value)
+			style.Underline = strings.Contains(value, "underline")
+			style.StrikeThrough = strings.Contains(value, "line-through")
+		default:
+		}
+	}
+}
+
+// unescapeMarkdownText resolves the backslash escapes and character references within raw Markdown text. This is done
+// in a single pass, so that an escaped ampersand is never mistaken for the start of a character reference.
+func unescapeMarkdownText(raw []byte) string {
+	var buffer strings.Builder
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if ch == '\\' && i+1 < len(raw) && util.IsPunct(raw[i+1]) {
+			i++
+			buffer.WriteByte(raw[i])
+			continue
+		}
+		if ch == '&' {
+			if end := bytes.IndexByte(raw[i:], ';'); end > 1 {
+				reference := raw[i : i+end+1]
+				if resolved := util.ResolveEntityNames(util.ResolveNumericReferences(reference)); !bytes.Equal(resolved,
+					reference) {
+					buffer.Write(resolved)
+					i += end
+					continue
+				}
+			}
+		}
+		buffer.WriteByte(ch)
+	}
+	return buffer.String()
+}
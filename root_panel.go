@@ -14,6 +14,7 @@ import (
 
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 var _ Layout = &rootPanel{}
@@ -23,6 +24,8 @@ type rootPanel struct {
 	openMenuPanels []*menuPanel
 	menuBarPanel   *menuPanel
 	tooltipPanel   *Panel
+	dropDownPanel  *Panel
+	dropDownClose  func()
 	contentPanel   *Panel
 	menuBar        *menu
 	Panel
@@ -32,6 +35,7 @@ func newRootPanel(wnd *Window) *rootPanel {
 	p := &rootPanel{}
 	p.Self = p
 	p.SetLayout(p)
+	p.AccessibilityCallback = p.accessibility
 	p.window = wnd
 	content := NewPanel()
 	content.SetLayout(&FlowLayout{
@@ -42,6 +46,15 @@ func newRootPanel(wnd *Window) *rootPanel {
 	return p
 }
 
+// accessibility describes the window, which the root panel represents to assistive technologies.
+func (p *rootPanel) accessibility() Accessibility {
+	a := Accessibility{Role: role.Window, Name: p.window.Title(), ActiveItem: -1}
+	if slices.Contains(modalStack, p.window) {
+		a.Role = role.Dialog
+	}
+	return a
+}
+
 func (p *rootPanel) MenuBar() *Panel {
 	if p.menuBarPanel == nil {
 		return nil
@@ -99,6 +112,9 @@ func (p *rootPanel) setContent(content Paneler) {
 		if p.tooltipPanel != nil {
 			index++
 		}
+		if p.dropDownPanel != nil {
+			index++
+		}
 		p.AddChildAtIndex(panel, index)
 	}
 	p.NeedsLayout = true
@@ -121,6 +137,47 @@ func (p *rootPanel) setTooltip(tip *Panel) {
 	}
 }
 
+// setDropDown places the panel above the content, such as the suggestions of a ComboBox. Only one may be present at a
+// time; any existing one is closed first. closer will be called when a mouse down occurs outside of the panel and is
+// expected to remove it by calling setDropDown(nil, nil). Its frame is managed by its owner.
+func (p *rootPanel) setDropDown(panel *Panel, closer func()) {
+	if p.dropDownPanel != nil {
+		existing := p.dropDownPanel
+		existingCloser := p.dropDownClose
+		p.dropDownPanel = nil
+		p.dropDownClose = nil
+		existing.MarkForRedraw()
+		p.RemoveChild(existing)
+		if panel != nil && existingCloser != nil {
+			SafeCall(existingCloser)
+		}
+	}
+	p.dropDownPanel = panel
+	p.dropDownClose = closer
+	if panel != nil {
+		index := len(p.openMenuPanels)
+		if p.menuBarPanel != nil {
+			index++
+		}
+		if p.tooltipPanel != nil {
+			index++
+		}
+		p.AddChildAtIndex(panel, index)
+		panel.MarkForRedraw()
+	}
+}
+
+// dropDownRect returns the frame for a drop-down of the given size that belongs to the owner. It is placed just below
+// the owner, or just above it if there isn't enough room below.
+func (p *rootPanel) dropDownRect(owner *Panel, size geom.Size) geom.Rect {
+	ownerRect := owner.RectToRoot(owner.ContentRect(true))
+	r := geom.NewRect(ownerRect.X, ownerRect.Bottom(), size.Width, size.Height)
+	if r.Bottom() > p.FrameRect().Height && ownerRect.Y-r.Height >= 0 {
+		r.Y = ownerRect.Y - r.Height
+	}
+	return r.Align()
+}
+
 func (p *rootPanel) LayoutSizes(_ *Panel, hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
 	if p.contentPanel != nil {
 		minSize, prefSize, maxSize = p.contentPanel.Sizes(hint)
@@ -188,6 +245,9 @@ func (p *rootPanel) preMouseDown(_ *Window, where geom.Point) bool {
 		}
 	}
 	SafeCall(func() { p.closeMenuStackStoppingAt(nil) })
+	if p.dropDownPanel != nil && p.dropDownClose != nil && !where.In(p.dropDownPanel.FrameRect()) {
+		SafeCall(p.dropDownClose)
+	}
 	return false
 }
 
@@ -0,0 +1,79 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import "unicode"
+
+// SpellChecker defines the methods a spell checker must provide. Checks are made from goroutines other than the UI
+// thread, so implementations must be safe for concurrent use.
+type SpellChecker interface {
+	// Check returns true if the word is spelled correctly.
+	Check(word string) bool
+	// Suggest returns up to limit replacements for a misspelled word, best first.
+	Suggest(word string, limit int) []string
+	// Add adds the word to the dictionary, so that it is considered to be spelled correctly from then on.
+	Add(word string)
+}
+
+// FindMisspelledWords returns the rune ranges [start, end) of the words within the text that the checker reports as
+// misspelled, in order. Words are runs of letters, which may contain apostrophes between letters. Runs that also
+// contain digits or underscores, such as "3rd" or "my_var", are not checked.
+func FindMisspelledWords(checker SpellChecker, text []rune) [][2]int {
+	var misspelled [][2]int
+	for i := 0; i < len(text); {
+		if !isSpellCheckWordPart(text[i]) && !unicode.IsDigit(text[i]) && text[i] != '_' {
+			i++
+			continue
+		}
+		start := i
+		skip := false
+		for ; i < len(text); i++ {
+			r := text[i]
+			if unicode.IsDigit(r) || r == '_' {
+				skip = true
+			} else if !isSpellCheckWordPart(r) && !isSpellCheckApostrophe(text, start, i) {
+				break
+			}
+		}
+		if !skip && !checker.Check(string(text[start:i])) {
+			misspelled = append(misspelled, [2]int{start, i})
+		}
+	}
+	return misspelled
+}
+
+func isSpellCheckWordPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r)
+}
+
+// isSpellCheckApostrophe returns true if the rune at index i is an apostrophe within the word that starts at start.
+func isSpellCheckApostrophe(text []rune, start, i int) bool {
+	return (text[i] == '\'' || text[i] == '’') && i > start && i+1 < len(text) && isSpellCheckWordPart(text[i+1])
+}
+
+// adjustRangesForEdit returns the ranges, which are in ascending order, adjusted for the replacement of the runes
+// [start, end) with count runes. Ranges touching the replaced runes are dropped, as the words they cover have changed.
+func adjustRangesForEdit(ranges [][2]int, start, end, count int) [][2]int {
+	if len(ranges) == 0 {
+		return ranges
+	}
+	delta := count - (end - start)
+	adjusted := make([][2]int, 0, len(ranges))
+	for _, r := range ranges {
+		switch {
+		case r[1] < start:
+			adjusted = append(adjusted, r)
+		case r[0] > end:
+			adjusted = append(adjusted, [2]int{r[0] + delta, r[1] + delta})
+		default:
+		}
+	}
+	return adjusted
+}
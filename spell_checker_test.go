@@ -0,0 +1,124 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison"
+)
+
+const (
+	testAffixData = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwz'
+REP 2
+REP f ph
+REP ph f
+NOSUGGEST !
+FORBIDDENWORD *
+NEEDAFFIX %
+
+# Prefixes
+PFX U Y 1
+PFX U 0 un .
+
+# Suffixes
+SFX S Y 3
+SFX S y ies [^aeiou]y
+SFX S 0 s [aeiou]y
+SFX S 0 s [^y]
+
+SFX D Y 2
+SFX D 0 d e
+SFX D 0 ed [^e]
+`
+	testWordData = `10
+happy/U
+lock/USD
+city/S
+bake/D
+London
+phone/S
+damn/!
+irregardless/*
+wiki/%S
+and\/or
+`
+)
+
+type wordSet map[string]bool
+
+func (w wordSet) Check(word string) bool           { return w[word] }
+func (w wordSet) Suggest(_ string, _ int) []string { return nil }
+func (w wordSet) Add(word string)                  { w[word] = true }
+
+func newTestDictionary(c check.Checker) *unison.HunspellDictionary {
+	d, err := unison.NewHunspellDictionary(strings.NewReader(testAffixData), strings.NewReader(testWordData))
+	c.NoError(err)
+	return d
+}
+
+func TestHunspellDictionaryCheck(t *testing.T) {
+	c := check.New(t)
+	d := newTestDictionary(c)
+	for _, word := range []string{
+		"happy", "unhappy", "lock", "locks", "locked", "unlock", "unlocks", "unlocked", "city", "cities", "bake",
+		"baked", "London", "phones", "damn", "wikis", "and/or", "Happy", "HAPPY", "LONDON", "Unlocked",
+	} {
+		c.True(d.Check(word), word)
+	}
+	for _, word := range []string{
+		"hapy", "citys", "cityies", "unbake", "bakeed", "london", "irregardless", "wiki", "and", "unhappys", "HaPpY",
+	} {
+		c.False(d.Check(word), word)
+	}
+}
+
+func TestHunspellDictionarySuggest(t *testing.T) {
+	c := check.New(t)
+	d := newTestDictionary(c)
+	c.Equal([]string{"phone"}, d.Suggest("fone", 1), "REP replacements are tried first")
+	c.True(slices.Contains(d.Suggest("hapy", 5), "happy"))
+	c.True(slices.Contains(d.Suggest("citeis", 5), "cities"))
+	c.True(slices.Contains(d.Suggest("lockcity", 5), "lock city"))
+	c.False(slices.Contains(d.Suggest("damm", 5), "damn"), "words marked NOSUGGEST are never suggested")
+	c.Nil(d.Suggest("hapy", 0))
+}
+
+func TestHunspellDictionaryAdd(t *testing.T) {
+	c := check.New(t)
+	d := newTestDictionary(c)
+	c.False(d.Check("unison"))
+	d.Add("unison")
+	c.True(d.Check("unison"))
+	c.True(d.Check("Unison"))
+	c.True(slices.Contains(d.Suggest("unisno", 5), "unison"))
+}
+
+func TestHunspellDictionaryEncoding(t *testing.T) {
+	c := check.New(t)
+	d, err := unison.NewHunspellDictionary(strings.NewReader("SET ISO8859-1\n"), strings.NewReader("1\ncaf\xe9\n"))
+	c.NoError(err)
+	c.True(d.Check("café"))
+	_, err = unison.NewHunspellDictionary(strings.NewReader("SET KOI8-R\n"), strings.NewReader("0\n"))
+	c.HasError(err)
+}
+
+func TestFindMisspelledWords(t *testing.T) {
+	c := check.New(t)
+	checker := wordSet{"Don’t": true, "café": true}
+	c.Equal([][2]int{{6, 10}, {22, 26}, {34, 38}},
+		unison.FindMisspelledWords(checker, []rune("Don’t spel 3rd my_var wrds, café. dogs' 42")),
+		"words holding digits or underscores are skipped and trailing apostrophes aren't part of a word")
+	c.Nil(unison.FindMisspelledWords(checker, nil))
+}
@@ -0,0 +1,480 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ Layout = &SplitPanel{}
+
+// SplitPanelLayoutData is used to control how a child of a SplitPanel is sized. Set it on the child via
+// SetLayoutData(). Children without it use the zero value.
+type SplitPanelLayoutData struct {
+	Minimum     float32 // The minimum size along the split axis; 0 means the child's own minimum size is used
+	Maximum     float32 // The maximum size along the split axis; 0 means there is no limit
+	Fixed       bool    // If true, the pane retains its size when the SplitPanel is resized
+	Collapsible bool    // If true, double-clicking an adjacent divider will collapse the pane
+}
+
+// SplitPanel arranges its children in a row (horizontal) or column (vertical), with a draggable divider between each
+// of them. When the SplitPanel is resized, the change is distributed among the panes that are not fixed, in proportion
+// to their current sizes. The appearance of the dividers is controlled by the embedded DockTheme.
+type SplitPanel struct {
+	// DividersChangedCallback is called after the user moves a divider or collapses or restores a pane.
+	DividersChangedCallback func()
+	panes                   map[*Panel]*splitPane
+	DockTheme
+	Panel
+	dragIndex           int
+	dragInitialPosition float32
+	dragEventPosition   float32
+	horizontal          bool
+	dragIsValid         bool
+}
+
+type splitPane struct {
+	size      float32 // Less than 0 until the pane has been sized
+	restore   float32 // The size to return to when no longer collapsed
+	collapsed bool
+}
+
+// NewSplitPanel creates a new, empty SplitPanel. A horizontal SplitPanel places its children side by side with
+// vertical dividers between them, while a vertical one stacks them with horizontal dividers between them.
+func NewSplitPanel(horizontal bool) *SplitPanel {
+	s := &SplitPanel{
+		DockTheme:  DefaultDockTheme,
+		panes:      make(map[*Panel]*splitPane),
+		dragIndex:  -1,
+		horizontal: horizontal,
+	}
+	s.Self = s
+	s.SetLayout(s)
+	s.DrawCallback = s.DefaultDraw
+	s.UpdateCursorCallback = s.DefaultUpdateCursor
+	s.MouseDownCallback = s.DefaultMouseDown
+	s.MouseDragCallback = s.DefaultMouseDrag
+	s.MouseUpCallback = s.DefaultMouseUp
+	return s
+}
+
+// Horizontal returns true if the children are placed side by side rather than stacked.
+func (s *SplitPanel) Horizontal() bool {
+	return s.horizontal
+}
+
+// SetHorizontal sets whether the children are placed side by side rather than stacked. The existing pane sizes are
+// discarded.
+func (s *SplitPanel) SetHorizontal(horizontal bool) {
+	if s.horizontal != horizontal {
+		s.horizontal = horizontal
+		clear(s.panes)
+		s.MarkForLayoutAndRedraw()
+	}
+}
+
+// DividerCount returns the number of dividers, which is one less than the number of children.
+func (s *SplitPanel) DividerCount() int {
+	return max(len(s.Children())-1, 0)
+}
+
+// DividerPosition returns the position of the divider at the specified index, relative to the start of the content
+// area. Returns -1 if the index is out of range.
+func (s *SplitPanel) DividerPosition(index int) float32 {
+	children := s.Children()
+	if index < 0 || index >= len(children)-1 {
+		return -1
+	}
+	s.ensureSized(children)
+	var pos float32
+	for i := 0; i <= index; i++ {
+		pos += s.pane(children[i]).size
+	}
+	return pos + float32(index)*s.DockDividerSize()
+}
+
+// SetDividerPosition sets the position of the divider at the specified index, relative to the start of the content
+// area. Only the panes on either side of the divider are resized, and their minimum and maximum sizes are respected.
+// A collapsed pane that is given space by this is restored.
+func (s *SplitPanel) SetDividerPosition(index int, pos float32) {
+	children := s.Children()
+	if index < 0 || index >= len(children)-1 {
+		return
+	}
+	s.ensureSized(children)
+	before := s.pane(children[index])
+	after := s.pane(children[index+1])
+	combined := before.size + after.size
+	minBefore, maxBefore := s.limits(children[index])
+	minAfter, maxAfter := s.limits(children[index+1])
+	size := pos - (s.DividerPosition(index) - before.size)
+	if (before.collapsed && size < minBefore) || (after.collapsed && combined-size < minAfter) {
+		// Not far enough to restore the collapsed pane
+		return
+	}
+	size = max(min(size, maxBefore, combined-minAfter), minBefore, combined-maxAfter, 0)
+	size = min(size, combined)
+	if size != before.size {
+		before.size = size
+		after.size = combined - size
+		before.collapsed = before.collapsed && size == 0
+		after.collapsed = after.collapsed && after.size == 0
+		s.MarkForLayoutAndRedraw()
+	}
+}
+
+// Collapsed returns true if the child at the specified index is collapsed.
+func (s *SplitPanel) Collapsed(index int) bool {
+	children := s.Children()
+	if index < 0 || index >= len(children) {
+		return false
+	}
+	return s.pane(children[index]).collapsed
+}
+
+// SetCollapsed collapses or restores the child at the specified index. The space it occupied is given to, or taken
+// back from, the child that follows it, or the one that precedes it if it is the last child.
+func (s *SplitPanel) SetCollapsed(index int, collapsed bool) {
+	children := s.Children()
+	if index < 0 || index >= len(children) || len(children) < 2 {
+		return
+	}
+	s.ensureSized(children)
+	p := s.pane(children[index])
+	if p.collapsed == collapsed {
+		return
+	}
+	neighborIndex := index + 1
+	if neighborIndex == len(children) {
+		neighborIndex = index - 1
+	}
+	neighbor := s.pane(children[neighborIndex])
+	if collapsed {
+		p.restore = p.size
+		neighbor.size += p.size
+		p.size = 0
+	} else {
+		minNeighbor, _ := s.limits(children[neighborIndex])
+		p.size = p.restore
+		neighbor.size = max(neighbor.size-p.size, minNeighbor)
+	}
+	p.collapsed = collapsed
+	s.MarkForLayoutAndRedraw()
+}
+
+// toggleCollapsed collapses or restores one of the panes adjacent to the divider at the specified index. Returns true
+// if a change was made.
+func (s *SplitPanel) toggleCollapsed(index int) bool {
+	children := s.Children()
+	if index < 0 || index >= len(children)-1 {
+		return false
+	}
+	for _, i := range []int{index, index + 1} {
+		if s.pane(children[i]).collapsed {
+			s.SetCollapsed(i, false)
+			return true
+		}
+	}
+	for _, i := range []int{index, index + 1} {
+		if s.layoutData(children[i]).Collapsible {
+			s.SetCollapsed(i, true)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SplitPanel) pane(child *Panel) *splitPane {
+	p, ok := s.panes[child]
+	if !ok {
+		p = &splitPane{size: -1}
+		s.panes[child] = p
+	}
+	return p
+}
+
+func (s *SplitPanel) layoutData(child *Panel) SplitPanelLayoutData {
+	if data, ok := child.LayoutData().(*SplitPanelLayoutData); ok && data != nil {
+		return *data
+	}
+	return SplitPanelLayoutData{}
+}
+
+// length returns the extent of the size along the split axis.
+func (s *SplitPanel) length(size geom.Size) float32 {
+	if s.horizontal {
+		return size.Width
+	}
+	return size.Height
+}
+
+// position returns the coordinate of the point along the split axis.
+func (s *SplitPanel) position(where geom.Point) float32 {
+	if s.horizontal {
+		return where.X
+	}
+	return where.Y
+}
+
+// limits returns the minimum and maximum sizes of the child along the split axis.
+func (s *SplitPanel) limits(child *Panel) (minimum, maximum float32) {
+	data := s.layoutData(child)
+	minimum = data.Minimum
+	if minimum <= 0 {
+		minSize, _, _ := child.Sizes(geom.Size{})
+		minimum = s.length(minSize)
+	}
+	maximum = data.Maximum
+	if maximum <= 0 {
+		maximum = math.MaxFloat32
+	}
+	return minimum, max(maximum, minimum)
+}
+
+// ensureSized gives any children that haven't been sized yet their preferred size.
+func (s *SplitPanel) ensureSized(children []*Panel) {
+	for _, child := range children {
+		if p := s.pane(child); p.size < 0 {
+			_, prefSize, _ := child.Sizes(geom.Size{})
+			minimum, maximum := s.limits(child)
+			p.size = max(min(s.length(prefSize), maximum), minimum)
+			if p.collapsed {
+				p.restore = p.size
+				p.size = 0
+			}
+		}
+	}
+}
+
+// fit adjusts the sizes of the children so that they fill the available space. Panes that are not fixed absorb the
+// difference first, in proportion to their current sizes. Fixed panes are only adjusted if the others can't absorb it
+// all.
+func (s *SplitPanel) fit(children []*Panel, available float32) {
+	s.ensureSized(children)
+	mins := make([]float32, len(children))
+	maxs := make([]float32, len(children))
+	var total float32
+	for i, child := range children {
+		p := s.pane(child)
+		if p.collapsed {
+			p.size = 0
+			continue
+		}
+		mins[i], maxs[i] = s.limits(child)
+		p.size = max(min(p.size, maxs[i]), mins[i])
+		total += p.size
+	}
+	delta := available - total
+	for _, includeFixed := range []bool{false, true} {
+		for delta > 0.5 || delta < -0.5 {
+			var weight float32
+			var candidates []int
+			for i, child := range children {
+				p := s.pane(child)
+				if p.collapsed || (!includeFixed && s.layoutData(child).Fixed) {
+					continue
+				}
+				if (delta > 0 && p.size < maxs[i]) || (delta < 0 && p.size > mins[i]) {
+					candidates = append(candidates, i)
+					weight += p.size
+				}
+			}
+			if len(candidates) == 0 {
+				break
+			}
+			var applied float32
+			for _, i := range candidates {
+				p := s.pane(children[i])
+				share := delta / float32(len(candidates))
+				if weight > 0 {
+					share = delta * p.size / weight
+				}
+				size := max(min(p.size+share, maxs[i]), mins[i])
+				applied += size - p.size
+				p.size = size
+			}
+			delta -= applied
+			if applied < 0.01 && applied > -0.01 {
+				break
+			}
+		}
+	}
+}
+
+// dividerRect returns the area occupied by the divider at the specified index.
+func (s *SplitPanel) dividerRect(children []*Panel, index int) geom.Rect {
+	r := children[index].FrameRect()
+	size := s.DockDividerSize()
+	if s.horizontal {
+		return geom.NewRect(r.Right(), r.Y, size, r.Height)
+	}
+	return geom.NewRect(r.X, r.Bottom(), r.Width, size)
+}
+
+// dividerAt returns the index of the divider at the position, or -1 if there isn't one.
+func (s *SplitPanel) dividerAt(where geom.Point) int {
+	children := s.Children()
+	for i := 0; i < len(children)-1; i++ {
+		if where.In(s.dividerRect(children, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DefaultDraw fills in the background and draws the dividers.
+func (s *SplitPanel) DefaultDraw(gc *Canvas, dirty geom.Rect) {
+	gc.DrawRect(dirty, s.BackgroundInk.Paint(gc, dirty, paintstyle.Fill))
+	children := s.Children()
+	for i := 0; i < len(children)-1; i++ {
+		if r := s.dividerRect(children, i); r.Intersects(dirty) {
+			if s.horizontal {
+				s.DrawHorizontalGripper(gc, r)
+			} else {
+				s.DrawVerticalGripper(gc, r)
+			}
+		}
+	}
+}
+
+// DefaultUpdateCursor adjusts the cursor for any dividers it may be over.
+func (s *SplitPanel) DefaultUpdateCursor(where geom.Point) *Cursor {
+	if s.dragIndex != -1 || s.dividerAt(where) != -1 {
+		if s.horizontal {
+			return ResizeHorizontalCursor()
+		}
+		return ResizeVerticalCursor()
+	}
+	return nil
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (s *SplitPanel) DefaultMouseDown(where geom.Point, button, clickCount int, _ mod.Modifiers) bool {
+	if button != ButtonLeft {
+		return false
+	}
+	index := s.dividerAt(where)
+	if index == -1 {
+		return false
+	}
+	if clickCount == 2 {
+		if s.toggleCollapsed(index) && s.DividersChangedCallback != nil {
+			SafeCall(s.DividersChangedCallback)
+		}
+		return true
+	}
+	s.dragIndex = index
+	s.dragInitialPosition = s.DividerPosition(index)
+	s.dragEventPosition = s.position(where)
+	s.dragIsValid = false
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (s *SplitPanel) DefaultMouseDrag(where geom.Point, _ int, _ mod.Modifiers) bool {
+	s.dragDivider(where)
+	return true
+}
+
+func (s *SplitPanel) dragDivider(where geom.Point) {
+	if s.dragIndex != -1 {
+		if !s.dragIsValid {
+			s.dragIsValid = s.IsDragGesture(where)
+		}
+		if s.dragIsValid {
+			s.SetDividerPosition(s.dragIndex, s.dragInitialPosition+s.position(where)-s.dragEventPosition)
+		}
+	}
+}
+
+// DefaultMouseUp provides the default mouse up handling.
+func (s *SplitPanel) DefaultMouseUp(where geom.Point, _ int, _ mod.Modifiers) bool {
+	if s.dragIndex != -1 {
+		if s.dragIsValid {
+			s.dragDivider(where)
+			if s.DividersChangedCallback != nil {
+				SafeCall(s.DividersChangedCallback)
+			}
+		}
+		s.dragIndex = -1
+	}
+	return true
+}
+
+// LayoutSizes implements Layout.
+func (s *SplitPanel) LayoutSizes(target *Panel, hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	children := s.Children()
+	dividers := float32(max(len(children)-1, 0)) * s.DockDividerSize()
+	var minLength, prefLength float32
+	for _, child := range children {
+		minSize2, prefSize2, _ := child.Sizes(geom.Size{})
+		if s.horizontal {
+			minSize.Height = max(minSize.Height, minSize2.Height)
+			prefSize.Height = max(prefSize.Height, prefSize2.Height)
+		} else {
+			minSize.Width = max(minSize.Width, minSize2.Width)
+			prefSize.Width = max(prefSize.Width, prefSize2.Width)
+		}
+		p := s.pane(child)
+		if p.collapsed {
+			continue
+		}
+		minimum, maximum := s.limits(child)
+		minLength += minimum
+		if p.size >= 0 {
+			prefLength += p.size
+		} else {
+			prefLength += max(min(s.length(prefSize2), maximum), minimum)
+		}
+	}
+	if s.horizontal {
+		minSize.Width = minLength + dividers
+		prefSize.Width = prefLength + dividers
+	} else {
+		minSize.Height = minLength + dividers
+		prefSize.Height = prefLength + dividers
+	}
+	if b := target.Border(); b != nil {
+		insets := b.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+	}
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (s *SplitPanel) PerformLayout(_ *Panel) {
+	children := s.Children()
+	for child := range s.panes {
+		if child.Parent() != s.AsPanel() {
+			delete(s.panes, child)
+		}
+	}
+	r := s.ContentRect(false)
+	dividerSize := s.DockDividerSize()
+	s.fit(children, max(s.length(r.Size)-float32(max(len(children)-1, 0))*dividerSize, 0))
+	x := r.X
+	y := r.Y
+	for _, child := range children {
+		p := s.pane(child)
+		child.Hidden = p.collapsed
+		if s.horizontal {
+			child.SetFrameRect(geom.NewRect(x, y, p.size, r.Height))
+			x += p.size + dividerSize
+		} else {
+			child.SetFrameRect(geom.NewRect(x, y, r.Width, p.size))
+			y += p.size + dividerSize
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+// newSplitTestPane returns a panel with a minimum size of 20x20 and a preferred size of 100x100.
+func newSplitTestPane(data *SplitPanelLayoutData) *Panel {
+	p := NewPanel()
+	p.SetSizer(func(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+		return geom.NewSize(20, 20), geom.NewSize(100, 100), MaxSize(geom.NewSize(100, 100))
+	})
+	if data != nil {
+		p.SetLayoutData(data)
+	}
+	return p
+}
+
+func TestSplitPanelResizing(t *testing.T) {
+	c := check.New(t)
+	split := NewSplitPanel(true)
+	a := newSplitTestPane(&SplitPanelLayoutData{Fixed: true, Collapsible: true})
+	b := newSplitTestPane(nil)
+	cc := newSplitTestPane(&SplitPanelLayoutData{Maximum: 160})
+	split.AddChild(a)
+	split.AddChild(b)
+	split.AddChild(cc)
+	c.Equal(2, split.DividerCount())
+	c.Equal(float32(8), split.DockDividerSize())
+	_, pref, _ := split.Sizes(geom.Size{})
+	c.Equal(geom.NewSize(316, 100), pref)
+	split.SetFrameRect(geom.Rect{Size: pref})
+	split.ValidateLayout()
+	c.Equal(geom.NewRect(0, 0, 100, 100), a.FrameRect())
+	c.Equal(geom.NewRect(108, 0, 100, 100), b.FrameRect())
+	c.Equal(geom.NewRect(216, 0, 100, 100), cc.FrameRect())
+	c.Equal(float32(100), split.DividerPosition(0))
+	c.Equal(float32(208), split.DividerPosition(1))
+	c.Equal(float32(-1), split.DividerPosition(2))
+
+	split.SetFrameRect(geom.NewRect(0, 0, 376, 100))
+	split.ValidateLayout()
+	c.Equal(float32(100), a.FrameRect().Width, "fixed panes keep their size")
+	c.Equal(float32(130), b.FrameRect().Width, "the other panes share the extra space")
+	c.Equal(float32(130), cc.FrameRect().Width)
+
+	split.SetFrameRect(geom.NewRect(0, 0, 476, 100))
+	split.ValidateLayout()
+	c.Equal(float32(160), cc.FrameRect().Width, "panes don't grow beyond their maximum")
+	c.Equal(float32(200), b.FrameRect().Width)
+
+	split.SetDividerPosition(0, 10)
+	split.ValidateLayout()
+	c.Equal(float32(20), a.FrameRect().Width, "panes don't shrink below their minimum")
+	c.Equal(float32(280), b.FrameRect().Width, "only the panes next to the divider are changed")
+	split.SetDividerPosition(1, 476)
+	split.ValidateLayout()
+	c.Equal(float32(420), b.FrameRect().Width)
+	c.Equal(float32(20), cc.FrameRect().Width)
+
+	vertical := NewSplitPanel(false)
+	top := newSplitTestPane(nil)
+	bottom := newSplitTestPane(nil)
+	vertical.AddChild(top)
+	vertical.AddChild(bottom)
+	vertical.SetFrameRect(geom.NewRect(0, 0, 50, 308))
+	vertical.ValidateLayout()
+	c.Equal(geom.NewRect(0, 0, 50, 150), top.FrameRect())
+	c.Equal(geom.NewRect(0, 158, 50, 150), bottom.FrameRect())
+}
+
+func TestSplitPanelCollapseAndState(t *testing.T) {
+	c := check.New(t)
+	split := NewSplitPanel(true)
+	a := newSplitTestPane(&SplitPanelLayoutData{Collapsible: true})
+	b := newSplitTestPane(nil)
+	split.AddChild(a)
+	split.AddChild(b)
+	split.SetFrameRect(geom.NewRect(0, 0, 208, 100))
+	split.ValidateLayout()
+	var changes int
+	split.DividersChangedCallback = func() { changes++ }
+
+	c.False(split.DefaultMouseDown(geom.NewPoint(50, 50), ButtonLeft, 2, 0), "clicks outside a divider are ignored")
+	c.True(split.DefaultMouseDown(geom.NewPoint(104, 50), ButtonLeft, 2, 0))
+	split.ValidateLayout()
+	c.True(split.Collapsed(0), "double-clicking a divider collapses a collapsible pane next to it")
+	c.True(a.Hidden)
+	c.Equal(geom.NewRect(8, 0, 200, 100), b.FrameRect())
+	c.Equal(1, changes)
+
+	data, err := json.Marshal(NewSplitPanelState(split))
+	c.NoError(err)
+	c.Equal(`{"panes":[{"size":100,"collapsed":true},{"size":200}]}`, string(data))
+
+	c.True(split.DefaultMouseDown(geom.NewPoint(4, 50), ButtonLeft, 2, 0))
+	split.ValidateLayout()
+	c.False(split.Collapsed(0), "double-clicking again restores it")
+	c.False(a.Hidden)
+	c.Equal(float32(100), a.FrameRect().Width)
+	c.Equal(2, changes)
+
+	split.SetDividerPosition(0, 60)
+	split.ValidateLayout()
+	c.Equal(float32(60), a.FrameRect().Width)
+
+	var state SplitPanelState
+	c.NoError(json.Unmarshal(data, &state))
+	state.Apply(split)
+	split.ValidateLayout()
+	c.True(split.Collapsed(0))
+	c.Equal(float32(200), b.FrameRect().Width)
+	split.SetCollapsed(0, false)
+	split.ValidateLayout()
+	c.Equal(float32(100), a.FrameRect().Width, "the collapsed pane is restored to its saved size")
+}
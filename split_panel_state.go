@@ -0,0 +1,64 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+// SplitPanelState holds a snapshot of the divider positions of a SplitPanel, expressed as the sizes of its panes.
+type SplitPanelState struct {
+	Panes []*SplitPaneState `json:"panes,omitzero"` // In the same order as the children of the SplitPanel
+}
+
+// SplitPaneState holds the state of a single pane within a SplitPanelState.
+type SplitPaneState struct {
+	Size      float32 `json:"size,omitzero"` // For a collapsed pane, the size it will be restored to
+	Collapsed bool    `json:"collapsed,omitzero"`
+}
+
+// NewSplitPanelState creates a new SplitPanelState for the given SplitPanel.
+func NewSplitPanelState(split *SplitPanel) *SplitPanelState {
+	children := split.Children()
+	split.ensureSized(children)
+	s := &SplitPanelState{Panes: make([]*SplitPaneState, len(children))}
+	for i, child := range children {
+		p := split.pane(child)
+		ps := &SplitPaneState{
+			Size:      p.size,
+			Collapsed: p.collapsed,
+		}
+		if p.collapsed {
+			ps.Size = p.restore
+		}
+		s.Panes[i] = ps
+	}
+	return s
+}
+
+// Apply the saved SplitPanelState to the specified SplitPanel. Panes are matched to children by position. Children
+// beyond those in the state retain their current size, while panes in the state beyond the children are ignored. The
+// sizes are adjusted to fit the SplitPanel at its next layout.
+func (s *SplitPanelState) Apply(split *SplitPanel) {
+	children := split.Children()
+	for i, ps := range s.Panes {
+		if i >= len(children) {
+			break
+		}
+		if ps == nil {
+			continue
+		}
+		p := split.pane(children[i])
+		p.collapsed = ps.Collapsed
+		if ps.Collapsed {
+			p.restore = max(ps.Size, 0)
+			p.size = 0
+		} else {
+			p.size = max(ps.Size, 0)
+		}
+	}
+	split.MarkForLayoutAndRedraw()
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/pathop"
+)
+
+// DefaultStepperTheme holds the default StepperTheme values for Steppers. Modifying this data will not alter existing
+// Steppers, but will alter any Steppers created in the future.
+var DefaultStepperTheme = StepperTheme{
+	BackgroundInk:   ThemeAboveSurface,
+	OnBackgroundInk: ThemeOnAboveSurface,
+	EdgeInk:         ThemeSurfaceEdge,
+	PressedInk:      ThemeFocus,
+	OnPressedInk:    ThemeOnFocus,
+	CornerRadius:    geom.NewUniformSize(4),
+	Width:           14,
+	RepeatDelay:     400 * time.Millisecond,
+	RepeatInterval:  50 * time.Millisecond,
+}
+
+// StepperTheme holds theming data for a Stepper.
+type StepperTheme struct {
+	BackgroundInk   Ink
+	OnBackgroundInk Ink
+	EdgeInk         Ink
+	PressedInk      Ink
+	OnPressedInk    Ink
+	CornerRadius    geom.Size
+	Width           float32
+	RepeatDelay     time.Duration // The time a button must be held down before it starts repeating
+	RepeatInterval  time.Duration // The time between each repeat once repeating has started
+}
+
+// Stepper provides a pair of buttons, one above the other, for stepping a value up or down. Holding a button down
+// repeats the step until it is released.
+type Stepper struct {
+	// StepCallback is called each time a step is taken, with 1 for the up button or -1 for the down button. mods holds
+	// the modifier keys that were down at the time.
+	StepCallback func(steps int, mods mod.Modifiers)
+	Panel
+	StepperTheme
+	repeatGeneration int
+	pressed          int
+	mods             mod.Modifiers
+	over             bool
+}
+
+// NewStepper creates a new Stepper.
+func NewStepper() *Stepper {
+	s := &Stepper{StepperTheme: DefaultStepperTheme}
+	s.Self = s
+	s.SetSizer(s.DefaultSizes)
+	s.DrawCallback = s.DefaultDraw
+	s.MouseDownCallback = s.DefaultMouseDown
+	s.MouseDragCallback = s.DefaultMouseDrag
+	s.MouseUpCallback = s.DefaultMouseUp
+	return s
+}
+
+// NewNumericFieldStepper creates a new Stepper that steps the value of the field and matches its height, for placement
+// beside it. The field's Step is set to one if it is currently zero.
+func NewNumericFieldStepper[T xmath.Integer | xmath.Float](field *NumericField[T]) *Stepper {
+	if field.Step == 0 {
+		field.Step = 1
+	}
+	s := NewStepper()
+	s.StepCallback = field.StepValue
+	s.SetSizer(func(hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
+		minSize, prefSize, maxSize = s.DefaultSizes(hint)
+		_, fieldPrefSize, _ := field.Sizes(geom.Size{})
+		prefSize.Height = max(prefSize.Height, fieldPrefSize.Height)
+		return minSize, prefSize, maxSize
+	})
+	return s
+}
+
+// DefaultSizes provides the default sizing.
+func (s *Stepper) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	minSize = geom.NewSize(s.Width, s.Width)
+	prefSize = geom.NewSize(s.Width, s.Width*2)
+	maxSize = geom.NewSize(s.Width, DefaultMaxSize)
+	if border := s.Border(); border != nil {
+		insets := border.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+		maxSize = maxSize.Add(insets)
+	}
+	return minSize, prefSize, maxSize
+}
+
+// buttonRect returns the rectangle of the up button when steps is positive, or the down button otherwise.
+func (s *Stepper) buttonRect(steps int) geom.Rect {
+	r := s.ContentRect(false)
+	r.Height /= 2
+	if steps <= 0 {
+		r.Y += r.Height
+	}
+	return r
+}
+
+// DefaultDraw provides the default drawing.
+func (s *Stepper) DefaultDraw(canvas *Canvas, _ geom.Rect) {
+	r := s.ContentRect(false)
+	canvas.DrawRoundedRect(r, s.CornerRadius, s.BackgroundInk.Paint(canvas, r, paintstyle.Fill))
+	for _, steps := range []int{1, -1} {
+		buttonRect := s.buttonRect(steps)
+		ink := s.OnBackgroundInk
+		if s.pressed == steps && s.over {
+			canvas.Save()
+			canvas.ClipRect(buttonRect, pathop.Intersect, false)
+			canvas.DrawRoundedRect(r, s.CornerRadius, s.PressedInk.Paint(canvas, r, paintstyle.Fill))
+			canvas.Restore()
+			ink = s.OnPressedInk
+		}
+		size := min(buttonRect.Width, buttonRect.Height) / 4
+		center := buttonRect.Center()
+		if steps < 0 {
+			size = -size
+		}
+		path := NewPath()
+		path.MoveTo(geom.NewPoint(center.X-size, center.Y+size/2))
+		path.LineTo(geom.NewPoint(center.X, center.Y-size/2))
+		path.LineTo(geom.NewPoint(center.X+size, center.Y+size/2))
+		path.Close()
+		canvas.DrawPath(path, ink.Paint(canvas, buttonRect, paintstyle.Fill))
+	}
+	divider := geom.NewRect(r.X, r.CenterY()-0.5, r.Width, 1)
+	canvas.DrawRect(divider, s.EdgeInk.Paint(canvas, divider, paintstyle.Fill))
+	edge := r.Inset(geom.NewUniformInsets(0.5))
+	canvas.DrawRoundedRect(edge, s.CornerRadius, s.EdgeInk.Paint(canvas, edge, paintstyle.Stroke))
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (s *Stepper) DefaultMouseDown(where geom.Point, button, _ int, mods mod.Modifiers) bool {
+	if button != ButtonLeft || !s.Enabled() {
+		return true
+	}
+	s.pressed = -1
+	if where.In(s.buttonRect(1)) {
+		s.pressed = 1
+	}
+	s.over = true
+	s.mods = mods
+	s.step()
+	s.repeatGeneration++
+	generation := s.repeatGeneration
+	InvokeTaskAfter(func() { s.repeat(generation) }, s.RepeatDelay)
+	s.MarkForRedraw()
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (s *Stepper) DefaultMouseDrag(where geom.Point, _ int, mods mod.Modifiers) bool {
+	if s.pressed != 0 {
+		s.mods = mods
+		if over := where.In(s.buttonRect(s.pressed)); over != s.over {
+			s.over = over
+			s.MarkForRedraw()
+		}
+	}
+	return true
+}
+
+// DefaultMouseUp provides the default mouse up handling.
+func (s *Stepper) DefaultMouseUp(_ geom.Point, _ int, _ mod.Modifiers) bool {
+	if s.pressed != 0 {
+		s.pressed = 0
+		s.repeatGeneration++
+		s.MarkForRedraw()
+	}
+	return true
+}
+
+func (s *Stepper) step() {
+	if s.StepCallback != nil {
+		steps := s.pressed
+		mods := s.mods
+		SafeCall(func() { s.StepCallback(steps, mods) })
+	}
+}
+
+// repeat takes another step while the button that started the repeating is still held down, then schedules the next
+// one. A newer generation means the button has since been released.
+func (s *Stepper) repeat(generation int) {
+	if generation != s.repeatGeneration || s.pressed == 0 {
+		return
+	}
+	if s.over {
+		s.step()
+	}
+	InvokeTaskAfter(func() { s.repeat(generation) }, s.RepeatInterval)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+func TestStepperAutoRepeat(t *testing.T) {
+	c := check.New(t)
+	field := NewNumericField(5, 0, 20, strconv.Itoa, strconv.Atoi, nil)
+	s := NewNumericFieldStepper(field)
+	c.Equal(1, field.Step, "a step is set if there wasn't one")
+	_, pref, _ := s.Sizes(geom.Size{})
+	_, fieldPref, _ := field.Sizes(geom.Size{})
+	c.Equal(max(fieldPref.Height, 2*s.Width), pref.Height, "the stepper matches the height of the field")
+	// Keep the timers from firing on their own, so that the repeats can be driven directly
+	s.RepeatDelay = time.Hour
+	s.RepeatInterval = time.Hour
+	s.SetFrameRect(geom.NewRect(0, 0, 14, 20))
+	up := s.buttonRect(1).Center()
+	down := s.buttonRect(-1).Center()
+
+	c.True(s.DefaultMouseDown(up, ButtonLeft, 1, 0))
+	c.Equal(6, field.Value(), "pressing a button takes a step immediately")
+	generation := s.repeatGeneration
+	s.repeat(generation)
+	s.repeat(generation)
+	c.Equal(8, field.Value(), "holding the button down repeats the step")
+	s.DefaultMouseDrag(down, ButtonLeft, 0)
+	s.repeat(generation)
+	c.Equal(8, field.Value(), "no steps are taken while the mouse is outside of the pressed button")
+	s.DefaultMouseDrag(up, ButtonLeft, mod.Shift)
+	s.repeat(generation)
+	c.Equal(18, field.Value(), "the modifier keys apply to repeated steps")
+	s.repeat(generation)
+	c.Equal(20, field.Value(), "steps are limited to the maximum")
+	s.DefaultMouseUp(up, ButtonLeft, 0)
+	s.repeat(generation)
+	c.Equal(20, field.Value(), "releasing the button stops the repeating")
+
+	c.True(s.DefaultMouseDown(down, ButtonLeft, 1, 0))
+	c.Equal(19, field.Value())
+	s.repeat(generation)
+	c.Equal(19, field.Value(), "repeats from an earlier press are ignored")
+	s.DefaultMouseUp(down, ButtonLeft, 0)
+}
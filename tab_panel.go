@@ -0,0 +1,453 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/side"
+)
+
+var (
+	_ Layout = &TabPanel{}
+	_ Layout = &tabPanelContent{}
+)
+
+// DefaultTabPanelTheme holds the default TabPanelTheme values for TabPanels. Modifying this data will not alter
+// existing TabPanels, but will alter any TabPanels created in the future.
+var DefaultTabPanelTheme = TabPanelTheme{
+	HeaderInk:       ThemeSurface,
+	BackgroundInk:   ThemeAboveSurface,
+	OnBackgroundInk: ThemeOnAboveSurface,
+	EdgeInk:         ThemeSurfaceEdge,
+	TabFocusedInk:   ThemeFocus,
+	OnTabFocusedInk: ThemeOnFocus,
+	TabCurrentInk:   ThemeDeepestFocus,
+	OnTabCurrentInk: ThemeOnDeepestFocus,
+	DropAreaInk:     ThemeWarning,
+	TabBorder:       NewEmptyBorder(geom.Insets{Top: 2, Left: 4, Bottom: 2, Right: 4}),
+	LabelTheme:      defaultDockLabelTheme(),
+	ButtonTheme:     defaultDockButtonTheme(),
+	Gap:             4,
+	HeaderInset:     4,
+	MinimumTabWidth: 50,
+	TabGap:          4,
+	TabInsertSize:   3,
+}
+
+// TabPanelTheme holds theming data for a TabPanel.
+type TabPanelTheme struct {
+	HeaderInk       Ink
+	BackgroundInk   Ink
+	OnBackgroundInk Ink
+	EdgeInk         Ink
+	TabFocusedInk   Ink
+	OnTabFocusedInk Ink
+	TabCurrentInk   Ink
+	OnTabCurrentInk Ink
+	DropAreaInk     Ink
+	TabBorder       Border
+	LabelTheme      LabelTheme
+	ButtonTheme     ButtonTheme
+	Gap             float32
+	HeaderInset     float32
+	MinimumTabWidth float32
+	TabGap          float32
+	TabInsertSize   float32
+}
+
+// TabPanel holds one or more panels, only one of which is shown at a time, along with a row of tabs for choosing
+// between them. Unlike a DockContainer, it has no involvement with docking and places no requirements on its content.
+// When the keyboard focus is within it, Ctrl-Tab and Ctrl-PageDown switch to the next tab, while Ctrl-Shift-Tab and
+// Ctrl-PageUp switch to the previous one.
+type TabPanel struct {
+	CurrentTabChangedCallback func()
+	TabMovedCallback          func(from, to int)
+	TabClosedCallback         func(tab *TabPanelTab)
+	header                    *tabPanelHeader
+	content                   *tabPanelContent
+	TabPanelTheme
+	Panel
+	placement   side.Enum
+	current     int
+	reorderable bool
+}
+
+type tabPanelContent struct {
+	owner *TabPanel
+	Panel
+}
+
+// NewTabPanel creates a new, empty TabPanel with its tabs along the top.
+func NewTabPanel() *TabPanel {
+	t := &TabPanel{
+		TabPanelTheme: DefaultTabPanelTheme,
+		current:       -1,
+		reorderable:   true,
+	}
+	t.Self = t
+	t.SetLayout(t)
+	t.header = newTabPanelHeader(t)
+	t.content = &tabPanelContent{owner: t}
+	t.content.Self = t.content
+	t.content.SetLayout(t.content)
+	t.AddChild(t.header)
+	t.AddChild(t.content)
+	t.KeyDownCallback = t.DefaultKeyDown
+	return t
+}
+
+// TabPlacement returns the side of the content the tabs are placed on.
+func (t *TabPanel) TabPlacement() side.Enum {
+	return t.placement
+}
+
+// SetTabPlacement sets the side of the content the tabs are placed on.
+func (t *TabPanel) SetTabPlacement(placement side.Enum) {
+	placement = placement.EnsureValid()
+	if t.placement != placement {
+		t.placement = placement
+		t.header.adjustToPlacement()
+		t.MarkForLayoutAndRedraw()
+	}
+}
+
+// Reorderable returns true if the user may reorder the tabs by dragging them.
+func (t *TabPanel) Reorderable() bool {
+	return t.reorderable
+}
+
+// SetReorderable sets whether the user may reorder the tabs by dragging them. Defaults to true.
+func (t *TabPanel) SetReorderable(reorderable bool) {
+	t.reorderable = reorderable
+}
+
+// Tabs returns the tabs within this TabPanel, in order.
+func (t *TabPanel) Tabs() []*TabPanelTab {
+	return t.header.tabs()
+}
+
+// TabIndex returns the index of the tab, or -1 if it isn't within this TabPanel.
+func (t *TabPanel) TabIndex(tab *TabPanelTab) int {
+	for i, one := range t.header.tabs() {
+		if one == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddTab adds a new tab at the end of the existing tabs. The icon may be nil. If the content implements the TabCloser
+// interface, the tab will be closable. The first tab added becomes the current one.
+func (t *TabPanel) AddTab(title string, icon Drawable, content Paneler) *TabPanelTab {
+	return t.InsertTab(-1, title, icon, content)
+}
+
+// InsertTab inserts a new tab at the specified index. An out-of-bounds index will cause the tab to be added at the end.
+// The icon may be nil. If the content implements the TabCloser interface, the tab will be closable. The first tab added
+// becomes the current one.
+func (t *TabPanel) InsertTab(index int, title string, icon Drawable, content Paneler) *TabPanelTab {
+	tabs := t.header.tabs()
+	if index < 0 || index > len(tabs) {
+		index = len(tabs)
+	}
+	tab := newTabPanelTab(t, title, icon, content)
+	t.header.AddChildAtIndex(tab, index)
+	t.content.AddChildAtIndex(content, index)
+	if t.current == -1 {
+		t.setCurrentIndex(0, false)
+	} else {
+		if index <= t.current {
+			t.current++
+		}
+		t.content.syncVisibility()
+	}
+	t.MarkForLayoutAndRedraw()
+	return tab
+}
+
+// CurrentIndex returns the index of the tab whose content is being shown, or -1 if there are no tabs.
+func (t *TabPanel) CurrentIndex() int {
+	return t.current
+}
+
+// CurrentTab returns the tab whose content is being shown. May return nil.
+func (t *TabPanel) CurrentTab() *TabPanelTab {
+	if tabs := t.header.tabs(); t.current >= 0 && t.current < len(tabs) {
+		return tabs[t.current]
+	}
+	return nil
+}
+
+// SetCurrentIndex makes the tab at the specified index the current one. If the keyboard focus was within the content
+// of the previous tab, it will be moved to the content of the new one.
+func (t *TabPanel) SetCurrentIndex(index int) {
+	t.setCurrentIndex(index, true)
+}
+
+func (t *TabPanel) setCurrentIndex(index int, notify bool) {
+	tabs := t.header.tabs()
+	if index < 0 || index >= len(tabs) || index == t.current {
+		return
+	}
+	hadFocus := t.focusIsWithinContent()
+	t.current = index
+	t.content.syncVisibility()
+	t.header.MarkForLayoutAndRedraw()
+	t.MarkForRedraw()
+	if hadFocus {
+		t.focusCurrentContent()
+	}
+	if notify && t.CurrentTabChangedCallback != nil {
+		SafeCall(t.CurrentTabChangedCallback)
+	}
+}
+
+// SelectNextTab makes the tab after the current one the current one, wrapping around to the first tab if necessary.
+func (t *TabPanel) SelectNextTab() {
+	if count := len(t.header.tabs()); count > 1 {
+		t.SetCurrentIndex((t.current + 1) % count)
+	}
+}
+
+// SelectPreviousTab makes the tab before the current one the current one, wrapping around to the last tab if
+// necessary.
+func (t *TabPanel) SelectPreviousTab() {
+	if count := len(t.header.tabs()); count > 1 {
+		t.SetCurrentIndex((t.current + count - 1) % count)
+	}
+}
+
+// MoveTab moves the tab at the from index to the to index. The current tab remains current.
+func (t *TabPanel) MoveTab(from, to int) {
+	tabs := t.header.tabs()
+	if from < 0 || from >= len(tabs) || to < 0 || to >= len(tabs) || from == to {
+		return
+	}
+	current := t.CurrentTab()
+	tab := tabs[from]
+	content := t.content.Children()[from]
+	t.header.RemoveChildAtIndex(from)
+	t.header.AddChildAtIndex(tab, to)
+	t.content.RemoveChildAtIndex(from)
+	t.content.AddChildAtIndex(content, to)
+	t.current = t.TabIndex(current)
+	t.content.syncVisibility()
+	t.MarkForLayoutAndRedraw()
+	if t.TabMovedCallback != nil {
+		SafeCall(func() { t.TabMovedCallback(from, to) })
+	}
+}
+
+// AttemptClose attempts to close the tab at the specified index. This only has an effect if the tab is closable. If
+// its content implements the TabCloser interface, it will be consulted first. Returns true if the tab was closed.
+func (t *TabPanel) AttemptClose(index int) bool {
+	tabs := t.header.tabs()
+	if index < 0 || index >= len(tabs) || !tabs[index].Closable() {
+		return false
+	}
+	tab := tabs[index]
+	if closer, ok := tab.content.(TabCloser); ok {
+		if !closer.MayAttemptClose() || !closer.AttemptClose() {
+			return false
+		}
+	}
+	// The TabCloser may have already removed the tab itself
+	if index = t.TabIndex(tab); index != -1 {
+		t.RemoveTab(index)
+	}
+	return true
+}
+
+// RemoveTab removes the tab at the specified index. If it was the current tab, one of its neighbors becomes current.
+func (t *TabPanel) RemoveTab(index int) {
+	tabs := t.header.tabs()
+	if index < 0 || index >= len(tabs) {
+		return
+	}
+	tab := tabs[index]
+	hadFocus := t.focusIsWithinContent()
+	t.header.RemoveChildAtIndex(index)
+	t.content.RemoveChildAtIndex(index)
+	changed := false
+	switch {
+	case index < t.current:
+		t.current--
+	case index == t.current:
+		if t.current == len(tabs)-1 {
+			t.current--
+		}
+		changed = true
+	}
+	t.content.syncVisibility()
+	t.MarkForLayoutAndRedraw()
+	if hadFocus {
+		t.focusCurrentContent()
+	}
+	if t.TabClosedCallback != nil {
+		SafeCall(func() { t.TabClosedCallback(tab) })
+	}
+	if changed && t.current != -1 && t.CurrentTabChangedCallback != nil {
+		SafeCall(t.CurrentTabChangedCallback)
+	}
+}
+
+func (t *TabPanel) focusIsWithinContent() bool {
+	if wnd := t.Window(); wnd != nil {
+		for focus := wnd.Focus(); focus != nil; focus = focus.Parent() {
+			if focus == t.content.AsPanel() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *TabPanel) focusIsWithin() bool {
+	if wnd := t.Window(); wnd != nil {
+		for focus := wnd.Focus(); focus != nil; focus = focus.Parent() {
+			if focus == t.AsPanel() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *TabPanel) focusCurrentContent() {
+	if wnd := t.Window(); wnd != nil {
+		if tab := t.CurrentTab(); tab != nil {
+			if panel := tab.content.AsPanel(); panel.Focusable() || panel.FirstFocusableChild() != nil {
+				wnd.SetFocus(panel)
+			}
+		}
+	}
+}
+
+// DefaultKeyDown provides the default key down handling, which switches tabs in response to Ctrl-Tab, Ctrl-Shift-Tab,
+// Ctrl-PageDown and Ctrl-PageUp.
+func (t *TabPanel) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, _ bool) bool {
+	if !mods.ControlDown() || mods.OptionDown() || mods.CommandDown() {
+		return false
+	}
+	switch keyCode {
+	case KeyTab:
+		if mods.ShiftDown() {
+			t.SelectPreviousTab()
+		} else {
+			t.SelectNextTab()
+		}
+	case KeyPageDown:
+		t.SelectNextTab()
+	case KeyPageUp:
+		t.SelectPreviousTab()
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *TabPanel) vertical() bool {
+	return t.placement == side.Left || t.placement == side.Right
+}
+
+// LayoutSizes implements Layout.
+func (t *TabPanel) LayoutSizes(target *Panel, hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	if t.vertical() {
+		minSize, prefSize, maxSize = t.header.Sizes(geom.NewSize(0, hint.Height))
+		minSize.Width = prefSize.Width
+		maxSize.Width = prefSize.Width
+		min2, pref2, max2 := t.content.Sizes(geom.NewSize(max(hint.Width-prefSize.Width, 0), hint.Height))
+		minSize.Width += min2.Width
+		prefSize.Width += pref2.Width
+		maxSize.Width += max2.Width
+		minSize.Height = max(minSize.Height, min2.Height)
+		prefSize.Height = max(prefSize.Height, pref2.Height)
+		maxSize.Height = max(maxSize.Height, max2.Height)
+	} else {
+		minSize, prefSize, maxSize = t.header.Sizes(geom.NewSize(hint.Width, 0))
+		minSize.Height = prefSize.Height
+		maxSize.Height = prefSize.Height
+		min2, pref2, max2 := t.content.Sizes(geom.NewSize(hint.Width, max(hint.Height-prefSize.Height, 0)))
+		minSize.Width = max(minSize.Width, min2.Width)
+		prefSize.Width = max(prefSize.Width, pref2.Width)
+		maxSize.Width = max(maxSize.Width, max2.Width)
+		minSize.Height += min2.Height
+		prefSize.Height += pref2.Height
+		maxSize.Height += max2.Height
+	}
+	if b := target.Border(); b != nil {
+		insets := b.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+		maxSize = maxSize.Add(insets)
+	}
+	return minSize, prefSize, maxSize
+}
+
+// PerformLayout implements Layout.
+func (t *TabPanel) PerformLayout(_ *Panel) {
+	r := t.ContentRect(false)
+	hr := r
+	cr := r
+	if t.vertical() {
+		_, pref, _ := t.header.Sizes(geom.NewSize(0, r.Height))
+		hr.Width = min(pref.Width, r.Width)
+		cr.Width = r.Width - hr.Width
+		if t.placement == side.Right {
+			hr.X = cr.Right()
+		} else {
+			cr.X = hr.Right()
+		}
+	} else {
+		_, pref, _ := t.header.Sizes(geom.NewSize(r.Width, 0))
+		hr.Height = min(pref.Height, r.Height)
+		cr.Height = r.Height - hr.Height
+		if t.placement == side.Bottom {
+			hr.Y = cr.Bottom()
+		} else {
+			cr.Y = hr.Bottom()
+		}
+	}
+	t.header.SetFrameRect(hr)
+	t.content.SetFrameRect(cr)
+}
+
+// syncVisibility hides all but the content of the current tab.
+func (c *tabPanelContent) syncVisibility() {
+	for i, child := range c.Children() {
+		child.Hidden = i != c.owner.current
+	}
+	c.MarkForRedraw()
+}
+
+func (c *tabPanelContent) LayoutSizes(_ *Panel, hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	for _, child := range c.Children() {
+		min2, pref2, max2 := child.Sizes(hint)
+		minSize = minSize.Max(min2)
+		prefSize = prefSize.Max(pref2)
+		maxSize = maxSize.Max(max2)
+	}
+	if b := c.Border(); b != nil {
+		insets := b.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+		maxSize = maxSize.Add(insets)
+	}
+	return minSize.Ceil(), prefSize.Ceil(), maxSize.Ceil()
+}
+
+func (c *tabPanelContent) PerformLayout(_ *Panel) {
+	r := c.ContentRect(false)
+	for i, child := range c.Children() {
+		child.Hidden = i != c.owner.current
+		child.SetFrameRect(r)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/side"
+)
+
+var _ Layout = &tabPanelHeader{}
+
+type tabPanelHeader struct {
+	owner          *TabPanel
+	overflowButton *Button
+	Panel
+	insertIndex int
+}
+
+func newTabPanelHeader(owner *TabPanel) *tabPanelHeader {
+	h := &tabPanelHeader{
+		owner:          owner,
+		overflowButton: createDockHeaderButton(),
+		insertIndex:    -1,
+	}
+	h.Self = h
+	h.DrawCallback = h.draw
+	h.SetLayout(h)
+	h.overflowButton.ClickCallback = h.handleOverflowPopup
+	h.AddChild(h.overflowButton)
+	h.adjustToPlacement()
+	return h
+}
+
+// adjustToPlacement updates the border to place the edge against the content.
+func (h *tabPanelHeader) adjustToPlacement() {
+	var edge, inset geom.Insets
+	switch h.owner.placement {
+	case side.Bottom:
+		edge.Top = 1
+		inset = geom.NewHorizontalInsets(h.owner.HeaderInset)
+	case side.Left:
+		edge.Right = 1
+		inset = geom.NewVerticalInsets(h.owner.HeaderInset)
+	case side.Right:
+		edge.Left = 1
+		inset = geom.NewVerticalInsets(h.owner.HeaderInset)
+	default:
+		edge.Bottom = 1
+		inset = geom.NewHorizontalInsets(h.owner.HeaderInset)
+	}
+	h.SetBorder(NewCompoundBorder(NewLineBorder(h.owner.EdgeInk, geom.Size{}, edge, false), NewEmptyBorder(inset)))
+	h.MarkForLayoutAndRedraw()
+}
+
+func (h *tabPanelHeader) tabs() []*TabPanelTab {
+	children := h.Children()
+	tabs := make([]*TabPanelTab, 0, len(children))
+	for _, c := range children {
+		if tab, ok := c.Self.(*TabPanelTab); ok {
+			tabs = append(tabs, tab)
+		}
+	}
+	return tabs
+}
+
+func (h *tabPanelHeader) draw(gc *Canvas, rect geom.Rect) {
+	gc.DrawRect(rect, h.owner.HeaderInk.Paint(gc, rect, paintstyle.Fill))
+	if h.insertIndex < 0 {
+		return
+	}
+	tabs := h.tabs()
+	r := h.ContentRect(false)
+	// The insert position may refer to a tab hidden by the overflow logic, whose frame is stale, so anchor the marker
+	// to the nearest visible tab instead.
+	var pos float32
+	if i := nextVisibleTabPanelTab(tabs, h.insertIndex); i != -1 {
+		pos = h.leading(tabs[i].FrameRect()) - ((h.owner.TabGap-h.owner.TabInsertSize)/2 + h.owner.TabInsertSize + 1)
+	} else if i = previousVisibleTabPanelTab(tabs, h.insertIndex-1); i != -1 {
+		pos = h.trailing(tabs[i].FrameRect())
+	} else {
+		return
+	}
+	if h.owner.vertical() {
+		r.Y = pos
+		r.Height = h.owner.TabInsertSize
+	} else {
+		r.X = pos
+		r.Width = h.owner.TabInsertSize
+	}
+	gc.DrawRect(r, h.owner.DropAreaInk.Paint(gc, r, paintstyle.Fill))
+}
+
+// leading returns the starting coordinate of the rect along the axis the tabs are laid out on.
+func (h *tabPanelHeader) leading(r geom.Rect) float32 {
+	if h.owner.vertical() {
+		return r.Y
+	}
+	return r.X
+}
+
+// trailing returns the ending coordinate of the rect along the axis the tabs are laid out on.
+func (h *tabPanelHeader) trailing(r geom.Rect) float32 {
+	if h.owner.vertical() {
+		return r.Bottom()
+	}
+	return r.Right()
+}
+
+// nextVisibleTabPanelTab returns the index of the first tab at or after index that is not hidden, or -1 if there is
+// none.
+func nextVisibleTabPanelTab(tabs []*TabPanelTab, index int) int {
+	for i := max(index, 0); i < len(tabs); i++ {
+		if !tabs[i].Hidden {
+			return i
+		}
+	}
+	return -1
+}
+
+// previousVisibleTabPanelTab returns the index of the last tab at or before index that is not hidden, or -1 if there is
+// none.
+func previousVisibleTabPanelTab(tabs []*TabPanelTab, index int) int {
+	for i := min(index, len(tabs)-1); i >= 0; i-- {
+		if !tabs[i].Hidden {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertIndexAt returns the index a tab dragged to the position would be inserted at.
+func (h *tabPanelHeader) insertIndexAt(where geom.Point) int {
+	pos := where.X
+	if h.owner.vertical() {
+		pos = where.Y
+	}
+	tabs := h.tabs()
+	for i, one := range tabs {
+		if one.Hidden {
+			// Tabs hidden by the overflow logic retain the frame from when they were last visible, so their stale
+			// rects must not participate in hit-testing.
+			continue
+		}
+		r := one.FrameRect()
+		if pos < (h.leading(r)+h.trailing(r))/2 {
+			return i
+		}
+		if pos < h.trailing(r) {
+			return i + 1
+		}
+	}
+	return len(tabs)
+}
+
+func (h *tabPanelHeader) dragUpdated(where geom.Point) {
+	if index := h.insertIndexAt(where); index != h.insertIndex {
+		h.insertIndex = index
+		h.MarkForRedraw()
+	}
+}
+
+func (h *tabPanelHeader) drop(tab *TabPanelTab, where geom.Point) {
+	h.insertIndex = -1
+	h.MarkForRedraw()
+	if from := h.owner.TabIndex(tab); from != -1 {
+		to := h.insertIndexAt(where)
+		if to > from {
+			to--
+		}
+		h.owner.MoveTab(from, to)
+	}
+}
+
+func (h *tabPanelHeader) LayoutSizes(target *Panel, _ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	tabs := h.tabs()
+	vertical := h.owner.vertical()
+	for i, tab := range tabs {
+		_, size, _ := tab.Sizes(geom.Size{})
+		if vertical {
+			prefSize.Width = max(prefSize.Width, size.Width)
+			prefSize.Height += size.Height
+			if i == 0 {
+				minSize.Height += size.Height
+			}
+		} else {
+			prefSize.Width += max(size.Width, h.owner.MinimumTabWidth)
+			prefSize.Height = max(prefSize.Height, size.Height)
+			if i == 0 {
+				minSize.Width += size.Width
+			}
+		}
+	}
+	if len(tabs) > 1 {
+		gaps := float32(len(tabs)-1) * h.owner.TabGap
+		if vertical {
+			prefSize.Height += gaps
+		} else {
+			prefSize.Width += gaps
+		}
+	}
+	if vertical {
+		minSize.Width = prefSize.Width
+	} else {
+		minSize.Height = prefSize.Height
+	}
+	if b := target.Border(); b != nil {
+		insets := b.Insets().Size()
+		minSize = minSize.Add(insets)
+		prefSize = prefSize.Add(insets)
+	}
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+func (h *tabPanelHeader) PerformLayout(_ *Panel) {
+	r := h.ContentRect(false)
+	tabs := h.tabs()
+	vertical := h.owner.vertical()
+	gap := h.owner.TabGap
+	available := r.Width
+	if vertical {
+		available = r.Height
+	}
+	// lengths holds the size of each tab along the axis the tabs are laid out on
+	lengths := make([]float32, len(tabs))
+	used := float32(max(len(tabs)-1, 0)) * gap
+	for i, tab := range tabs {
+		_, size, _ := tab.Sizes(geom.Size{})
+		if vertical {
+			lengths[i] = size.Height
+		} else {
+			lengths[i] = max(size.Width, h.owner.MinimumTabWidth)
+		}
+		used += lengths[i]
+	}
+	current := h.owner.current
+	if used > available && !vertical {
+		// Shrink the non-current tabs down, but no further than the minimum tab width
+		var shrinkable float32
+		for i := range tabs {
+			if i != current {
+				shrinkable += lengths[i] - h.owner.MinimumTabWidth
+			}
+		}
+		if shrinkable > 0 {
+			ratio := min((used-available)/shrinkable, 1)
+			for i := range tabs {
+				if i != current {
+					delta := (lengths[i] - h.owner.MinimumTabWidth) * ratio
+					lengths[i] -= delta
+					used -= delta
+				}
+			}
+		}
+	}
+	hidden := make([]bool, len(tabs))
+	var overflowSize geom.Size
+	count := 0
+	if used > available && len(tabs) > 1 {
+		// Still not small enough... add the overflow button and start moving trailing tabs into it
+		for i := len(tabs) - 1; i >= 0; i-- {
+			if i == current {
+				continue
+			}
+			hidden[i] = true
+			count++
+			used -= lengths[i] + gap
+			h.overflowButton.SetTitle("»" + strconv.Itoa(count))
+			_, overflowSize, _ = h.overflowButton.Sizes(geom.Size{})
+			extra := overflowSize.Width
+			if vertical {
+				extra = overflowSize.Height
+			}
+			if used+gap+extra <= available {
+				break
+			}
+		}
+	}
+	if used > available && !vertical && current >= 0 && current < len(tabs) {
+		// STILL not small enough... reduce the size of the current tab, too
+		lengths[current] = max(lengths[current]-(used-available), h.owner.MinimumTabWidth)
+	}
+	pos := h.leading(r)
+	for i, tab := range tabs {
+		tab.Hidden = hidden[i]
+		if tab.Hidden {
+			continue
+		}
+		if vertical {
+			tab.SetFrameRect(geom.NewRect(r.X, pos, r.Width, lengths[i]).Align())
+		} else {
+			tab.SetFrameRect(geom.NewRect(pos, r.Y, lengths[i], r.Height).Align())
+		}
+		pos += lengths[i] + gap
+	}
+	h.overflowButton.Hidden = count == 0
+	if !h.overflowButton.Hidden {
+		if vertical {
+			h.overflowButton.SetFrameRect(geom.NewRect(r.X+(r.Width-overflowSize.Width)/2, pos, overflowSize.Width,
+				overflowSize.Height).Align())
+		} else {
+			h.overflowButton.SetFrameRect(geom.NewRect(pos, r.Y+(r.Height-overflowSize.Height)/2, overflowSize.Width,
+				overflowSize.Height).Align())
+		}
+	}
+}
+
+func (h *tabPanelHeader) handleOverflowPopup() {
+	tabs := h.tabs()
+	m := DefaultMenuFactory().NewMenu(PopupMenuTemporaryBaseID, "", nil)
+	defer m.Dispose()
+	for i, tab := range tabs {
+		if tab.Hidden {
+			m.InsertItem(-1, m.Factory().NewItem(PopupMenuTemporaryBaseID+i+1, tab.Title(), KeyBinding{}, nil, func(item MenuItem) {
+				h.owner.SetCurrentIndex(item.ID() - (PopupMenuTemporaryBaseID + 1))
+			}))
+		}
+	}
+	m.Popup(h.overflowButton.RectToRoot(h.overflowButton.ContentRect(true)), 0)
+}
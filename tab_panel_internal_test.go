@@ -0,0 +1,186 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/side"
+)
+
+// tabCloserTestPanel is tab content that implements TabCloser.
+type tabCloserTestPanel struct {
+	Panel
+	allow    bool
+	attempts int
+}
+
+func newTabCloserTestPanel(allow bool) *tabCloserTestPanel {
+	p := &tabCloserTestPanel{allow: allow}
+	p.Self = p
+	return p
+}
+
+func (p *tabCloserTestPanel) MayAttemptClose() bool {
+	return p.allow
+}
+
+func (p *tabCloserTestPanel) AttemptClose() bool {
+	p.attempts++
+	return true
+}
+
+func tabPanelTitles(t *TabPanel) string {
+	var titles string
+	for _, tab := range t.Tabs() {
+		titles += tab.Title()
+	}
+	return titles
+}
+
+func TestTabPanelSelection(t *testing.T) {
+	c := check.New(t)
+	tp := NewTabPanel()
+	c.Equal(-1, tp.CurrentIndex())
+	c.Nil(tp.CurrentTab())
+	contents := []*Panel{NewPanel(), NewPanel(), NewPanel()}
+	for i, title := range []string{"a", "b", "c"} {
+		tp.AddTab(title, nil, contents[i])
+	}
+	c.Equal("abc", tabPanelTitles(tp))
+	c.Equal(0, tp.CurrentIndex(), "the first tab added becomes current")
+	c.False(contents[0].Hidden)
+	c.True(contents[1].Hidden)
+
+	var changes int
+	tp.CurrentTabChangedCallback = func() { changes++ }
+	tp.SetCurrentIndex(2)
+	c.Equal(2, tp.CurrentIndex())
+	c.True(tp.CurrentTab().Content() == contents[2])
+	c.True(contents[0].Hidden)
+	c.False(contents[2].Hidden)
+	c.Equal(1, changes)
+	tp.SetCurrentIndex(2)
+	tp.SetCurrentIndex(5)
+	c.Equal(1, changes, "unchanged or invalid indexes are ignored")
+
+	tp.InsertTab(0, "z", nil, NewPanel())
+	c.Equal("zabc", tabPanelTitles(tp))
+	c.Equal(3, tp.CurrentIndex(), "inserting before the current tab keeps it current")
+
+	c.True(tp.DefaultKeyDown(KeyTab, mod.Control, false))
+	c.Equal(0, tp.CurrentIndex(), "Ctrl-Tab wraps around to the first tab")
+	c.True(tp.DefaultKeyDown(KeyTab, mod.Control|mod.Shift, false))
+	c.Equal(3, tp.CurrentIndex(), "Ctrl-Shift-Tab wraps around to the last tab")
+	c.True(tp.DefaultKeyDown(KeyPageUp, mod.Control, false))
+	c.Equal(2, tp.CurrentIndex())
+	c.True(tp.DefaultKeyDown(KeyPageDown, mod.Control, false))
+	c.Equal(3, tp.CurrentIndex())
+	c.False(tp.DefaultKeyDown(KeyTab, 0, false), "a plain Tab is left for focus traversal")
+	c.False(tp.DefaultKeyDown(KeyPageDown, 0, false))
+}
+
+func TestTabPanelMoveAndClose(t *testing.T) {
+	c := check.New(t)
+	tp := NewTabPanel()
+	refuser := newTabCloserTestPanel(false)
+	closer := newTabCloserTestPanel(true)
+	tp.AddTab("a", nil, NewPanel())
+	tp.AddTab("b", nil, refuser)
+	tp.AddTab("c", nil, closer)
+	tp.AddTab("d", nil, NewPanel())
+	tp.SetCurrentIndex(1)
+
+	var moves [][2]int
+	tp.TabMovedCallback = func(from, to int) { moves = append(moves, [2]int{from, to}) }
+	tp.MoveTab(1, 3)
+	c.Equal("acdb", tabPanelTitles(tp))
+	c.Equal(3, tp.CurrentIndex(), "the current tab remains current when moved")
+	tp.MoveTab(0, 2)
+	c.Equal("cdab", tabPanelTitles(tp))
+	c.Equal(3, tp.CurrentIndex())
+	c.Equal([][2]int{{1, 3}, {0, 2}}, moves)
+
+	var closed []string
+	tp.TabClosedCallback = func(tab *TabPanelTab) { closed = append(closed, tab.Title()) }
+	c.False(tp.Tabs()[1].Closable(), "tabs are not closable unless their content is a TabCloser")
+	c.False(tp.AttemptClose(1))
+	c.True(tp.Tabs()[3].Closable())
+	c.False(tp.AttemptClose(3), "the TabCloser may refuse")
+	c.Equal("cdab", tabPanelTitles(tp))
+
+	c.True(tp.AttemptClose(0))
+	c.Equal(1, closer.attempts)
+	c.Equal("dab", tabPanelTitles(tp))
+	c.Equal(2, tp.CurrentIndex(), "closing a tab before the current one keeps it current")
+
+	tp.Tabs()[1].SetClosable(true)
+	tp.SetCurrentIndex(1)
+	c.True(tp.AttemptClose(1))
+	c.Equal("db", tabPanelTitles(tp))
+	c.Equal(1, tp.CurrentIndex(), "the following tab replaces a closed current tab")
+	tp.RemoveTab(1)
+	c.Equal(0, tp.CurrentIndex(), "the preceding tab replaces a closed last tab")
+	tp.RemoveTab(0)
+	c.Equal(-1, tp.CurrentIndex())
+	c.Equal([]string{"c", "a", "b", "d"}, closed)
+}
+
+func TestTabPanelLayout(t *testing.T) {
+	c := check.New(t)
+	tp := NewTabPanel()
+	for _, title := range []string{"One", "Two", "Three", "Four"} {
+		content := NewPanel()
+		content.SetSizer(func(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+			return geom.Size{}, geom.NewSize(100, 100), MaxSize(geom.Size{})
+		})
+		tp.AddTab(title, nil, content)
+	}
+	_, pref, _ := tp.Sizes(geom.Size{})
+	tp.SetFrameRect(geom.Rect{Size: pref})
+	tp.ValidateLayout()
+	header := tp.header.FrameRect()
+	content := tp.content.FrameRect()
+	c.Equal(header.Bottom(), content.Y, "the tabs are above the content by default")
+	c.True(tp.header.overflowButton.Hidden)
+	tabs := tp.Tabs()
+	for i := 1; i < len(tabs); i++ {
+		c.True(tabs[i-1].FrameRect().Right() < tabs[i].FrameRect().X)
+	}
+
+	// Drop the first tab between the third and fourth tabs
+	tp.header.dragUpdated(geom.NewPoint(tabs[3].FrameRect().X-1, tabs[3].FrameRect().CenterY()))
+	c.Equal(3, tp.header.insertIndex)
+	tp.header.drop(tabs[0], geom.NewPoint(tabs[2].FrameRect().Right()-1, tabs[2].FrameRect().CenterY()))
+	c.Equal(-1, tp.header.insertIndex)
+	c.Equal("TwoThreeOneFour", tabPanelTitles(tp))
+
+	tp.SetTabPlacement(side.Left)
+	tp.ValidateLayout()
+	header = tp.header.FrameRect()
+	content = tp.content.FrameRect()
+	c.Equal(header.Right(), content.X, "the tabs are to the left of the content")
+	tabs = tp.Tabs()
+	for i := 1; i < len(tabs); i++ {
+		c.True(tabs[i-1].FrameRect().Bottom() < tabs[i].FrameRect().Y)
+	}
+
+	tp.SetTabPlacement(side.Bottom)
+	tp.SetCurrentIndex(3)
+	tp.SetFrameRect(geom.NewRect(0, 0, 120, 200))
+	tp.ValidateLayout()
+	c.Equal(tp.content.FrameRect().Bottom(), tp.header.FrameRect().Y, "the tabs are below the content")
+	c.False(tp.header.overflowButton.Hidden, "tabs that don't fit are moved into the overflow menu")
+	c.False(tp.Tabs()[3].Hidden, "the current tab is never moved into the overflow menu")
+	c.True(tp.Tabs()[2].Hidden)
+}
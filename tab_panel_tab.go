@@ -0,0 +1,245 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/side"
+)
+
+// TabPanelTab is a single tab within a TabPanel. Create them with TabPanel.AddTab() or TabPanel.InsertTab().
+type TabPanelTab struct {
+	owner   *TabPanel
+	content Paneler
+	title   *Label
+	button  *Button
+	Panel
+	pressed  bool
+	dragging bool
+}
+
+func newTabPanelTab(owner *TabPanel, title string, icon Drawable, content Paneler) *TabPanelTab {
+	t := &TabPanelTab{
+		owner:   owner,
+		content: content,
+		title:   NewLabel(),
+	}
+	t.Self = t
+	t.DrawCallback = t.draw
+	t.SetBorder(owner.TabBorder)
+	t.SetLayout(&FlexLayout{
+		Columns:  1,
+		HSpacing: owner.Gap,
+	})
+	t.title.LabelTheme = owner.LabelTheme
+	t.title.SetTitle(title)
+	t.title.Drawable = icon
+	t.title.SetLayoutData(&FlexLayoutData{HGrab: true, VAlign: align.Middle})
+	t.AddChild(t.title)
+	if _, ok := content.(TabCloser); ok {
+		t.SetClosable(true)
+	}
+	t.MouseDownCallback = t.mouseDown
+	t.MouseDragCallback = t.mouseDrag
+	t.MouseUpCallback = t.mouseUp
+	t.UpdateCursorCallback = t.updateCursor
+	return t
+}
+
+// Content returns the content shown when this tab is current.
+func (t *TabPanelTab) Content() Paneler {
+	return t.content
+}
+
+// Title returns the title of this tab.
+func (t *TabPanelTab) Title() string {
+	return t.title.String()
+}
+
+// SetTitle sets the title of this tab.
+func (t *TabPanelTab) SetTitle(title string) {
+	if title != t.title.String() {
+		t.title.SetTitle(title)
+		t.markForLayout()
+	}
+}
+
+// Icon returns the icon of this tab. May be nil.
+func (t *TabPanelTab) Icon() Drawable {
+	return t.title.Drawable
+}
+
+// SetIcon sets the icon of this tab. May be nil.
+func (t *TabPanelTab) SetIcon(icon Drawable) {
+	if icon != t.title.Drawable {
+		t.title.Drawable = icon
+		t.markForLayout()
+	}
+}
+
+// Closable returns true if this tab shows a close button.
+func (t *TabPanelTab) Closable() bool {
+	return t.button != nil
+}
+
+// SetClosable sets whether this tab shows a close button. Tabs whose content implements the TabCloser interface are
+// closable by default.
+func (t *TabPanelTab) SetClosable(closable bool) {
+	if closable == t.Closable() {
+		return
+	}
+	flex, ok := t.Layout().(*FlexLayout)
+	if !ok {
+		return
+	}
+	if closable {
+		t.button = NewButton()
+		t.button.ButtonTheme = t.owner.ButtonTheme
+		t.button.SetFocusable(false)
+		fSize := t.owner.LabelTheme.Font.Baseline()
+		t.button.Drawable = &DrawableSVG{
+			SVG:  CircledXSVG,
+			Size: geom.NewSize(fSize, fSize),
+		}
+		t.button.SetLayoutData(&FlexLayoutData{HAlign: align.End, VAlign: align.Middle})
+		t.button.ClickCallback = func() { t.owner.AttemptClose(t.owner.TabIndex(t)) }
+		t.AddChild(t.button)
+		flex.Columns++
+	} else {
+		t.RemoveChild(t.button)
+		t.button = nil
+		flex.Columns--
+	}
+	t.markForLayout()
+}
+
+func (t *TabPanelTab) markForLayout() {
+	t.NeedsLayout = true
+	t.title.NeedsLayout = true
+	if p := t.Parent(); p != nil {
+		p.MarkForLayoutAndRedraw()
+	}
+	t.MarkForRedraw()
+}
+
+func (t *TabPanelTab) draw(gc *Canvas, _ geom.Rect) {
+	var bg, fg Ink
+	switch {
+	case t.pressed:
+		bg = t.owner.TabFocusedInk
+		fg = t.owner.OnTabFocusedInk
+	case t.owner.CurrentTab() == t:
+		if t.owner.focusIsWithin() {
+			bg = t.owner.TabFocusedInk
+			fg = t.owner.OnTabFocusedInk
+		} else {
+			bg = t.owner.TabCurrentInk
+			fg = t.owner.OnTabCurrentInk
+		}
+	default:
+		bg = t.owner.BackgroundInk
+		fg = t.owner.OnBackgroundInk
+	}
+	if t.title.OnBackgroundInk != fg {
+		t.title.OnBackgroundInk = fg
+		t.title.SetTitle(t.title.String())
+	}
+	if t.button != nil {
+		t.button.OnBackgroundInk = fg
+	}
+	r := t.ContentRect(true)
+	p := tabShape(r, t.owner.placement)
+	gc.DrawPath(p, bg.Paint(gc, r, paintstyle.Fill))
+	gc.DrawPath(p, t.owner.EdgeInk.Paint(gc, r, paintstyle.Stroke))
+}
+
+// tabShape returns the outline of a tab occupying the rect, with its rounded corners on the side away from the content
+// and its open edge against the content.
+func tabShape(r geom.Rect, placement side.Enum) *Path {
+	length := r.Width
+	depth := r.Height
+	if placement == side.Left || placement == side.Right {
+		length, depth = depth, length
+	}
+	// pt maps a position along the tab and a distance from its outer edge into the rect
+	pt := func(along, in float32) geom.Point {
+		switch placement {
+		case side.Bottom:
+			return geom.NewPoint(r.X+along, r.Bottom()-in)
+		case side.Left:
+			return geom.NewPoint(r.X+in, r.Y+along)
+		case side.Right:
+			return geom.NewPoint(r.Right()-in, r.Y+along)
+		default:
+			return geom.NewPoint(r.X+along, r.Y+in)
+		}
+	}
+	p := NewPath()
+	p.MoveTo(pt(0, depth))
+	p.LineTo(pt(0, 6))
+	p.CubicTo(pt(0, 6), pt(0, 1), pt(6, 1))
+	cornerStart := length - 7
+	p.LineTo(pt(cornerStart, 1))
+	end := length - 1
+	p.CubicTo(pt(cornerStart, 1), pt(end, 1), pt(end, 7))
+	p.LineTo(pt(end, depth))
+	p.Close()
+	return p
+}
+
+func (t *TabPanelTab) updateCursor(_ geom.Point) *Cursor {
+	if t.dragging {
+		return ClosedHandCursor()
+	}
+	return nil
+}
+
+func (t *TabPanelTab) mouseDown(_ geom.Point, button, _ int, _ mod.Modifiers) bool {
+	if button != ButtonLeft {
+		return true
+	}
+	t.pressed = true
+	t.dragging = false
+	t.MarkForRedraw()
+	return true
+}
+
+func (t *TabPanelTab) mouseDrag(where geom.Point, button int, _ mod.Modifiers) bool {
+	if !t.pressed || button != ButtonLeft || !t.owner.reorderable {
+		return true
+	}
+	if !t.dragging && t.IsDragGesture(where) {
+		t.dragging = true
+		t.UpdateCursorNow()
+	}
+	if t.dragging {
+		t.owner.header.dragUpdated(t.PointTo(where, t.owner.header.AsPanel()))
+	}
+	return true
+}
+
+func (t *TabPanelTab) mouseUp(where geom.Point, button int, _ mod.Modifiers) bool {
+	if !t.pressed || button != ButtonLeft {
+		return true
+	}
+	t.pressed = false
+	if t.dragging {
+		t.dragging = false
+		t.owner.header.drop(t, t.PointTo(where, t.owner.header.AsPanel()))
+		t.UpdateCursorNow()
+	} else if where.In(t.ContentRect(true)) {
+		t.owner.SetCurrentIndex(t.owner.TabIndex(t))
+	}
+	t.MarkForRedraw()
+	return true
+}
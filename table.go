@@ -10,17 +10,23 @@
 package unison
 
 import (
+	"iter"
 	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/tid"
 	"github.com/richardwilkes/toolbox/v2/uti"
 	"github.com/richardwilkes/toolbox/v2/xmath"
 	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/aggregate"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/role"
 )
 
 // TableDragData holds the data from a table row drag.
@@ -48,16 +54,27 @@ func (c *ColumnInfo) resizable() bool {
 
 type tableCache[T TableRowConstraint[T]] struct {
 	row    T
+	group  *tableGroup[T] // Set for group rows, which have no row data
 	parent int
 	depth  int
 	height float32
 }
 
+func (c *tableCache[T]) id() tid.TID {
+	if c.group != nil {
+		return c.group.id
+	}
+	return c.row.ID()
+}
+
 type tableHitRect struct {
 	handler func()
 	geom.Rect
 }
 
+// virtualSizingRowLimit is the maximum number of rows of a VirtualTableModel that are examined when sizing columns.
+const virtualSizingRowLimit = 1000
+
 // dragTableData is actually a *TableDragData[T], but cannot be stored with the originating table since it must be
 // accessible from the drag data. All access occurs on the UI thread during a single drag & drop operation, so only
 // one drag can be in flight at a time and no synchronization is required.
@@ -77,10 +94,13 @@ var DefaultTableTheme = TableTheme{
 	OnInactiveSelectionInk: ThemeOnDeepFocus,
 	IndirectSelectionInk:   ThemeDeeperFocus,
 	OnIndirectSelectionInk: ThemeOnDeeperFocus,
+	ErrorInk:               ThemeError,
+	SearchMatchInk:         ThemeSearchMatch,
 	Padding:                geom.NewUniformInsets(4),
 	HierarchyIndent:        16,
 	MinimumRowHeight:       16,
 	ColumnResizeSlop:       4,
+	TypeToSelectTimeout:    DefaultTypeToSelectTimeout,
 	ShowColumnDivider:      true,
 	ShowFirstColumnDivider: true,
 	ShowLastColumnDivider:  true,
@@ -99,11 +119,14 @@ type TableTheme struct {
 	OnInactiveSelectionInk Ink
 	IndirectSelectionInk   Ink
 	OnIndirectSelectionInk Ink
+	ErrorInk               Ink
+	SearchMatchInk         Ink
 	Padding                geom.Insets
 	HierarchyColumnID      int
 	HierarchyIndent        float32
 	MinimumRowHeight       float32
 	ColumnResizeSlop       float32
+	TypeToSelectTimeout    time.Duration
 	ShowRowDivider         bool
 	ShowColumnDivider      bool
 	ShowFirstColumnDivider bool
@@ -114,6 +137,8 @@ type TableTheme struct {
 type Table[T TableRowConstraint[T]] struct {
 	SelectionChangedCallback func()
 	DoubleClickCallback      func()
+	CellEditedCallback       func(row T, col int)
+	FormatAggregateCallback  func(col int, kind aggregate.Enum, value float64) string
 	DragRemovedRowsCallback  func() // Called whenever a drag removes one or more rows from a model, but only if the source and destination tables were different.
 	DropOccurredCallback     func() // Called whenever a drop occurs that modifies the model.
 	Columns                  []ColumnInfo
@@ -125,21 +150,44 @@ type Table[T TableRowConstraint[T]] struct {
 	lastSel                  tid.TID
 	hitRects                 []tableHitRect
 	rowCache                 []tableCache[T]
+	virtual                  VirtualTableModel[T] // The Model, if it was a VirtualTableModel as of the last SyncToModel()
 	lastMouseEnterCellPanel  *Panel
 	lastMouseDownCellPanel   *Panel
+	placeholderCell          *Panel
+	total                    *tableGroup[T]
+	groups                   []*tableGroup[T]
+	aggregates               map[int]aggregate.Enum
+	groupIDs                 map[string]tid.TID
+	closedGroups             map[string]bool
+	cellEdit                 *tableCellEdit[T]
+	hiddenColumns            []tableHiddenColumn[T]
+	cellRanges               []TableCellRange
+	quickFilter              *tableQuickFilter
 	TableTheme
 	Panel
 	pressedHitRect           geom.Rect
+	typeToSelect             typeToSelect
 	interactionRow           int
 	interactionColumn        int
 	lastMouseMotionRow       int
 	lastMouseMotionColumn    int
 	startRow                 int
 	endBeforeRow             int
+	virtualRowCount          int
+	editColumn               int
+	groupColumn              int
+	FrozenColumns            int // The number of leading columns that remain in view when scrolling horizontally
+	FrozenRows               int // The number of leading rows that remain in view when scrolling vertically
 	columnResizeStart        float32
 	columnResizeBase         float32
 	columnResizeOverhead     float32
+	VirtualRowHeight         float32 // Height of each row of a VirtualTableModel; estimated from the first loaded if 0
+	virtualRowHeight         float32
+	footerHeight             float32
 	PreventUserColumnResize  bool
+	AllowUserColumnReorder   bool // Requires the row data to identify columns by their ColumnInfo.ID
+	AllowUserColumnHiding    bool // Requires the row data to identify columns by their ColumnInfo.ID
+	CellSelection            bool // Selects rectangular ranges of cells rather than rows
 	awaitingSizeColumnsToFit bool
 	awaitingSyncToModel      bool
 	selNeedsPrune            bool
@@ -147,6 +195,9 @@ type Table[T TableRowConstraint[T]] struct {
 	dividerDrag              bool
 	hasHierarchy             bool
 	noScrollOnFocus          bool
+	virtualHeightKnown       bool
+	cellSelectionDrag        bool
+	totalRowVisible          bool
 }
 
 // NewTable creates a new Table control.
@@ -159,6 +210,7 @@ func NewTable[T TableRowConstraint[T]](model TableModel[T]) *Table[T] {
 		interactionColumn:     -1,
 		lastMouseMotionRow:    -1,
 		lastMouseMotionColumn: -1,
+		groupColumn:           -1,
 	}
 	t.Self = t
 	t.SetFocusable(true)
@@ -174,7 +226,11 @@ func NewTable[T TableRowConstraint[T]](model TableModel[T]) *Table[T] {
 	t.MouseEnterCallback = t.DefaultMouseEnter
 	t.MouseExitCallback = t.DefaultMouseExit
 	t.KeyDownCallback = t.DefaultKeyDown
+	t.RuneTypedCallback = t.DefaultRuneTyped
+	t.FocusChangeInHierarchyCallback = t.DefaultFocusChangeInHierarchy
+	t.AccessibilityCallback = t.DefaultAccessibility
 	t.InstallCmdHandlers(SelectAllItemID, AlwaysEnabled, func(_ any) { t.SelectAll() })
+	t.installCellSelectionCmdHandlers()
 	t.wasDragged = false
 	return t
 }
@@ -205,10 +261,172 @@ func (t *Table[T]) ClearDrawRowRange() {
 
 // CurrentDrawRowRange returns the range of rows that are considered for sizing and drawing.
 func (t *Table[T]) CurrentDrawRowRange() (start, endBefore int) {
-	if t.startRow < t.endBeforeRow && t.startRow >= 0 && t.endBeforeRow <= len(t.rowCache) {
+	if t.startRow < t.endBeforeRow && t.startRow >= 0 && t.endBeforeRow <= t.rowCount() {
 		return t.startRow, t.endBeforeRow
 	}
-	return 0, len(t.rowCache)
+	return 0, t.rowCount()
+}
+
+// rowCount returns the number of rows being displayed.
+func (t *Table[T]) rowCount() int {
+	if t.virtual != nil {
+		return t.virtualRowCount
+	}
+	return len(t.rowCache)
+}
+
+// rowAt returns the row at the index, or false if it belongs to a VirtualTableModel and hasn't been loaded yet or is a
+// group row, which has no row data.
+func (t *Table[T]) rowAt(index int) (row T, ok bool) {
+	if t.virtual != nil {
+		return t.virtual.Row(index, t)
+	}
+	return t.rowCache[index].row, t.rowCache[index].group == nil
+}
+
+// rowID returns the ID of the row at the index, which is available even for rows that haven't been loaded yet.
+func (t *Table[T]) rowID(index int) tid.TID {
+	if t.virtual != nil {
+		return t.virtual.RowID(index)
+	}
+	return t.rowCache[index].id()
+}
+
+// rowIndexForID returns the index of the row with the ID, or -1 if it isn't currently displayed.
+func (t *Table[T]) rowIndexForID(id tid.TID) int {
+	if t.virtual != nil {
+		if index := t.virtual.RowIndex(id); index < t.virtualRowCount {
+			return index
+		}
+		return -1
+	}
+	for i := range t.rowCache {
+		if t.rowCache[i].id() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Table[T]) rowHeight(index int) float32 {
+	if t.virtual != nil {
+		return t.virtualRowHeight
+	}
+	return t.rowCache[index].height
+}
+
+func (t *Table[T]) rowDepth(index int) int {
+	if t.virtual != nil {
+		return 0
+	}
+	return t.rowCache[index].depth
+}
+
+// rowTop returns the offset of the top of the row from the top of the first row.
+func (t *Table[T]) rowTop(index int) float32 {
+	if t.virtual != nil {
+		return float32(index) * t.virtualRowStride()
+	}
+	var y float32
+	for i := range index {
+		y += t.rowCache[i].height
+		if t.ShowRowDivider {
+			y++
+		}
+	}
+	return y
+}
+
+// virtualRowStride returns the distance from the top of one row of a VirtualTableModel to the top of the next.
+func (t *Table[T]) virtualRowStride() float32 {
+	if t.ShowRowDivider {
+		return t.virtualRowHeight + 1
+	}
+	return t.virtualRowHeight
+}
+
+// visibleRowRange returns the range of rows within the draw row range that are visible within the table's ScrollPanel.
+// This is only narrowed for a VirtualTableModel, where it is also limited to at most virtualSizingRowLimit rows.
+func (t *Table[T]) visibleRowRange() (start, endBefore int) {
+	start, endBefore = t.CurrentDrawRowRange()
+	if t.virtual == nil || endBefore <= start {
+		return start, endBefore
+	}
+	visible := t.ContentRect(false)
+	if sp := t.ScrollRoot(); sp != nil {
+		view := sp.ContentView()
+		visible = view.RectTo(view.ContentRect(false), t.AsPanel())
+	}
+	var top float32
+	if border := t.Border(); border != nil {
+		top = border.Insets().Top
+	}
+	if stride := t.virtualRowStride(); stride > 0 {
+		start = max(start, int((visible.Y-top)/stride))
+		endBefore = min(endBefore, int(xmath.Ceil((visible.Bottom()-top)/stride)))
+	}
+	return start, max(min(endBefore, start+virtualSizingRowLimit), start)
+}
+
+// sizingRows returns an iterator over the rows used to determine column widths. For a VirtualTableModel, only the
+// visible rows that have been loaded are included.
+func (t *Table[T]) sizingRows() iter.Seq2[int, tableCache[T]] {
+	return func(yield func(int, tableCache[T]) bool) {
+		if t.virtual == nil {
+			for i, cache := range t.rowCache {
+				if !yield(i, cache) {
+					return
+				}
+			}
+			return
+		}
+		start, endBefore := t.visibleRowRange()
+		for i := start; i < endBefore; i++ {
+			if row, ok := t.rowAt(i); ok {
+				if !yield(i, tableCache[T]{row: row, parent: -1, height: t.virtualRowHeight}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// estimateVirtualRowHeight determines the height of the rows of a VirtualTableModel, using the VirtualRowHeight if it
+// has been set, or the height of the first visible row that has been loaded otherwise.
+func (t *Table[T]) estimateVirtualRowHeight() {
+	if t.VirtualRowHeight > 0 {
+		t.virtualRowHeight = t.VirtualRowHeight
+		t.virtualHeightKnown = true
+		return
+	}
+	t.virtualRowHeight = max(t.virtualRowHeight, t.MinimumRowHeight)
+	for i, cache := range t.sizingRows() {
+		t.virtualRowHeight = t.heightForColumns(cache.row, nil, i, 0)
+		t.virtualHeightKnown = true
+		return
+	}
+}
+
+// RowsLoaded implements TableRowLoadListener. It is called by a VirtualTableModel when rows the table asked for have
+// been loaded.
+func (t *Table[T]) RowsLoaded() {
+	if t.virtual == nil {
+		return
+	}
+	if !t.virtualHeightKnown {
+		if t.estimateVirtualRowHeight(); t.virtualHeightKnown {
+			t.adjustFrameToPrefSize()
+			t.MarkForLayoutRecursivelyUpward()
+		}
+	}
+	t.MarkForRedraw()
+}
+
+func (t *Table[T]) adjustFrameToPrefSize() {
+	_, pref, _ := t.DefaultSizes(geom.Size{})
+	rect := t.FrameRect()
+	rect.Size = pref
+	t.SetFrameRect(rect)
 }
 
 // CurrentHierarchyIndent returns the current hierarchy indent, which will be 0 if the table has no containers.
@@ -228,6 +446,21 @@ func (t *Table[T]) leadingColumnDividerWidth() float32 {
 
 // DefaultDraw provides the default drawing.
 func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
+	t.layoutCellEditor()
+	t.hitRects = nil
+	t.drawRegion(canvas, dirty, geom.Point{})
+	t.drawFrozenRegions(canvas, dirty)
+	t.drawFooter(canvas, dirty)
+	if t.CellSelection {
+		t.drawFocusedCell(canvas)
+	}
+	t.drawCellEditError(canvas)
+}
+
+// drawRegion draws the rows and columns that intersect the dirty rect. The offset is the translation that has been
+// applied to the canvas, which is non-zero when drawing the frozen columns or rows.
+func (t *Table[T]) drawRegion(canvas *Canvas, dirty geom.Rect, offset geom.Point) {
+	editRow, editCol := t.CellBeingEdited()
 	selectionInk := t.SelectionInk
 	if !t.Focused() {
 		selectionInk = t.InactiveSelectionInk
@@ -257,24 +490,32 @@ func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
 
 	startRow, endBeforeRow := t.CurrentDrawRowRange()
 	y := insets.Top
-	for i := startRow; i < endBeforeRow; i++ {
-		y1 := y + t.rowCache[i].height
-		if t.ShowRowDivider {
-			y1++
+	if t.virtual != nil {
+		if stride := t.virtualRowStride(); stride > 0 && dirty.Y > y {
+			skip := min(int((dirty.Y-y)/stride), endBeforeRow-startRow)
+			startRow += skip
+			y += float32(skip) * stride
 		}
-		if y1 >= dirty.Y {
-			break
+	} else {
+		for i := startRow; i < endBeforeRow; i++ {
+			y1 := y + t.rowCache[i].height
+			if t.ShowRowDivider {
+				y1++
+			}
+			if y1 >= dirty.Y {
+				break
+			}
+			y = y1
+			startRow = i + 1
 		}
-		y = y1
-		startRow = i + 1
 	}
 
 	lastY := dirty.Bottom()
 	rect := dirty
 	rect.Y = y
 	for r := startRow; r < endBeforeRow && rect.Y < lastY; r++ {
-		rect.Height = t.rowCache[r].height
-		if t.IsRowOrAnyParentSelected(r) {
+		rect.Height = t.rowHeight(r)
+		if !t.CellSelection && t.IsRowOrAnyParentSelected(r) {
 			var rowInk Ink
 			if t.IsRowSelected(r) {
 				rowInk = selectionInk
@@ -287,7 +528,7 @@ func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
 			paint := t.BandingInk.Paint(canvas, rect, paintstyle.Fill)
 			canvas.DrawRect(rect, paint)
 		}
-		rect.Y += t.rowCache[r].height
+		rect.Y += rect.Height
 		if t.ShowRowDivider && r != endBeforeRow-1 {
 			rect.Height = 1
 			paint := t.InteriorDividerInk.Paint(canvas, rect, paintstyle.Fill)
@@ -320,27 +561,40 @@ func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
 	rect = dirty
 	rect.Y = y
 	lastX := dirty.Right()
-	t.hitRects = nil
 	for r := startRow; r < endBeforeRow && rect.Y < lastY; r++ {
 		rect.X = x
-		rect.Height = t.rowCache[r].height
+		rect.Height = t.rowHeight(r)
+		row, loaded := t.rowAt(r)
+		group := t.groupAt(r)
 		for c := firstCol; c < len(t.Columns) && rect.X < lastX; c++ {
 			fg, bg, selected, indirectlySelected, focused := t.cellParams(r, c)
 			rect.Width = t.Columns[c].Current
+			if t.CellSelection && selected {
+				canvas.DrawRect(rect, bg.Paint(canvas, rect, paintstyle.Fill))
+			}
 			cellRect := rect.Inset(t.Padding)
-			row := t.rowCache[r].row
+			if (!loaded && group == nil) || (r == editRow && c == editCol) {
+				if !loaded {
+					t.drawPlaceholderCell(canvas, cellRect, fg)
+				}
+				rect.X += t.Columns[c].Current
+				if t.ShowColumnDivider && (t.ShowLastColumnDivider || c < len(t.Columns)-1) {
+					rect.X++
+				}
+				continue
+			}
 			if t.Columns[c].ID == t.HierarchyColumnID {
 				if hierarchyIndent := t.CurrentHierarchyIndent(); hierarchyIndent > 0 {
-					if row.CanHaveChildren() {
+					if group != nil || row.CanHaveChildren() {
 						const disclosureIndent = 2
 						disclosureSize := min(hierarchyIndent, t.MinimumRowHeight) - disclosureIndent*2
 						canvas.Save()
-						left := cellRect.X + hierarchyIndent*float32(t.rowCache[r].depth) + disclosureIndent
+						left := cellRect.X + hierarchyIndent*float32(t.rowDepth(r)) + disclosureIndent
 						top := cellRect.Y + (t.MinimumRowHeight-disclosureSize)/2
-						t.hitRects = append(t.hitRects,
-							t.newTableHitRect(geom.NewRect(left, top, disclosureSize, disclosureSize), row))
+						t.hitRects = append(t.hitRects, t.newTableHitRect(geom.NewRect(left+offset.X,
+							top+offset.Y, disclosureSize, disclosureSize), row, group))
 						canvas.Translate(geom.NewPoint(left, top))
-						if row.IsOpen() {
+						if (group != nil && t.isGroupOpen(group)) || (group == nil && row.IsOpen()) {
 							offset := disclosureSize / 2
 							offsetPt := geom.NewPoint(offset, offset)
 							canvas.Translate(offsetPt)
@@ -352,12 +606,13 @@ func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
 							geom.NewRect(0, 0, disclosureSize, disclosureSize), nil, chevronPaint)
 						canvas.Restore()
 					}
-					indent := hierarchyIndent*float32(t.rowCache[r].depth+1) + t.Padding.Left
+					indent := hierarchyIndent*float32(t.rowDepth(r)+1) + t.Padding.Left
 					cellRect.X += indent
 					cellRect.Width -= indent
 				}
 			}
-			cell := row.ColumnCell(r, c, fg, bg, selected, indirectlySelected, focused).AsPanel()
+			cell := t.columnCell(row, group, r, c, fg, bg, selected, indirectlySelected, focused)
+			restore := t.highlightQuickFilterMatches(cell, c)
 			t.installCell(cell, cellRect)
 			canvas.Save()
 			canvas.Translate(cellRect.Point)
@@ -366,22 +621,127 @@ func (t *Table[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
 			cell.Draw(canvas, cellRect)
 			t.uninstallCell(cell)
 			canvas.Restore()
+			if restore != nil {
+				restore()
+			}
 			rect.X += t.Columns[c].Current
 			if t.ShowColumnDivider && (t.ShowLastColumnDivider || c < len(t.Columns)-1) {
 				rect.X++
 			}
 		}
-		rect.Y += t.rowCache[r].height
+		rect.Y += rect.Height
 		if t.ShowRowDivider {
 			rect.Y++
 		}
 	}
 }
 
-func (t *Table[T]) cellParams(row, _ int) (fg, bg Ink, selected, indirectlySelected, focused bool) {
+// drawPlaceholderCell draws a placeholder for a cell of a row that is still being loaded.
+func (t *Table[T]) drawPlaceholderCell(canvas *Canvas, cellRect geom.Rect, fg Ink) {
+	const barHeight = 6
+	if cellRect.Width <= 0 || cellRect.Height <= 0 {
+		return
+	}
+	bar := geom.NewRect(cellRect.X, cellRect.Y+(min(cellRect.Height, t.MinimumRowHeight)-barHeight)/2,
+		xmath.Floor(cellRect.Width*2/3), min(barHeight, cellRect.Height))
+	ink := &ColorFilteredInk{
+		OriginalInk: fg,
+		ColorFilter: Alpha30Filter(),
+	}
+	canvas.DrawRoundedRect(bar, geom.NewUniformSize(bar.Height/2), ink.Paint(canvas, bar, paintstyle.Fill))
+}
+
+// DefaultAccessibility provides the default accessibility description. Each visible row is exposed as an item, with an
+// item for each of its cells, named by the text within the cell.
+func (t *Table[T]) DefaultAccessibility() Accessibility {
+	a := Accessibility{
+		Item:       t.accessibleRow,
+		ItemCount:  t.rowCount(),
+		ActiveItem: t.LastSelectedRowIndex(),
+		Role:       role.Table,
+		State:      AccessibleMultiSelectable,
+	}
+	if t.hasHierarchy {
+		a.Role = role.TreeTable
+	}
+	return a
+}
+
+func (t *Table[T]) accessibleRow(index int) Accessibility {
+	if index < 0 || index >= t.rowCount() {
+		return Accessibility{ActiveItem: -1}
+	}
+	row, ok := t.rowAt(index)
+	group := t.groupAt(index)
+	if !ok && group == nil {
+		return Accessibility{
+			Name:       i18n.Text("Loading…"),
+			Bounds:     t.RowFrame(index),
+			ActiveItem: -1,
+			Role:       role.TableRow,
+		}
+	}
+	id := t.rowID(index)
+	a := Accessibility{
+		Action: func() {
+			if i := t.rowIndexForID(id); i != -1 {
+				t.ClearSelection()
+				t.SelectByIndex(i)
+				t.ScrollRowIntoView(i)
+			}
+		},
+		Item: func(col int) Accessibility {
+			if index >= t.rowCount() || t.rowID(index) != id || col < 0 || col >= len(t.Columns) {
+				return Accessibility{ActiveItem: -1}
+			}
+			return Accessibility{
+				Name:       AccessibleText(t.cell(index, col)),
+				Bounds:     t.CellFrame(index, col),
+				ActiveItem: -1,
+				Role:       role.TableCell,
+			}
+		},
+		Bounds:     t.RowFrame(index),
+		ItemCount:  len(t.Columns),
+		ActiveItem: -1,
+		Role:       role.TableRow,
+		State:      AccessibleSelectable,
+	}
+	names := make([]string, 0, len(t.Columns))
+	for col := range t.Columns {
+		if name := AccessibleText(t.cell(index, col)); name != "" {
+			names = append(names, name)
+		}
+	}
+	a.Name = strings.Join(names, " ")
+	if t.selMap[id] {
+		a.State |= AccessibleSelected
+	}
+	switch {
+	case group != nil:
+		a.State |= AccessibleExpandable
+		if t.isGroupOpen(group) {
+			a.State |= AccessibleExpanded
+		}
+	case row.CanHaveChildren():
+		a.State |= AccessibleExpandable
+		if row.IsOpen() {
+			a.State |= AccessibleExpanded
+		}
+	}
+	return a
+}
+
+func (t *Table[T]) cellParams(row, col int) (fg, bg Ink, selected, indirectlySelected, focused bool) {
 	focused = t.Focused()
-	selected = t.IsRowSelected(row)
-	indirectlySelected = !selected && t.IsRowOrAnyParentSelected(row)
+	if t.CellSelection {
+		// The focused cell is outlined rather than filled
+		focusRow, focusCol := t.FocusedCell()
+		selected = t.IsCellSelected(row, col) && (row != focusRow || col != focusCol)
+	} else {
+		selected = t.IsRowSelected(row)
+		indirectlySelected = !selected && t.IsRowOrAnyParentSelected(row)
+	}
 	switch {
 	case selected && focused:
 		fg = t.OnSelectionInk
@@ -403,8 +763,25 @@ func (t *Table[T]) cellParams(row, _ int) (fg, bg Ink, selected, indirectlySelec
 }
 
 func (t *Table[T]) cell(row, col int) *Panel {
+	rowData, ok := t.rowAt(row)
+	group := t.groupAt(row)
+	if !ok && group == nil {
+		if t.placeholderCell == nil {
+			t.placeholderCell = NewPanel()
+		}
+		return t.placeholderCell
+	}
 	fg, bg, selected, indirectlySelected, focused := t.cellParams(row, col)
-	return t.rowCache[row].row.ColumnCell(row, col, fg, bg, selected, indirectlySelected, focused).AsPanel()
+	return t.columnCell(rowData, group, row, col, fg, bg, selected, indirectlySelected, focused)
+}
+
+// columnCell returns the cell for the column of the row data, or of the group row if group isn't nil.
+func (t *Table[T]) columnCell(rowData T, group *tableGroup[T], row, col int, fg, bg Ink, selected,
+	indirectlySelected, focused bool) *Panel {
+	if group != nil {
+		return t.groupCell(group, col, fg)
+	}
+	return rowData.ColumnCell(row, col, fg, bg, selected, indirectlySelected, focused).AsPanel()
 }
 
 func (t *Table[T]) installCell(cell *Panel, frame geom.Rect) {
@@ -419,19 +796,31 @@ func (t *Table[T]) uninstallCell(cell *Panel) {
 
 // RowHeights returns the heights of each row.
 func (t *Table[T]) RowHeights() []float32 {
-	heights := make([]float32, len(t.rowCache))
-	for i := range t.rowCache {
-		heights[i] = t.rowCache[i].height
+	heights := make([]float32, t.rowCount())
+	for i := range heights {
+		heights[i] = t.rowHeight(i)
 	}
 	return heights
 }
 
 // OverRow returns the row index that the y coordinate is over, or -1 if it isn't over any row.
 func (t *Table[T]) OverRow(y float32) int {
+	if t.overFooter(y) {
+		return -1
+	}
+	y = t.unfreezeY(y)
 	var insets geom.Insets
 	if border := t.Border(); border != nil {
 		insets = border.Insets()
 	}
+	if t.virtual != nil {
+		if stride := t.virtualRowStride(); stride > 0 && y >= insets.Top {
+			if i := int((y - insets.Top) / stride); i < t.virtualRowCount {
+				return i
+			}
+		}
+		return -1
+	}
 	end := insets.Top
 	for i := range t.rowCache {
 		start := end
@@ -448,6 +837,7 @@ func (t *Table[T]) OverRow(y float32) int {
 
 // OverColumn returns the column index that the x coordinate is over, or -1 if it isn't over any column.
 func (t *Table[T]) OverColumn(x float32) int {
+	x = t.unfreezeX(x)
 	var insets geom.Insets
 	if border := t.Border(); border != nil {
 		insets = border.Insets()
@@ -472,6 +862,9 @@ func (t *Table[T]) OverColumnDivider(x float32) int {
 	if len(t.Columns) == 0 {
 		return -1
 	}
+	// The slop is taken into account so that the divider along the right edge of the frozen columns can be found from
+	// either side of it
+	x = t.unfreezeX(x-t.ColumnResizeSlop) + t.ColumnResizeSlop
 	var insets geom.Insets
 	if border := t.Border(); border != nil {
 		insets = border.Insets()
@@ -491,13 +884,13 @@ func (t *Table[T]) OverColumnDivider(x float32) int {
 
 // CellWidth returns the current width of a given cell.
 func (t *Table[T]) CellWidth(row, col int) float32 {
-	if row < 0 || col < 0 || row >= len(t.rowCache) || col >= len(t.Columns) {
+	if row < 0 || col < 0 || row >= t.rowCount() || col >= len(t.Columns) {
 		return 0
 	}
 	width := t.Columns[col].Current - (t.Padding.Left + t.Padding.Right)
 	if t.Columns[col].ID == t.HierarchyColumnID {
 		if hierarchyIndent := t.CurrentHierarchyIndent(); hierarchyIndent > 0 {
-			width -= hierarchyIndent*float32(t.rowCache[row].depth+1) + t.Padding.Left
+			width -= hierarchyIndent*float32(t.rowDepth(row)+1) + t.Padding.Left
 		}
 	}
 	return width
@@ -519,6 +912,7 @@ func (t *Table[T]) ColumnEdges(col int) (left, right float32) {
 			left++
 		}
 	}
+	left += t.frozenShift(-1, col).X
 	right = left + t.Columns[col].Current
 	left += t.Padding.Left
 	right -= t.Padding.Right
@@ -535,7 +929,7 @@ func (t *Table[T]) ColumnEdges(col int) (left, right float32) {
 
 // CellFrame returns the frame of the given cell.
 func (t *Table[T]) CellFrame(row, col int) geom.Rect {
-	if row < 0 || col < 0 || row >= len(t.rowCache) || col >= len(t.Columns) {
+	if row < 0 || col < 0 || row >= t.rowCount() || col >= len(t.Columns) {
 		return geom.Rect{}
 	}
 	var insets geom.Insets
@@ -549,17 +943,12 @@ func (t *Table[T]) CellFrame(row, col int) geom.Rect {
 			x++
 		}
 	}
-	y := insets.Top
-	for r := range row {
-		y += t.rowCache[r].height
-		if t.ShowRowDivider {
-			y++
-		}
-	}
-	rect := geom.NewRect(x, y, t.Columns[col].Current, t.rowCache[row].height).Inset(t.Padding)
+	y := insets.Top + t.rowTop(row)
+	rect := geom.NewRect(x, y, t.Columns[col].Current, t.rowHeight(row)).Inset(t.Padding)
+	rect.Point = rect.Point.Add(t.frozenShift(row, col))
 	if t.Columns[col].ID == t.HierarchyColumnID {
 		if hierarchyIndent := t.CurrentHierarchyIndent(); hierarchyIndent > 0 {
-			indent := hierarchyIndent*float32(t.rowCache[row].depth+1) + t.Padding.Left
+			indent := hierarchyIndent*float32(t.rowDepth(row)+1) + t.Padding.Left
 			rect.X += indent
 			rect.Width -= indent
 			if rect.Width < 1 {
@@ -572,26 +961,27 @@ func (t *Table[T]) CellFrame(row, col int) geom.Rect {
 
 // RowFrame returns the frame of the row.
 func (t *Table[T]) RowFrame(row int) geom.Rect {
-	if row < 0 || row >= len(t.rowCache) {
+	if row < 0 || row >= t.rowCount() {
 		return geom.Rect{}
 	}
 	rect := t.ContentRect(false)
-	for i := range row {
-		rect.Y += t.rowCache[i].height
-		if t.ShowRowDivider {
-			rect.Y++
-		}
-	}
-	rect.Height = t.rowCache[row].height
+	rect.Y += t.rowTop(row) + t.frozenShift(row, -1).Y
+	rect.Height = t.rowHeight(row)
 	return rect
 }
 
-func (t *Table[T]) newTableHitRect(rect geom.Rect, row T) tableHitRect {
+func (t *Table[T]) newTableHitRect(rect geom.Rect, row T, group *tableGroup[T]) tableHitRect {
 	return tableHitRect{
 		Rect: rect,
 		handler: func() {
-			open := !row.IsOpen()
-			row.SetOpen(open)
+			var open bool
+			if group != nil {
+				open = !t.isGroupOpen(group)
+				t.setGroupOpen(group, open)
+			} else {
+				open = !row.IsOpen()
+				row.SetOpen(open)
+			}
 			t.SyncToModel()
 			if !open {
 				t.PruneSelectionOfUndisclosedNodes()
@@ -737,7 +1127,7 @@ func (t *Table[T]) DefaultMouseMove(where geom.Point, mods mod.Modifiers) bool {
 // DefaultMouseExit provides the default mouse exit handling.
 func (t *Table[T]) DefaultMouseExit() bool {
 	if t.lastMouseEnterCellPanel != nil && t.lastMouseEnterCellPanel.MouseExitCallback != nil &&
-		t.lastMouseMotionRow >= 0 && t.lastMouseMotionRow < len(t.rowCache) &&
+		t.lastMouseMotionRow >= 0 && t.lastMouseMotionRow < t.rowCount() &&
 		t.lastMouseMotionColumn >= 0 && t.lastMouseMotionColumn < len(t.Columns) {
 		cell := t.cell(t.lastMouseMotionRow, t.lastMouseMotionColumn)
 		rect := t.CellFrame(t.lastMouseMotionRow, t.lastMouseMotionColumn)
@@ -753,11 +1143,15 @@ func (t *Table[T]) DefaultMouseExit() bool {
 
 // DefaultMouseDown provides the default mouse down handling.
 func (t *Table[T]) DefaultMouseDown(where geom.Point, button, clickCount int, mods mod.Modifiers) bool {
+	if !t.CommitCellEdit() {
+		return true
+	}
 	t.RequestFocusWithoutScroll()
 	t.wasDragged = false
 	t.dividerDrag = false
 	t.lastSel = ""
 	t.pressedHitRect = geom.Rect{}
+	t.cellSelectionDrag = false
 
 	t.interactionRow = -1
 	t.interactionColumn = -1
@@ -819,23 +1213,23 @@ func (t *Table[T]) DefaultMouseDown(where geom.Point, button, clickCount int, mo
 				}
 			}
 		}
-		rowData := t.rowCache[row].row
-		id := rowData.ID()
+		col := t.OverColumn(where.X)
+		if col != -1 {
+			t.editColumn = col
+		}
+		id := t.rowID(row)
 		switch {
+		case t.CellSelection:
+			t.cellSelectionMouseDown(row, col, button, mods)
 		case mods&mod.Shift != 0: // Extend selection from anchor
 			selAnchorIndex := -1
 			if t.selAnchor != "" {
-				for i, c := range t.rowCache {
-					if c.row.ID() == t.selAnchor {
-						selAnchorIndex = i
-						break
-					}
-				}
+				selAnchorIndex = t.rowIndexForID(t.selAnchor)
 			}
 			if selAnchorIndex != -1 {
 				last := max(selAnchorIndex, row)
 				for i := min(selAnchorIndex, row); i <= last; i++ {
-					t.selMap[t.rowCache[i].row.ID()] = true
+					t.selMap[t.rowID(i)] = true
 				}
 				t.notifyOfSelectionChange()
 			} else if !t.selMap[id] { // No anchor, so behave like a regular click
@@ -860,6 +1254,9 @@ func (t *Table[T]) DefaultMouseDown(where geom.Point, button, clickCount int, mo
 			t.notifyOfSelectionChange()
 		}
 		t.MarkForRedraw()
+		if button == ButtonLeft && clickCount == 2 && mods&mod.NonSticky == 0 && t.EditCell(row, col) {
+			return true
+		}
 		if button == ButtonLeft && clickCount == 2 && t.DoubleClickCallback != nil && len(t.selMap) != 0 {
 			SafeCall(t.DoubleClickCallback)
 		}
@@ -874,6 +1271,10 @@ func (t *Table[T]) notifyOfSelectionChange() {
 // DefaultMouseDrag provides the default mouse drag handling.
 func (t *Table[T]) DefaultMouseDrag(where geom.Point, button int, mods mod.Modifiers) bool {
 	t.wasDragged = true
+	if t.cellSelectionDrag {
+		t.cellSelectionMouseDrag(where)
+		return true
+	}
 	stop := false
 	if t.interactionColumn != -1 {
 		if t.interactionRow == -1 {
@@ -900,7 +1301,7 @@ func (t *Table[T]) DefaultMouseDrag(where geom.Point, button int, mods mod.Modif
 				stop = true
 			}
 		} else if t.lastMouseDownCellPanel != nil && t.lastMouseDownCellPanel.MouseDragCallback != nil &&
-			t.interactionRow < len(t.rowCache) && t.interactionColumn < len(t.Columns) {
+			t.interactionRow < t.rowCount() && t.interactionColumn < len(t.Columns) {
 			cell := t.cell(t.interactionRow, t.interactionColumn)
 			rect := t.CellFrame(t.interactionRow, t.interactionColumn)
 			t.installCell(cell, rect)
@@ -939,7 +1340,7 @@ func (t *Table[T]) DefaultMouseUp(where geom.Point, button int, mods mod.Modifie
 		t.notifyOfSelectionChange()
 	}
 
-	if !stop && t.interactionRow != -1 && t.interactionColumn != -1 && t.interactionRow < len(t.rowCache) &&
+	if !stop && t.interactionRow != -1 && t.interactionColumn != -1 && t.interactionRow < t.rowCount() &&
 		t.interactionColumn < len(t.Columns) && t.lastMouseDownCellPanel != nil &&
 		t.lastMouseDownCellPanel.MouseUpCallback != nil {
 		cell := t.cell(t.interactionRow, t.interactionColumn)
@@ -954,21 +1355,28 @@ func (t *Table[T]) DefaultMouseUp(where geom.Point, button int, mods mod.Modifie
 	t.lastMouseDownCellPanel = nil
 	t.interactionRow = -1
 	t.interactionColumn = -1
+	t.cellSelectionDrag = false
 	return stop
 }
 
 // DefaultKeyDown provides the default key down handling.
 func (t *Table[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if t.cellEdit != nil {
+		return t.handleCellEditKeyDown(keyCode, mods)
+	}
 	if IsControlAction(keyCode, mods) {
 		if t.DoubleClickCallback != nil && len(t.selMap) != 0 {
 			SafeCall(t.DoubleClickCallback)
 		}
 		return true
 	}
+	if t.CellSelection && t.handleCellSelectionKeyDown(keyCode, mods) {
+		return true
+	}
 	switch keyCode {
 	case KeyLeft:
 		if !repeat && t.HasSelection() {
-			altered := false
+			altered := t.setSelectedGroupsOpen(false)
 			for _, row := range t.SelectedRows(false) {
 				if mods.OptionDown() {
 					if setOpenRecursively(row, false) {
@@ -986,7 +1394,7 @@ func (t *Table[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bo
 		}
 	case KeyRight:
 		if !repeat && t.HasSelection() {
-			altered := false
+			altered := t.setSelectedGroupsOpen(true)
 			for _, row := range t.SelectedRows(false) {
 				if mods.OptionDown() {
 					if setOpenRecursively(row, true) {
@@ -1006,7 +1414,7 @@ func (t *Table[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bo
 		if t.HasSelection() {
 			i = max(t.FirstSelectedRowIndex()-1, 0)
 		} else {
-			i = len(t.rowCache) - 1
+			i = t.rowCount() - 1
 		}
 		if !mods.ShiftDown() {
 			t.ClearSelection()
@@ -1014,7 +1422,7 @@ func (t *Table[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bo
 		t.SelectByIndex(i)
 		t.ScrollRowCellIntoView(i, 0)
 	case KeyDown:
-		i := min(t.LastSelectedRowIndex()+1, len(t.rowCache)-1)
+		i := min(t.LastSelectedRowIndex()+1, t.rowCount()-1)
 		if !mods.ShiftDown() {
 			t.ClearSelection()
 		}
@@ -1030,12 +1438,14 @@ func (t *Table[T]) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bo
 		t.ScrollRowCellIntoView(0, 0)
 	case KeyEnd:
 		if mods.ShiftDown() && t.HasSelection() {
-			t.SelectRange(t.LastSelectedRowIndex(), len(t.rowCache)-1)
+			t.SelectRange(t.LastSelectedRowIndex(), t.rowCount()-1)
 		} else {
 			t.ClearSelection()
-			t.SelectByIndex(len(t.rowCache) - 1)
+			t.SelectByIndex(t.rowCount() - 1)
 		}
-		t.ScrollRowCellIntoView(len(t.rowCache)-1, 0)
+		t.ScrollRowCellIntoView(t.rowCount()-1, 0)
+	case KeyF2:
+		return t.editSelectedRow(false) != nil
 	default:
 		return false
 	}
@@ -1070,10 +1480,18 @@ func (t *Table[T]) PruneSelectionOfUndisclosedNodes() {
 	}
 	oldLen := len(t.selMap)
 	selMap := make(map[tid.TID]bool, oldLen)
-	for _, entry := range t.rowCache {
-		id := entry.row.ID()
-		if t.selMap[id] {
-			selMap[id] = true
+	if t.virtual != nil {
+		for id := range t.selMap {
+			if t.rowIndexForID(id) != -1 {
+				selMap[id] = true
+			}
+		}
+	} else {
+		for i := range t.rowCache {
+			id := t.rowCache[i].id()
+			if t.selMap[id] {
+				selMap[id] = true
+			}
 		}
 	}
 	t.selMap = selMap
@@ -1087,8 +1505,17 @@ func (t *Table[T]) FirstSelectedRowIndex() int {
 	if len(t.selMap) == 0 {
 		return -1
 	}
-	for i, entry := range t.rowCache {
-		if t.selMap[entry.row.ID()] {
+	if t.virtual != nil {
+		first := -1
+		for id := range t.selMap {
+			if i := t.rowIndexForID(id); i != -1 && (first == -1 || i < first) {
+				first = i
+			}
+		}
+		return first
+	}
+	for i := range t.rowCache {
+		if t.selMap[t.rowCache[i].id()] {
 			return i
 		}
 	}
@@ -1100,46 +1527,81 @@ func (t *Table[T]) LastSelectedRowIndex() int {
 	if len(t.selMap) == 0 {
 		return -1
 	}
+	if t.virtual != nil {
+		last := -1
+		for id := range t.selMap {
+			last = max(last, t.rowIndexForID(id))
+		}
+		return last
+	}
 	for i := len(t.rowCache) - 1; i >= 0; i-- {
-		if t.selMap[t.rowCache[i].row.ID()] {
+		if t.selMap[t.rowCache[i].id()] {
 			return i
 		}
 	}
 	return -1
 }
 
-// IsRowOrAnyParentSelected returns true if the specified row index or any of its parents are selected.
+// IsRowOrAnyParentSelected returns true if the specified row index or any of its parents are selected. The rows of a
+// group row are not considered to be selected along with it.
 func (t *Table[T]) IsRowOrAnyParentSelected(index int) bool {
+	if t.virtual != nil {
+		return t.IsRowSelected(index)
+	}
 	if index < 0 || index >= len(t.rowCache) {
 		return false
 	}
 	for index >= 0 {
-		if t.selMap[t.rowCache[index].row.ID()] {
+		if t.selMap[t.rowCache[index].id()] {
 			return true
 		}
-		index = t.rowCache[index].parent
+		if index = t.rowCache[index].parent; index >= 0 && t.rowCache[index].group != nil {
+			break
+		}
 	}
 	return false
 }
 
 // IsRowSelected returns true if the specified row index is selected.
 func (t *Table[T]) IsRowSelected(index int) bool {
-	if index < 0 || index >= len(t.rowCache) {
+	if index < 0 || index >= t.rowCount() {
 		return false
 	}
-	return t.selMap[t.rowCache[index].row.ID()]
+	return t.selMap[t.rowID(index)]
 }
 
 // SelectedRows returns the currently selected rows. If 'minimal' is true, then children of selected rows that may also
-// be selected are not returned, just the topmost row that is selected in any given hierarchy.
+// be selected are not returned, just the topmost row that is selected in any given hierarchy. For a VirtualTableModel,
+// only the selected rows that are currently loaded are returned; use CopySelectionMap() to obtain the IDs of all of
+// them. Selected group rows are not returned, nor are they considered to be the parents of their rows.
 func (t *Table[T]) SelectedRows(minimal bool) []T {
 	t.PruneSelectionOfUndisclosedNodes()
 	if len(t.selMap) == 0 {
 		return nil
 	}
+	if t.virtual != nil {
+		indexes := make([]int, 0, len(t.selMap))
+		for id := range t.selMap {
+			if i := t.rowIndexForID(id); i != -1 {
+				indexes = append(indexes, i)
+			}
+		}
+		slices.Sort(indexes)
+		rows := make([]T, 0, len(indexes))
+		for _, i := range indexes {
+			if row, ok := t.rowAt(i); ok {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	}
 	rows := make([]T, 0, len(t.selMap))
 	for _, entry := range t.rowCache {
-		if t.selMap[entry.row.ID()] && (!minimal || entry.parent == -1 || !t.IsRowOrAnyParentSelected(entry.parent)) {
+		if entry.group != nil || !t.selMap[entry.row.ID()] {
+			continue
+		}
+		if !minimal || entry.parent == -1 || t.rowCache[entry.parent].group != nil ||
+			!t.IsRowOrAnyParentSelected(entry.parent) {
 			rows = append(rows, entry.row)
 		}
 	}
@@ -1180,9 +1642,10 @@ func (t *Table[T]) SelectionCount() int {
 
 // ClearSelection clears the selection.
 func (t *Table[T]) ClearSelection() {
-	if len(t.selMap) == 0 {
+	if len(t.selMap) == 0 && len(t.cellRanges) == 0 {
 		return
 	}
+	t.cellRanges = nil
 	t.selMap = make(map[tid.TID]bool)
 	t.selNeedsPrune = false
 	t.selAnchor = ""
@@ -1190,13 +1653,18 @@ func (t *Table[T]) ClearSelection() {
 	t.notifyOfSelectionChange()
 }
 
-// SelectAll selects all rows.
+// SelectAll selects all rows, or all cells if CellSelection is enabled.
 func (t *Table[T]) SelectAll() {
-	t.selMap = make(map[tid.TID]bool, len(t.rowCache))
+	count := t.rowCount()
+	if t.CellSelection {
+		t.SetSelectedCellRanges(TableCellRange{EndRow: count - 1, EndColumn: len(t.Columns) - 1})
+		return
+	}
+	t.selMap = make(map[tid.TID]bool, count)
 	t.selNeedsPrune = false
 	t.selAnchor = ""
-	for _, cache := range t.rowCache {
-		id := cache.row.ID()
+	for i := range count {
+		id := t.rowID(i)
 		t.selMap[id] = true
 		if t.selAnchor == "" {
 			t.selAnchor = id
@@ -1210,8 +1678,8 @@ func (t *Table[T]) SelectAll() {
 // selection exists.
 func (t *Table[T]) SelectByIndex(indexes ...int) {
 	for _, index := range indexes {
-		if index >= 0 && index < len(t.rowCache) {
-			id := t.rowCache[index].row.ID()
+		if index >= 0 && index < t.rowCount() {
+			id := t.rowID(index)
 			t.selMap[id] = true
 			t.selNeedsPrune = true
 			if t.selAnchor == "" {
@@ -1227,12 +1695,12 @@ func (t *Table[T]) SelectByIndex(indexes ...int) {
 // selection exists.
 func (t *Table[T]) SelectRange(start, end int) {
 	start = max(start, 0)
-	end = min(end, len(t.rowCache)-1)
+	end = min(end, t.rowCount()-1)
 	if start > end {
 		return
 	}
 	for i := start; i <= end; i++ {
-		id := t.rowCache[i].row.ID()
+		id := t.rowID(i)
 		t.selMap[id] = true
 		t.selNeedsPrune = true
 		if t.selAnchor == "" {
@@ -1246,8 +1714,8 @@ func (t *Table[T]) SelectRange(start, end int) {
 // DeselectByIndex deselects the given indexes.
 func (t *Table[T]) DeselectByIndex(indexes ...int) {
 	for _, index := range indexes {
-		if index >= 0 && index < len(t.rowCache) {
-			delete(t.selMap, t.rowCache[index].row.ID())
+		if index >= 0 && index < t.rowCount() {
+			delete(t.selMap, t.rowID(index))
 		}
 	}
 	t.MarkForRedraw()
@@ -1257,21 +1725,22 @@ func (t *Table[T]) DeselectByIndex(indexes ...int) {
 // DeselectRange deselects the given range.
 func (t *Table[T]) DeselectRange(start, end int) {
 	start = max(start, 0)
-	end = min(end, len(t.rowCache)-1)
+	end = min(end, t.rowCount()-1)
 	if start > end {
 		return
 	}
 	for i := start; i <= end; i++ {
-		delete(t.selMap, t.rowCache[i].row.ID())
+		delete(t.selMap, t.rowID(i))
 	}
 	t.MarkForRedraw()
 	t.notifyOfSelectionChange()
 }
 
-// DiscloseRow ensures the given row can be viewed by opening all parents that lead to it. Returns true if any
-// modification was made.
+// DiscloseRow ensures the given row can be viewed by opening all parents that lead to it, along with the group row
+// holding it. Returns true if any modification was made.
 func (t *Table[T]) DiscloseRow(row T, delaySync bool) bool {
 	modified := false
+	root := row
 	p := row.Parent()
 	var zero T
 	for p != zero {
@@ -1279,8 +1748,15 @@ func (t *Table[T]) DiscloseRow(row T, delaySync bool) bool {
 			p.SetOpen(true)
 			modified = true
 		}
+		root = p
 		p = p.Parent()
 	}
+	if t.groupColumn >= 0 {
+		if key := root.CellDataForSort(t.groupColumn); t.closedGroups[key] {
+			delete(t.closedGroups, key)
+			modified = true
+		}
+	}
 	if modified {
 		if delaySync {
 			t.EventuallySyncToModel()
@@ -1314,43 +1790,88 @@ func (t *Table[T]) SetRootRows(rows []T) {
 	t.selMap = make(map[tid.TID]bool)
 	t.selNeedsPrune = false
 	t.selAnchor = ""
+	t.cellRanges = nil
 	t.SyncToModel()
 }
 
 // SyncToModel causes the table to update its internal caches to reflect the current model.
 func (t *Table[T]) SyncToModel() {
-	rowCount := 0
+	if vm, ok := t.Model.(VirtualTableModel[T]); ok {
+		t.syncToVirtualModel(vm)
+		return
+	}
+	t.virtual = nil
 	roots := t.RootRows()
-	t.hasHierarchy = false
-	if t.filteredRows != nil {
-		rowCount = len(t.filteredRows)
-		for _, row := range t.filteredRows {
-			if !t.hasHierarchy && row.CanHaveChildren() {
-				t.hasHierarchy = true
+	t.syncGroups(roots)
+	t.hasHierarchy = len(t.groups) != 0
+	rowCount := 0
+	if len(t.groups) != 0 {
+		for _, group := range t.groups {
+			rowCount++
+			if t.isGroupOpen(group) {
+				rowCount += t.countRows(group.rows)
 			}
 		}
 	} else {
-		for _, row := range roots {
-			if !t.hasHierarchy && row.CanHaveChildren() {
-				t.hasHierarchy = true
-			}
-			rowCount += t.countOpenRowChildrenRecursively(row)
-		}
+		rowCount = t.countRows(roots)
 	}
 	t.rowCache = make([]tableCache[T], rowCount)
 	j := 0
-	for _, row := range roots {
-		j = t.buildRowCacheEntry(row, -1, j, 0)
+	if len(t.groups) != 0 {
+		for _, group := range t.groups {
+			j = t.buildGroupCacheEntry(group, j)
+		}
+	} else {
+		for _, row := range roots {
+			j = t.buildRowCacheEntry(row, -1, j, 0)
+		}
 	}
+	t.syncFooterHeight()
 	t.selNeedsPrune = true
-	_, pref, _ := t.DefaultSizes(geom.Size{})
-	rect := t.FrameRect()
-	rect.Size = pref
-	t.SetFrameRect(rect)
+	t.adjustFrameToPrefSize()
+	t.layoutCellEditor()
+	t.MarkForRedraw()
+	t.MarkForLayoutRecursivelyUpward()
+}
+
+// syncToVirtualModel updates the table to reflect a VirtualTableModel. Only the number of rows is retrieved; the rows
+// themselves are requested as they are needed.
+func (t *Table[T]) syncToVirtualModel(vm VirtualTableModel[T]) {
+	t.virtual = vm
+	t.rowCache = nil
+	t.filteredRows = nil
+	t.total = nil
+	t.groups = nil
+	t.hasHierarchy = false
+	t.virtualRowCount = vm.RootRowCount()
+	if !t.virtualHeightKnown {
+		t.estimateVirtualRowHeight()
+	}
+	t.syncFooterHeight()
+	t.selNeedsPrune = true
+	t.adjustFrameToPrefSize()
+	t.layoutCellEditor()
 	t.MarkForRedraw()
 	t.MarkForLayoutRecursivelyUpward()
 }
 
+// countRows returns the number of rows that will be displayed for the top-level rows, noting whether any of them can
+// have children.
+func (t *Table[T]) countRows(rows []T) int {
+	count := 0
+	for _, row := range rows {
+		if !t.hasHierarchy && row.CanHaveChildren() {
+			t.hasHierarchy = true
+		}
+		if t.filteredRows != nil {
+			count++
+		} else {
+			count += t.countOpenRowChildrenRecursively(row)
+		}
+	}
+	return count
+}
+
 func (t *Table[T]) countOpenRowChildrenRecursively(row T) int {
 	count := 1
 	if row.CanHaveChildren() && row.IsOpen() {
@@ -1365,7 +1886,7 @@ func (t *Table[T]) buildRowCacheEntry(row T, parentIndex, index, depth int) int
 	t.rowCache[index].row = row
 	t.rowCache[index].parent = parentIndex
 	t.rowCache[index].depth = depth
-	t.rowCache[index].height = t.heightForColumns(row, index, depth)
+	t.rowCache[index].height = t.heightForColumns(row, nil, index, depth)
 	parentIndex = index
 	index++
 	if t.filteredRows == nil && row.CanHaveChildren() && row.IsOpen() {
@@ -1376,7 +1897,22 @@ func (t *Table[T]) buildRowCacheEntry(row T, parentIndex, index, depth int) int
 	return index
 }
 
-func (t *Table[T]) heightForColumns(rowData T, row, depth int) float32 {
+func (t *Table[T]) buildGroupCacheEntry(group *tableGroup[T], index int) int {
+	var zero T
+	t.rowCache[index].group = group
+	t.rowCache[index].parent = -1
+	t.rowCache[index].height = t.heightForColumns(zero, group, index, 0)
+	parentIndex := index
+	index++
+	if t.isGroupOpen(group) {
+		for _, row := range group.rows {
+			index = t.buildRowCacheEntry(row, parentIndex, index, 1)
+		}
+	}
+	return index
+}
+
+func (t *Table[T]) heightForColumns(rowData T, group *tableGroup[T], row, depth int) float32 {
 	var height float32
 	for col := range t.Columns {
 		w := t.Columns[col].Current
@@ -1389,7 +1925,7 @@ func (t *Table[T]) heightForColumns(rowData T, row, depth int) float32 {
 				w -= t.Padding.Left + hierarchyIndent*float32(depth+1)
 			}
 		}
-		size := t.cellPrefSize(rowData, row, col, w)
+		size := t.cellPrefSize(rowData, group, row, col, w)
 		size.Height += t.Padding.Top + t.Padding.Bottom
 		if height < size.Height {
 			height = size.Height
@@ -1398,9 +1934,9 @@ func (t *Table[T]) heightForColumns(rowData T, row, depth int) float32 {
 	return max(xmath.Ceil(height), t.MinimumRowHeight)
 }
 
-func (t *Table[T]) cellPrefSize(rowData T, row, col int, widthConstraint float32) geom.Size {
+func (t *Table[T]) cellPrefSize(rowData T, group *tableGroup[T], row, col int, widthConstraint float32) geom.Size {
 	fg, bg, selected, indirectlySelected, focused := t.cellParams(row, col)
-	cell := rowData.ColumnCell(row, col, fg, bg, selected, indirectlySelected, focused).AsPanel()
+	cell := t.columnCell(rowData, group, row, col, fg, bg, selected, indirectlySelected, focused)
 	_, size, _ := cell.Sizes(geom.NewSize(widthConstraint, 0))
 	return size
 }
@@ -1415,12 +1951,12 @@ func (t *Table[T]) SizeColumnsToFitWithExcessIn(columnID int) {
 		current[col] = max(t.Columns[col].Minimum, 0)
 		t.Columns[col].Current = 0
 	}
-	for row, cache := range t.rowCache {
+	for row, cache := range t.sizingRows() {
 		for col := range t.Columns {
 			if col == excessColumnIndex {
 				continue
 			}
-			pref := t.cellPrefSize(cache.row, row, col, 0)
+			pref := t.cellPrefSize(cache.row, cache.group, row, col, 0)
 			minimum := t.Columns[col].AutoMinimum
 			if minimum > 0 && pref.Width < minimum {
 				pref.Width = minimum
@@ -1467,9 +2003,9 @@ func (t *Table[T]) SizeColumnsToFit(adjust bool) {
 		current[col] = max(t.Columns[col].Minimum, 0)
 		t.Columns[col].Current = 0
 	}
-	for row, cache := range t.rowCache {
+	for row, cache := range t.sizingRows() {
 		for col := range t.Columns {
-			pref := t.cellPrefSize(cache.row, row, col, 0)
+			pref := t.cellPrefSize(cache.row, cache.group, row, col, 0)
 			minimum := t.Columns[col].AutoMinimum
 			if minimum > 0 && pref.Width < minimum {
 				pref.Width = minimum
@@ -1495,10 +2031,7 @@ func (t *Table[T]) SizeColumnsToFit(adjust bool) {
 	}
 	t.SyncRowHeights()
 	if adjust {
-		_, pref, _ := t.DefaultSizes(geom.Size{})
-		rect := t.FrameRect()
-		rect.Size = pref
-		t.SetFrameRect(rect)
+		t.adjustFrameToPrefSize()
 	}
 }
 
@@ -1510,8 +2043,8 @@ func (t *Table[T]) SizeColumnToFit(col int, adjust bool) {
 	}
 	current := max(t.Columns[col].Minimum, 0)
 	t.Columns[col].Current = 0
-	for row, cache := range t.rowCache {
-		pref := t.cellPrefSize(cache.row, row, col, 0)
+	for row, cache := range t.sizingRows() {
+		pref := t.cellPrefSize(cache.row, cache.group, row, col, 0)
 		minimum := t.Columns[col].AutoMinimum
 		if minimum > 0 && pref.Width < minimum {
 			pref.Width = minimum
@@ -1534,19 +2067,21 @@ func (t *Table[T]) SizeColumnToFit(col int, adjust bool) {
 	t.Columns[col].Current = current
 	t.SyncRowHeights()
 	if adjust {
-		_, pref, _ := t.DefaultSizes(geom.Size{})
-		rect := t.FrameRect()
-		rect.Size = pref
-		t.SetFrameRect(rect)
+		t.adjustFrameToPrefSize()
 	}
 }
 
 // SyncRowHeights recalculates the height of each row based on the current column widths. Call this after adjusting
 // column widths directly (i.e. outside of the SizeColumns* methods) so that rows whose content wraps to a height that
-// depends on the available width are given the correct amount of vertical space.
+// depends on the available width are given the correct amount of vertical space. For a VirtualTableModel, the single
+// height used for all rows is determined again.
 func (t *Table[T]) SyncRowHeights() {
+	if t.virtual != nil {
+		t.estimateVirtualRowHeight()
+		return
+	}
 	for row, cache := range t.rowCache {
-		t.rowCache[row].height = t.heightForColumns(cache.row, row, cache.depth)
+		t.rowCache[row].height = t.heightForColumns(cache.row, cache.group, row, cache.depth)
 	}
 }
 
@@ -1581,8 +2116,12 @@ func (t *Table[T]) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Si
 		prefSize.Width += t.Columns[col].Current
 	}
 	startRow, endBeforeRow := t.CurrentDrawRowRange()
-	for _, cache := range t.rowCache[startRow:endBeforeRow] {
-		prefSize.Height += cache.height
+	if t.virtual != nil {
+		prefSize.Height = float32(endBeforeRow-startRow) * t.virtualRowHeight
+	} else {
+		for _, cache := range t.rowCache[startRow:endBeforeRow] {
+			prefSize.Height += cache.height
+		}
 	}
 	if t.ShowColumnDivider {
 		prefSize.Width += float32(len(t.Columns)) + t.leadingColumnDividerWidth()
@@ -1593,6 +2132,7 @@ func (t *Table[T]) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Si
 	if t.ShowRowDivider && endBeforeRow > startRow {
 		prefSize.Height += float32((endBeforeRow - startRow) - 1)
 	}
+	prefSize.Height += t.footerExtent()
 	if border := t.Border(); border != nil {
 		prefSize = prefSize.Add(border.Insets().Size())
 	}
@@ -1600,42 +2140,38 @@ func (t *Table[T]) DefaultSizes(_ geom.Size) (minSize, prefSize, maxSize geom.Si
 	return prefSize, prefSize, prefSize
 }
 
-// RowFromIndex returns the row data for the given index.
+// RowFromIndex returns the row data for the given index. The zero value is returned for a group row, as well as for a
+// row of a VirtualTableModel that hasn't been loaded yet.
 func (t *Table[T]) RowFromIndex(index int) T {
-	if index < 0 || index >= len(t.rowCache) {
+	if index < 0 || index >= t.rowCount() {
 		var zero T
 		return zero
 	}
-	return t.rowCache[index].row
+	row, _ := t.rowAt(index)
+	return row
 }
 
 // RowToIndex returns the row's index within the displayed data, or -1 if it isn't currently in the disclosed rows.
 func (t *Table[T]) RowToIndex(rowData T) int {
-	id := rowData.ID()
-	for row, data := range t.rowCache {
-		if data.row.ID() == id {
-			return row
-		}
-	}
-	return -1
+	return t.rowIndexForID(rowData.ID())
 }
 
 // LastRowIndex returns the index of the last row. Will be -1 if there are no rows.
 func (t *Table[T]) LastRowIndex() int {
-	return len(t.rowCache) - 1
+	return t.rowCount() - 1
 }
 
 // ScrollRowIntoView scrolls the row at the given index into view.
 func (t *Table[T]) ScrollRowIntoView(row int) {
 	if frame := t.RowFrame(row); !frame.Empty() {
-		t.ScrollRectIntoView(frame)
+		t.ScrollRectIntoView(t.expandForFrozen(frame, row, -1))
 	}
 }
 
 // ScrollRowCellIntoView scrolls the cell from the row and column at the given indexes into view.
 func (t *Table[T]) ScrollRowCellIntoView(row, col int) {
 	if frame := t.CellFrame(row, col); !frame.Empty() {
-		t.ScrollRectIntoView(frame)
+		t.ScrollRectIntoView(t.expandForFrozen(frame, row, col))
 	}
 }
 
@@ -1647,8 +2183,12 @@ func (t *Table[T]) IsFiltered() bool {
 
 // ApplyFilter applies a filter to the data. When a non-nil filter is applied, all rows (recursively) are passed through
 // the filter. Only those that the filter returns false for will be visible in the table. When a filter is applied, no
-// hierarchy is display and no modifications to the row data should be performed.
+// hierarchy is display and no modifications to the row data should be performed. Filtering is not available for a
+// VirtualTableModel, so the filter is ignored in that case.
 func (t *Table[T]) ApplyFilter(filter func(row T) bool) {
+	if _, ok := t.Model.(VirtualTableModel[T]); ok {
+		return
+	}
 	if filter == nil {
 		if t.filteredRows == nil {
 			return
@@ -1690,6 +2230,9 @@ func (t *Table[T]) InstallDragSupport(svg *SVG, dataType *uti.DataType, singular
 				Table: t,
 				Rows:  t.SelectedRows(true),
 			}
+			if len(data.Rows) == 0 {
+				return true
+			}
 			drawable := NewTableDragDrawable(data, svg, singularName, pluralName)
 			size := drawable.LogicalSize()
 			img, err := NewImageFromDrawing(int(size.Width), int(size.Height), 144, func(c *Canvas) {
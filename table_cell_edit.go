@@ -0,0 +1,451 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"reflect"
+	"unicode"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// TableEditableRow may be implemented by the row data of a Table to permit its cells to be edited in place. Editing of
+// a cell begins when it is double-clicked, when F2 is pressed or when the user starts typing while its row is selected.
+// Tab and Shift+Tab commit the edit and move to the next or previous editable cell, Return commits the edit and Escape
+// cancels it. Each committed edit is recorded in the UndoManager for the table, if there is one, and the table's
+// CellEditedCallback is called whenever a cell's value is changed by an edit or by undoing or redoing one.
+type TableEditableRow interface {
+	// CellEditor returns a new editor for the cell at the column index, initialized with the cell's current value, or
+	// nil if the cell cannot be edited.
+	CellEditor(col int) *TableCellEditor
+	// CellValue returns the current value of the cell at the column index, in the same form the cell's editor returns
+	// it.
+	CellValue(col int) any
+	// ValidateCellValue returns an error if the value is not acceptable for the cell at the column index. The error's
+	// message is shown to the user and the edit remains in progress.
+	ValidateCellValue(col int, value any) error
+	// SetCellValue sets the value of the cell at the column index. This is called when an edit is committed, as well as
+	// when it is undone or redone.
+	SetCellValue(col int, value any)
+}
+
+// TableCellEditor holds the widget used to edit a cell of a Table in place.
+type TableCellEditor struct {
+	// Editor is the widget placed over the cell while it is being edited. It, or one of its children, should be
+	// focusable.
+	Editor Paneler
+	// Value returns the value currently held by the Editor.
+	Value func() any
+	// RuneTyped, if not nil, is called with the rune that was typed to begin editing the cell. Cells whose editor does
+	// not provide this do not begin editing when the user starts typing.
+	RuneTyped func(ch rune)
+	err       error
+}
+
+// NewTableFieldCellEditor creates a new TableCellEditor that uses the Field, whose value is its text.
+func NewTableFieldCellEditor(field *Field) *TableCellEditor {
+	field.SelectAll()
+	return &TableCellEditor{
+		Editor: field,
+		Value:  func() any { return field.Text() },
+		RuneTyped: func(ch rune) {
+			field.SelectAll()
+			field.DefaultRuneTyped(ch)
+		},
+	}
+}
+
+// NewTableNumericFieldCellEditor creates a new TableCellEditor that uses the NumericField, whose value is its numeric
+// value.
+func NewTableNumericFieldCellEditor[N xmath.Integer | xmath.Float](field *NumericField[N]) *TableCellEditor {
+	field.SelectAll()
+	return &TableCellEditor{
+		Editor: field,
+		Value:  func() any { return field.Value() },
+		RuneTyped: func(ch rune) {
+			field.SelectAll()
+			field.DefaultRuneTyped(ch)
+		},
+	}
+}
+
+// NewTableDateFieldCellEditor creates a new TableCellEditor that uses the DateField, whose value is its date.
+func NewTableDateFieldCellEditor(field *DateField) *TableCellEditor {
+	field.SelectAll()
+	return &TableCellEditor{
+		Editor: field,
+		Value:  func() any { return field.Value() },
+		RuneTyped: func(ch rune) {
+			field.SelectAll()
+			field.DefaultRuneTyped(ch)
+		},
+	}
+}
+
+// NewTableTimeFieldCellEditor creates a new TableCellEditor that uses the TimeField, whose value is its time.
+func NewTableTimeFieldCellEditor(field *TimeField) *TableCellEditor {
+	field.SelectAll()
+	return &TableCellEditor{
+		Editor: field,
+		Value:  func() any { return field.Value() },
+		RuneTyped: func(ch rune) {
+			field.SelectAll()
+			field.DefaultRuneTyped(ch)
+		},
+	}
+}
+
+// NewTableDateRangeFieldCellEditor creates a new TableCellEditor that uses the DateRangeField, whose value is its
+// DateRange. Typing to begin editing replaces the start date.
+func NewTableDateRangeFieldCellEditor(field *DateRangeField) *TableCellEditor {
+	field.Start().SelectAll()
+	return &TableCellEditor{
+		Editor: field,
+		Value:  func() any { return field.Value() },
+		RuneTyped: func(ch rune) {
+			field.Start().SelectAll()
+			field.Start().DefaultRuneTyped(ch)
+		},
+	}
+}
+
+// NewTablePopupMenuCellEditor creates a new TableCellEditor that uses the PopupMenu, whose value is its selected item,
+// or the zero value of V if nothing is selected.
+func NewTablePopupMenuCellEditor[V comparable](popup *PopupMenu[V]) *TableCellEditor {
+	return &TableCellEditor{
+		Editor: popup,
+		Value: func() any {
+			item, _ := popup.Selected()
+			return item
+		},
+	}
+}
+
+// NewTableCheckBoxCellEditor creates a new TableCellEditor that uses the CheckBox, whose value is its check.Enum
+// state.
+func NewTableCheckBoxCellEditor(checkbox *CheckBox) *TableCellEditor {
+	return &TableCellEditor{
+		Editor: checkbox,
+		Value:  func() any { return checkbox.State },
+	}
+}
+
+// Err returns the error from the most recent failed attempt to commit the edit, if any.
+func (e *TableCellEditor) Err() error {
+	return e.err
+}
+
+func (e *TableCellEditor) setErr(err error) {
+	e.err = err
+	p := e.Editor.AsPanel()
+	if err != nil {
+		p.Tooltip = NewTooltipWithText(err.Error())
+	} else {
+		p.Tooltip = nil
+	}
+}
+
+type tableCellEdit[T TableRowConstraint[T]] struct {
+	row    T
+	editor *TableCellEditor
+	col    int
+}
+
+// EditCell begins editing the cell at the row and column indexes, first committing any edit already in progress.
+// Returns false if the cell cannot be edited or the edit in progress could not be committed.
+func (t *Table[T]) EditCell(row, col int) bool {
+	if !t.CommitCellEdit() {
+		return false
+	}
+	editor := t.cellEditor(row, col)
+	if editor == nil {
+		return false
+	}
+	t.beginCellEdit(row, col, editor)
+	return true
+}
+
+// CellBeingEdited returns the row and column indexes of the cell currently being edited, or -1, -1 if no cell is being
+// edited.
+func (t *Table[T]) CellBeingEdited() (row, col int) {
+	if t.cellEdit == nil {
+		return -1, -1
+	}
+	if row = t.RowToIndex(t.cellEdit.row); row == -1 {
+		return -1, -1
+	}
+	return row, t.cellEdit.col
+}
+
+// CellEditor returns the editor for the cell currently being edited, or nil if no cell is being edited.
+func (t *Table[T]) CellEditor() *TableCellEditor {
+	if t.cellEdit == nil {
+		return nil
+	}
+	return t.cellEdit.editor
+}
+
+// CommitCellEdit validates the value of the cell being edited and, if it is acceptable, stores it in the row and ends
+// the edit. Returns false if the value was rejected, in which case the edit remains in progress. Returns true if no
+// cell is being edited.
+func (t *Table[T]) CommitCellEdit() bool {
+	edit := t.cellEdit
+	if edit == nil {
+		return true
+	}
+	editable, ok := any(edit.row).(TableEditableRow)
+	if !ok {
+		t.CancelCellEdit()
+		return true
+	}
+	value := edit.editor.Value()
+	if err := editable.ValidateCellValue(edit.col, value); err != nil {
+		edit.editor.setErr(err)
+		t.MarkForRedraw()
+		return false
+	}
+	t.endCellEdit()
+	before := editable.CellValue(edit.col)
+	if reflect.DeepEqual(before, value) {
+		return true
+	}
+	t.setCellValue(edit.row, edit.col, value)
+	if mgr := UndoManagerFor(t); mgr != nil {
+		mgr.Add(&UndoEdit[any]{
+			ID:         NextUndoID(),
+			EditName:   i18n.Text("Edit Cell"),
+			EditCost:   1,
+			UndoFunc:   func(e *UndoEdit[any]) { t.setCellValue(edit.row, edit.col, e.BeforeData) },
+			RedoFunc:   func(e *UndoEdit[any]) { t.setCellValue(edit.row, edit.col, e.AfterData) },
+			BeforeData: before,
+			AfterData:  value,
+		})
+	}
+	return true
+}
+
+// CancelCellEdit ends the edit of the cell being edited, if any, without changing its value.
+func (t *Table[T]) CancelCellEdit() {
+	if t.cellEdit != nil {
+		t.endCellEdit()
+	}
+}
+
+func (t *Table[T]) cellEditor(row, col int) *TableCellEditor {
+	if row < 0 || row >= t.rowCount() || col < 0 || col >= len(t.Columns) {
+		return nil
+	}
+	rowData, ok := t.rowAt(row)
+	if !ok {
+		return nil
+	}
+	editable, ok := any(rowData).(TableEditableRow)
+	if !ok {
+		return nil
+	}
+	editor := editable.CellEditor(col)
+	if editor == nil || editor.Editor == nil || editor.Value == nil {
+		return nil
+	}
+	return editor
+}
+
+func (t *Table[T]) beginCellEdit(row, col int, editor *TableCellEditor) {
+	rowData, _ := t.rowAt(row)
+	t.cellEdit = &tableCellEdit[T]{row: rowData, col: col, editor: editor}
+	t.editColumn = col
+	t.ScrollRowCellIntoView(row, col)
+	t.AddChild(editor.Editor)
+	t.layoutCellEditor()
+	editor.Editor.AsPanel().RequestFocus()
+	t.MarkForRedraw()
+}
+
+func (t *Table[T]) endCellEdit() {
+	edit := t.cellEdit
+	t.cellEdit = nil
+	p := edit.editor.Editor.AsPanel()
+	if wnd := t.Window(); wnd != nil {
+		if focus := wnd.Focus(); focus != nil && AncestorIsOrSelf(focus, p) {
+			t.RequestFocusWithoutScroll()
+		}
+	}
+	p.RemoveFromParent()
+	t.MarkForRedraw()
+}
+
+// layoutCellEditor positions the editor of the cell being edited over the cell, ending the edit if its row is no longer
+// displayed.
+func (t *Table[T]) layoutCellEditor() {
+	if t.cellEdit == nil {
+		return
+	}
+	row, col := t.CellBeingEdited()
+	if row == -1 || col >= len(t.Columns) {
+		t.CancelCellEdit()
+		return
+	}
+	p := t.cellEdit.editor.Editor.AsPanel()
+	if frame := t.CellFrame(row, col); frame != p.FrameRect() {
+		p.SetFrameRect(frame)
+	}
+	p.ValidateLayout()
+}
+
+func (t *Table[T]) setCellValue(row T, col int, value any) {
+	if t.cellEdit != nil && t.cellEdit.row == row && t.cellEdit.col == col {
+		t.CancelCellEdit()
+	}
+	if editable, ok := any(row).(TableEditableRow); ok {
+		editable.SetCellValue(col, value)
+		if t.CellEditedCallback != nil {
+			SafeCall(func() { t.CellEditedCallback(row, col) })
+		}
+		t.SyncToModel()
+	}
+}
+
+// handleCellEditKeyDown handles the keys that control an edit in progress.
+func (t *Table[T]) handleCellEditKeyDown(keyCode KeyCode, mods mod.Modifiers) bool {
+	switch keyCode {
+	case KeyTab:
+		if mods&(mod.NonSticky&^mod.Shift) != 0 {
+			return false
+		}
+		row, col := t.CellBeingEdited()
+		if t.CommitCellEdit() {
+			t.editAdjacentCell(row, col, !mods.ShiftDown())
+		}
+	case KeyReturn, KeyNumPadEnter:
+		t.CommitCellEdit()
+	case KeyEscape:
+		t.CancelCellEdit()
+	default:
+		return false
+	}
+	return true
+}
+
+// editAdjacentCell begins editing the next (or previous) editable cell after the one at the row and column indexes,
+// moving on to the following (or preceding) rows as needed, skipping over any group rows. Only rows that are already
+// available are considered.
+func (t *Table[T]) editAdjacentCell(row, col int, forward bool) {
+	step := 1
+	if !forward {
+		step = -1
+	}
+	count := t.rowCount()
+	for r := row; r >= 0 && r < count; r += step {
+		if _, ok := t.rowAt(r); !ok && t.groupAt(r) == nil {
+			return
+		}
+		if r != row {
+			if forward {
+				col = -1
+			} else {
+				col = len(t.Columns)
+			}
+		}
+		for c := col + step; c >= 0 && c < len(t.Columns); c += step {
+			if editor := t.cellEditor(r, c); editor != nil {
+				if t.CellSelection {
+					t.SelectCell(r, c)
+				} else if r != row {
+					t.ClearSelection()
+					t.SelectByIndex(r)
+				}
+				t.beginCellEdit(r, c, editor)
+				return
+			}
+		}
+	}
+}
+
+// editSelectedRow begins editing a cell of the last selected row, or of the focused cell's row if CellSelection is
+// enabled, preferring the column of the most recently edited, clicked or focused cell. If requireRuneTyped is true,
+// only cells whose editor accepts a typed rune are considered.
+func (t *Table[T]) editSelectedRow(requireRuneTyped bool) *TableCellEditor {
+	row := t.LastSelectedRowIndex()
+	if t.CellSelection {
+		row, _ = t.FocusedCell()
+	}
+	if row == -1 || t.cellEdit != nil {
+		return nil
+	}
+	cols := make([]int, 0, len(t.Columns)+1)
+	if t.editColumn >= 0 && t.editColumn < len(t.Columns) {
+		cols = append(cols, t.editColumn)
+	}
+	for c := range t.Columns {
+		if c != t.editColumn {
+			cols = append(cols, c)
+		}
+	}
+	for _, c := range cols {
+		if editor := t.cellEditor(row, c); editor != nil && (!requireRuneTyped || editor.RuneTyped != nil) {
+			t.beginCellEdit(row, c, editor)
+			return editor
+		}
+	}
+	return nil
+}
+
+// DefaultRuneTyped provides the default rune typed handling, which begins editing a cell of the selected row if it is
+// editable. Otherwise, the next row whose text begins with the runes typed in quick succession is selected, using the
+// primary sort column, or the hierarchy column if the rows aren't sorted. Once such a search has begun, further runes
+// typed before the TypeToSelectTimeout passes continue it rather than beginning an edit.
+func (t *Table[T]) DefaultRuneTyped(ch rune) bool {
+	if t.cellEdit != nil || unicode.IsControl(ch) {
+		return false
+	}
+	if !unicode.IsSpace(ch) && !t.typeToSelect.active(t.TypeToSelectTimeout) {
+		if editor := t.editSelectedRow(true); editor != nil {
+			editor.RuneTyped(ch)
+			return true
+		}
+	}
+	return t.selectTypedRow(ch)
+}
+
+// DefaultFocusChangeInHierarchy provides the default focus change in hierarchy handling, which ends any edit in
+// progress once the keyboard focus leaves its editor, committing it if its value is acceptable.
+func (t *Table[T]) DefaultFocusChangeInHierarchy(_, to *Panel) {
+	edit := t.cellEdit
+	if edit == nil || (to != nil && AncestorIsOrSelf(to, edit.editor.Editor)) {
+		return
+	}
+	InvokeTask(func() {
+		if t.cellEdit == edit {
+			if wnd := t.Window(); wnd != nil {
+				if focus := wnd.Focus(); focus != nil && AncestorIsOrSelf(focus, edit.editor.Editor) {
+					return
+				}
+			}
+			if !t.CommitCellEdit() {
+				t.CancelCellEdit()
+			}
+		}
+	})
+}
+
+// drawCellEditError outlines the editor of the cell being edited if its value was rejected.
+func (t *Table[T]) drawCellEditError(canvas *Canvas) {
+	if t.cellEdit == nil || t.cellEdit.editor.err == nil {
+		return
+	}
+	rect := t.cellEdit.editor.Editor.AsPanel().FrameRect().Inset(geom.NewUniformInsets(-1.5))
+	paint := t.ErrorInk.Paint(canvas, rect, paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	canvas.DrawRect(rect, paint)
+}
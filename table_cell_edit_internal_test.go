@@ -0,0 +1,197 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	checkstate "github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// editableRow is a TableRowData whose first column holds editable text that may not be empty, whose second column holds
+// an editable check state and whose third column cannot be edited.
+type editableRow struct {
+	id    tid.TID
+	text  string
+	state checkstate.Enum
+}
+
+func (r *editableRow) CloneForTarget(_ Paneler, _ *editableRow) *editableRow {
+	clone := *r
+	return &clone
+}
+func (r *editableRow) ID() tid.TID                  { return r.id }
+func (r *editableRow) Parent() *editableRow         { return nil }
+func (r *editableRow) SetParent(_ *editableRow)     {}
+func (r *editableRow) CanHaveChildren() bool        { return false }
+func (r *editableRow) Children() []*editableRow     { return nil }
+func (r *editableRow) SetChildren(_ []*editableRow) {}
+func (r *editableRow) CellDataForSort(_ int) string { return r.text }
+func (r *editableRow) ColumnCell(_, _ int, _, _ Ink, _, _, _ bool) Paneler {
+	return NewPanel()
+}
+func (r *editableRow) IsOpen() bool   { return false }
+func (r *editableRow) SetOpen(_ bool) {}
+
+func (r *editableRow) CellEditor(col int) *TableCellEditor {
+	switch col {
+	case 0:
+		field := NewField()
+		field.SetText(r.text)
+		return NewTableFieldCellEditor(field)
+	case 1:
+		checkbox := NewCheckBox()
+		checkbox.State = r.state
+		return NewTableCheckBoxCellEditor(checkbox)
+	default:
+		return nil
+	}
+}
+
+func (r *editableRow) CellValue(col int) any {
+	if col == 0 {
+		return r.text
+	}
+	return r.state
+}
+
+func (r *editableRow) ValidateCellValue(col int, value any) error {
+	if col == 0 && value == "" {
+		return errs.New("a name is required")
+	}
+	return nil
+}
+
+func (r *editableRow) SetCellValue(col int, value any) {
+	if col == 0 {
+		r.text, _ = value.(string)
+	} else {
+		r.state, _ = value.(checkstate.Enum)
+	}
+}
+
+func newEditableTable(t *testing.T) (*Table[*editableRow], *UndoManager, []*editableRow) {
+	host := newUndoHost(t)
+	rows := []*editableRow{{id: "a", text: "alpha"}, {id: "b", text: "beta"}}
+	model := &SimpleTableModel[*editableRow]{}
+	model.SetRootRows(rows)
+	table := NewTable[*editableRow](model)
+	table.Columns = []ColumnInfo{{ID: 0, Current: 100}, {ID: 1, Current: 20}, {ID: 2, Current: 50}}
+	host.AddChild(table)
+	table.SyncToModel()
+	return table, host.mgr, rows
+}
+
+func editorField(c check.Checker, table *Table[*editableRow]) *Field {
+	field, ok := table.CellEditor().Editor.(*Field)
+	c.True(ok)
+	return field
+}
+
+func TestTableCellEditCommitAndUndo(t *testing.T) {
+	c := check.New(t)
+	table, mgr, rows := newEditableTable(t)
+	var edited []int
+	table.CellEditedCallback = func(_ *editableRow, col int) { edited = append(edited, col) }
+
+	c.False(table.EditCell(0, 2), "cells without an editor can't be edited")
+	c.True(table.EditCell(0, 0))
+	row, col := table.CellBeingEdited()
+	c.Equal(0, row)
+	c.Equal(0, col)
+	field := editorField(c, table)
+	c.True(field.Parent() == table.AsPanel())
+	c.Equal(table.CellFrame(0, 0), field.FrameRect())
+	c.Equal("alpha", field.Text())
+
+	field.SetText("gamma")
+	c.True(table.CommitCellEdit())
+	c.Nil(table.CellEditor())
+	c.Nil(field.Parent())
+	c.Equal("gamma", rows[0].text)
+	c.Equal([]int{0}, edited)
+
+	c.True(mgr.CanUndo())
+	mgr.Undo()
+	c.Equal("alpha", rows[0].text)
+	mgr.Redo()
+	c.Equal("gamma", rows[0].text)
+	c.Equal([]int{0, 0, 0}, edited)
+
+	c.True(table.EditCell(1, 0))
+	c.True(table.CommitCellEdit())
+	c.Equal([]int{0, 0, 0}, edited, "an unchanged value is not an edit")
+	mgr.Undo()
+	c.Equal("alpha", rows[0].text, "an unchanged value is not recorded for undo")
+}
+
+func TestTableCellEditValidation(t *testing.T) {
+	c := check.New(t)
+	table, mgr, rows := newEditableTable(t)
+	c.True(table.EditCell(1, 0))
+	editor := table.CellEditor()
+	editorField(c, table).SetText("")
+	c.False(table.CommitCellEdit())
+	c.HasError(editor.Err())
+	c.NotNil(editor.Editor.AsPanel().Tooltip)
+	row, _ := table.CellBeingEdited()
+	c.Equal(1, row, "the edit remains in progress")
+
+	c.True(table.DefaultKeyDown(KeyEscape, 0, false))
+	c.Nil(table.CellEditor())
+	c.Equal("beta", rows[1].text)
+	c.False(mgr.CanUndo())
+}
+
+func TestTableCellEditKeyboard(t *testing.T) {
+	c := check.New(t)
+	table, _, rows := newEditableTable(t)
+	table.SelectByIndex(0)
+	c.True(table.DefaultKeyDown(KeyF2, 0, false))
+	row, col := table.CellBeingEdited()
+	c.Equal(0, row)
+	c.Equal(0, col)
+
+	c.True(table.DefaultKeyDown(KeyTab, 0, false))
+	row, col = table.CellBeingEdited()
+	c.Equal(0, row)
+	c.Equal(1, col)
+	checkbox, ok := table.CellEditor().Editor.(*CheckBox)
+	c.True(ok)
+	checkbox.State = checkstate.On
+
+	c.True(table.DefaultKeyDown(KeyTab, 0, false), "tabbing past the last editable cell moves to the next row")
+	c.Equal(checkstate.On, rows[0].state)
+	row, col = table.CellBeingEdited()
+	c.Equal(1, row)
+	c.Equal(0, col)
+	c.True(table.IsRowSelected(1))
+	c.False(table.IsRowSelected(0))
+
+	c.True(table.DefaultKeyDown(KeyTab, mod.Shift, false))
+	row, col = table.CellBeingEdited()
+	c.Equal(0, row)
+	c.Equal(1, col)
+
+	c.True(table.DefaultKeyDown(KeyReturn, 0, false))
+	c.Nil(table.CellEditor())
+
+	table.ClearSelection()
+	table.SelectByIndex(1)
+	c.False(table.DefaultRuneTyped(' '))
+	c.True(table.DefaultRuneTyped('z'), "typing begins editing a cell whose editor accepts typing")
+	c.Equal("z", editorField(c, table).Text())
+	c.True(table.DefaultKeyDown(KeyReturn, 0, false))
+	c.Equal("z", rows[1].text)
+}
@@ -0,0 +1,516 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"html"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/uti"
+	"github.com/richardwilkes/unison/drag"
+	"github.com/richardwilkes/unison/enums/mod"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// TableCellRange identifies a rectangular range of cells within a Table by the row and column indexes of two opposing
+// corners, both of which are included in the range. When used for the selection, the start is the cell the selection
+// was begun from and the end is the cell it was extended to.
+type TableCellRange struct {
+	StartRow    int
+	StartColumn int
+	EndRow      int
+	EndColumn   int
+}
+
+// NewTableCellRange creates a new TableCellRange containing just the cell at the row and column indexes.
+func NewTableCellRange(row, col int) TableCellRange {
+	return TableCellRange{StartRow: row, StartColumn: col, EndRow: row, EndColumn: col}
+}
+
+// Normalized returns a copy of the range whose start is its top-left corner and whose end is its bottom-right corner.
+func (r TableCellRange) Normalized() TableCellRange {
+	return TableCellRange{
+		StartRow:    min(r.StartRow, r.EndRow),
+		StartColumn: min(r.StartColumn, r.EndColumn),
+		EndRow:      max(r.StartRow, r.EndRow),
+		EndColumn:   max(r.StartColumn, r.EndColumn),
+	}
+}
+
+// Contains returns true if the cell at the row and column indexes is within the range.
+func (r TableCellRange) Contains(row, col int) bool {
+	n := r.Normalized()
+	return row >= n.StartRow && row <= n.EndRow && col >= n.StartColumn && col <= n.EndColumn
+}
+
+// TableCellTextRow may be implemented by the row data of a Table to control the text copied from its cells and the
+// conversion of text pasted into them when the table's CellSelection is enabled. Without it, the text of a cell is
+// taken from the accessible text of its panel and only cells whose value is a string accept pasted text.
+type TableCellTextRow interface {
+	// CellText returns the text of the cell in the column.
+	CellText(col int) string
+	// ParseCellText converts text into a value for the cell in the column, suitable for passing to the SetCellValue()
+	// method of TableEditableRow.
+	ParseCellText(col int, text string) (any, error)
+}
+
+// tableCellValue holds the value of a cell of a Table.
+type tableCellValue[T TableRowConstraint[T]] struct {
+	row   T
+	value any
+	col   int
+}
+
+// SelectedCellRanges returns the ranges of cells that are selected, normalized, in the order they were selected. Only
+// meaningful when CellSelection is enabled.
+func (t *Table[T]) SelectedCellRanges() []TableCellRange {
+	ranges := make([]TableCellRange, len(t.cellRanges))
+	for i, r := range t.cellRanges {
+		ranges[i] = r.Normalized()
+	}
+	return ranges
+}
+
+// SetSelectedCellRanges replaces the selected cells with the ranges. The last range is the active one, whose start is
+// the focused cell. Ranges are clipped to the table's rows and columns. Only meaningful when CellSelection is enabled.
+func (t *Table[T]) SetSelectedCellRanges(ranges ...TableCellRange) {
+	t.cellRanges = nil
+	lastRow := t.rowCount() - 1
+	lastCol := len(t.Columns) - 1
+	if lastRow >= 0 && lastCol >= 0 {
+		for _, r := range ranges {
+			n := r.Normalized()
+			if n.EndRow < 0 || n.EndColumn < 0 || n.StartRow > lastRow || n.StartColumn > lastCol {
+				continue
+			}
+			t.cellRanges = append(t.cellRanges, TableCellRange{
+				StartRow:    min(max(r.StartRow, 0), lastRow),
+				StartColumn: min(max(r.StartColumn, 0), lastCol),
+				EndRow:      min(max(r.EndRow, 0), lastRow),
+				EndColumn:   min(max(r.EndColumn, 0), lastCol),
+			})
+		}
+	}
+	t.cellSelectionChanged()
+}
+
+// IsCellSelected returns true if the cell at the row and column indexes is selected. Only meaningful when
+// CellSelection is enabled.
+func (t *Table[T]) IsCellSelected(row, col int) bool {
+	for _, r := range t.cellRanges {
+		if r.Contains(row, col) {
+			return true
+		}
+	}
+	return false
+}
+
+// FocusedCell returns the row and column indexes of the focused cell, which is the start of the active range of the
+// selection, or -1, -1 if no cells are selected. Only meaningful when CellSelection is enabled.
+func (t *Table[T]) FocusedCell() (row, col int) {
+	if len(t.cellRanges) == 0 {
+		return -1, -1
+	}
+	r := t.cellRanges[len(t.cellRanges)-1]
+	return r.StartRow, r.StartColumn
+}
+
+// SelectCell replaces the selected cells with just the cell at the row and column indexes, making it the focused cell.
+// Only meaningful when CellSelection is enabled.
+func (t *Table[T]) SelectCell(row, col int) {
+	t.SetSelectedCellRanges(NewTableCellRange(row, col))
+}
+
+// ExtendCellSelection extends the active range of the selection from the focused cell to the cell at the row and
+// column indexes. If no cells are selected, selects just that cell. Only meaningful when CellSelection is enabled.
+func (t *Table[T]) ExtendCellSelection(row, col int) {
+	if len(t.cellRanges) == 0 {
+		t.SelectCell(row, col)
+		return
+	}
+	ranges := slices.Clone(t.cellRanges)
+	ranges[len(ranges)-1].EndRow = row
+	ranges[len(ranges)-1].EndColumn = col
+	t.SetSelectedCellRanges(ranges...)
+}
+
+// installCellSelectionCmdHandlers installs the handlers for copying and pasting cells. When CellSelection isn't
+// enabled, the commands are passed on to the table's ancestors.
+func (t *Table[T]) installCellSelectionCmdHandlers() {
+	for _, one := range []struct {
+		can func() bool
+		do  func()
+		id  int
+	}{
+		{id: CopyItemID, can: t.CanCopySelectedCells, do: t.CopySelectedCells},
+		{id: PasteItemID, can: t.CanPasteCells, do: t.PasteCells},
+	} {
+		t.InstallCmdHandlers(one.id, func(src any) bool {
+			if t.CellSelection {
+				return one.can()
+			}
+			return t.Parent().CanPerformCmd(src, one.id)
+		}, func(src any) {
+			if t.CellSelection {
+				one.do()
+			} else {
+				t.Parent().PerformCmd(src, one.id)
+			}
+		})
+	}
+}
+
+// cellSelectionChanged brings the row selection into line with the cell selection, so that the rows containing selected
+// cells are considered selected, then notifies of the change.
+func (t *Table[T]) cellSelectionChanged() {
+	t.selMap = make(map[tid.TID]bool)
+	t.selAnchor = ""
+	for _, r := range t.cellRanges {
+		n := r.Normalized()
+		for row := n.StartRow; row <= n.EndRow; row++ {
+			t.selMap[t.rowID(row)] = true
+		}
+	}
+	if row, col := t.FocusedCell(); row != -1 {
+		t.selAnchor = t.rowID(row)
+		t.editColumn = col
+	}
+	t.MarkForRedraw()
+	t.notifyOfSelectionChange()
+}
+
+// cellSelectionMouseDown adjusts the cell selection in response to a mouse down on the cell at the row and column
+// indexes.
+func (t *Table[T]) cellSelectionMouseDown(row, col, button int, mods mod.Modifiers) {
+	if col == -1 {
+		col = len(t.Columns) - 1
+	}
+	switch {
+	case mods&mod.Shift != 0:
+		t.ExtendCellSelection(row, col)
+	case mods.DiscontiguousSelectionDown():
+		t.SetSelectedCellRanges(append(slices.Clone(t.cellRanges), NewTableCellRange(row, col))...)
+	case button != ButtonLeft && t.IsCellSelected(row, col):
+		// Leave the selection alone so that a context menu can act upon it
+	default:
+		t.SelectCell(row, col)
+	}
+	t.cellSelectionDrag = button == ButtonLeft
+}
+
+// cellSelectionMouseDrag extends the active range of the selection to the cell under the point.
+func (t *Table[T]) cellSelectionMouseDrag(where geom.Point) {
+	row := t.OverRow(where.Y)
+	if row == -1 {
+		if where.Y < 0 {
+			row = 0
+		} else {
+			row = t.rowCount() - 1
+		}
+	}
+	col := t.OverColumn(where.X)
+	if col == -1 {
+		if left, _ := t.ColumnEdges(0); where.X < left {
+			col = 0
+		} else {
+			col = len(t.Columns) - 1
+		}
+	}
+	if len(t.cellRanges) == 0 || row == -1 {
+		return
+	}
+	if r := t.cellRanges[len(t.cellRanges)-1]; r.EndRow != row || r.EndColumn != col {
+		t.ExtendCellSelection(row, col)
+		t.ScrollRowCellIntoView(row, col)
+	}
+}
+
+// handleCellSelectionKeyDown handles the keys that navigate the cell selection.
+func (t *Table[T]) handleCellSelectionKeyDown(keyCode KeyCode, mods mod.Modifiers) bool {
+	if mods&mod.NonSticky == mod.OSMenuCommand() {
+		// Handle copy/paste commands directly in case no menu is present
+		switch keyCode {
+		case KeyC:
+			if t.CanCopySelectedCells() {
+				t.CopySelectedCells()
+				return true
+			}
+		case KeyV:
+			if t.CanPasteCells() {
+				t.PasteCells()
+				return true
+			}
+		default:
+		}
+	}
+	lastRow := t.rowCount() - 1
+	lastCol := len(t.Columns) - 1
+	if lastRow < 0 || lastCol < 0 {
+		return false
+	}
+	row, col := 0, 0
+	if len(t.cellRanges) != 0 {
+		r := t.cellRanges[len(t.cellRanges)-1]
+		if mods.ShiftDown() {
+			row, col = r.EndRow, r.EndColumn
+		} else {
+			row, col = r.StartRow, r.StartColumn
+		}
+	}
+	switch keyCode {
+	case KeyLeft:
+		col = max(col-1, 0)
+	case KeyRight:
+		col = min(col+1, lastCol)
+	case KeyUp:
+		row = max(row-1, 0)
+	case KeyDown:
+		row = min(row+1, lastRow)
+	case KeyHome:
+		if mods.CommandDown() || mods.ControlDown() {
+			row = 0
+		}
+		col = 0
+	case KeyEnd:
+		if mods.CommandDown() || mods.ControlDown() {
+			row = lastRow
+		}
+		col = lastCol
+	default:
+		return false
+	}
+	if mods.ShiftDown() {
+		t.ExtendCellSelection(row, col)
+	} else {
+		t.SelectCell(row, col)
+	}
+	t.ScrollRowCellIntoView(row, col)
+	return true
+}
+
+// drawFocusedCell draws the outline around the focused cell.
+func (t *Table[T]) drawFocusedCell(canvas *Canvas) {
+	row, col := t.FocusedCell()
+	if row == -1 || row >= t.rowCount() || col >= len(t.Columns) {
+		return
+	}
+	if editRow, editCol := t.CellBeingEdited(); editRow == row && editCol == col {
+		return
+	}
+	ink := t.SelectionInk
+	if !t.Focused() {
+		ink = t.InactiveSelectionInk
+	}
+	rect := t.CellFrame(row, col).Inset(geom.NewUniformInsets(-1))
+	paint := ink.Paint(canvas, rect, paintstyle.Stroke)
+	paint.SetStrokeWidth(2)
+	canvas.DrawRect(rect, paint)
+}
+
+// selectedCellBlock returns the indexes of the rows and columns that contain selected cells, in ascending order.
+func (t *Table[T]) selectedCellBlock() (rows, cols []int) {
+	rowSet := make(map[int]bool)
+	colSet := make(map[int]bool)
+	for _, r := range t.cellRanges {
+		n := r.Normalized()
+		for row := n.StartRow; row <= n.EndRow; row++ {
+			rowSet[row] = true
+		}
+		for col := n.StartColumn; col <= n.EndColumn; col++ {
+			colSet[col] = true
+		}
+	}
+	for row := range rowSet {
+		rows = append(rows, row)
+	}
+	for col := range colSet {
+		cols = append(cols, col)
+	}
+	slices.Sort(rows)
+	slices.Sort(cols)
+	return rows, cols
+}
+
+// CanCopySelectedCells returns true if CellSelection is enabled and cells are selected.
+func (t *Table[T]) CanCopySelectedCells() bool {
+	return t.CellSelection && len(t.cellRanges) != 0
+}
+
+// CopySelectedCells copies the text of the selected cells to the clipboard as both tab-separated values and an HTML
+// table, so that they can be pasted into spreadsheets and word processors. When disjoint ranges of cells are selected,
+// the rows and columns between them are omitted and cells that aren't selected are left empty.
+func (t *Table[T]) CopySelectedCells() {
+	if !t.CanCopySelectedCells() {
+		return
+	}
+	rows, cols := t.selectedCellBlock()
+	flatten := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+	var tsv, htm strings.Builder
+	htm.WriteString("<table>")
+	for _, row := range rows {
+		htm.WriteString("<tr>")
+		for i, col := range cols {
+			if i != 0 {
+				tsv.WriteByte('\t')
+			}
+			var text string
+			if t.IsCellSelected(row, col) {
+				text = t.cellText(row, col)
+			}
+			tsv.WriteString(flatten.Replace(text))
+			htm.WriteString("<td>")
+			htm.WriteString(strings.ReplaceAll(html.EscapeString(text), "\n", "<br>"))
+			htm.WriteString("</td>")
+		}
+		tsv.WriteByte('\n')
+		htm.WriteString("</tr>")
+	}
+	htm.WriteString("</table>")
+	ClipboardSetData(
+		drag.Data{Type: uti.UTF8PlainText, Data: []byte(tsv.String())},
+		drag.Data{Type: richTextHTMLDataType, Data: []byte(htm.String())},
+	)
+}
+
+// cellText returns the text of the cell at the row and column indexes.
+func (t *Table[T]) cellText(row, col int) string {
+	if group := t.groupAt(row); group != nil {
+		return t.groupCellText(group, col)
+	}
+	rowData, ok := t.rowAt(row)
+	if !ok {
+		return ""
+	}
+	if textRow, ok2 := any(rowData).(TableCellTextRow); ok2 {
+		return textRow.CellText(col)
+	}
+	return AccessibleText(t.cell(row, col))
+}
+
+// CanPasteCells returns true if CellSelection is enabled, a cell is focused and the clipboard has text.
+func (t *Table[T]) CanPasteCells() bool {
+	return t.CellSelection && len(t.cellRanges) != 0 && ClipboardHasText()
+}
+
+// PasteCells pastes tab-separated values from the clipboard into the cells, starting at the top-left corner of the
+// active range of the selection. A single value is pasted into every cell of the active range. Only cells of rows
+// that implement TableEditableRow, and which accept the value, are changed. The changes are recorded in the
+// UndoManager for the table as a single edit.
+func (t *Table[T]) PasteCells() {
+	if !t.CanPasteCells() {
+		return
+	}
+	values := parseTSV(ClipboardGetText())
+	if len(values) == 0 {
+		return
+	}
+	target := t.cellRanges[len(t.cellRanges)-1].Normalized()
+	if len(values) == 1 && len(values[0]) == 1 {
+		one := values[0][0]
+		values = make([][]string, target.EndRow-target.StartRow+1)
+		for i := range values {
+			values[i] = slices.Repeat([]string{one}, target.EndColumn-target.StartColumn+1)
+		}
+	}
+	if !t.CommitCellEdit() {
+		t.CancelCellEdit()
+	}
+	var before, after []tableCellValue[T]
+	for i, line := range values {
+		rowData, ok := t.rowAt(target.StartRow + i)
+		if !ok {
+			continue
+		}
+		editable, ok := any(rowData).(TableEditableRow)
+		if !ok {
+			continue
+		}
+		textRow, hasTextRow := any(rowData).(TableCellTextRow)
+		for j, text := range line {
+			col := target.StartColumn + j
+			if col >= len(t.Columns) {
+				break
+			}
+			current := editable.CellValue(col)
+			var value any = text
+			if hasTextRow {
+				var err error
+				if value, err = textRow.ParseCellText(col, text); err != nil {
+					continue
+				}
+			} else if _, isString := current.(string); !isString {
+				continue
+			}
+			if reflect.DeepEqual(current, value) || editable.ValidateCellValue(col, value) != nil {
+				continue
+			}
+			before = append(before, tableCellValue[T]{row: rowData, col: col, value: current})
+			after = append(after, tableCellValue[T]{row: rowData, col: col, value: value})
+		}
+	}
+	if len(after) == 0 {
+		return
+	}
+	t.setCellValues(after)
+	width := 0
+	for _, line := range values {
+		width = max(width, len(line))
+	}
+	t.SetSelectedCellRanges(TableCellRange{
+		StartRow:    target.StartRow,
+		StartColumn: target.StartColumn,
+		EndRow:      target.StartRow + len(values) - 1,
+		EndColumn:   target.StartColumn + width - 1,
+	})
+	if mgr := UndoManagerFor(t); mgr != nil {
+		mgr.Add(&UndoEdit[[]tableCellValue[T]]{
+			ID:         NextUndoID(),
+			EditName:   i18n.Text("Paste Cells"),
+			EditCost:   1,
+			UndoFunc:   func(e *UndoEdit[[]tableCellValue[T]]) { t.setCellValues(e.BeforeData) },
+			RedoFunc:   func(e *UndoEdit[[]tableCellValue[T]]) { t.setCellValues(e.AfterData) },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+}
+
+// setCellValues sets the values of the cells, calling the CellEditedCallback for each, then syncs to the model once.
+func (t *Table[T]) setCellValues(values []tableCellValue[T]) {
+	for _, one := range values {
+		if editable, ok := any(one.row).(TableEditableRow); ok {
+			editable.SetCellValue(one.col, one.value)
+			if t.CellEditedCallback != nil {
+				SafeCall(func() { t.CellEditedCallback(one.row, one.col) })
+			}
+		}
+	}
+	t.SyncToModel()
+}
+
+// parseTSV splits tab-separated values into lines of fields. A trailing line break is ignored.
+func parseTSV(text string) [][]string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	values := make([][]string, len(lines))
+	for i, line := range lines {
+		values[i] = strings.Split(line, "\t")
+	}
+	return values
+}
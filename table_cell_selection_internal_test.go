@@ -0,0 +1,196 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// gridRow is a TableRowData whose cells hold text, all of which may be edited. Its last column holds a number.
+type gridRow struct {
+	id     tid.TID
+	text   []string
+	number int
+}
+
+func (r *gridRow) CloneForTarget(_ Paneler, _ *gridRow) *gridRow {
+	clone := *r
+	return &clone
+}
+func (r *gridRow) ID() tid.TID                  { return r.id }
+func (r *gridRow) Parent() *gridRow             { return nil }
+func (r *gridRow) SetParent(_ *gridRow)         {}
+func (r *gridRow) CanHaveChildren() bool        { return false }
+func (r *gridRow) Children() []*gridRow         { return nil }
+func (r *gridRow) SetChildren(_ []*gridRow)     {}
+func (r *gridRow) CellDataForSort(_ int) string { return "" }
+func (r *gridRow) ColumnCell(_, _ int, _, _ Ink, _, _, _ bool) Paneler {
+	return NewPanel()
+}
+func (r *gridRow) IsOpen() bool   { return false }
+func (r *gridRow) SetOpen(_ bool) {}
+
+func (r *gridRow) CellEditor(_ int) *TableCellEditor {
+	return nil
+}
+
+func (r *gridRow) CellValue(col int) any {
+	if col < len(r.text) {
+		return r.text[col]
+	}
+	return r.number
+}
+
+func (r *gridRow) ValidateCellValue(_ int, value any) error {
+	if value == "!" {
+		return errs.New("not permitted")
+	}
+	return nil
+}
+
+func (r *gridRow) SetCellValue(col int, value any) {
+	if col < len(r.text) {
+		r.text[col], _ = value.(string)
+	} else {
+		r.number, _ = value.(int)
+	}
+}
+
+func (r *gridRow) CellText(col int) string {
+	if col < len(r.text) {
+		return r.text[col]
+	}
+	return strconv.Itoa(r.number)
+}
+
+func (r *gridRow) ParseCellText(col int, text string) (any, error) {
+	if col < len(r.text) {
+		return text, nil
+	}
+	return strconv.Atoi(text)
+}
+
+// newGridTable builds a table in cell selection mode with 4 rows of 3 columns, the last of which holds numbers.
+func newGridTable(t *testing.T) (*Table[*gridRow], *UndoManager, []*gridRow) {
+	host := newUndoHost(t)
+	rows := make([]*gridRow, 4)
+	for i := range rows {
+		s := strconv.Itoa(i)
+		rows[i] = &gridRow{id: tid.TID(s), text: []string{"a" + s, "b" + s}, number: i}
+	}
+	model := &SimpleTableModel[*gridRow]{}
+	model.SetRootRows(rows)
+	table := NewTable[*gridRow](model)
+	table.CellSelection = true
+	table.Columns = []ColumnInfo{{ID: 0, Current: 100}, {ID: 1, Current: 100}, {ID: 2, Current: 100}}
+	host.AddChild(table)
+	table.SyncToModel()
+	return table, host.mgr, rows
+}
+
+func TestTableCellSelectionNavigation(t *testing.T) {
+	c := check.New(t)
+	table, _, _ := newGridTable(t)
+	table.SelectCell(1, 1)
+	row, col := table.FocusedCell()
+	c.Equal(1, row)
+	c.Equal(1, col)
+	c.Equal(1, table.FirstSelectedRowIndex(), "rows containing selected cells are selected")
+
+	c.True(table.DefaultKeyDown(KeyDown, mod.Shift, false))
+	c.True(table.DefaultKeyDown(KeyRight, mod.Shift, false))
+	c.True(table.DefaultKeyDown(KeyRight, mod.Shift, false))
+	c.Equal([]TableCellRange{{StartRow: 1, StartColumn: 1, EndRow: 2, EndColumn: 2}}, table.SelectedCellRanges())
+	c.True(table.IsCellSelected(2, 2))
+	c.False(table.IsCellSelected(2, 0))
+	c.Equal(2, table.SelectionCount())
+
+	c.True(table.DefaultKeyDown(KeyLeft, 0, false))
+	c.Equal([]TableCellRange{NewTableCellRange(1, 0)}, table.SelectedCellRanges(), "moving collapses the selection")
+	c.True(table.DefaultKeyDown(KeyEnd, mod.Control, false))
+	row, col = table.FocusedCell()
+	c.Equal(3, row)
+	c.Equal(2, col)
+
+	table.SelectAll()
+	c.Equal([]TableCellRange{{EndRow: 3, EndColumn: 2}}, table.SelectedCellRanges())
+	table.ClearSelection()
+	row, _ = table.FocusedCell()
+	c.Equal(-1, row)
+}
+
+func TestTableCellSelectionMouse(t *testing.T) {
+	c := check.New(t)
+	table, _, _ := newGridTable(t)
+	at := func(row, col int) geom.Point { return table.CellFrame(row, col).Center() }
+	c.True(table.DefaultMouseDown(at(0, 0), ButtonLeft, 1, 0))
+	c.True(table.DefaultMouseDrag(at(2, 1), ButtonLeft, 0))
+	table.DefaultMouseUp(at(2, 1), ButtonLeft, 0)
+	c.Equal([]TableCellRange{{EndRow: 2, EndColumn: 1}}, table.SelectedCellRanges())
+
+	c.True(table.DefaultMouseDown(at(3, 2), ButtonLeft, 1, mod.Control))
+	table.DefaultMouseUp(at(3, 2), ButtonLeft, mod.Control)
+	c.Equal([]TableCellRange{{EndRow: 2, EndColumn: 1}, NewTableCellRange(3, 2)}, table.SelectedCellRanges())
+	row, col := table.FocusedCell()
+	c.Equal(3, row)
+	c.Equal(2, col)
+
+	c.True(table.DefaultMouseDown(at(1, 2), ButtonLeft, 1, mod.Shift))
+	table.DefaultMouseUp(at(1, 2), ButtonLeft, mod.Shift)
+	c.Equal([]TableCellRange{{EndRow: 2, EndColumn: 1}, {StartRow: 1, StartColumn: 2, EndRow: 3, EndColumn: 2}},
+		table.SelectedCellRanges())
+}
+
+func TestTableCellSelectionClipboard(t *testing.T) {
+	enableHeadlessForTest(t)
+	c := check.New(t)
+	table, mgr, rows := newGridTable(t)
+	table.SetSelectedCellRanges(TableCellRange{StartRow: 0, StartColumn: 1, EndRow: 1, EndColumn: 2})
+	c.True(table.CanPerformCmd(table, CopyItemID))
+	table.PerformCmd(table, CopyItemID)
+	c.Equal("b0\t0\nb1\t1\n", ClipboardGetText())
+	c.Equal("<table><tr><td>b0</td><td>0</td></tr><tr><td>b1</td><td>1</td></tr></table>",
+		string(ClipboardGetData(richTextHTMLDataType)))
+
+	table.SelectCell(2, 1)
+	c.True(table.DefaultKeyDown(KeyV, mod.OSMenuCommand(), false))
+	c.Equal([]string{"b0", "b1"}, []string{rows[2].text[1], rows[3].text[1]})
+	c.Equal([]int{0, 1}, []int{rows[2].number, rows[3].number}, "the pasted text is parsed by the row")
+	c.Equal("a3", rows[3].text[0])
+	c.Equal([]TableCellRange{{StartRow: 2, StartColumn: 1, EndRow: 3, EndColumn: 2}}, table.SelectedCellRanges())
+
+	mgr.Undo()
+	c.Equal([]string{"b2", "b3"}, []string{rows[2].text[1], rows[3].text[1]}, "the paste is undone as a whole")
+	c.Equal([]int{2, 3}, []int{rows[2].number, rows[3].number})
+
+	ClipboardSetText("z\n")
+	table.SetSelectedCellRanges(TableCellRange{EndRow: 1, EndColumn: 0})
+	table.PasteCells()
+	c.Equal([]string{"z", "z"}, []string{rows[0].text[0], rows[1].text[0]}, "a single value fills the range")
+
+	ClipboardSetText("!\tx\ty\tw")
+	table.SelectCell(1, 0)
+	table.PasteCells()
+	c.Equal("z", rows[1].text[0], "rejected values are skipped")
+	c.Equal("x", rows[1].text[1])
+	c.Equal(1, rows[1].number, "values that can't be parsed are skipped")
+	c.Equal([]TableCellRange{{StartRow: 1, EndRow: 1, EndColumn: 2}}, table.SelectedCellRanges(),
+		"values beyond the last column are dropped")
+
+	table.CellSelection = false
+	c.False(table.CanPerformCmd(table, CopyItemID), "without cell selection the command is left to the ancestors")
+}
@@ -10,6 +10,8 @@
 package unison
 
 import (
+	"strconv"
+
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/mod"
@@ -49,6 +51,7 @@ var DefaultTableColumnHeaderTheme = LabelTheme{
 type DefaultTableColumnHeader[T TableRowConstraint[T]] struct {
 	*Label
 	less          func(a, b string) bool
+	compare       func(a, b any) int
 	sortIndicator *DrawableSVG
 	sortState     SortState
 }
@@ -97,8 +100,23 @@ func (h *DefaultTableColumnHeader[T]) DefaultSizes(hint geom.Size) (minSize, pre
 // DefaultDraw provides the default drawing.
 func (h *DefaultTableColumnHeader[T]) DefaultDraw(canvas *Canvas, _ geom.Rect) {
 	r := h.ContentRect(false)
+	var orderText *Text
 	if h.sortIndicator != nil {
 		r.Width -= h.Gap + h.sortIndicator.LogicalSize().Width
+		if h.showSortOrder() {
+			ink := h.OnBackgroundInk
+			if !h.Enabled() {
+				ink = &ColorFilteredInk{
+					OriginalInk: ink,
+					ColorFilter: Grayscale30Filter(),
+				}
+			}
+			orderText = NewText(strconv.Itoa(h.sortState.Order+1), &TextDecoration{
+				Font:            h.Font.Face().Font(h.Font.Size() * 0.75),
+				OnBackgroundInk: ink,
+			})
+			r.Width -= orderText.Width()
+		}
 	}
 	DrawLabel(canvas, r, h.HAlign, h.VAlign, h.Font, h.Text, h.OnBackgroundInk, nil, h.Drawable, h.Side, h.Gap,
 		!h.Enabled())
@@ -112,7 +130,30 @@ func (h *DefaultTableColumnHeader[T]) DefaultDraw(canvas *Canvas, _ geom.Rect) {
 			paint.SetColorFilter(Grayscale30Filter())
 		}
 		h.sortIndicator.DrawInRect(canvas, r, nil, paint)
+		if orderText != nil {
+			orderText.Draw(canvas, geom.NewPoint(r.Right(), r.Bottom()))
+		}
+	}
+}
+
+// showSortOrder returns true if the position of this column within the sort criteria should be shown, which is the
+// case when more than one column is being sorted on.
+func (h *DefaultTableColumnHeader[T]) showSortOrder() bool {
+	if h.sortState.Order > 0 {
+		return true
+	}
+	if p := h.Parent(); p != nil {
+		if header, ok := p.Self.(*TableHeader[T]); ok {
+			for _, hdr := range header.ColumnHeaders {
+				if hdr != TableColumnHeader[T](h) {
+					if ss := hdr.SortState(); ss.Sortable && ss.Order >= 0 {
+						return true
+					}
+				}
+			}
+		}
 	}
+	return false
 }
 
 // SortState returns the current SortState.
@@ -124,7 +165,7 @@ func (h *DefaultTableColumnHeader[T]) SortState() SortState {
 func (h *DefaultTableColumnHeader[T]) SetSortState(state SortState) {
 	if h.sortState != state {
 		h.sortState = state
-		if h.sortState.Sortable && h.sortState.Order == 0 {
+		if h.sortState.Sortable && h.sortState.Order >= 0 {
 			baseline := h.Font.Baseline()
 			h.sortIndicator = &DrawableSVG{Size: geom.NewSize(baseline, baseline)}
 			if h.sortState.Ascending {
@@ -139,11 +180,16 @@ func (h *DefaultTableColumnHeader[T]) SetSortState(state SortState) {
 	}
 }
 
-// DefaultMouseUp provides the default mouse up handling.
-func (h *DefaultTableColumnHeader[T]) DefaultMouseUp(where geom.Point, _ int, _ mod.Modifiers) bool {
+// DefaultMouseUp provides the default mouse up handling. Holding down the shift key adds the column to the sort
+// criteria rather than making it the primary sort column.
+func (h *DefaultTableColumnHeader[T]) DefaultMouseUp(where geom.Point, _ int, mods mod.Modifiers) bool {
 	if h.sortState.Sortable && where.In(h.ContentRect(false)) {
 		if header, ok := h.Parent().Self.(*TableHeader[T]); ok {
-			header.SortOn(h)
+			if mods.ShiftDown() {
+				header.AddSortOn(h)
+			} else {
+				header.SortOn(h)
+			}
 			header.ApplySort()
 		}
 	}
@@ -154,3 +200,14 @@ func (h *DefaultTableColumnHeader[T]) DefaultMouseUp(where geom.Point, _ int, _
 func (h *DefaultTableColumnHeader[T]) Less() func(a, b string) bool {
 	return h.less
 }
+
+// Compare implements TableColumnComparer, returning the function set via SetCompare(), if any.
+func (h *DefaultTableColumnHeader[T]) Compare() func(a, b any) int {
+	return h.compare
+}
+
+// SetCompare sets the function used to compare the values returned by the CellSortValue() method of rows that implement
+// TableSortValueRow. May pass nil to use CompareSortValues.
+func (h *DefaultTableColumnHeader[T]) SetCompare(compare func(a, b any) int) {
+	h.compare = compare
+}
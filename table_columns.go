@@ -0,0 +1,152 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/xreflect"
+)
+
+// tableColumn describes a column of a Table, whether visible or hidden.
+type tableColumn[T TableRowConstraint[T]] struct {
+	header TableColumnHeader[T]
+	info   ColumnInfo
+	hidden bool
+}
+
+// tableHiddenColumn holds a column of a Table that has been hidden, along with its position amongst all of the table's
+// columns.
+type tableHiddenColumn[T TableRowConstraint[T]] struct {
+	header TableColumnHeader[T]
+	info   ColumnInfo
+	index  int
+}
+
+// MoveColumn moves the visible column at index 'from' to index 'to', along with its column header. Since the column
+// indexes passed to the row data change as a result, rows of tables whose columns can be moved should identify columns
+// by their ColumnInfo.ID.
+func (t *Table[T]) MoveColumn(from, to int) {
+	if from == to || from < 0 || to < 0 || from >= len(t.Columns) || to >= len(t.Columns) {
+		return
+	}
+	all := t.allColumns()
+	fromPos := t.columnPosition(all, from)
+	toPos := t.columnPosition(all, to)
+	column := all[fromPos]
+	all = slices.Insert(slices.Delete(all, fromPos, fromPos+1), toPos, column)
+	t.setAllColumns(all)
+}
+
+// ColumnHidden returns true if the column with the ID has been hidden.
+func (t *Table[T]) ColumnHidden(id int) bool {
+	for _, one := range t.hiddenColumns {
+		if one.info.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SetColumnHidden hides or shows the column with the ID, along with its column header. A hidden column retains its
+// position amongst the other columns and is restored there when shown again. The last visible column cannot be hidden.
+// Since the column indexes passed to the row data change as a result, rows of tables whose columns can be hidden should
+// identify columns by their ColumnInfo.ID.
+func (t *Table[T]) SetColumnHidden(id int, hidden bool) {
+	if t.ColumnHidden(id) == hidden || (hidden && len(t.Columns) < 2) {
+		return
+	}
+	all := t.allColumns()
+	for i := range all {
+		if all[i].info.ID == id {
+			all[i].hidden = hidden
+			t.setAllColumns(all)
+			return
+		}
+	}
+}
+
+// HiddenColumns returns the columns that have been hidden.
+func (t *Table[T]) HiddenColumns() []ColumnInfo {
+	columns := make([]ColumnInfo, len(t.hiddenColumns))
+	for i, one := range t.hiddenColumns {
+		columns[i] = one.info
+	}
+	return columns
+}
+
+// columnHeader returns the column header for the visible column at the index, or nil if there isn't one.
+func (t *Table[T]) columnHeader(col int) TableColumnHeader[T] {
+	if t.header != nil && col >= 0 && col < len(t.header.ColumnHeaders) {
+		return t.header.ColumnHeaders[col]
+	}
+	return nil
+}
+
+// allColumns returns all of the table's columns, both visible and hidden, in order.
+func (t *Table[T]) allColumns() []tableColumn[T] {
+	all := make([]tableColumn[T], 0, len(t.Columns)+len(t.hiddenColumns))
+	v := 0
+	h := 0
+	for v < len(t.Columns) || h < len(t.hiddenColumns) {
+		if h < len(t.hiddenColumns) && (t.hiddenColumns[h].index <= len(all) || v >= len(t.Columns)) {
+			one := t.hiddenColumns[h]
+			all = append(all, tableColumn[T]{header: one.header, info: one.info, hidden: true})
+			h++
+		} else {
+			all = append(all, tableColumn[T]{header: t.columnHeader(v), info: t.Columns[v]})
+			v++
+		}
+	}
+	return all
+}
+
+// columnPosition returns the position within the columns returned by allColumns() of the visible column at the index.
+func (t *Table[T]) columnPosition(all []tableColumn[T], col int) int {
+	for i := range all {
+		if !all[i].hidden {
+			if col == 0 {
+				return i
+			}
+			col--
+		}
+	}
+	return -1
+}
+
+// setAllColumns rearranges the table's columns, and those of its header, to match the columns, which must be the same
+// ones returned by allColumns(), though possibly reordered and with their hidden state altered.
+func (t *Table[T]) setAllColumns(all []tableColumn[T]) {
+	if !t.CommitCellEdit() {
+		t.CancelCellEdit()
+	}
+	columns := make([]ColumnInfo, 0, len(all))
+	headers := make([]TableColumnHeader[T], 0, len(all))
+	hasHeaders := t.header != nil
+	t.hiddenColumns = nil
+	for i, one := range all {
+		if one.hidden {
+			t.hiddenColumns = append(t.hiddenColumns, tableHiddenColumn[T]{header: one.header, info: one.info, index: i})
+			continue
+		}
+		columns = append(columns, one.info)
+		if xreflect.IsNil(one.header) {
+			hasHeaders = false
+		} else {
+			headers = append(headers, one.header)
+		}
+	}
+	t.Columns = columns
+	if hasHeaders {
+		t.header.ColumnHeaders = headers
+		t.header.MarkForLayoutAndRedraw()
+	}
+	t.SyncToModel()
+}
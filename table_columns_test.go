@@ -0,0 +1,108 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison"
+)
+
+// newColumnsTestTable builds a table with three sortable columns whose IDs are 10, 20 and 30, along with a header for
+// them.
+func newColumnsTestTable() (*unison.Table[*tableTestRow], *unison.TableHeader[*tableTestRow], []*testColumnHeader) {
+	table := newTestTable(flatRows(3)...)
+	headers := make([]*testColumnHeader, 3)
+	columnHeaders := make([]unison.TableColumnHeader[*tableTestRow], 3)
+	for i := range headers {
+		table.Columns = append(table.Columns, unison.ColumnInfo{ID: (i + 1) * 10, Current: float32(50 + i)})
+		headers[i] = newTestColumnHeader()
+		headers[i].state = unison.SortState{Order: -1, Ascending: true, Sortable: true}
+		columnHeaders[i] = headers[i]
+	}
+	return table, unison.NewTableHeader(table, columnHeaders...), headers
+}
+
+func columnIDs(table *unison.Table[*tableTestRow]) []int {
+	ids := make([]int, len(table.Columns))
+	for i, column := range table.Columns {
+		ids[i] = column.ID
+	}
+	return ids
+}
+
+func TestTableMoveColumn(t *testing.T) {
+	c := check.New(t)
+	table, header, headers := newColumnsTestTable()
+	table.MoveColumn(0, 2)
+	c.Equal([]int{20, 30, 10}, columnIDs(table))
+	c.True(header.ColumnHeaders[2] == unison.TableColumnHeader[*tableTestRow](headers[0]))
+	table.MoveColumn(2, 1)
+	c.Equal([]int{20, 10, 30}, columnIDs(table))
+	c.True(header.ColumnHeaders[1] == unison.TableColumnHeader[*tableTestRow](headers[0]))
+	table.MoveColumn(0, 3)
+	c.Equal([]int{20, 10, 30}, columnIDs(table), "out of range moves are ignored")
+}
+
+func TestTableSetColumnHidden(t *testing.T) {
+	c := check.New(t)
+	table, header, headers := newColumnsTestTable()
+	table.SetColumnHidden(20, true)
+	c.True(table.ColumnHidden(20))
+	c.Equal([]int{10, 30}, columnIDs(table))
+	c.Equal(2, len(header.ColumnHeaders))
+	c.Equal(1, len(table.HiddenColumns()))
+	c.Equal(20, table.HiddenColumns()[0].ID)
+
+	table.MoveColumn(1, 0)
+	c.Equal([]int{30, 10}, columnIDs(table))
+	table.SetColumnHidden(20, false)
+	c.False(table.ColumnHidden(20))
+	c.Equal([]int{30, 20, 10}, columnIDs(table), "a shown column returns to its former position")
+	c.True(header.ColumnHeaders[1] == unison.TableColumnHeader[*tableTestRow](headers[1]))
+
+	table.SetColumnHidden(10, true)
+	table.SetColumnHidden(20, true)
+	table.SetColumnHidden(30, true)
+	c.Equal([]int{30}, columnIDs(table), "the last visible column can't be hidden")
+	c.Equal(2, len(table.HiddenColumns()))
+}
+
+func TestTableLayoutState(t *testing.T) {
+	c := check.New(t)
+	table, _, headers := newColumnsTestTable()
+	table.MoveColumn(2, 0)
+	table.SetColumnHidden(20, true)
+	table.Columns[1].Current = 75
+	headers[2].state = unison.SortState{Order: 0, Ascending: false, Sortable: true}
+
+	state := unison.NewTableLayoutState(table)
+	c.Equal(3, len(state.Columns))
+	c.Equal(unison.TableColumnState{ID: 30, Width: 52, SortOrder: 1}, *state.Columns[0])
+	c.Equal(unison.TableColumnState{ID: 10, Width: 75}, *state.Columns[1])
+	c.Equal(unison.TableColumnState{ID: 20, Width: 51, Hidden: true}, *state.Columns[2])
+
+	data, err := json.Marshal(state)
+	c.NoError(err)
+	var restored unison.TableLayoutState
+	c.NoError(json.Unmarshal(data, &restored))
+	restored.Columns = append(restored.Columns, &unison.TableColumnState{ID: 99, Width: 1000})
+
+	other, otherHeader, otherHeaders := newColumnsTestTable()
+	restored.Apply(other)
+	c.Equal([]int{30, 10}, columnIDs(other))
+	c.Equal(float32(75), other.Columns[1].Current)
+	c.True(other.ColumnHidden(20))
+	c.Equal(unison.SortState{Order: 0, Ascending: false, Sortable: true}, otherHeaders[2].state)
+	c.Equal(-1, otherHeaders[0].state.Order)
+	c.True(otherHeader.ColumnHeaders[0] == unison.TableColumnHeader[*tableTestRow](otherHeaders[2]))
+}
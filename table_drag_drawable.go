@@ -22,26 +22,31 @@ type dragDrawable struct {
 
 // NewTableDragDrawable creates a new drawable for a table row drag.
 func NewTableDragDrawable[T TableRowConstraint[T]](data *TableDragData[T], svg *SVG, singularName, pluralName string) Drawable {
+	return newDragDrawable(data.Table.SelectionInk, data.Table.OnSelectionInk, CountTableRows(data.Rows), svg,
+		singularName, pluralName)
+}
+
+func newDragDrawable(ink, onInk Ink, count int, svg *SVG, singularName, pluralName string) Drawable {
 	label := NewLabel()
 	label.DrawCallback = func(gc *Canvas, rect geom.Rect) {
 		r := rect.Inset(geom.NewUniformInsets(1))
 		corner := geom.NewUniformSize(r.Height / 2)
 		gc.SaveWithOpacity(0.7)
-		fillPaint := data.Table.SelectionInk.Paint(gc, r, paintstyle.Fill)
+		fillPaint := ink.Paint(gc, r, paintstyle.Fill)
 		gc.DrawRoundedRect(r, corner, fillPaint)
-		strokePaint := data.Table.OnSelectionInk.Paint(gc, r, paintstyle.Stroke)
+		strokePaint := onInk.Paint(gc, r, paintstyle.Stroke)
 		gc.DrawRoundedRect(r, corner, strokePaint)
 		gc.Restore()
 		label.DefaultDraw(gc, rect)
 	}
-	label.OnBackgroundInk = data.Table.OnSelectionInk
+	label.OnBackgroundInk = onInk
 	label.SetBorder(NewEmptyBorder(geom.Insets{
 		Top:    4,
 		Left:   label.Font.LineHeight(),
 		Bottom: 4,
 		Right:  label.Font.LineHeight(),
 	}))
-	if count := CountTableRows(data.Rows); count == 1 {
+	if count == 1 {
 		label.SetTitle(fmt.Sprintf("1 %s", singularName))
 	} else {
 		label.SetTitle(fmt.Sprintf("%d %s", count, pluralName))
@@ -109,6 +109,13 @@ func (d *TableDrop[T, U]) DragUpdatedCallback(di drag.Info, where geom.Point, _
 		// Over row
 		d.TargetIndex = -1
 		row := d.Table.RowFromIndex(rowIndex)
+		if row == zero {
+			// Rows without data, such as group rows, can't be dropped on
+			d.inDragOver = false
+			d.Table.MarkForRedraw()
+			d.Table.FlushDrawing()
+			return drag.None
+		}
 		rect := d.Table.CellFrame(rowIndex, max(hierarchyColumnIndex, 0))
 		if where.Y >= d.Table.RowFrame(rowIndex).CenterY() {
 			d.top = min(rect.Bottom()+1+d.Table.Padding.Bottom, contentRect.Bottom()-1)
@@ -0,0 +1,254 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"encoding/csv"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// TableExportOptions holds the options for exporting the rows of a Table. The rows are exported as they are currently
+// displayed, so the sort, filter, grouping, open state of rows with children and order of the columns are all
+// respected. Group rows and the total row, when visible, are exported with the text of their cells. Rows of a
+// VirtualTableModel that have not been loaded are skipped.
+type TableExportOptions[T TableRowConstraint[T]] struct {
+	// Formatters provides the text for the cells of specific columns, keyed by their ColumnInfo.ID. The cells of other
+	// columns use the text from CellText() if the row implements TableCellTextRow, or CellDataForSort() if not.
+	Formatters map[int]func(row T) string
+	// Indent is placed before the text of the hierarchy column once for each level of depth. Defaults to two spaces.
+	// Not used when DepthColumn is true.
+	Indent string
+	// DepthColumnTitle is the title of the depth column. Defaults to "Depth".
+	DepthColumnTitle string
+	// DepthColumn adds a leading column holding the depth of each row, rather than indenting the hierarchy column.
+	DepthColumn bool
+	// SelectedOnly exports only the selected rows.
+	SelectedOnly bool
+	// OmitTitles leaves out the row of column titles, which are otherwise taken from the table's header, if it has one.
+	// Markdown tables always have a title row, so an empty one will be emitted in that case.
+	OmitTitles bool
+}
+
+// tableExport holds the text collected from a Table for export.
+type tableExport struct {
+	titles    []string
+	rows      [][]string
+	depths    []int
+	indent    string
+	hierarchy int
+}
+
+// ExportCSV writes the rows as comma-separated values, quoted as described by RFC 4180. The options may be nil.
+func (t *Table[T]) ExportCSV(w io.Writer, options *TableExportOptions[T]) error {
+	return t.collectExport(options).writeDelimited(w, ',')
+}
+
+// ExportTSV writes the rows as tab-separated values, quoted as described by RFC 4180. The options may be nil.
+func (t *Table[T]) ExportTSV(w io.Writer, options *TableExportOptions[T]) error {
+	return t.collectExport(options).writeDelimited(w, '\t')
+}
+
+// ExportHTML writes the rows as an HTML table. The options may be nil.
+func (t *Table[T]) ExportHTML(w io.Writer, options *TableExportOptions[T]) error {
+	e := t.collectExport(options)
+	escape := func(text string) string { return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>") }
+	var buffer strings.Builder
+	buffer.WriteString("<table>\n")
+	if e.titles != nil {
+		buffer.WriteString("<thead>\n<tr>")
+		for _, title := range e.titles {
+			buffer.WriteString("<th>")
+			buffer.WriteString(escape(title))
+			buffer.WriteString("</th>")
+		}
+		buffer.WriteString("</tr>\n</thead>\n")
+	}
+	buffer.WriteString("<tbody>\n")
+	for r, row := range e.rows {
+		buffer.WriteString("<tr>")
+		for c, text := range row {
+			buffer.WriteString("<td>")
+			buffer.WriteString(e.markupIndent(r, c))
+			buffer.WriteString(escape(text))
+			buffer.WriteString("</td>")
+		}
+		buffer.WriteString("</tr>\n")
+	}
+	buffer.WriteString("</tbody>\n</table>\n")
+	return writeExport(w, buffer.String())
+}
+
+// ExportMarkdown writes the rows as a GitHub-flavored Markdown table. The options may be nil.
+func (t *Table[T]) ExportMarkdown(w io.Writer, options *TableExportOptions[T]) error {
+	e := t.collectExport(options)
+	escape := strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+	titles := e.titles
+	if titles == nil {
+		titles = make([]string, len(t.Columns))
+		if options != nil && options.DepthColumn {
+			titles = append(titles, "")
+		}
+	}
+	var buffer strings.Builder
+	writeRow := func(r int, row []string) {
+		buffer.WriteByte('|')
+		for c, text := range row {
+			buffer.WriteByte(' ')
+			if r >= 0 {
+				buffer.WriteString(e.markupIndent(r, c))
+			}
+			buffer.WriteString(escape.Replace(text))
+			buffer.WriteString(" |")
+		}
+		buffer.WriteByte('\n')
+	}
+	writeRow(-1, titles)
+	buffer.WriteByte('|')
+	for range titles {
+		buffer.WriteString(" --- |")
+	}
+	buffer.WriteByte('\n')
+	for r, row := range e.rows {
+		writeRow(r, row)
+	}
+	return writeExport(w, buffer.String())
+}
+
+// collectExport collects the text to be exported from the rows currently being displayed.
+func (t *Table[T]) collectExport(options *TableExportOptions[T]) *tableExport {
+	if options == nil {
+		options = &TableExportOptions[T]{}
+	}
+	e := &tableExport{
+		indent:    options.Indent,
+		hierarchy: -1,
+	}
+	if e.indent == "" {
+		e.indent = "  "
+	}
+	if !options.DepthColumn && t.CurrentHierarchyIndent() > 0 {
+		for c := range t.Columns {
+			if t.Columns[c].ID == t.HierarchyColumnID {
+				e.hierarchy = c
+				break
+			}
+		}
+	}
+	if !options.OmitTitles && t.header != nil {
+		e.titles = make([]string, 0, len(t.Columns)+1)
+		if options.DepthColumn {
+			title := options.DepthColumnTitle
+			if title == "" {
+				title = i18n.Text("Depth")
+			}
+			e.titles = append(e.titles, title)
+		}
+		for c := range t.Columns {
+			var title string
+			if c < len(t.header.ColumnHeaders) {
+				title = AccessibleText(t.header.ColumnHeaders[c])
+			}
+			e.titles = append(e.titles, title)
+		}
+	}
+	for r := range t.rowCount() {
+		if options.SelectedOnly && !t.IsRowSelected(r) {
+			continue
+		}
+		row, ok := t.rowAt(r)
+		group := t.groupAt(r)
+		if !ok && group == nil {
+			continue
+		}
+		depth := t.rowDepth(r)
+		cells := make([]string, 0, len(t.Columns)+1)
+		if options.DepthColumn {
+			cells = append(cells, strconv.Itoa(depth))
+		}
+		for c := range t.Columns {
+			var text string
+			if group != nil {
+				text = t.groupCellText(group, c)
+			} else if format, exists := options.Formatters[t.Columns[c].ID]; exists && format != nil {
+				text = format(row)
+			} else if textRow, isTextRow := any(row).(TableCellTextRow); isTextRow {
+				text = textRow.CellText(c)
+			} else {
+				text = row.CellDataForSort(c)
+			}
+			cells = append(cells, text)
+		}
+		e.rows = append(e.rows, cells)
+		e.depths = append(e.depths, depth)
+	}
+	if t.hasFooter() && !options.SelectedOnly {
+		cells := make([]string, 0, len(t.Columns)+1)
+		if options.DepthColumn {
+			cells = append(cells, "0")
+		}
+		for c := range t.Columns {
+			cells = append(cells, t.groupCellText(t.total, c))
+		}
+		e.rows = append(e.rows, cells)
+		e.depths = append(e.depths, 0)
+	}
+	return e
+}
+
+// plainIndent returns the indentation for the cell as plain text.
+func (e *tableExport) plainIndent(row, col int) string {
+	if col != e.hierarchy || e.depths[row] == 0 {
+		return ""
+	}
+	return strings.Repeat(e.indent, e.depths[row])
+}
+
+// markupIndent returns the indentation for the cell for use in HTML or Markdown, where runs of spaces would otherwise
+// be collapsed.
+func (e *tableExport) markupIndent(row, col int) string {
+	return strings.ReplaceAll(html.EscapeString(e.plainIndent(row, col)), " ", "&nbsp;")
+}
+
+func (e *tableExport) writeDelimited(w io.Writer, delimiter rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+	cw.UseCRLF = true
+	if e.titles != nil {
+		if err := cw.Write(e.titles); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	for r, row := range e.rows {
+		if e.hierarchy != -1 {
+			row[e.hierarchy] = e.plainIndent(r, e.hierarchy) + row[e.hierarchy]
+		}
+		if err := cw.Write(row); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+func writeExport(w io.Writer, text string) error {
+	if _, err := io.WriteString(w, text); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
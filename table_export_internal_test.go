@@ -0,0 +1,86 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func newExportTestTable() *Table[*sortTestRow] {
+	parent := &sortTestRow{id: "p", name: "Fruit, fresh", size: 3}
+	parent.SetChildren([]*sortTestRow{{id: "x", name: `Apple "red"`, size: 1, parent: parent}})
+	table, _ := newSortTestTable(&SimpleTableModel[*sortTestRow]{}, parent, &sortTestRow{id: "q", name: "Nut|s", size: 2})
+	table.HierarchyColumnID = 1
+	return table
+}
+
+func exportTestSizes() map[int]func(row *sortTestRow) string {
+	return map[int]func(row *sortTestRow) string{
+		2: func(row *sortTestRow) string { return strconv.Itoa(row.size) + " kg" },
+	}
+}
+
+func TestTableExportDelimited(t *testing.T) {
+	c := check.New(t)
+	table := newExportTestTable()
+	var buffer strings.Builder
+	c.NoError(table.ExportCSV(&buffer, &TableExportOptions[*sortTestRow]{Formatters: exportTestSizes()}))
+	c.Equal("Name,Size\r\n\"Fruit, fresh\",3 kg\r\n\"  Apple \"\"red\"\"\",1 kg\r\nNut|s,2 kg\r\n", buffer.String())
+
+	buffer.Reset()
+	c.NoError(table.ExportTSV(&buffer, &TableExportOptions[*sortTestRow]{DepthColumn: true, OmitTitles: true}))
+	c.Equal("0\tFruit, fresh\t\r\n1\t\"Apple \"\"red\"\"\"\t\r\n0\tNut|s\t\r\n", buffer.String(),
+		"without a formatter, the size column uses the empty text from CellDataForSort()")
+
+	table.SelectByIndex(1)
+	buffer.Reset()
+	c.NoError(table.ExportCSV(&buffer, &TableExportOptions[*sortTestRow]{SelectedOnly: true, OmitTitles: true}))
+	c.Equal("\"  Apple \"\"red\"\"\",\r\n", buffer.String())
+
+	table.ApplyFilter(func(row *sortTestRow) bool { return row.id == "p" })
+	buffer.Reset()
+	c.NoError(table.ExportCSV(&buffer, &TableExportOptions[*sortTestRow]{OmitTitles: true, Indent: "-"}))
+	c.Equal("\"Apple \"\"red\"\"\",\r\nNut|s,\r\n", buffer.String(), "filtered rows are exported without hierarchy")
+}
+
+func TestTableExportMarkup(t *testing.T) {
+	c := check.New(t)
+	table := newExportTestTable()
+	var buffer strings.Builder
+	c.NoError(table.ExportHTML(&buffer, &TableExportOptions[*sortTestRow]{Formatters: exportTestSizes()}))
+	c.Equal(`<table>
+<thead>
+<tr><th>Name</th><th>Size</th></tr>
+</thead>
+<tbody>
+<tr><td>Fruit, fresh</td><td>3 kg</td></tr>
+<tr><td>&nbsp;&nbsp;Apple &#34;red&#34;</td><td>1 kg</td></tr>
+<tr><td>Nut|s</td><td>2 kg</td></tr>
+</tbody>
+</table>
+`, buffer.String())
+
+	buffer.Reset()
+	c.NoError(table.ExportMarkdown(&buffer, &TableExportOptions[*sortTestRow]{Formatters: exportTestSizes()}))
+	c.Equal(`| Name | Size |
+| --- | --- |
+| Fruit, fresh | 3 kg |
+| &nbsp;&nbsp;Apple "red" | 1 kg |
+| Nut\|s | 2 kg |
+`, buffer.String())
+
+	buffer.Reset()
+	c.NoError(table.ExportMarkdown(&buffer, &TableExportOptions[*sortTestRow]{OmitTitles: true, SelectedOnly: true}))
+	c.Equal("|  |  |\n| --- | --- |\n", buffer.String(), "markdown tables always have a title row")
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// tableQuickFilter holds the state of a Field attached to a Table with AttachQuickFilter().
+type tableQuickFilter struct {
+	field            *Field
+	previousModified func(before, after *FieldState)
+	columnIDs        []int
+	terms            [][]rune
+}
+
+// AttachQuickFilter attaches a Field whose text filters the rows of the table as it is typed. For a row to remain
+// visible, each word of the text must fuzzy match the text of at least one of the columns with the given IDs, or of
+// any column if no IDs are given. A word fuzzy matches when its characters appear within the text in the same order,
+// ignoring case. The text of a column is obtained from CellText() if the row implements TableCellTextRow, or
+// CellDataForSort() if not. The portions of the text of any Labels within the cells that match are highlighted with the
+// SearchMatchInk. Any quick filter that was already attached is detached first. As with ApplyFilter(), the filter is
+// ignored for a VirtualTableModel.
+func (t *Table[T]) AttachQuickFilter(field *Field, columnIDs ...int) {
+	t.DetachQuickFilter()
+	qf := &tableQuickFilter{
+		field:            field,
+		previousModified: field.ModifiedCallback,
+		columnIDs:        slices.Clone(columnIDs),
+	}
+	t.quickFilter = qf
+	field.ModifiedCallback = func(before, after *FieldState) {
+		if qf.previousModified != nil {
+			qf.previousModified(before, after)
+		}
+		if t.quickFilter == qf {
+			t.applyQuickFilter(field.Text())
+		}
+	}
+	t.applyQuickFilter(field.Text())
+}
+
+// DetachQuickFilter detaches the Field attached with AttachQuickFilter(), restoring its original ModifiedCallback and
+// removing the filter.
+func (t *Table[T]) DetachQuickFilter() {
+	qf := t.quickFilter
+	if qf == nil {
+		return
+	}
+	t.quickFilter = nil
+	qf.field.ModifiedCallback = qf.previousModified
+	t.ApplyFilter(nil)
+	t.MarkForRedraw()
+}
+
+func (t *Table[T]) applyQuickFilter(text string) {
+	qf := t.quickFilter
+	qf.terms = qf.terms[:0]
+	for _, word := range strings.Fields(text) {
+		qf.terms = append(qf.terms, []rune(word))
+	}
+	if len(qf.terms) == 0 {
+		t.ApplyFilter(nil)
+	} else {
+		t.ApplyFilter(func(row T) bool { return !t.matchesQuickFilter(row) })
+	}
+	t.MarkForRedraw()
+}
+
+// matchesQuickFilter returns true if each term of the quick filter matches at least one of its columns of the row.
+func (t *Table[T]) matchesQuickFilter(row T) bool {
+	texts := make([][]rune, 0, len(t.Columns))
+	for c := range t.Columns {
+		if t.isQuickFilterColumn(c) {
+			texts = append(texts, []rune(t.searchText(row, c)))
+		}
+	}
+	for _, term := range t.quickFilter.terms {
+		if !slices.ContainsFunc(texts, func(text []rune) bool { return fuzzyMatch(text, term) != nil }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Table[T]) isQuickFilterColumn(col int) bool {
+	qf := t.quickFilter
+	return qf != nil && (len(qf.columnIDs) == 0 || slices.Contains(qf.columnIDs, t.Columns[col].ID))
+}
+
+// searchText returns the text of the row's column for the purpose of searching.
+func (t *Table[T]) searchText(row T, col int) string {
+	if textRow, ok := any(row).(TableCellTextRow); ok {
+		return textRow.CellText(col)
+	}
+	return row.CellDataForSort(col)
+}
+
+// highlightQuickFilterMatches highlights the portions of the text of the Labels within the cell that match the quick
+// filter, returning a function that restores their original text, or nil if there was nothing to highlight.
+func (t *Table[T]) highlightQuickFilterMatches(cell *Panel, col int) func() {
+	if !t.isQuickFilterColumn(col) || len(t.quickFilter.terms) == 0 {
+		return nil
+	}
+	var labels []*Label
+	var original []*Text
+	var highlight func(p *Panel)
+	highlight = func(p *Panel) {
+		if label, ok := p.Self.(*Label); ok && !label.Text.Empty() {
+			var ranges [][2]int
+			for _, term := range t.quickFilter.terms {
+				ranges = append(ranges, fuzzyMatch(label.Text.Runes(), term)...)
+			}
+			if len(ranges) != 0 {
+				labels = append(labels, label)
+				original = append(original, label.Text)
+				label.Text = label.Text.withBackground(ranges, t.SearchMatchInk)
+			}
+		}
+		for _, child := range p.children {
+			highlight(child)
+		}
+	}
+	highlight(cell)
+	if len(labels) == 0 {
+		return nil
+	}
+	return func() {
+		for i, label := range labels {
+			label.Text = original[i]
+		}
+	}
+}
+
+// fuzzyMatch returns the ranges of the runes within the text that match the term, or nil if it doesn't match. The term
+// matches if its runes appear within the text in the same order, ignoring case. An occurrence of the term as a whole is
+// preferred over one whose runes are spread apart.
+func fuzzyMatch(text, term []rune) [][2]int {
+	if len(term) == 0 {
+		return nil
+	}
+	for i := 0; i+len(term) <= len(text); i++ {
+		if hasPrefixFold(text[i:], term) {
+			return [][2]int{{i, i + len(term)}}
+		}
+	}
+	var ranges [][2]int
+	j := 0
+	for i, r := range text {
+		if j < len(term) && equalFoldRune(r, term[j]) {
+			if n := len(ranges); n != 0 && ranges[n-1][1] == i {
+				ranges[n-1][1]++
+			} else {
+				ranges = append(ranges, [2]int{i, i + 1})
+			}
+			j++
+		}
+	}
+	if j < len(term) {
+		return nil
+	}
+	return ranges
+}
+
+// selectTypedRow selects the next row whose text in the primary sort column, or the hierarchy column if the rows
+// aren't sorted, begins with the runes typed in quick succession.
+func (t *Table[T]) selectTypedRow(ch rune) bool {
+	if unicode.IsSpace(ch) && !t.typeToSelect.active(t.TypeToSelectTimeout) {
+		return false
+	}
+	col := t.typeToSelectColumn()
+	if col == -1 {
+		return false
+	}
+	current := t.FirstSelectedRowIndex()
+	if t.CellSelection {
+		current, _ = t.FocusedCell()
+	}
+	index := t.typeToSelect.find(ch, t.TypeToSelectTimeout, t.rowCount(), current, func(index int) string {
+		if row, ok := t.rowAt(index); ok {
+			return t.searchText(row, col)
+		}
+		return ""
+	})
+	if index != -1 {
+		if t.CellSelection {
+			_, focusedCol := t.FocusedCell()
+			t.SelectCell(index, max(focusedCol, 0))
+		} else {
+			t.ClearSelection()
+			t.SelectByIndex(index)
+		}
+		t.ScrollRowCellIntoView(index, col)
+	}
+	return true
+}
+
+// typeToSelectColumn returns the index of the column used to select rows by typing, or -1 if there are no columns.
+func (t *Table[T]) typeToSelectColumn() int {
+	if t.header != nil {
+		if keys := t.header.SortKeys(); len(keys) != 0 && keys[0].Column < len(t.Columns) {
+			return keys[0].Column
+		}
+	}
+	if len(t.Columns) == 0 {
+		return -1
+	}
+	for c := range t.Columns {
+		if t.Columns[c].ID == t.HierarchyColumnID {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+// newFruitTable builds a table whose first column holds the names of fruit.
+func newFruitTable(t *testing.T) *Table[*gridRow] {
+	table, _, rows := newGridTable(t)
+	for i, name := range []string{"apple", "Apricot", "banana", "cherry"} {
+		rows[i].text[0] = name
+	}
+	table.TypeToSelectTimeout = time.Hour
+	return table
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	c := check.New(t)
+	c.Equal([][2]int{{2, 5}}, fuzzyMatch([]rune("Blueberry"), []rune("UEB")))
+	c.Equal([][2]int{{0, 1}, {2, 4}, {8, 9}}, fuzzyMatch([]rune("Blueberry"), []rune("buey")))
+	c.Equal([][2]int{{0, 2}}, fuzzyMatch([]rune("bubble"), []rune("bu")), "the first whole occurrence is preferred")
+	c.Nil(fuzzyMatch([]rune("Blueberry"), []rune("yb")))
+	c.Nil(fuzzyMatch([]rune("Blueberry"), nil))
+}
+
+func TestTableTypeToSelect(t *testing.T) {
+	c := check.New(t)
+	table := newFruitTable(t)
+	c.False(table.DefaultRuneTyped(' '), "a space doesn't begin a search")
+	c.True(table.DefaultRuneTyped('a'))
+	row, col := table.FocusedCell()
+	c.Equal(0, row)
+	c.Equal(0, col)
+	c.True(table.DefaultRuneTyped('p'))
+	row, _ = table.FocusedCell()
+	c.Equal(0, row, "the current row remains selected while it matches")
+	c.True(table.DefaultRuneTyped('R'))
+	row, _ = table.FocusedCell()
+	c.Equal(1, row, "matching ignores case")
+
+	table.typeToSelect.last = time.Now().Add(-2 * time.Hour)
+	c.True(table.DefaultRuneTyped('a'))
+	row, _ = table.FocusedCell()
+	c.Equal(0, row, "once the timeout passes, a new search begins with the row after the current one")
+	c.True(table.DefaultRuneTyped('a'))
+	row, _ = table.FocusedCell()
+	c.Equal(1, row, "typing the same rune again moves to the next match")
+
+	table.CellSelection = false
+	table.typeToSelect.last = time.Time{}
+	c.True(table.DefaultRuneTyped('c'))
+	c.Equal(3, table.FirstSelectedRowIndex())
+	c.Equal(1, table.SelectionCount())
+	c.True(table.DefaultRuneTyped('z'))
+	c.Equal(3, table.FirstSelectedRowIndex(), "the selection is left alone when nothing matches")
+}
+
+func TestTableQuickFilter(t *testing.T) {
+	c := check.New(t)
+	table := newFruitTable(t)
+	field := NewField()
+	var modified int
+	field.ModifiedCallback = func(_, _ *FieldState) { modified++ }
+	table.AttachQuickFilter(field, 0)
+
+	field.SetText("ap")
+	c.Equal(2, table.rowCount())
+	c.Equal(1, modified, "the field's own callback is still called")
+	field.SetText("bnn")
+	c.Equal(1, table.rowCount())
+	c.Equal("banana", table.rowCache[0].row.text[0])
+	field.SetText("ap cot")
+	c.Equal(1, table.rowCount(), "every word must match")
+	c.Equal("Apricot", table.rowCache[0].row.text[0])
+	field.SetText("b1")
+	c.Equal(0, table.rowCount(), "only the chosen columns are matched")
+
+	field.SetText("aple")
+	cell := NewPanel()
+	label := NewLabel()
+	label.SetTitle("apple")
+	cell.AddChild(label)
+	original := label.Text
+	c.Nil(table.highlightQuickFilterMatches(cell, 1), "columns that aren't filtered aren't highlighted")
+	restore := table.highlightQuickFilterMatches(cell, 0)
+	c.NotNil(restore)
+	highlighted := make([]bool, 5)
+	for i, d := range label.Text.decorations {
+		highlighted[i] = d.BackgroundInk == table.SearchMatchInk
+	}
+	c.Equal([]bool{true, true, false, true, true}, highlighted)
+	restore()
+	c.True(label.Text == original)
+
+	field.SetText("")
+	c.Equal(4, table.rowCount())
+	field.SetText("cherry")
+	table.DetachQuickFilter()
+	c.Equal(4, table.rowCount())
+	field.SetText("apple")
+	c.Equal(4, table.rowCount(), "a detached field no longer filters")
+	c.Equal(8, modified)
+}
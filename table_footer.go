@@ -0,0 +1,130 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// hasFooter returns true if the total row is being shown below the other rows.
+func (t *Table[T]) hasFooter() bool {
+	return t.totalRowVisible && t.total != nil
+}
+
+// syncFooterHeight updates the cached height of the footer row.
+func (t *Table[T]) syncFooterHeight() {
+	if t.hasFooter() {
+		var zero T
+		t.footerHeight = t.heightForColumns(zero, t.total, -1, 0)
+	} else {
+		t.footerHeight = 0
+	}
+}
+
+// footerExtent returns the height of the footer row, including the divider above it, or 0 if there isn't one.
+func (t *Table[T]) footerExtent() float32 {
+	if !t.hasFooter() {
+		return 0
+	}
+	return t.footerHeight + 1
+}
+
+// footerRect returns the area occupied by the footer row and the divider above it. This is normally at the bottom of
+// the table, but is moved up to the bottom of the parent's visible area when the table extends beyond it.
+func (t *Table[T]) footerRect() geom.Rect {
+	extent := t.footerExtent()
+	if extent == 0 {
+		return geom.Rect{}
+	}
+	var insets geom.Insets
+	if border := t.Border(); border != nil {
+		insets = border.Insets()
+	}
+	frame := t.FrameRect()
+	bottom := frame.Height - insets.Bottom
+	if p := t.Parent(); p != nil {
+		bottom = min(bottom, p.FrameRect().Height-frame.Y-insets.Bottom)
+	}
+	return geom.NewRect(insets.Left, max(bottom-extent, insets.Top), frame.Width-(insets.Left+insets.Right), extent)
+}
+
+// overFooter returns true if the y coordinate is over the footer row.
+func (t *Table[T]) overFooter(y float32) bool {
+	if !t.hasFooter() {
+		return false
+	}
+	rect := t.footerRect()
+	return y >= rect.Y && y < rect.Bottom()
+}
+
+// drawFooter draws the footer row over the top of any rows it hides.
+func (t *Table[T]) drawFooter(canvas *Canvas, dirty geom.Rect) {
+	rect := t.footerRect()
+	if rect.Empty() || !rect.Intersects(dirty) {
+		return
+	}
+	// Discard any hit rects that are now hidden beneath the footer
+	t.hitRects = slices.DeleteFunc(t.hitRects, func(one tableHitRect) bool { return one.Rect.Intersects(rect) })
+	canvas.DrawRect(rect, t.BackgroundInk.Paint(canvas, rect, paintstyle.Fill))
+	divider := rect
+	divider.Height = 1
+	canvas.DrawRect(divider, t.InteriorDividerInk.Paint(canvas, divider, paintstyle.Fill))
+	rect.Y++
+	rect.Height--
+	frames := make([]geom.Rect, len(t.Columns))
+	x := rect.X + t.leadingColumnDividerWidth()
+	for c := range t.Columns {
+		frames[c] = geom.NewRect(x+t.frozenShift(-1, c).X, rect.Y, t.Columns[c].Current, rect.Height)
+		x += t.Columns[c].Current
+		if t.ShowColumnDivider && (t.ShowLastColumnDivider || c < len(t.Columns)-1) {
+			x++
+		}
+	}
+	// The scrolling columns are drawn first so that the frozen columns are drawn over the top of them
+	frozen := t.frozenColumnCount()
+	for c := frozen; c < len(t.Columns); c++ {
+		t.drawFooterCell(canvas, frames[c], c)
+	}
+	for c := range frozen {
+		t.drawFooterCell(canvas, frames[c], c)
+	}
+}
+
+// drawFooterCell draws the cell of the footer row for the column within the frame, followed by its trailing divider.
+func (t *Table[T]) drawFooterCell(canvas *Canvas, frame geom.Rect, col int) {
+	if frame.Width <= 0 {
+		return
+	}
+	canvas.DrawRect(frame, t.BackgroundInk.Paint(canvas, frame, paintstyle.Fill))
+	if t.ShowColumnDivider && (t.ShowLastColumnDivider || col < len(t.Columns)-1) {
+		divider := geom.NewRect(frame.Right(), frame.Y, 1, frame.Height)
+		canvas.DrawRect(divider, t.InteriorDividerInk.Paint(canvas, divider, paintstyle.Fill))
+	}
+	cellRect := frame.Inset(t.Padding)
+	if t.Columns[col].ID == t.HierarchyColumnID {
+		if hierarchyIndent := t.CurrentHierarchyIndent(); hierarchyIndent > 0 {
+			indent := hierarchyIndent + t.Padding.Left
+			cellRect.X += indent
+			cellRect.Width -= indent
+		}
+	}
+	cell := t.groupCell(t.total, col, t.OnBackgroundInk)
+	t.installCell(cell, cellRect)
+	canvas.Save()
+	canvas.Translate(cellRect.Point)
+	cellRect.X = 0
+	cellRect.Y = 0
+	cell.Draw(canvas, cellRect)
+	t.uninstallCell(cell)
+	canvas.Restore()
+}
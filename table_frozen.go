@@ -0,0 +1,176 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/pathop"
+)
+
+// frozenColumnCount returns the number of leading columns that are frozen.
+func (t *Table[T]) frozenColumnCount() int {
+	return min(max(t.FrozenColumns, 0), len(t.Columns))
+}
+
+// frozenRowCount returns the number of leading rows that are frozen.
+func (t *Table[T]) frozenRowCount() int {
+	return min(max(t.FrozenRows, 0), t.rowCount())
+}
+
+// frozenOffset returns the distance the frozen columns and rows must be shifted to remain in view, which is how far the
+// table has been scrolled within its parent, normally the content view of a ScrollPanel.
+func (t *Table[T]) frozenOffset() geom.Point {
+	var offset geom.Point
+	if t.Parent() == nil {
+		return offset
+	}
+	frame := t.FrameRect()
+	if t.frozenColumnCount() > 0 {
+		offset.X = max(-frame.X, 0)
+	}
+	if t.frozenRowCount() > 0 {
+		offset.Y = max(-frame.Y, 0)
+	}
+	return offset
+}
+
+// frozenShift returns the amount the cell is shifted by being within the frozen columns or rows. Pass -1 for the row or
+// column to ignore that axis.
+func (t *Table[T]) frozenShift(row, col int) geom.Point {
+	if t.FrozenColumns <= 0 && t.FrozenRows <= 0 {
+		return geom.Point{}
+	}
+	offset := t.frozenOffset()
+	if col < 0 || col >= t.frozenColumnCount() {
+		offset.X = 0
+	}
+	if row < 0 || row >= t.frozenRowCount() {
+		offset.Y = 0
+	}
+	return offset
+}
+
+// frozenRight returns the x-coordinate just past the frozen columns, ignoring any shift applied to keep them in view.
+func (t *Table[T]) frozenRight() float32 {
+	n := t.frozenColumnCount()
+	if n == 0 {
+		return 0
+	}
+	var insets geom.Insets
+	if border := t.Border(); border != nil {
+		insets = border.Insets()
+	}
+	x := insets.Left + t.leadingColumnDividerWidth()
+	for c := range n {
+		x += t.Columns[c].Current
+		if t.ShowColumnDivider && (t.ShowLastColumnDivider || c < len(t.Columns)-1) {
+			x++
+		}
+	}
+	return x
+}
+
+// frozenBottom returns the y-coordinate just past the frozen rows, ignoring any shift applied to keep them in view.
+func (t *Table[T]) frozenBottom() float32 {
+	n := t.frozenRowCount()
+	if n == 0 {
+		return 0
+	}
+	var insets geom.Insets
+	if border := t.Border(); border != nil {
+		insets = border.Insets()
+	}
+	return insets.Top + t.rowTop(n)
+}
+
+// unfreezeX converts an x-coordinate over the frozen columns to the position it would have had if they had not been
+// shifted to remain in view.
+func (t *Table[T]) unfreezeX(x float32) float32 {
+	if dx := t.frozenOffset().X; dx > 0 && x < dx+t.frozenRight() {
+		return x - dx
+	}
+	return x
+}
+
+// unfreezeY converts a y-coordinate over the frozen rows to the position it would have had if they had not been shifted
+// to remain in view.
+func (t *Table[T]) unfreezeY(y float32) float32 {
+	if dy := t.frozenOffset().Y; dy > 0 && y < dy+t.frozenBottom() {
+		return y - dy
+	}
+	return y
+}
+
+// expandForFrozen expands the frame of a cell that scrolls so that scrolling it into view also leaves it clear of the
+// frozen columns and rows, as well as the footer row, drawn over the top of it. Pass -1 for the row or column to ignore
+// that axis.
+func (t *Table[T]) expandForFrozen(rect geom.Rect, row, col int) geom.Rect {
+	if n := t.frozenColumnCount(); n > 0 && col >= n {
+		width := t.frozenRight()
+		rect.X -= width
+		rect.Width += width
+	}
+	if n := t.frozenRowCount(); n > 0 && row >= n {
+		height := t.frozenBottom()
+		rect.Y -= height
+		rect.Height += height
+	}
+	if row >= 0 {
+		rect.Height += t.footerExtent()
+	}
+	return rect
+}
+
+// drawFrozenRegions draws the frozen columns and rows over the top of the scrolled content, shifted so that they remain
+// in view, followed by a divider along the edge of each.
+func (t *Table[T]) drawFrozenRegions(canvas *Canvas, dirty geom.Rect) {
+	offset := t.frozenOffset()
+	if offset.X <= 0 && offset.Y <= 0 {
+		return
+	}
+	right := t.frozenRight()
+	bottom := t.frozenBottom()
+	if offset.X > 0 {
+		t.drawFrozenRegion(canvas, geom.NewRect(offset.X, dirty.Y, right, dirty.Height).Intersect(dirty),
+			geom.NewPoint(offset.X, 0))
+	}
+	if offset.Y > 0 {
+		t.drawFrozenRegion(canvas, geom.NewRect(dirty.X, offset.Y, dirty.Width, bottom).Intersect(dirty),
+			geom.NewPoint(0, offset.Y))
+		if offset.X > 0 {
+			t.drawFrozenRegion(canvas, geom.NewRect(offset.X, offset.Y, right, bottom).Intersect(dirty), offset)
+		}
+	}
+	paint := t.InteriorDividerInk.Paint(canvas, dirty, paintstyle.Fill)
+	if offset.X > 0 {
+		canvas.DrawRect(geom.NewRect(offset.X+right-1, dirty.Y, 1, dirty.Height), paint)
+	}
+	if offset.Y > 0 {
+		canvas.DrawRect(geom.NewRect(dirty.X, offset.Y+bottom-1, dirty.Width, 1), paint)
+	}
+}
+
+// drawFrozenRegion draws the portion of the table that appears within the region once shifted by the offset.
+func (t *Table[T]) drawFrozenRegion(canvas *Canvas, region geom.Rect, offset geom.Point) {
+	if region.Empty() {
+		return
+	}
+	// Discard any hit rects that are now hidden beneath the region
+	t.hitRects = slices.DeleteFunc(t.hitRects, func(one tableHitRect) bool { return one.Rect.Intersects(region) })
+	canvas.Save()
+	canvas.ClipRect(region, pathop.Intersect, false)
+	canvas.Translate(offset)
+	region.Point = region.Point.Sub(offset)
+	t.drawRegion(canvas, region, offset)
+	canvas.Restore()
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+func TestTableFrozenColumnsAndRows(t *testing.T) {
+	c := check.New(t)
+	table, _, _ := newGridTable(t)
+	table.ShowColumnDivider = false
+	table.FrozenColumns = 1
+	table.FrozenRows = 1
+	corner := table.CellFrame(0, 0)
+	left := table.CellFrame(3, 0)
+	top := table.CellFrame(0, 2)
+	scrolling := table.CellFrame(3, 2)
+	rowHeight := table.RowFrame(1).Y - table.RowFrame(0).Y
+
+	// Scroll so that the first two rows and half of the second column are out of view
+	table.SetFrameRect(geom.NewRect(-150, -rowHeight*2, 300, rowHeight*4))
+	c.Equal(corner.Point.Add(geom.NewPoint(150, rowHeight*2)), table.CellFrame(0, 0).Point)
+	c.Equal(left.Point.Add(geom.NewPoint(150, 0)), table.CellFrame(3, 0).Point)
+	c.Equal(top.Point.Add(geom.NewPoint(0, rowHeight*2)), table.CellFrame(0, 2).Point)
+	c.Equal(scrolling, table.CellFrame(3, 2))
+	c.Equal(table.CellFrame(0, 1).Y, table.RowFrame(0).Y+table.Padding.Top)
+
+	c.Equal(0, table.OverColumn(160), "the frozen column hides those beneath it")
+	c.Equal(2, table.OverColumn(260))
+	c.Equal(0, table.OverRow(rowHeight*2+1))
+	c.Equal(3, table.OverRow(rowHeight*3+1))
+	c.Equal(0, table.OverColumnDivider(252), "the frozen divider can be found from the scrolling side")
+	c.Equal(-1, table.OverColumnDivider(200), "dividers beneath the frozen column are hidden")
+	c.Equal(2, table.OverColumnDivider(300))
+
+	expanded := table.expandForFrozen(scrolling, 3, 2)
+	c.Equal(scrolling.X-100, expanded.X, "cells are scrolled clear of the frozen columns")
+	c.Equal(scrolling.Y-rowHeight, expanded.Y, "cells are scrolled clear of the frozen rows")
+	c.Equal(table.CellFrame(0, 0), table.expandForFrozen(table.CellFrame(0, 0), 0, 0))
+
+	frame := table.CellFrame(3, 0)
+	c.True(table.DefaultMouseDown(frame.Center(), ButtonLeft, 1, 0))
+	table.DefaultMouseUp(frame.Center(), ButtonLeft, 0)
+	row, col := table.FocusedCell()
+	c.Equal(3, row)
+	c.Equal(0, col)
+
+	table.FrozenColumns = 0
+	table.FrozenRows = 0
+	c.Equal(scrolling, table.CellFrame(3, 2))
+	c.Equal(1, table.OverColumn(160))
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/unison/enums/aggregate"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+// tableGroup is a summary row synthesized by a Table: either the group row for the top-level rows sharing a value in
+// the column being grouped on, or the total of all top-level rows.
+type tableGroup[T TableRowConstraint[T]] struct {
+	id    tid.TID
+	key   string
+	rows  []T
+	total bool
+}
+
+// GroupColumn returns the column index the rows are being grouped on, or -1 if they aren't grouped.
+func (t *Table[T]) GroupColumn() int {
+	return t.groupColumn
+}
+
+// SetGroupColumn sets the column index to group the top-level rows on, or -1 to stop grouping them. A collapsible
+// group row is synthesized for each distinct text returned by the CellDataForSort() method of the rows for the column,
+// holding the rows that share it in the order they appear, so sorting on the column also orders the groups. Group rows
+// may be selected, but are never returned by SelectedRows() or RowFromIndex(); use GroupAt() to examine them. Grouping
+// is not available for a VirtualTableModel. This will call SyncToModel() automatically.
+func (t *Table[T]) SetGroupColumn(col int) {
+	t.groupColumn = max(col, -1)
+	t.SyncToModel()
+}
+
+// Aggregate returns the aggregate that summarizes the column index in the group rows and total row, if any.
+func (t *Table[T]) Aggregate(col int) (kind aggregate.Enum, exists bool) {
+	kind, exists = t.aggregates[col]
+	return kind, exists
+}
+
+// SetAggregate sets the aggregate that summarizes the column index in the group rows and total row. Values are taken
+// from the CellSortValue() method of rows that implement TableSortValueRow, or parsed from the text returned by
+// CellDataForSort() if not. Cells with values that aren't numeric are skipped, other than when counting. This will
+// call SyncToModel() automatically.
+func (t *Table[T]) SetAggregate(col int, kind aggregate.Enum) {
+	if t.aggregates == nil {
+		t.aggregates = make(map[int]aggregate.Enum)
+	}
+	t.aggregates[col] = kind.EnsureValid()
+	t.SyncToModel()
+}
+
+// ClearAggregate removes the aggregate for the column index, if any. This will call SyncToModel() automatically.
+func (t *Table[T]) ClearAggregate(col int) {
+	delete(t.aggregates, col)
+	t.SyncToModel()
+}
+
+// TotalRowVisible returns true if the total row is shown below the other rows.
+func (t *Table[T]) TotalRowVisible() bool {
+	return t.totalRowVisible
+}
+
+// SetTotalRowVisible sets whether a row summarizing all of the top-level rows with the aggregates set for the columns
+// is shown below the other rows. When the table is within a ScrollPanel, the total row remains pinned to the bottom of
+// the visible area. It cannot be selected and is not moved by sorting or filtering. The total row is not available for
+// a VirtualTableModel. This will call SyncToModel() automatically.
+func (t *Table[T]) SetTotalRowVisible(visible bool) {
+	t.totalRowVisible = visible
+	t.SyncToModel()
+}
+
+// GroupAt returns the value shared by the rows of the group row at the index, along with those rows, or false if the
+// row at the index isn't a group row. Do not alter the returned list.
+func (t *Table[T]) GroupAt(index int) (key string, rows []T, ok bool) {
+	if group := t.groupAt(index); group != nil {
+		return group.key, group.rows, true
+	}
+	return "", nil, false
+}
+
+// AggregateValue returns the value of the aggregate for the column index within the group row at the index, or within
+// the total of all top-level rows if the index is -1. Returns false if there is no such row, no aggregate has been set
+// for the column, or none of the summarized rows has a numeric value in it.
+func (t *Table[T]) AggregateValue(index, col int) (value float64, ok bool) {
+	group := t.total
+	if index != -1 {
+		group = t.groupAt(index)
+	}
+	if group == nil {
+		return 0, false
+	}
+	return t.aggregateValue(group, col)
+}
+
+// groupAt returns the group row at the index, or nil if the row at the index isn't a group row.
+func (t *Table[T]) groupAt(index int) *tableGroup[T] {
+	if t.virtual != nil || index < 0 || index >= len(t.rowCache) {
+		return nil
+	}
+	return t.rowCache[index].group
+}
+
+// syncGroups rebuilds the total row and group rows from the top-level rows. The ID and open state of a group row are
+// retained for as long as rows with its value exist.
+func (t *Table[T]) syncGroups(roots []T) {
+	t.total = &tableGroup[T]{rows: roots, total: true}
+	t.groups = nil
+	if t.groupColumn < 0 {
+		return
+	}
+	if t.groupIDs == nil {
+		t.groupIDs = make(map[string]tid.TID)
+	}
+	groups := make(map[string]*tableGroup[T])
+	for _, row := range roots {
+		key := row.CellDataForSort(t.groupColumn)
+		group, exists := groups[key]
+		if !exists {
+			id, known := t.groupIDs[key]
+			if !known {
+				id = tid.MustNewTID('g')
+				t.groupIDs[key] = id
+			}
+			group = &tableGroup[T]{id: id, key: key}
+			groups[key] = group
+			t.groups = append(t.groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+	for key := range t.groupIDs {
+		if _, exists := groups[key]; !exists {
+			delete(t.groupIDs, key)
+			delete(t.closedGroups, key)
+		}
+	}
+}
+
+func (t *Table[T]) isGroupOpen(group *tableGroup[T]) bool {
+	return !t.closedGroups[group.key]
+}
+
+func (t *Table[T]) setGroupOpen(group *tableGroup[T], open bool) {
+	if open {
+		delete(t.closedGroups, group.key)
+		return
+	}
+	if t.closedGroups == nil {
+		t.closedGroups = make(map[string]bool)
+	}
+	t.closedGroups[group.key] = true
+}
+
+// setSelectedGroupsOpen opens or closes the selected group rows, returning true if any of them were altered.
+func (t *Table[T]) setSelectedGroupsOpen(open bool) bool {
+	altered := false
+	for _, group := range t.groups {
+		if t.selMap[group.id] && t.isGroupOpen(group) != open {
+			t.setGroupOpen(group, open)
+			altered = true
+		}
+	}
+	return altered
+}
+
+func (t *Table[T]) aggregateValue(group *tableGroup[T], col int) (value float64, ok bool) {
+	kind, exists := t.aggregates[col]
+	if !exists {
+		return 0, false
+	}
+	if kind == aggregate.Count {
+		return float64(len(group.rows)), true
+	}
+	count := 0
+	for _, row := range group.rows {
+		v, isNumber := numericCellValue(row, col)
+		if !isNumber {
+			continue
+		}
+		switch {
+		case count == 0:
+			value = v
+		case kind == aggregate.Min:
+			value = min(value, v)
+		case kind == aggregate.Max:
+			value = max(value, v)
+		default:
+			value += v
+		}
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	if kind == aggregate.Average {
+		value /= float64(count)
+	}
+	return value, true
+}
+
+// groupCellText returns the text for the column of a group row or the total row. By default, counts are shown as
+// whole numbers, averages are rounded to two decimal places and other values are shown with as many digits as
+// necessary. Set FormatAggregateCallback to change this.
+func (t *Table[T]) groupCellText(group *tableGroup[T], col int) string {
+	if kind, exists := t.aggregates[col]; exists {
+		value, ok := t.aggregateValue(group, col)
+		switch {
+		case !ok:
+			return ""
+		case t.FormatAggregateCallback != nil:
+			return t.FormatAggregateCallback(col, kind, value)
+		case kind == aggregate.Average:
+			value = math.Round(value*100) / 100
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	if group.total {
+		if col == max(t.groupColumn, 0) {
+			return i18n.Text("Total")
+		}
+	} else if col == t.groupColumn {
+		return group.key
+	}
+	return ""
+}
+
+// groupCell returns the cell for the column of a group row or the total row, which is a Label with an emphasized
+// font, with aggregate values aligned to the end of the cell.
+func (t *Table[T]) groupCell(group *tableGroup[T], col int, foreground Ink) *Panel {
+	label := NewLabel()
+	label.Font = EmphasizedSystemFont
+	label.OnBackgroundInk = foreground
+	if _, exists := t.aggregates[col]; exists {
+		label.HAlign = align.End
+	}
+	label.SetTitle(t.groupCellText(group, col))
+	return label.AsPanel()
+}
+
+// cellSortValue returns the typed value of the cell if the row implements TableSortValueRow, or its text if not.
+func cellSortValue[T TableRowConstraint[T]](row T, col int) any {
+	if sortValueRow, ok := any(row).(TableSortValueRow); ok {
+		return sortValueRow.CellSortValue(col)
+	}
+	return row.CellDataForSort(col)
+}
+
+// numericCellValue returns the value of the cell as a number, if it is one or its text can be parsed as one.
+func numericCellValue[T TableRowConstraint[T]](row T, col int) (float64, bool) {
+	value := cellSortValue(row, col)
+	if value == nil {
+		return 0, false
+	}
+	if v := reflect.ValueOf(value); isNumericValue(v) {
+		return numericValue(v), true
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(fmt.Sprint(value)), ",", ""), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison/enums/aggregate"
+)
+
+func newGroupTestTable() (*Table[*sortTestRow], TableColumnHeader[*sortTestRow]) {
+	model := &SimpleTableModel[*sortTestRow]{}
+	model.SetRootRows([]*sortTestRow{
+		{id: "a", name: "beta", size: 2},
+		{id: "b", name: "alpha", size: 10},
+		{id: "c", name: "beta", size: 5},
+		{id: "d", name: "alpha", size: 3},
+		{id: "e", name: "gamma", size: 1},
+	})
+	table := NewTable[*sortTestRow](model)
+	table.Columns = []ColumnInfo{{ID: 0, Current: 100}, {ID: 1, Current: 100}}
+	nameHeader := NewTableColumnHeader[*sortTestRow]("Name", "", nil)
+	sizeHeader := NewTableColumnHeader[*sortTestRow]("Size", "", nil)
+	NewTableHeader(table, TableColumnHeader[*sortTestRow](nameHeader), sizeHeader)
+	table.SyncToModel()
+	return table, sizeHeader
+}
+
+// groupTestIDs returns the IDs of the displayed rows, with the values of group rows in brackets.
+func groupTestIDs(table *Table[*sortTestRow]) string {
+	var ids string
+	for i := range table.rowCount() {
+		if key, _, ok := table.GroupAt(i); ok {
+			ids += "[" + key + "]"
+		} else {
+			ids += string(table.RowFromIndex(i).ID())
+		}
+	}
+	return ids
+}
+
+func TestTableGrouping(t *testing.T) {
+	c := check.New(t)
+	table, _ := newGroupTestTable()
+	c.Equal(-1, table.GroupColumn())
+	c.Equal("abcde", groupTestIDs(table), "rows aren't grouped initially")
+	c.False(table.hasHierarchy)
+
+	table.SetGroupColumn(0)
+	table.SetAggregate(1, aggregate.Sum)
+	c.Equal("[beta]ac[alpha]bd[gamma]e", groupTestIDs(table))
+	c.True(table.hasHierarchy)
+	c.Equal(0, table.rowDepth(0))
+	c.Equal(1, table.rowDepth(1))
+	c.Nil(table.RowFromIndex(0), "group rows have no row data")
+	key, rows, ok := table.GroupAt(0)
+	c.True(ok)
+	c.Equal("beta", key)
+	c.Equal("ac", sortTestIDs(rows))
+	_, _, ok = table.GroupAt(1)
+	c.False(ok)
+	c.Equal("beta", table.cellText(0, 0))
+	c.Equal("7", table.cellText(0, 1))
+	value, ok := table.AggregateValue(0, 1)
+	c.True(ok)
+	c.Equal(7.0, value)
+	_, ok = table.AggregateValue(1, 1)
+	c.False(ok, "only group rows and the total row have aggregates")
+
+	table.SelectAll()
+	c.Equal("acbde", sortTestIDs(table.SelectedRows(false)), "group rows are never returned as selected rows")
+	c.Equal("acbde", sortTestIDs(table.SelectedRows(true)), "group rows aren't the parents of their rows")
+	table.ClearSelection()
+	table.SelectByIndex(0)
+	c.True(table.IsRowOrAnyParentSelected(0))
+	c.False(table.IsRowOrAnyParentSelected(1), "selecting a group row doesn't select its rows")
+	c.Equal(0, len(table.SelectedRows(true)))
+	table.DefaultKeyDown(KeyLeft, 0, false)
+	c.Equal("[beta][alpha]bd[gamma]e", groupTestIDs(table), "selected group rows can be closed")
+	id := table.rowID(0)
+	table.Model.SetRootRows(append(table.Model.RootRows(), &sortTestRow{id: "f", name: "beta", size: 4}))
+	table.SyncToModel()
+	c.Equal("[beta][alpha]bd[gamma]e", groupTestIDs(table), "the open state is retained when syncing")
+	c.Equal(id, table.rowID(0), "the ID is retained when syncing")
+	c.True(table.DiscloseRow(table.Model.RootRows()[5], false))
+	c.Equal("[beta]acf[alpha]bd[gamma]e", groupTestIDs(table), "disclosing a row opens its group row")
+
+	table.SetGroupColumn(-1)
+	c.Equal("abcdef", groupTestIDs(table))
+}
+
+func TestTableGroupAggregates(t *testing.T) {
+	c := check.New(t)
+	table, sizeHeader := newGroupTestTable()
+	table.SetGroupColumn(0)
+	table.SetAggregate(1, aggregate.Sum)
+	c.Equal("Total", table.groupCellText(table.total, 0))
+	c.Equal("21", table.groupCellText(table.total, 1))
+	for kind, expected := range map[aggregate.Enum]string{
+		aggregate.Count:   "5",
+		aggregate.Min:     "1",
+		aggregate.Max:     "10",
+		aggregate.Average: "4.2",
+	} {
+		table.SetAggregate(1, kind)
+		c.Equal(expected, table.groupCellText(table.total, 1), kind.String())
+	}
+	table.SetAggregate(1, aggregate.Average)
+	c.Equal("3.5", table.cellText(0, 1))
+	table.FormatAggregateCallback = func(_ int, kind aggregate.Enum, _ float64) string { return kind.Key() }
+	c.Equal("average", table.cellText(0, 1))
+	table.FormatAggregateCallback = nil
+	table.ClearAggregate(1)
+	c.Equal("", table.cellText(0, 1))
+	_, exists := table.Aggregate(1)
+	c.False(exists)
+
+	table.SetAggregate(1, aggregate.Sum)
+	table.header.SortOn(sizeHeader)
+	table.header.ApplySort()
+	c.Equal("[gamma]e[beta]ac[alpha]db", groupTestIDs(table),
+		"groups are ordered by their first row and their rows by their own values")
+	c.Equal("eadcb", sortTestIDs(table.Model.RootRows()))
+
+	var buffer bytes.Buffer
+	table.SetTotalRowVisible(true)
+	c.NoError(table.ExportTSV(&buffer, &TableExportOptions[*sortTestRow]{OmitTitles: true, DepthColumn: true}))
+	c.Equal(strings.Join([]string{
+		"0\tgamma\t1", "1\tgamma\t", "0\tbeta\t7", "1\tbeta\t", "1\tbeta\t", "0\talpha\t13", "1\talpha\t",
+		"1\talpha\t", "0\tTotal\t21", "",
+	}, "\r\n"), buffer.String(), "group rows and the total row are exported with their text")
+}
+
+func TestTableTotalRow(t *testing.T) {
+	c := check.New(t)
+	table, _ := newGroupTestTable()
+	table.SetAggregate(1, aggregate.Sum)
+	_, pref, _ := table.DefaultSizes(geom.Size{})
+	c.False(table.TotalRowVisible())
+	table.SetTotalRowVisible(true)
+	c.True(table.TotalRowVisible())
+	c.True(table.footerHeight > 0)
+	value, ok := table.AggregateValue(-1, 1)
+	c.True(ok, "the total row is available without grouping")
+	c.Equal(21.0, value)
+	_, prefWithFooter, _ := table.DefaultSizes(geom.Size{})
+	c.Equal(pref.Height+table.footerHeight+1, prefWithFooter.Height)
+	c.Equal(prefWithFooter.Height, table.footerRect().Bottom())
+	c.Equal(-1, table.OverRow(prefWithFooter.Height-1), "the total row can't be selected")
+
+	// Scroll so that the table extends beyond both ends of its parent
+	parent := NewPanel()
+	parent.AddChild(table)
+	parent.SetFrameRect(geom.NewRect(0, 0, 200, 50))
+	table.SetFrameRect(geom.NewRect(0, -10, 200, prefWithFooter.Height))
+	c.Equal(float32(60), table.footerRect().Bottom(), "the total row is pinned to the bottom of the visible area")
+	c.Equal(-1, table.OverRow(59))
+	row := table.OverRow(table.footerRect().Y - 1)
+	c.True(row >= 0)
+	frame := table.RowFrame(row)
+	c.Equal(frame.Height+table.footerHeight+1, table.expandForFrozen(frame, row, -1).Height,
+		"rows are scrolled clear of the total row")
+
+	table.SetTotalRowVisible(false)
+	c.Equal(float32(0), table.footerExtent())
+	c.Equal(geom.Rect{}, table.footerRect())
+}
@@ -10,14 +10,20 @@
 package unison
 
 import (
+	"cmp"
+	"fmt"
 	"slices"
 	"sort"
 
 	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xmath"
+	"github.com/richardwilkes/toolbox/v2/xreflect"
 	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/mod"
 	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/pathop"
 )
 
 // DefaultTableHeaderTheme holds the default TableHeaderTheme values for TableHeaders. Modifying this data will not
@@ -46,6 +52,7 @@ type TableHeader[T TableRowConstraint[T]] struct {
 	columnResizeBase     float32
 	columnResizeOverhead float32
 	inHeader             bool
+	reordering           bool
 }
 
 // NewTableHeader creates a new TableHeader.
@@ -94,6 +101,7 @@ func (h *TableHeader[T]) ColumnFrame(col int) geom.Rect {
 			x++
 		}
 	}
+	x += h.table.frozenShift(-1, col).X
 	return geom.NewRect(x, insets.Top, h.table.Columns[col].Current, h.FrameRect().Height-insets.Height()).
 		Inset(h.table.Padding)
 }
@@ -136,6 +144,37 @@ func (h *TableHeader[T]) combinedInsets() geom.Insets {
 
 // DefaultDraw provides the default drawing.
 func (h *TableHeader[T]) DefaultDraw(canvas *Canvas, dirty geom.Rect) {
+	h.drawColumns(canvas, dirty)
+	if dx := h.table.frozenOffset().X; dx > 0 {
+		// Draw the headers of the frozen columns over the top of the others, shifted to match the table
+		right := h.table.frozenRight()
+		if region := geom.NewRect(dx, dirty.Y, right, dirty.Height).Intersect(dirty); !region.Empty() {
+			canvas.Save()
+			canvas.ClipRect(region, pathop.Intersect, false)
+			canvas.Translate(geom.NewPoint(dx, 0))
+			region.X -= dx
+			h.drawColumns(canvas, region)
+			canvas.Restore()
+		}
+		rect := geom.NewRect(dx+right-1, dirty.Y, 1, dirty.Height)
+		canvas.DrawRect(rect, h.InteriorDividerColor.Paint(canvas, rect, paintstyle.Fill))
+	}
+
+	if h.reordering && h.interactionColumn >= 0 && h.interactionColumn < len(h.table.Columns) {
+		// Highlight the column being moved
+		left, right := h.table.ColumnEdges(h.interactionColumn)
+		insets := h.combinedInsets()
+		rect := geom.NewRect(left, insets.Top, right-left, h.FrameRect().Height-insets.Height())
+		ink := &ColorFilteredInk{
+			OriginalInk: h.table.SelectionInk,
+			ColorFilter: Alpha30Filter(),
+		}
+		canvas.DrawRect(rect, ink.Paint(canvas, rect, paintstyle.Fill))
+	}
+}
+
+// drawColumns draws the column headers that intersect the dirty rect.
+func (h *TableHeader[T]) drawColumns(canvas *Canvas, dirty geom.Rect) {
 	backgroundPaint := h.BackgroundInk.Paint(canvas, dirty, paintstyle.Fill)
 	canvas.DrawRect(dirty, backgroundPaint)
 
@@ -275,6 +314,11 @@ func (h *TableHeader[T]) DefaultMouseMove(where geom.Point, mods mod.Modifiers)
 func (h *TableHeader[T]) DefaultMouseDown(where geom.Point, button, clickCount int, mods mod.Modifiers) bool {
 	h.interactionColumn = -1
 	h.inHeader = false
+	h.reordering = false
+	if button == ButtonRight && clickCount == 1 && h.table.AllowUserColumnHiding {
+		// Claim the click so that the mouse up is delivered here, where the column menu will be shown.
+		return true
+	}
 	if !h.table.PreventUserColumnResize {
 		if over := h.table.OverColumnDivider(where.X); over != -1 {
 			if h.table.Columns[over].resizable() {
@@ -322,6 +366,16 @@ func (h *TableHeader[T]) DefaultMouseDown(where geom.Point, button, clickCount i
 
 // DefaultMouseDrag provides the default mouse drag handling.
 func (h *TableHeader[T]) DefaultMouseDrag(where geom.Point, _ int, _ mod.Modifiers) bool {
+	if h.inHeader && h.interactionColumn != -1 && h.table.AllowUserColumnReorder {
+		if !h.reordering && h.IsDragGesture(where) {
+			h.reordering = true
+		}
+		if h.reordering {
+			h.moveColumnTo(where.X)
+			h.MarkForRedraw()
+			return true
+		}
+	}
 	if !h.table.PreventUserColumnResize && !h.inHeader && h.interactionColumn != -1 {
 		width := h.columnResizeBase + where.X - h.columnResizeStart
 		if width < h.columnResizeOverhead {
@@ -348,6 +402,20 @@ func (h *TableHeader[T]) DefaultMouseDrag(where geom.Point, _ int, _ mod.Modifie
 
 // DefaultMouseUp provides the default mouse up handling.
 func (h *TableHeader[T]) DefaultMouseUp(where geom.Point, button int, mods mod.Modifiers) bool {
+	if button == ButtonRight && h.table.AllowUserColumnHiding {
+		if where.In(h.ContentRect(true)) {
+			h.ShowContextMenu(where)
+		}
+		return true
+	}
+	if h.reordering {
+		// The column was moved, so it shouldn't also be treated as a click on its header
+		h.reordering = false
+		h.interactionColumn = -1
+		h.inHeader = false
+		h.MarkForRedraw()
+		return true
+	}
 	stop := false
 	if h.inHeader && h.interactionColumn != -1 && h.interactionColumn < len(h.ColumnHeaders) {
 		cell := h.ColumnHeaders[h.interactionColumn].AsPanel()
@@ -361,6 +429,67 @@ func (h *TableHeader[T]) DefaultMouseUp(where geom.Point, button int, mods mod.M
 	return stop
 }
 
+// moveColumnTo moves the column being dragged past any neighbor whose center the x coordinate has crossed.
+func (h *TableHeader[T]) moveColumnTo(x float32) {
+	for {
+		col := h.interactionColumn
+		switch {
+		case col > 0 && x < h.columnCenter(col-1):
+			h.table.MoveColumn(col, col-1)
+			h.interactionColumn--
+		case col < len(h.table.Columns)-1 && x > h.columnCenter(col+1):
+			h.table.MoveColumn(col, col+1)
+			h.interactionColumn++
+		default:
+			return
+		}
+	}
+}
+
+func (h *TableHeader[T]) columnCenter(col int) float32 {
+	left, right := h.table.ColumnEdges(col)
+	return (left + right) / 2
+}
+
+// ShowContextMenu displays the column menu at the specified position, which should be in local coordinates. It lists
+// every column of the table, checking those that are visible, and choosing one hides or shows it.
+func (h *TableHeader[T]) ShowContextMenu(where geom.Point) {
+	all := h.table.allColumns()
+	if len(all) == 0 {
+		return
+	}
+	fac := DefaultMenuFactory()
+	cm := fac.NewMenu(PopupMenuTemporaryBaseID|ContextMenuIDFlag, "", nil)
+	for i, one := range all {
+		id := one.info.ID
+		item := fac.NewItem(-1, columnHeaderTitle[T](one.header, i), KeyBinding{},
+			func(MenuItem) bool { return h.table.ColumnHidden(id) || len(h.table.Columns) > 1 },
+			func(MenuItem) { h.table.SetColumnHidden(id, !h.table.ColumnHidden(id)) })
+		if !one.hidden {
+			item.SetCheckState(check.On)
+		}
+		cm.InsertItem(-1, item)
+	}
+	where = h.PointToRoot(where)
+	cm.Popup(geom.NewRect(where.X, where.Y, 1, 1), 0)
+	cm.Dispose()
+}
+
+// columnHeaderTitle returns the title to use for a column in the column menu.
+func columnHeaderTitle[T TableRowConstraint[T]](header TableColumnHeader[T], index int) string {
+	if !xreflect.IsNil(header) {
+		if title := AccessibleText(header); title != "" {
+			return title
+		}
+		if tip := header.AsPanel().Tooltip; tip != nil {
+			if title := AccessibleText(tip); title != "" {
+				return title
+			}
+		}
+	}
+	return fmt.Sprintf(i18n.Text("Column %d"), index+1)
+}
+
 // SortOn adjusts the sort such that the specified header is the primary sort column. If the header was already the
 // primary sort column, then its ascending/descending flag will be flipped instead.
 func (h *TableHeader[T]) SortOn(header TableColumnHeader[T]) {
@@ -404,6 +533,27 @@ func (h *TableHeader[T]) SortOn(header TableColumnHeader[T]) {
 	}
 }
 
+// AddSortOn adds the specified header to the end of the sort criteria, making it a secondary sort column when another
+// column is already being sorted on. If the header was already part of the sort criteria, then its ascending/descending
+// flag will be flipped instead.
+func (h *TableHeader[T]) AddSortOn(header TableColumnHeader[T]) {
+	s := header.SortState()
+	if !s.Sortable {
+		return
+	}
+	if s.Order >= 0 {
+		s.Ascending = !s.Ascending
+	} else {
+		s.Order = 0
+		for _, hdr := range h.ColumnHeaders {
+			if ss := hdr.SortState(); ss.Sortable && ss.Order >= s.Order {
+				s.Order = ss.Order + 1
+			}
+		}
+	}
+	header.SetSortState(s)
+}
+
 type headerWithIndex[T TableRowConstraint[T]] struct {
 	header TableColumnHeader[T]
 	index  int
@@ -419,33 +569,54 @@ func (h *TableHeader[T]) HasSort() bool {
 	return false
 }
 
-// ApplySort sorts the table according to the current sort criteria.
-func (h *TableHeader[T]) ApplySort() {
-	headers := make([]*headerWithIndex[T], len(h.ColumnHeaders))
-	for i, hdr := range h.ColumnHeaders {
-		headers[i] = &headerWithIndex[T]{
-			index:  i,
-			header: hdr,
+// SortKeys returns the current sort criteria, with the primary sort column first.
+func (h *TableHeader[T]) SortKeys() []TableSortKey {
+	headers := h.sortHeaders()
+	keys := make([]TableSortKey, len(headers))
+	for i, hdr := range headers {
+		keys[i] = TableSortKey{
+			Column:    hdr.index,
+			Ascending: hdr.header.SortState().Ascending,
 		}
-	}
-	sort.Slice(headers, func(i, j int) bool {
-		s1 := headers[i].header.SortState()
-		if !s1.Sortable || s1.Order < 0 {
-			return false
+		if hdr.index < len(h.table.Columns) {
+			keys[i].ID = h.table.Columns[hdr.index].ID
 		}
-		s2 := headers[j].header.SortState()
-		if !s2.Sortable || s2.Order < 0 {
-			return true
+	}
+	return keys
+}
+
+// sortHeaders returns the column headers that are part of the sort criteria, with the primary sort column first.
+func (h *TableHeader[T]) sortHeaders() []*headerWithIndex[T] {
+	headers := make([]*headerWithIndex[T], 0, len(h.ColumnHeaders))
+	for i, hdr := range h.ColumnHeaders {
+		if s := hdr.SortState(); s.Sortable && s.Order >= 0 {
+			headers = append(headers, &headerWithIndex[T]{
+				index:  i,
+				header: hdr,
+			})
 		}
-		return s1.Order < s2.Order
+	}
+	slices.SortStableFunc(headers, func(a, b *headerWithIndex[T]) int {
+		return cmp.Compare(a.header.SortState().Order, b.header.SortState().Order)
 	})
-	for i, hdr := range headers {
-		s := hdr.header.SortState()
-		if !s.Sortable || s.Order < 0 {
-			headers = headers[:i]
-			break
-		}
+	return headers
+}
+
+// ApplySort sorts the table according to the current sort criteria. The sort is stable, so rows that compare as equal
+// retain their relative order, and child rows are sorted amongst their siblings. When the table's model implements
+// SortableTableModel, the model is asked to order its rows instead. Otherwise, when the table's model is a
+// VirtualTableModel, the model is responsible for ordering its rows, so only the table's display is updated.
+func (h *TableHeader[T]) ApplySort() {
+	if model, ok := h.table.Model.(SortableTableModel); ok {
+		model.SortRows(h.SortKeys())
+		h.table.SyncToModel()
+		return
 	}
+	if _, ok := h.table.Model.(VirtualTableModel[T]); ok {
+		h.table.SyncToModel()
+		return
+	}
+	headers := h.sortHeaders()
 	if h.table.filteredRows == nil {
 		roots := slices.Clone(h.table.RootRows())
 		h.applySort(headers, roots)
@@ -458,23 +629,16 @@ func (h *TableHeader[T]) ApplySort() {
 
 func (h *TableHeader[T]) applySort(headers []*headerWithIndex[T], rows []T) {
 	if len(headers) > 0 && len(rows) > 0 {
-		sort.Slice(rows, func(i, j int) bool {
+		slices.SortStableFunc(rows, func(a, b T) int {
 			for _, hdr := range headers {
-				d1 := rows[i].CellDataForSort(hdr.index)
-				d2 := rows[j].CellDataForSort(hdr.index)
-				if d1 != d2 {
-					ascending := hdr.header.SortState().Ascending
-					less := hdr.header.Less()
-					if less == nil {
-						less = h.Less
-					}
-					if less(d1, d2) {
-						return ascending
+				if result := h.compareRows(hdr, a, b); result != 0 {
+					if hdr.header.SortState().Ascending {
+						return result
 					}
-					return !ascending
+					return -result
 				}
 			}
-			return false
+			return 0
 		})
 		if h.table.filteredRows == nil {
 			for _, row := range rows {
@@ -489,3 +653,35 @@ func (h *TableHeader[T]) applySort(headers []*headerWithIndex[T], rows []T) {
 		}
 	}
 }
+
+// compareRows compares the rows in the column of the header, using typed values if the rows provide them.
+func (h *TableHeader[T]) compareRows(hdr *headerWithIndex[T], a, b T) int {
+	if va, ok := any(a).(TableSortValueRow); ok {
+		if vb, ok2 := any(b).(TableSortValueRow); ok2 {
+			compare := CompareSortValues
+			if comparer, ok3 := hdr.header.(TableColumnComparer); ok3 {
+				if f := comparer.Compare(); f != nil {
+					compare = f
+				}
+			}
+			return compare(va.CellSortValue(hdr.index), vb.CellSortValue(hdr.index))
+		}
+	}
+	d1 := a.CellDataForSort(hdr.index)
+	d2 := b.CellDataForSort(hdr.index)
+	if d1 == d2 {
+		return 0
+	}
+	less := hdr.header.Less()
+	if less == nil {
+		less = h.Less
+	}
+	switch {
+	case less(d1, d2):
+		return -1
+	case less(d2, d1):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/xreflect"
+)
+
+// TableLayoutState holds a snapshot of the arrangement of the columns of a Table: their order, widths, visibility and
+// sort state. Columns are identified by their ColumnInfo.ID.
+type TableLayoutState struct {
+	Columns []*TableColumnState `json:"columns,omitzero"` // In order, including the hidden columns
+}
+
+// TableColumnState holds the state of a single column within a TableLayoutState.
+type TableColumnState struct {
+	ID        int     `json:"id"`
+	Width     float32 `json:"width,omitzero"`
+	SortOrder int     `json:"sort_order,omitzero"` // One-based, so that zero means the column isn't being sorted on
+	Ascending bool    `json:"ascending,omitzero"`
+	Hidden    bool    `json:"hidden,omitzero"`
+}
+
+// NewTableLayoutState creates a new TableLayoutState for the given Table. The sort state is only captured if the table
+// has a TableHeader.
+func NewTableLayoutState[T TableRowConstraint[T]](table *Table[T]) *TableLayoutState {
+	all := table.allColumns()
+	s := &TableLayoutState{Columns: make([]*TableColumnState, len(all))}
+	for i, one := range all {
+		cs := &TableColumnState{
+			ID:     one.info.ID,
+			Width:  one.info.Current,
+			Hidden: one.hidden,
+		}
+		if !xreflect.IsNil(one.header) {
+			if ss := one.header.SortState(); ss.Sortable && ss.Order >= 0 {
+				cs.SortOrder = ss.Order + 1
+				cs.Ascending = ss.Ascending
+			}
+		}
+		s.Columns[i] = cs
+	}
+	return s
+}
+
+// Apply the saved TableLayoutState to the specified Table. Columns that are in the table but not in the state are
+// placed after those that are, in their current order. Columns in the state that are not in the table are ignored. If
+// the table has a TableHeader with a sort, the sort is applied.
+func (s *TableLayoutState) Apply[T TableRowConstraint[T]](table *Table[T]) {
+	remaining := table.allColumns()
+	all := make([]tableColumn[T], 0, len(remaining))
+	sortStates := make(map[int]*TableColumnState, len(s.Columns))
+	for _, cs := range s.Columns {
+		if cs == nil {
+			continue
+		}
+		for i := range remaining {
+			if remaining[i].info.ID == cs.ID {
+				one := remaining[i]
+				remaining = slices.Delete(remaining, i, i+1)
+				if cs.Width > 0 {
+					one.info.Current = cs.Width
+				}
+				one.hidden = cs.Hidden
+				sortStates[cs.ID] = cs
+				all = append(all, one)
+				break
+			}
+		}
+	}
+	all = append(all, remaining...)
+	hasVisible := false
+	for i := range all {
+		if !all[i].hidden {
+			hasVisible = true
+			break
+		}
+	}
+	if !hasVisible && len(all) != 0 {
+		all[0].hidden = false
+	}
+	if table.header != nil {
+		for _, one := range all {
+			if xreflect.IsNil(one.header) {
+				continue
+			}
+			ss := one.header.SortState()
+			if ss.Sortable {
+				if cs, ok := sortStates[one.info.ID]; ok {
+					ss.Order = cs.SortOrder - 1
+					ss.Ascending = cs.Ascending
+					one.header.SetSortState(ss)
+				}
+			}
+		}
+	}
+	table.setAllColumns(all)
+	if table.header != nil && table.header.HasSort() {
+		table.header.ApplySort()
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// TableSortKey describes one of the columns a Table is being sorted on.
+type TableSortKey struct {
+	Column    int // The index of the column within Table.Columns
+	ID        int // The ColumnInfo.ID of the column
+	Ascending bool
+}
+
+// TableSortValueRow may be implemented by the row data of a Table to provide typed values for sorting, rather than the
+// strings returned by CellDataForSort().
+type TableSortValueRow interface {
+	// CellSortValue returns the value to sort on for the column index.
+	CellSortValue(col int) any
+}
+
+// TableColumnComparer may be implemented by a TableColumnHeader to compare the values returned by the CellSortValue()
+// method of rows that implement TableSortValueRow. If not implemented, or the returned function is nil,
+// CompareSortValues will be used.
+type TableColumnComparer interface {
+	// Compare returns a function that returns a negative number if a sorts before b, a positive number if a sorts
+	// after b, and zero if they are equivalent.
+	Compare() func(a, b any) int
+}
+
+// SortableTableModel may be implemented by a TableModel to take responsibility for ordering its rows, which is
+// necessary for a VirtualTableModel whose rows are not all held in memory. When the model of a Table implements this
+// interface, the table's header will call SortRows() rather than sorting the rows itself.
+type SortableTableModel interface {
+	// SortRows orders the rows by the keys, the first being the primary one. The keys will be empty when no sort is
+	// active, in which case the model may restore its natural order. Call SyncToModel() on any tables using the model
+	// once the rows have been reordered.
+	SortRows(keys []TableSortKey)
+}
+
+// CompareSortValues compares two values returned by the CellSortValue() method of rows that implement
+// TableSortValueRow. Numbers, including those of named types, are compared numerically, times chronologically, booleans
+// with false first and everything else as strings using a natural, case-insensitive comparison. A nil value sorts
+// before any other.
+func CompareSortValues(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == b:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok2 := b.(time.Time); ok2 {
+			return ta.Compare(tb)
+		}
+	}
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+	switch {
+	case va.CanInt() && vb.CanInt():
+		return cmp.Compare(va.Int(), vb.Int())
+	case va.CanUint() && vb.CanUint():
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case isNumericValue(va) && isNumericValue(vb):
+		return cmp.Compare(numericValue(va), numericValue(vb))
+	case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+		switch {
+		case va.Bool() == vb.Bool():
+			return 0
+		case vb.Bool():
+			return -1
+		default:
+			return 1
+		}
+	default:
+		return xstrings.NaturalCmp(fmt.Sprint(a), fmt.Sprint(b), true)
+	}
+}
+
+func isNumericValue(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint() || v.CanFloat()
+}
+
+func numericValue(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
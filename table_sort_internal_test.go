@@ -0,0 +1,162 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// sortTestRow is a TableRowData whose first column holds a name and whose second column holds a typed size.
+type sortTestRow struct {
+	parent   *sortTestRow
+	id       tid.TID
+	name     string
+	children []*sortTestRow
+	size     int
+}
+
+func (r *sortTestRow) CloneForTarget(_ Paneler, _ *sortTestRow) *sortTestRow {
+	clone := *r
+	return &clone
+}
+func (r *sortTestRow) ID() tid.TID                  { return r.id }
+func (r *sortTestRow) Parent() *sortTestRow         { return r.parent }
+func (r *sortTestRow) SetParent(p *sortTestRow)     { r.parent = p }
+func (r *sortTestRow) CanHaveChildren() bool        { return len(r.children) != 0 }
+func (r *sortTestRow) Children() []*sortTestRow     { return r.children }
+func (r *sortTestRow) SetChildren(c []*sortTestRow) { r.children = c }
+func (r *sortTestRow) CellDataForSort(col int) string {
+	if col == 0 {
+		return r.name
+	}
+	return "" // Forces the use of CellSortValue() for the size column
+}
+func (r *sortTestRow) ColumnCell(_, _ int, _, _ Ink, _, _, _ bool) Paneler {
+	return NewPanel()
+}
+func (r *sortTestRow) IsOpen() bool   { return true }
+func (r *sortTestRow) SetOpen(_ bool) {}
+
+func (r *sortTestRow) CellSortValue(col int) any {
+	if col == 0 {
+		return r.name
+	}
+	return r.size
+}
+
+// sortTestModel is a SimpleTableModel that records the sort requests made of it.
+type sortTestModel struct {
+	SimpleTableModel[*sortTestRow]
+	keys [][]TableSortKey
+}
+
+func (m *sortTestModel) SortRows(keys []TableSortKey) {
+	m.keys = append(m.keys, keys)
+}
+
+func newSortTestTable(model TableModel[*sortTestRow], rows ...*sortTestRow) (*Table[*sortTestRow],
+	[]*DefaultTableColumnHeader[*sortTestRow]) {
+	model.SetRootRows(rows)
+	table := NewTable(model)
+	table.Columns = []ColumnInfo{{ID: 1, Current: 100}, {ID: 2, Current: 100}}
+	headers := []*DefaultTableColumnHeader[*sortTestRow]{
+		NewTableColumnHeader[*sortTestRow]("Name", "", nil),
+		NewTableColumnHeader[*sortTestRow]("Size", "", nil),
+	}
+	NewTableHeader(table, TableColumnHeader[*sortTestRow](headers[0]), headers[1])
+	table.SyncToModel()
+	return table, headers
+}
+
+func sortTestIDs(rows []*sortTestRow) string {
+	var ids string
+	for _, row := range rows {
+		ids += string(row.id)
+	}
+	return ids
+}
+
+func TestCompareSortValues(t *testing.T) {
+	c := check.New(t)
+	type named int
+	now := time.Now()
+	c.Equal(-1, CompareSortValues(2, 10))
+	c.Equal(1, CompareSortValues(named(10), named(2)))
+	c.Equal(-1, CompareSortValues(1.5, 2))
+	c.Equal(0, CompareSortValues(uint8(3), 3))
+	c.Equal(-1, CompareSortValues(now, now.Add(time.Second)))
+	c.Equal(-1, CompareSortValues(false, true))
+	c.Equal(-1, CompareSortValues("item 2", "Item 10"))
+	c.Equal(-1, CompareSortValues(nil, 0))
+	c.Equal(0, CompareSortValues(nil, nil))
+}
+
+func TestTableMultiColumnSort(t *testing.T) {
+	c := check.New(t)
+	rows := []*sortTestRow{
+		{id: "a", name: "beta", size: 2},
+		{id: "b", name: "alpha", size: 10},
+		{id: "c", name: "beta", size: 10},
+		{id: "d", name: "alpha", size: 2},
+		{id: "e", name: "alpha", size: 10},
+	}
+	model := &SimpleTableModel[*sortTestRow]{}
+	table, headers := newSortTestTable(model, rows...)
+	header := table.header
+
+	header.SortOn(headers[0])
+	header.ApplySort()
+	c.Equal("bdeac", sortTestIDs(model.RootRows()), "rows that compare as equal keep their relative order")
+
+	header.AddSortOn(headers[1])
+	c.Equal(1, headers[1].SortState().Order)
+	header.AddSortOn(headers[1])
+	c.False(headers[1].SortState().Ascending, "adding a column already in the sort flips its direction")
+	c.Equal([]TableSortKey{{Column: 0, ID: 1, Ascending: true}, {Column: 1, ID: 2}}, header.SortKeys())
+	header.ApplySort()
+	c.Equal("bedca", sortTestIDs(model.RootRows()), "sizes are compared as numbers, not strings")
+	c.True(headers[1].showSortOrder())
+
+	header.SortOn(headers[1])
+	c.Equal(0, headers[1].SortState().Order)
+	c.Equal(1, headers[0].SortState().Order)
+	header.SortOn(headers[1])
+	c.True(headers[1].SortState().Ascending)
+	header.ApplySort()
+	c.Equal("dabec", sortTestIDs(model.RootRows()))
+}
+
+func TestTableSortPreservesHierarchy(t *testing.T) {
+	c := check.New(t)
+	parent := &sortTestRow{id: "p", name: "zulu"}
+	parent.SetChildren([]*sortTestRow{{id: "x", name: "b", parent: parent}, {id: "y", name: "a", parent: parent}})
+	model := &SimpleTableModel[*sortTestRow]{}
+	table, headers := newSortTestTable(model, parent, &sortTestRow{id: "q", name: "mike"})
+	table.header.SortOn(headers[0])
+	table.header.ApplySort()
+	c.Equal("qp", sortTestIDs(model.RootRows()))
+	c.Equal("yx", sortTestIDs(parent.Children()))
+	c.True(parent.Children()[0].Parent() == parent)
+}
+
+func TestTableSortDelegatesToModel(t *testing.T) {
+	c := check.New(t)
+	rows := []*sortTestRow{{id: "a", name: "beta"}, {id: "b", name: "alpha"}}
+	model := &sortTestModel{}
+	table, headers := newSortTestTable(model, rows...)
+	table.header.SortOn(headers[0])
+	table.header.ApplySort()
+	c.Equal("ab", sortTestIDs(model.RootRows()), "the model is responsible for ordering its rows")
+	c.Equal([][]TableSortKey{{{Column: 0, ID: 1, Ascending: true}}}, model.keys)
+}
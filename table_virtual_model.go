@@ -0,0 +1,260 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// VirtualTableModel is a TableModel that provides its rows on demand rather than holding all of them in memory. A Table
+// whose Model implements this interface only asks for the rows it needs, gives every row the same height and displays
+// no hierarchy, so it can present millions of rows. RootRowCount() must return the total number of rows; RootRows() and
+// SetRootRows() are not used by the table. Filtering and sorting are left to the model.
+type VirtualTableModel[T TableRowConstraint[T]] interface {
+	TableModel[T]
+	// Row returns the row at the index. If the row isn't available yet, return false; the table will display a
+	// placeholder in its place. The model should then arrange for the row to be loaded and, once it has been, call
+	// RowsLoaded() on the listener on the UI thread. A table passes itself as the listener each time it asks for a row,
+	// which it does for every visible row each time it draws, so a model should only retain a given listener once.
+	Row(index int, listener TableRowLoadListener) (row T, ok bool)
+	// RowID returns the ID of the row at the index. This must be available even when the row itself is not, since the
+	// table tracks its selection by ID.
+	RowID(index int) tid.TID
+	// RowIndex returns the index of the row with the ID, or -1 if there is no such row.
+	RowIndex(id tid.TID) int
+}
+
+// TableRowLoadListener is notified by a VirtualTableModel once rows that weren't available when asked for have been
+// loaded.
+type TableRowLoadListener interface {
+	// RowsLoaded is called on the UI thread once the rows have been loaded.
+	RowsLoaded()
+}
+
+// TableRowLoader provides the rows for a PagedTableModel. A loader that can order its rows may also implement
+// SortableTableModel, in which case the PagedTableModel will forward sort requests to it.
+type TableRowLoader[T TableRowConstraint[T]] interface {
+	// LoadRows returns the rows [start, start+count). This is called on a goroutine other than the UI thread. Rows
+	// beyond those returned, as well as all of the rows if this panics, are treated as unavailable and are not requested
+	// again until the page holding them is discarded.
+	LoadRows(start, count int) []T
+	// RowID returns the ID of the row at the index.
+	RowID(index int) tid.TID
+	// RowIndex returns the index of the row with the ID, or -1 if there is no such row.
+	RowIndex(id tid.TID) int
+}
+
+// PagedTableModel is a VirtualTableModel that loads its rows a page at a time in the background, keeping only the most
+// recently used pages in memory. Other than the loading done by its TableRowLoader, it must only be used on the UI
+// thread.
+type PagedTableModel[T TableRowConstraint[T]] struct {
+	loader     TableRowLoader[T]
+	pages      map[int]*tableRowPage[T]
+	loading    map[int][]TableRowLoadListener
+	recent     []int
+	rowCount   int
+	pageSize   int
+	maxPages   int
+	generation int
+}
+
+// tableRowPage holds the rows of a page of a PagedTableModel, along with the number of rows that were requested for it.
+type tableRowPage[T any] struct {
+	rows      []T
+	requested int
+	failed    bool
+}
+
+// NewPagedTableModel creates a new PagedTableModel with rowCount rows, which will be loaded by the loader in pages of
+// pageSize rows. At most maxPages pages are kept in memory, so this should be large enough to hold several screens
+// worth of rows.
+func NewPagedTableModel[T TableRowConstraint[T]](loader TableRowLoader[T], rowCount, pageSize,
+	maxPages int) *PagedTableModel[T] {
+	return &PagedTableModel[T]{
+		loader:   loader,
+		pages:    make(map[int]*tableRowPage[T]),
+		loading:  make(map[int][]TableRowLoadListener),
+		rowCount: max(rowCount, 0),
+		pageSize: max(pageSize, 1),
+		maxPages: max(maxPages, 1),
+	}
+}
+
+// RootRowCount implements TableModel.
+func (m *PagedTableModel[T]) RootRowCount() int {
+	return m.rowCount
+}
+
+// RootRows implements TableModel. Since the rows are not all held in memory, this always returns nil.
+func (m *PagedTableModel[T]) RootRows() []T {
+	return nil
+}
+
+// SetRootRows implements TableModel. The rows replace the current content and are held in memory until they are
+// evicted, at which point they will be reloaded from the TableRowLoader if needed again.
+func (m *PagedTableModel[T]) SetRootRows(rows []T) {
+	m.Reset(len(rows))
+	for page := 0; page*m.pageSize < len(rows); page++ {
+		pageRows := slices.Clone(rows[page*m.pageSize : min((page+1)*m.pageSize, len(rows))])
+		m.pages[page] = &tableRowPage[T]{rows: pageRows, requested: len(pageRows)}
+		m.touch(page)
+	}
+	m.evict()
+}
+
+// Reset discards all loaded rows and sets the number of rows. Rows that are still being loaded are discarded once they
+// arrive. Call SyncToModel() on any tables using this model afterward.
+func (m *PagedTableModel[T]) Reset(rowCount int) {
+	m.generation++
+	m.rowCount = max(rowCount, 0)
+	clear(m.pages)
+	clear(m.loading)
+	m.recent = m.recent[:0]
+}
+
+// SetRowCount sets the number of rows, keeping the loaded pages that are unaffected by the change. This is intended for
+// models whose rows are only ever appended or removed from the end, such as a log. Call SyncToModel() on any tables
+// using this model afterward.
+func (m *PagedTableModel[T]) SetRowCount(rowCount int) {
+	m.rowCount = max(rowCount, 0)
+	for page, p := range m.pages {
+		if p.requested != m.pageRowCount(page) {
+			m.discard(page)
+		}
+	}
+}
+
+// SortRows implements SortableTableModel. If the TableRowLoader also implements SortableTableModel, the request is
+// forwarded to it and the loaded rows are discarded so that they will be reloaded in the new order. Otherwise, this
+// does nothing.
+func (m *PagedTableModel[T]) SortRows(keys []TableSortKey) {
+	if sorter, ok := m.loader.(SortableTableModel); ok {
+		sorter.SortRows(keys)
+		m.Reset(m.rowCount)
+	}
+}
+
+// Row implements VirtualTableModel. A row that the TableRowLoader failed to provide is reported as unavailable without
+// being requested again, until the page holding it is discarded, either by Reset(), by SetRowCount() changing the
+// number of rows in the page, or by being evicted.
+func (m *PagedTableModel[T]) Row(index int, listener TableRowLoadListener) (row T, ok bool) {
+	if index < 0 || index >= m.rowCount {
+		return row, false
+	}
+	page := index / m.pageSize
+	offset := index - page*m.pageSize
+	if p, exists := m.pages[page]; exists {
+		if offset < len(p.rows) {
+			m.touch(page)
+			return p.rows[offset], true
+		}
+		if p.failed || offset < p.requested {
+			m.touch(page)
+			return row, false
+		}
+		// The page was loaded before rows were appended to it, so it must be loaded again.
+		m.discard(page)
+	}
+	listeners, exists := m.loading[page]
+	if listener != nil && !slices.Contains(listeners, listener) {
+		listeners = append(listeners, listener)
+	}
+	m.loading[page] = listeners
+	if !exists {
+		start := page * m.pageSize
+		count := m.pageRowCount(page)
+		generation := m.generation
+		go func() {
+			var rows []T
+			failed := true
+			SafeCall(func() {
+				rows = m.loader.LoadRows(start, count)
+				failed = false
+			})
+			InvokeTask(func() {
+				m.pageLoaded(page, generation, &tableRowPage[T]{rows: rows, requested: count, failed: failed})
+			})
+		}()
+	}
+	return row, false
+}
+
+// RowID implements VirtualTableModel.
+func (m *PagedTableModel[T]) RowID(index int) tid.TID {
+	return m.loader.RowID(index)
+}
+
+// RowIndex implements VirtualTableModel.
+func (m *PagedTableModel[T]) RowIndex(id tid.TID) int {
+	if index := m.loader.RowIndex(id); index < m.rowCount {
+		return index
+	}
+	return -1
+}
+
+// IsRowLoaded returns true if the row at the index is currently held in memory.
+func (m *PagedTableModel[T]) IsRowLoaded(index int) bool {
+	if index < 0 || index >= m.rowCount {
+		return false
+	}
+	page := index / m.pageSize
+	p, exists := m.pages[page]
+	return exists && index-page*m.pageSize < len(p.rows)
+}
+
+// pageRowCount returns the number of rows the page holds given the current row count.
+func (m *PagedTableModel[T]) pageRowCount(page int) int {
+	return max(min(m.pageSize, m.rowCount-page*m.pageSize), 0)
+}
+
+func (m *PagedTableModel[T]) pageLoaded(page, generation int, p *tableRowPage[T]) {
+	if generation != m.generation {
+		return
+	}
+	listeners := m.loading[page]
+	delete(m.loading, page)
+	if len(p.rows) > p.requested {
+		p.rows = p.rows[:p.requested]
+	}
+	m.pages[page] = p
+	m.touch(page)
+	m.evict()
+	for _, listener := range listeners {
+		SafeCall(listener.RowsLoaded)
+	}
+}
+
+// touch marks the page as the most recently used one.
+func (m *PagedTableModel[T]) touch(page int) {
+	if len(m.recent) != 0 && m.recent[len(m.recent)-1] == page {
+		return
+	}
+	if i := slices.Index(m.recent, page); i != -1 {
+		m.recent = slices.Delete(m.recent, i, i+1)
+	}
+	m.recent = append(m.recent, page)
+}
+
+// evict discards the least recently used pages until no more than the maximum number of pages remain.
+func (m *PagedTableModel[T]) evict() {
+	for len(m.recent) > m.maxPages {
+		delete(m.pages, m.recent[0])
+		m.recent = slices.Delete(m.recent, 0, 1)
+	}
+}
+
+func (m *PagedTableModel[T]) discard(page int) {
+	delete(m.pages, page)
+	if i := slices.Index(m.recent, page); i != -1 {
+		m.recent = slices.Delete(m.recent, i, i+1)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// virtualTestRow is a minimal TableRowData whose cells all prefer the same height.
+type virtualTestRow struct {
+	id tid.TID
+}
+
+func (r *virtualTestRow) CloneForTarget(_ Paneler, _ *virtualTestRow) *virtualTestRow {
+	return &virtualTestRow{id: r.id}
+}
+func (r *virtualTestRow) ID() tid.TID                     { return r.id }
+func (r *virtualTestRow) Parent() *virtualTestRow         { return nil }
+func (r *virtualTestRow) SetParent(_ *virtualTestRow)     {}
+func (r *virtualTestRow) CanHaveChildren() bool           { return false }
+func (r *virtualTestRow) Children() []*virtualTestRow     { return nil }
+func (r *virtualTestRow) SetChildren(_ []*virtualTestRow) {}
+func (r *virtualTestRow) CellDataForSort(_ int) string    { return string(r.id) }
+func (r *virtualTestRow) ColumnCell(_, _ int, _, _ Ink, _, _, _ bool) Paneler {
+	p := NewPanel()
+	p.SetSizer(func(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+		size := geom.NewSize(50, 30)
+		return size, size, size
+	})
+	return p
+}
+func (r *virtualTestRow) IsOpen() bool   { return false }
+func (r *virtualTestRow) SetOpen(_ bool) {}
+
+// virtualTestLoader loads rows whose IDs are their indexes, recording each load it is asked to perform.
+type virtualTestLoader struct {
+	loads [][2]int
+	lock  sync.Mutex
+}
+
+func (l *virtualTestLoader) LoadRows(start, count int) []*virtualTestRow {
+	l.lock.Lock()
+	l.loads = append(l.loads, [2]int{start, count})
+	l.lock.Unlock()
+	rows := make([]*virtualTestRow, count)
+	for i := range rows {
+		rows[i] = &virtualTestRow{id: l.RowID(start + i)}
+	}
+	return rows
+}
+
+func (l *virtualTestLoader) RowID(index int) tid.TID {
+	return tid.TID(strconv.Itoa(index))
+}
+
+func (l *virtualTestLoader) RowIndex(id tid.TID) int {
+	index, err := strconv.Atoi(string(id))
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+func (l *virtualTestLoader) loadCount() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.loads)
+}
+
+// failingTestLoader returns fewer rows than it is asked for, or panics once fail is set.
+type failingTestLoader struct {
+	virtualTestLoader
+	missing int
+	fail    bool
+}
+
+func (l *failingTestLoader) LoadRows(start, count int) []*virtualTestRow {
+	rows := l.virtualTestLoader.LoadRows(start, count)
+	if l.fail {
+		panic("unable to load rows")
+	}
+	return rows[:count-l.missing]
+}
+
+// countingLoadListener counts the number of times it is notified that rows have been loaded.
+type countingLoadListener struct {
+	calls int
+}
+
+func (l *countingLoadListener) RowsLoaded() {
+	l.calls++
+}
+
+func newVirtualTestTable(loader *virtualTestLoader, rowCount int,
+	rowHeight float32) (*Table[*virtualTestRow], *PagedTableModel[*virtualTestRow]) {
+	model := NewPagedTableModel[*virtualTestRow](loader, rowCount, 100, 4)
+	table := NewTable[*virtualTestRow](model)
+	table.Columns = []ColumnInfo{{ID: 0, Current: 100}}
+	table.VirtualRowHeight = rowHeight
+	table.SyncToModel()
+	return table, model
+}
+
+// TestTableVirtualModel verifies that a table with a VirtualTableModel sizes itself and maps between rows and
+// coordinates using only the row count, loads just the pages of rows it is asked for, and tracks its selection by ID
+// without loading the selected rows.
+func TestTableVirtualModel(t *testing.T) {
+	c := check.New(t)
+	loader := &virtualTestLoader{}
+	table, model := newVirtualTestTable(loader, 2_000_000, 20)
+	c.Equal(1_999_999, table.LastRowIndex())
+	_, pref, _ := table.Sizes(geom.Size{})
+	c.Equal(float32(40_000_000), pref.Height)
+	c.Equal(1234, table.OverRow(1234*20+5))
+	c.Equal(float32(1234*20), table.RowFrame(1234).Y)
+	c.Equal(float32(1234*20)+table.Padding.Top, table.CellFrame(1234, 0).Y)
+	c.Equal(0, loader.loadCount(), "no rows are loaded by syncing, sizing or hit testing")
+
+	c.Nil(table.RowFromIndex(1_500_000), "rows are not available until their page has been loaded")
+	runPendingTask(t)
+	c.Equal([][2]int{{1_500_000, 100}}, loader.loads)
+	c.Equal(tid.TID("1500042"), table.RowFromIndex(1_500_042).ID())
+	c.Equal(1_500_042, table.RowToIndex(&virtualTestRow{id: "1500042"}))
+
+	table.SelectRange(1_000_000, 1_000_009)
+	c.Equal(10, table.SelectionCount())
+	c.Equal(1_000_000, table.FirstSelectedRowIndex())
+	c.Equal(1_000_009, table.LastSelectedRowIndex())
+	c.True(table.IsRowSelected(1_000_005))
+	c.True(table.CopySelectionMap()["1000003"])
+	c.Equal(1, loader.loadCount(), "selecting rows doesn't load them")
+	c.Equal(0, len(table.SelectedRows(false)), "only loaded rows are returned")
+	runPendingTask(t)
+	c.Equal(10, len(table.SelectedRows(false)))
+
+	for page := range 3 {
+		c.Nil(table.RowFromIndex(page * 100))
+		runPendingTask(t)
+	}
+	c.False(model.IsRowLoaded(1_500_000), "the least recently used page is evicted")
+	c.True(model.IsRowLoaded(1_000_000))
+	c.True(model.IsRowLoaded(250))
+
+	model.SetRowCount(2_000_050)
+	table.SyncToModel()
+	c.Equal(2_000_049, table.LastRowIndex())
+	c.True(model.IsRowLoaded(250), "pages unaffected by appended rows are kept")
+	c.Equal(10, table.SelectionCount())
+}
+
+// TestTableVirtualModelEstimatesRowHeight verifies that, when no VirtualRowHeight is given, the height of every row is
+// taken from the first row to be loaded.
+func TestTableVirtualModelEstimatesRowHeight(t *testing.T) {
+	c := check.New(t)
+	loader := &virtualTestLoader{}
+	table, _ := newVirtualTestTable(loader, 1000, 0)
+	c.Equal(table.MinimumRowHeight, table.RowFrame(1).Y)
+	c.Nil(table.RowFromIndex(0))
+	runPendingTask(t)
+	height := 30 + table.Padding.Top + table.Padding.Bottom
+	c.Equal(height, table.RowFrame(1).Y)
+	_, pref, _ := table.Sizes(geom.Size{})
+	c.Equal(1000*height, pref.Height)
+	c.Equal(1, loader.loadCount())
+}
+
+// TestPagedTableModelIncompleteLoads verifies that rows the loader fails to provide, whether because it returned too
+// few of them or because it panicked, are not requested again on each draw, and that a listener asking for the rows of
+// a page many times is only notified once when they arrive.
+func TestPagedTableModelIncompleteLoads(t *testing.T) {
+	c := check.New(t)
+	resetTaskQueue()
+	var recovered error
+	withRecoveryCallback(t, func(err error) { recovered = err })
+	loader := &failingTestLoader{missing: 10}
+	model := NewPagedTableModel[*virtualTestRow](loader, 250, 100, 4)
+	listener := &countingLoadListener{}
+	for range 3 {
+		_, ok := model.Row(95, listener)
+		c.False(ok)
+	}
+	runPendingTask(t)
+	c.Equal(1, listener.calls, "the listener is only notified once, no matter how many times it asked")
+	row, ok := model.Row(50, listener)
+	c.True(ok)
+	c.Equal(tid.TID("50"), row.ID())
+	for range 3 {
+		_, ok = model.Row(95, listener)
+		c.False(ok, "rows the loader didn't return are unavailable")
+	}
+	c.Equal(1, loader.loadCount(), "rows the loader didn't return are not requested again")
+
+	_, ok = model.Row(240, listener)
+	c.False(ok)
+	runPendingTask(t)
+	_, ok = model.Row(245, listener)
+	c.False(ok)
+	c.Equal(2, loader.loadCount())
+	model.SetRowCount(250)
+	_, ok = model.Row(245, listener)
+	c.False(ok)
+	c.Equal(2, loader.loadCount(), "a page whose row count hasn't changed is kept")
+	model.SetRowCount(260)
+	_, ok = model.Row(245, listener)
+	c.False(ok)
+	runPendingTask(t)
+	c.Equal(3, loader.loadCount(), "a page whose row count grew is loaded again")
+	c.True(model.IsRowLoaded(245))
+
+	loader.fail = true
+	_, ok = model.Row(150, listener)
+	c.False(ok)
+	runPendingTask(t)
+	c.NotNil(recovered)
+	c.Equal(4, listener.calls, "the listener is notified even when the load fails")
+	_, ok = model.Row(150, listener)
+	c.False(ok)
+	c.Equal(4, loader.loadCount(), "a failed load is not retried")
+
+	loader.fail = false
+	model.Reset(250)
+	_, ok = model.Row(150, listener)
+	c.False(ok)
+	runPendingTask(t)
+	c.True(model.IsRowLoaded(150), "a reset retries the load")
+	c.Equal(5, loader.loadCount())
+}
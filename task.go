@@ -29,7 +29,7 @@ func InvokeTask(f func()) {
 	taskQueueLock.Lock()
 	taskQueue = append(taskQueue, f)
 	taskQueueLock.Unlock()
-	apiPostEmptyEvent()
+	postEmptyEvent()
 }
 
 // InvokeTaskAfter schedules a function to be run on the UI thread after waiting for the specified duration.
@@ -64,6 +64,13 @@ func InvokeTaskAndWait(f func()) {
 	<-done
 }
 
+// hasPendingTasks returns true if there are tasks in the queue waiting to be run.
+func hasPendingTasks() bool {
+	taskQueueLock.Lock()
+	defer taskQueueLock.Unlock()
+	return taskQueueHead < len(taskQueue)
+}
+
 func processNextTask() {
 	var f func()
 	needsPost := false
@@ -92,7 +99,7 @@ func processNextTask() {
 	if f != nil {
 		SafeCall(f)
 		if needsPost {
-			apiPostEmptyEvent()
+			postEmptyEvent()
 		}
 	}
 }
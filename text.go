@@ -10,6 +10,7 @@
 package unison
 
 import (
+	"slices"
 	"strings"
 	"unicode"
 
@@ -30,6 +31,10 @@ type Text struct {
 	// reserves a full line and adding runs never shrinks the metrics below what the original decoration requires.
 	emptyTop    float32
 	emptyBottom float32
+	// layout is only needed when complex is true, that is, when the runes contain right-to-left or complex-script
+	// characters. It is computed by cache().
+	layout  *textLayout
+	complex bool
 }
 
 // NewText creates a new Text. Note that tabs and line endings are not considered.
@@ -143,7 +148,7 @@ func (t *Text) Slice(i, j int) *Text {
 	}
 	// The three-index slices cap the capacity at j so that a later AddRunes/AddString on the returned Text reallocates
 	// instead of overwriting this Text's elements beyond j.
-	return &Text{
+	other := &Text{
 		runes:       t.runes[i:j:j],
 		decorations: t.decorations[i:j:j],
 		widths:      t.widths[i:j:j],
@@ -152,6 +157,31 @@ func (t *Text) Slice(i, j int) *Text {
 		emptyTop:    t.emptyTop,
 		emptyBottom: t.emptyBottom,
 	}
+	if t.complex {
+		other.complex = slices.ContainsFunc(other.runes, needsComplexLayout)
+	}
+	return other
+}
+
+// withBackground returns a copy of this Text whose runes within the ranges are drawn over the ink. The ranges may
+// overlap.
+func (t *Text) withBackground(ranges [][2]int, ink Ink) *Text {
+	other := t.Slice(0, len(t.runes))
+	other.decorations = slices.Clone(other.decorations)
+	replaced := make(map[*TextDecoration]*TextDecoration)
+	for _, r := range ranges {
+		for i := max(r[0], 0); i < min(r[1], len(other.decorations)); i++ {
+			d := other.decorations[i]
+			highlighted, ok := replaced[d]
+			if !ok {
+				highlighted = d.Clone()
+				highlighted.BackgroundInk = ink
+				replaced[d] = highlighted
+			}
+			other.decorations[i] = highlighted
+		}
+	}
+	return other
 }
 
 // Runes returns the runes comprising this Text. Do not modify this slice.
@@ -196,7 +226,13 @@ func (t *Text) Baseline() float32 {
 }
 
 func (t *Text) cache() {
+	if t.layout != nil && t.layout.awaitingSystemFonts && systemFontMapReady.Load() {
+		t.extents.Width = -1
+	}
 	if t.extents.Width < 0 {
+		if t.complex {
+			t.layoutComplex()
+		}
 		t.extents.Width = f32.Sum(t.widths)
 		// Track the union of the vertical bounds of each run, relative to the baseline. Each run reserves its font's
 		// normal line box in addition to the box shifted by its BaselineOffset, so offset runs keep room for
@@ -236,6 +272,10 @@ func (t *Text) AddRunes(runes []rune, decoration *TextDecoration) {
 	}
 	t.text = ""
 	t.extents.Width = -1
+	t.layout = nil
+	if !t.complex {
+		t.complex = slices.ContainsFunc(runes, needsComplexLayout)
+	}
 	start := len(t.decorations)
 	if start != 0 && decoration.Equivalent(t.decorations[start-1]) {
 		decoration = t.decorations[start-1]
@@ -294,6 +334,13 @@ func (t *Text) Draw(canvas *Canvas, pt geom.Point) {
 	if len(t.decorations) == 0 {
 		return
 	}
+	t.cache()
+	if t.layout != nil {
+		for _, run := range t.layout.runs {
+			run.decoration.drawGlyphs(canvas, run.glyphs, run.xs, run.ys, geom.NewPoint(pt.X+run.x, pt.Y), run.width)
+		}
+		return
+	}
 	start := 0
 	current := t.decorations[0]
 	nx := pt.X
@@ -313,10 +360,17 @@ func (t *Text) Draw(canvas *Canvas, pt geom.Point) {
 	}
 }
 
-// RuneIndexForPosition returns the rune index within the string for the specified x-coordinate, where 0 is the start of
-// the string.
+// RuneIndexForPosition returns the rune index within the string for the specified x-coordinate, where 0 is the left
+// edge of the text. When the text is bidirectional, the index is that of the logical caret position closest to x.
 func (t *Text) RuneIndexForPosition(x float32) int {
-	if x <= 0 || len(t.widths) == 0 {
+	if len(t.widths) == 0 {
+		return 0
+	}
+	t.cache()
+	if t.layout != nil {
+		return t.layout.runeIndex(x, t.widths)
+	}
+	if x <= 0 {
 		return 0
 	}
 	var nx float32
@@ -333,61 +387,67 @@ func (t *Text) RuneIndexForPosition(x float32) int {
 }
 
 // PositionForRuneIndex returns the x-coordinate where the specified rune index starts. The returned coordinate assumes
-// 0 is the start of the string. Note that this does not account for any embedded line endings nor tabs.
+// 0 is the left edge of the text. When the text is bidirectional, this is the leading edge of the rune at the index,
+// which is its right edge for right-to-left runes. Note that this does not account for any embedded line endings nor
+// tabs.
 func (t *Text) PositionForRuneIndex(index int) float32 {
-	if index <= 0 || len(t.widths) == 0 {
+	if len(t.widths) == 0 {
+		return 0
+	}
+	t.cache()
+	if t.layout != nil {
+		return t.layout.caretPosition(index, t.widths)
+	}
+	if index <= 0 {
 		return 0
 	}
 	// An index past the end clamps to the full width rather than being an error.
 	return f32.Sum(t.widths[:min(index, len(t.widths))])
 }
 
-// BreakToWidth breaks the given text into multiple lines that are <= width. Trailing whitespace is not considered for
-// purposes of fitting within the given width. A minimum of one word will be placed on a line, even if that word is
-// wider than the given width.
+// RangesForRuneIndexes returns the horizontal extents, as pairs of left and right x-coordinates, covered by the runes
+// in the range [start, end). There is a single range unless the text is bidirectional, in which case a contiguous
+// logical range may be split up visually.
+func (t *Text) RangesForRuneIndexes(start, end int) [][2]float32 {
+	start = max(start, 0)
+	end = min(end, len(t.widths))
+	if start >= end {
+		return nil
+	}
+	t.cache()
+	if t.layout != nil {
+		return t.layout.visualRanges(start, end, t.widths)
+	}
+	left := t.PositionForRuneIndex(start)
+	return [][2]float32{{left, left + f32.Sum(t.widths[start:end])}}
+}
+
+// BreakToWidth breaks the given text into multiple lines that are <= width. Line break opportunities are determined by
+// the Unicode Line Breaking Algorithm (UAX #14), so text without spaces, such as Chinese or Japanese, can be broken
+// too. Trailing whitespace is not considered for purposes of fitting within the given width. A minimum of one word
+// will be placed on a line, even if that word is wider than the given width. See BreakToWidthWithOptions() for more
+// control.
 func (t *Text) BreakToWidth(width float32) []*Text {
+	return t.BreakToWidthWithOptions(width, LineBreakOptions{})
+}
+
+// BreakToWidthWithOptions breaks the given text into multiple lines that are <= width, as BreakToWidth() does, but
+// with optional support for soft hyphens and for breaking within words that are too wide to fit on a line.
+func (t *Text) BreakToWidthWithOptions(width float32, options LineBreakOptions) []*Text {
 	if t.Width() <= width {
 		return []*Text{t}
 	}
+	breaks := lineBreakOpportunities(t.runes, options.SoftHyphens)
 	var lines []*Text
 	start := 0
 	for start < len(t.runes) {
-		i := start
-		var w float32
-		for i < len(t.runes) && w+t.widths[i] < width {
-			w += t.widths[i]
-			i++
+		end := t.nextLineEnd(start, width, breaks, options.EmergencyBreaks)
+		line := t.Slice(start, end)
+		if options.SoftHyphens && end < len(t.runes) && t.runes[end-1] == softHyphen {
+			line = line.hyphenated()
 		}
-		if i == len(t.runes) {
-			lines = append(lines, t.Slice(start, len(t.runes)))
-			break
-		}
-		// Forward past any additional whitespace
-		for i < len(t.runes) && unicode.IsSpace(t.runes[i]) {
-			i++
-		}
-		// Backup to first break
-		for i > start && !isWordBreak(t.runes[i-1]) {
-			i--
-		}
-		if i == start {
-			// Nothing found that fits, so take the first word and any trailing whitespace after it
-			for i < len(t.runes) && !isWordBreak(t.runes[i]) {
-				i++
-			}
-			if i < len(t.runes) && isWordBreak(t.runes[i]) {
-				i++
-			}
-			for i < len(t.runes) && unicode.IsSpace(t.runes[i]) {
-				i++
-			}
-		}
-		lines = append(lines, t.Slice(start, i))
-		start = i
+		lines = append(lines, line)
+		start = end
 	}
 	return lines
 }
-
-func isWordBreak(ch rune) bool {
-	return unicode.IsSpace(ch) || ch == '/' || ch == '\\'
-}
@@ -0,0 +1,623 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// The x/text bidi package provides the character classes we need, but its Ordering only reports the direction of each
+// run in logical order, losing the embedding levels needed to reorder nested runs (numbers within right-to-left text,
+// for example). The functions here implement the rules of the Unicode Bidirectional Algorithm (UAX #9) that apply to
+// a single line of text on top of those classes.
+
+const maxBidiDepth = 125
+
+type bidiStatus struct {
+	level    uint8
+	override bidi.Class // ON when there is no override in effect
+	isolate  bool
+}
+
+type bidiSequence struct {
+	indexes []int
+	classes []bidi.Class
+	level   uint8
+	sos     bidi.Class
+	eos     bidi.Class
+}
+
+// bidiLevels returns the resolved embedding level of each rune along with the paragraph embedding level, which is
+// determined by the first strong character.
+func bidiLevels(runes []rune) (levels []uint8, paragraphLevel uint8) {
+	original := make([]bidi.Class, len(runes))
+	for i, r := range runes {
+		p, _ := bidi.LookupRune(r)
+		original[i] = p.Class()
+	}
+	matchingPDI := bidiMatchIsolates(original)
+	paragraphLevel = bidiFirstStrongLevel(original, 0, len(original), matchingPDI, 0)
+	classes := slices.Clone(original)
+	levels = make([]uint8, len(runes))
+	bidiExplicitLevels(classes, levels, paragraphLevel, matchingPDI)
+	for _, seq := range bidiIsolatingRunSequences(classes, levels, paragraphLevel, matchingPDI) {
+		seq.resolveWeakTypes()
+		seq.resolvePairedBrackets(runes, original)
+		seq.resolveNeutralTypes()
+		for i, index := range seq.indexes {
+			levels[index] = bidiImplicitLevel(levels[index], seq.classes[i])
+		}
+	}
+	// Removed characters take the level of the character before them, so that they don't split runs when reordering.
+	for i, c := range classes {
+		if c == bidi.BN {
+			if i == 0 {
+				levels[i] = paragraphLevel
+			} else {
+				levels[i] = levels[i-1]
+			}
+		}
+	}
+	// L1: trailing whitespace and isolate formatting characters are reset to the paragraph level.
+	for i := len(original) - 1; i >= 0 && bidiIsTrailingWhitespace(original[i]); i-- {
+		levels[i] = paragraphLevel
+	}
+	for i, c := range original {
+		if c == bidi.S || c == bidi.B {
+			levels[i] = paragraphLevel
+			for j := i - 1; j >= 0 && bidiIsTrailingWhitespace(original[j]); j-- {
+				levels[j] = paragraphLevel
+			}
+		}
+	}
+	return levels, paragraphLevel
+}
+
+// bidiVisualOrder returns the logical indexes of the levels in visual (left to right) order.
+func bidiVisualOrder(levels []uint8) []int {
+	order := make([]int, len(levels))
+	var highest uint8
+	lowestOdd := uint8(maxBidiDepth + 2)
+	for i, level := range levels {
+		order[i] = i
+		highest = max(highest, level)
+		if level&1 == 1 {
+			lowestOdd = min(lowestOdd, level)
+		}
+	}
+	for level := highest; level >= lowestOdd && level > 0; level-- {
+		for i := 0; i < len(levels); {
+			if levels[order[i]] < level {
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(levels) && levels[order[j]] >= level {
+				j++
+			}
+			slices.Reverse(order[i:j])
+			i = j
+		}
+	}
+	return order
+}
+
+func bidiIsIsolateInitiator(c bidi.Class) bool {
+	return c == bidi.LRI || c == bidi.RLI || c == bidi.FSI
+}
+
+func bidiIsRemovedByX9(c bidi.Class) bool {
+	switch c {
+	case bidi.LRE, bidi.RLE, bidi.LRO, bidi.RLO, bidi.PDF, bidi.BN:
+		return true
+	default:
+		return false
+	}
+}
+
+func bidiIsNeutralOrIsolate(c bidi.Class) bool {
+	switch c {
+	case bidi.B, bidi.S, bidi.WS, bidi.ON, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI:
+		return true
+	default:
+		return false
+	}
+}
+
+func bidiIsTrailingWhitespace(c bidi.Class) bool {
+	return c == bidi.WS || bidiIsIsolateInitiator(c) || c == bidi.PDI || bidiIsRemovedByX9(c)
+}
+
+func bidiDirectionOfLevel(level uint8) bidi.Class {
+	if level&1 == 1 {
+		return bidi.R
+	}
+	return bidi.L
+}
+
+// bidiMatchIsolates returns the index of the matching PDI for each isolate initiator, or len(classes) when there is
+// none. Entries for all other characters are -1.
+func bidiMatchIsolates(classes []bidi.Class) []int {
+	matching := make([]int, len(classes))
+	var open []int
+	for i, c := range classes {
+		matching[i] = -1
+		switch {
+		case bidiIsIsolateInitiator(c):
+			open = append(open, i)
+		case c == bidi.PDI && len(open) != 0:
+			matching[open[len(open)-1]] = i
+			open = open[:len(open)-1]
+		}
+	}
+	for _, i := range open {
+		matching[i] = len(classes)
+	}
+	return matching
+}
+
+// bidiFirstStrongLevel returns the level implied by the first strong character in [start, end), skipping over the
+// content of isolates, or defaultLevel if there is none.
+func bidiFirstStrongLevel(classes []bidi.Class, start, end int, matchingPDI []int, defaultLevel uint8) uint8 {
+	for i := start; i < end; i++ {
+		switch c := classes[i]; {
+		case c == bidi.L:
+			return 0
+		case c == bidi.R || c == bidi.AL:
+			return 1
+		case bidiIsIsolateInitiator(c):
+			i = matchingPDI[i]
+		}
+	}
+	return defaultLevel
+}
+
+// bidiExplicitLevels applies rules X1 through X9, assigning the explicit embedding level of each character and
+// replacing the classes of characters affected by directional overrides. Characters removed by X9 become BN.
+func bidiExplicitLevels(classes []bidi.Class, levels []uint8, paragraphLevel uint8, matchingPDI []int) {
+	stack := make([]bidiStatus, 1, 8)
+	stack[0] = bidiStatus{level: paragraphLevel, override: bidi.ON}
+	var overflowIsolates, overflowEmbeddings, validIsolates int
+	for i, c := range classes {
+		top := stack[len(stack)-1]
+		switch c {
+		case bidi.RLE, bidi.LRE, bidi.RLO, bidi.LRO:
+			levels[i] = top.level
+			classes[i] = bidi.BN
+			level := bidiNextLevel(top.level, c == bidi.RLE || c == bidi.RLO)
+			if level <= maxBidiDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				status := bidiStatus{level: level, override: bidi.ON}
+				switch c {
+				case bidi.RLO:
+					status.override = bidi.R
+				case bidi.LRO:
+					status.override = bidi.L
+				default:
+				}
+				stack = append(stack, status)
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+		case bidi.RLI, bidi.LRI, bidi.FSI:
+			levels[i] = top.level
+			if top.override != bidi.ON {
+				classes[i] = top.override
+			}
+			rtl := c == bidi.RLI
+			if c == bidi.FSI {
+				rtl = bidiFirstStrongLevel(classes, i+1, matchingPDI[i], matchingPDI, 0) == 1
+			}
+			level := bidiNextLevel(top.level, rtl)
+			if level <= maxBidiDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, bidiStatus{level: level, override: bidi.ON, isolate: true})
+			} else {
+				overflowIsolates++
+			}
+		case bidi.PDI:
+			switch {
+			case overflowIsolates > 0:
+				overflowIsolates--
+			case validIsolates > 0:
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			default:
+			}
+			top = stack[len(stack)-1]
+			levels[i] = top.level
+			if top.override != bidi.ON {
+				classes[i] = top.override
+			}
+		case bidi.PDF:
+			levels[i] = top.level
+			classes[i] = bidi.BN
+			switch {
+			case overflowIsolates > 0:
+			case overflowEmbeddings > 0:
+				overflowEmbeddings--
+			case !top.isolate && len(stack) > 1:
+				stack = stack[:len(stack)-1]
+			default:
+			}
+		case bidi.B:
+			levels[i] = paragraphLevel
+		case bidi.BN:
+			levels[i] = top.level
+		default:
+			levels[i] = top.level
+			if top.override != bidi.ON {
+				classes[i] = top.override
+			}
+		}
+	}
+}
+
+func bidiNextLevel(level uint8, rtl bool) uint8 {
+	if rtl {
+		return (level + 1) | 1
+	}
+	return (level + 2) &^ 1
+}
+
+// bidiIsolatingRunSequences applies rule X10, returning the isolating run sequences of the text, skipping characters
+// removed by X9.
+func bidiIsolatingRunSequences(classes []bidi.Class, levels []uint8, paragraphLevel uint8,
+	matchingPDI []int) []*bidiSequence {
+	// BD7: level runs.
+	var runs [][]int
+	var current []int
+	for i, c := range classes {
+		if c == bidi.BN {
+			continue
+		}
+		if len(current) != 0 && levels[current[len(current)-1]] != levels[i] {
+			runs = append(runs, current)
+			current = nil
+		}
+		current = append(current, i)
+	}
+	if len(current) != 0 {
+		runs = append(runs, current)
+	}
+	// BD13: chain level runs that end with an isolate initiator to the run that starts with its matching PDI.
+	runForStart := make(map[int]int, len(runs))
+	for i, run := range runs {
+		runForStart[run[0]] = i
+	}
+	used := make([]bool, len(runs))
+	var sequences []*bidiSequence
+	for i, run := range runs {
+		if used[i] {
+			continue
+		}
+		var indexes []int
+		for {
+			used[i] = true
+			indexes = append(indexes, run...)
+			last := run[len(run)-1]
+			if !bidiIsIsolateInitiator(classes[last]) || matchingPDI[last] >= len(classes) {
+				break
+			}
+			next, ok := runForStart[matchingPDI[last]]
+			if !ok {
+				break
+			}
+			i = next
+			run = runs[i]
+		}
+		sequences = append(sequences, newBidiSequence(indexes, classes, levels, paragraphLevel, matchingPDI))
+	}
+	return sequences
+}
+
+func newBidiSequence(indexes []int, classes []bidi.Class, levels []uint8, paragraphLevel uint8,
+	matchingPDI []int) *bidiSequence {
+	seq := &bidiSequence{
+		indexes: indexes,
+		classes: make([]bidi.Class, len(indexes)),
+		level:   levels[indexes[0]],
+	}
+	for i, index := range indexes {
+		seq.classes[i] = classes[index]
+	}
+	prevLevel := paragraphLevel
+	for i := indexes[0] - 1; i >= 0; i-- {
+		if classes[i] != bidi.BN {
+			prevLevel = levels[i]
+			break
+		}
+	}
+	seq.sos = bidiDirectionOfLevel(max(prevLevel, seq.level))
+	last := indexes[len(indexes)-1]
+	nextLevel := paragraphLevel
+	if !bidiIsIsolateInitiator(classes[last]) || matchingPDI[last] >= len(classes) {
+		for i := last + 1; i < len(classes); i++ {
+			if classes[i] != bidi.BN {
+				nextLevel = levels[i]
+				break
+			}
+		}
+	}
+	seq.eos = bidiDirectionOfLevel(max(nextLevel, seq.level))
+	return seq
+}
+
+// resolveWeakTypes applies rules W1 through W7.
+func (s *bidiSequence) resolveWeakTypes() {
+	// W1
+	prev := s.sos
+	for i, c := range s.classes {
+		if c == bidi.NSM {
+			if bidiIsIsolateInitiator(prev) || prev == bidi.PDI {
+				s.classes[i] = bidi.ON
+			} else {
+				s.classes[i] = prev
+			}
+		}
+		prev = s.classes[i]
+	}
+	// W2 & W3
+	lastStrong := s.sos
+	for i, c := range s.classes {
+		switch c {
+		case bidi.EN:
+			if lastStrong == bidi.AL {
+				s.classes[i] = bidi.AN
+			}
+		case bidi.L, bidi.R:
+			lastStrong = c
+		case bidi.AL:
+			lastStrong = c
+			s.classes[i] = bidi.R
+		default:
+		}
+	}
+	// W4
+	for i := 1; i < len(s.classes)-1; i++ {
+		c := s.classes[i]
+		before := s.classes[i-1]
+		after := s.classes[i+1]
+		switch {
+		case c == bidi.ES && before == bidi.EN && after == bidi.EN:
+			s.classes[i] = bidi.EN
+		case c == bidi.CS && before == after && (before == bidi.EN || before == bidi.AN):
+			s.classes[i] = before
+		default:
+		}
+	}
+	// W5
+	for i := 0; i < len(s.classes); {
+		if s.classes[i] != bidi.ET {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s.classes) && s.classes[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && s.classes[i-1] == bidi.EN) || (j < len(s.classes) && s.classes[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				s.classes[k] = bidi.EN
+			}
+		}
+		i = j
+	}
+	// W6 & W7
+	lastStrong = s.sos
+	for i, c := range s.classes {
+		switch c {
+		case bidi.ES, bidi.ET, bidi.CS:
+			s.classes[i] = bidi.ON
+		case bidi.EN:
+			if lastStrong == bidi.L {
+				s.classes[i] = bidi.L
+			}
+		case bidi.L, bidi.R:
+			lastStrong = c
+		default:
+		}
+	}
+}
+
+// bidiOpeningBracketFor returns the opening bracket that pairs with the closing bracket r. The x/text bidi package
+// doesn't expose the Bidi_Paired_Bracket property, but every pair in the Unicode data has its opening bracket either
+// immediately before the closing one or, for '[' & '{', two code points before it.
+func bidiOpeningBracketFor(r rune) rune {
+	if p, _ := bidi.LookupRune(r - 1); p.IsBracket() && p.IsOpeningBracket() {
+		return bidiCanonicalBracket(r - 1)
+	}
+	return bidiCanonicalBracket(r - 2)
+}
+
+// bidiMirror returns the mirrored form of a bracket, for display within right-to-left text when no shaper is available
+// to do it. Other characters are returned unchanged.
+func bidiMirror(r rune) rune {
+	switch r {
+	case '<':
+		return '>'
+	case '>':
+		return '<'
+	default:
+	}
+	p, _ := bidi.LookupRune(r)
+	if !p.IsBracket() {
+		return r
+	}
+	if !p.IsOpeningBracket() {
+		return bidiOpeningBracketFor(r)
+	}
+	if q, _ := bidi.LookupRune(r + 1); q.IsBracket() && !q.IsOpeningBracket() {
+		return r + 1
+	}
+	return r + 2
+}
+
+// bidiCanonicalBracket maps the deprecated angle brackets to their canonical equivalents, as required by BD16.
+func bidiCanonicalBracket(r rune) rune {
+	switch r {
+	case 0x2329:
+		return 0x3008
+	case 0x232A:
+		return 0x3009
+	default:
+		return r
+	}
+}
+
+// resolvePairedBrackets applies rule N0.
+func (s *bidiSequence) resolvePairedBrackets(runes []rune, original []bidi.Class) {
+	// BD16: locate the bracket pairs.
+	type opening struct {
+		bracket rune
+		pos     int
+	}
+	type pair struct{ open, close int }
+	var stack []opening
+	var pairs []pair
+outer:
+	for i, index := range s.indexes {
+		if s.classes[i] != bidi.ON || original[index] != bidi.ON {
+			continue
+		}
+		r := runes[index]
+		p, _ := bidi.LookupRune(r)
+		if !p.IsBracket() {
+			continue
+		}
+		if p.IsOpeningBracket() {
+			if len(stack) == 63 {
+				break
+			}
+			stack = append(stack, opening{bracket: bidiCanonicalBracket(r), pos: i})
+			continue
+		}
+		want := bidiOpeningBracketFor(bidiCanonicalBracket(r))
+		for j := len(stack) - 1; j >= 0; j-- {
+			if stack[j].bracket == want {
+				pairs = append(pairs, pair{open: stack[j].pos, close: i})
+				stack = stack[:j]
+				continue outer
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(a, b pair) int { return a.open - b.open })
+	embedding := bidiDirectionOfLevel(s.level)
+	strongOf := func(c bidi.Class) bidi.Class {
+		switch c {
+		case bidi.L:
+			return bidi.L
+		case bidi.R, bidi.AL, bidi.EN, bidi.AN:
+			return bidi.R
+		default:
+			return bidi.ON
+		}
+	}
+	for _, one := range pairs {
+		var foundEmbedding, foundOpposite bool
+		for i := one.open + 1; i < one.close; i++ {
+			switch strongOf(s.classes[i]) {
+			case embedding:
+				foundEmbedding = true
+			case bidi.ON:
+			default:
+				foundOpposite = true
+			}
+		}
+		var resolved bidi.Class
+		switch {
+		case foundEmbedding:
+			resolved = embedding
+		case foundOpposite:
+			context := s.sos
+			for i := one.open - 1; i >= 0; i-- {
+				if c := strongOf(s.classes[i]); c != bidi.ON {
+					context = c
+					break
+				}
+			}
+			if context != embedding {
+				resolved = context
+			} else {
+				resolved = embedding
+			}
+		default:
+			continue
+		}
+		for _, pos := range []int{one.open, one.close} {
+			s.classes[pos] = resolved
+			for i := pos + 1; i < len(s.classes) && original[s.indexes[i]] == bidi.NSM; i++ {
+				s.classes[i] = resolved
+			}
+		}
+	}
+}
+
+// resolveNeutralTypes applies rules N1 and N2.
+func (s *bidiSequence) resolveNeutralTypes() {
+	embedding := bidiDirectionOfLevel(s.level)
+	for i := 0; i < len(s.classes); {
+		if !bidiIsNeutralOrIsolate(s.classes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s.classes) && bidiIsNeutralOrIsolate(s.classes[j]) {
+			j++
+		}
+		before := s.sos
+		if i > 0 {
+			before = s.classes[i-1]
+		}
+		after := s.eos
+		if j < len(s.classes) {
+			after = s.classes[j]
+		}
+		if before == bidi.EN || before == bidi.AN {
+			before = bidi.R
+		}
+		if after == bidi.EN || after == bidi.AN {
+			after = bidi.R
+		}
+		resolved := embedding
+		if before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			s.classes[k] = resolved
+		}
+		i = j
+	}
+}
+
+// bidiImplicitLevel applies rules I1 and I2.
+func bidiImplicitLevel(level uint8, c bidi.Class) uint8 {
+	if level&1 == 0 {
+		switch c {
+		case bidi.R:
+			return level + 1
+		case bidi.AN, bidi.EN:
+			return level + 2
+		default:
+			return level
+		}
+	}
+	switch c {
+	case bidi.L, bidi.EN, bidi.AN:
+		return level + 1
+	default:
+		return level
+	}
+}
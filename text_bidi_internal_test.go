@@ -0,0 +1,118 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+// visualString returns the runes of str in the order they are displayed.
+func visualString(str string) string {
+	runes := []rune(str)
+	levels, _ := bidiLevels(runes)
+	out := make([]rune, 0, len(runes))
+	for _, i := range bidiVisualOrder(levels) {
+		out = append(out, runes[i])
+	}
+	return string(out)
+}
+
+// TestBidiVisualOrder verifies the reordering of mixed-direction text, including numbers, brackets, isolates and
+// overrides.
+func TestBidiVisualOrder(t *testing.T) {
+	c := check.New(t)
+	for _, one := range []struct {
+		logical string
+		visual  string
+	}{
+		{"abc", "abc"},
+		{"אבג", "גבא"},
+		{"abc אבג def", "abc גבא def"},
+		{"אבג 123", "123 גבא"},
+		{"אבג abc", "abc גבא"},
+		{"a (אבג) b", "a (גבא) b"},
+		{"אבג (abc)", ")abc( גבא"},
+		{"אבג 12 דה", "הד 12 גבא"},
+		{"abc אבג ", "abc גבא "},
+		{"1.5 אב", "בא 1.5"},
+		{"⁧אב⁩ cd", "⁧בא⁩ cd"},
+		{"ab ‮שלום‬", "ab ‮םולש‬"},
+		{"ab ‮cd‬", "ab ‮dc‬"},
+	} {
+		c.Equal(one.visual, visualString(one.logical), one.logical)
+	}
+}
+
+// TestBidiLevels verifies the resolved levels and the paragraph level, which comes from the first strong character.
+func TestBidiLevels(t *testing.T) {
+	c := check.New(t)
+	levels, paragraph := bidiLevels([]rune("ab אב 12"))
+	c.Equal(uint8(0), paragraph)
+	c.Equal([]uint8{0, 0, 0, 1, 1, 1, 2, 2}, levels)
+	levels, paragraph = bidiLevels([]rune("אב 12"))
+	c.Equal(uint8(1), paragraph)
+	c.Equal([]uint8{1, 1, 1, 2, 2}, levels)
+	_, paragraph = bidiLevels([]rune("123"))
+	c.Equal(uint8(0), paragraph)
+}
+
+// TestBidiMirror verifies the bracket pairing used for mirroring when no shaper is available.
+func TestBidiMirror(t *testing.T) {
+	c := check.New(t)
+	for _, pair := range []string{"()", "[]", "{}", "<>", "⁅⁆", "〈〉", "「」"} {
+		runes := []rune(pair)
+		c.Equal(runes[1], bidiMirror(runes[0]), pair)
+		c.Equal(runes[0], bidiMirror(runes[1]), pair)
+	}
+	c.Equal('a', bidiMirror('a'))
+}
+
+// TestTextBidiCaret verifies that positions and indexes map to carets correctly in mixed-direction text. The widths of
+// the Hebrew glyphs depend on the fonts available, so only the relationships between them are checked.
+func TestTextBidiCaret(t *testing.T) {
+	c := check.New(t)
+	dec := monoDec()
+	plain := NewText("abc", dec)
+	c.False(plain.complex)
+
+	txt := NewText("ab אב", dec)
+	c.True(txt.complex)
+	width := txt.Width()
+	c.NotNil(txt.layout)
+	c.Equal(float32(0), txt.PositionForRuneIndex(0))
+	// The caret before the first right-to-left rune is at its right edge, which is the end of the line, and the caret
+	// after the last is at the left edge of the run.
+	checkClose(c, width, txt.PositionForRuneIndex(3), "leading edge of the first right-to-left rune")
+	gap := txt.PositionForRuneIndex(5)
+	checkClose(c, txt.PositionForRuneIndex(2)+txt.widths[2], gap, "trailing edge of the last right-to-left rune")
+	c.True(txt.PositionForRuneIndex(4) > gap)
+	c.True(txt.PositionForRuneIndex(4) < width)
+	c.Equal(3, txt.RuneIndexForPosition(width))
+	c.Equal(0, txt.RuneIndexForPosition(-10))
+	c.Equal(5, txt.RuneIndexForPosition(gap+txt.widths[4]/4))
+
+	// A logical range that crosses the direction change isn't contiguous on screen.
+	ranges := txt.RangesForRuneIndexes(1, 4)
+	c.Equal(2, len(ranges))
+	checkClose(c, txt.widths[0], ranges[0][0], "start of the first range")
+	checkClose(c, width, ranges[1][1], "end of the second range")
+	c.Equal(1, len(txt.RangesForRuneIndexes(3, 5)))
+	c.Equal(1, len(plain.RangesForRuneIndexes(0, 2)))
+	c.Equal(0, len(plain.RangesForRuneIndexes(2, 2)))
+
+	// Slices are laid out on their own.
+	slice := txt.Slice(0, 2)
+	c.False(slice.complex)
+	slice = txt.Slice(3, 5)
+	c.True(slice.complex)
+	checkClose(c, slice.Width(), slice.PositionForRuneIndex(0), "right-to-left slice starts at its right edge")
+}
@@ -20,9 +20,14 @@ type TextDecoration struct {
 	Font            Font
 	BackgroundInk   Ink
 	OnBackgroundInk Ink
-	BaselineOffset  float32
-	Underline       bool
-	StrikeThrough   bool
+	// UnderlineInk is used to draw the underline, if any. When nil, OnBackgroundInk is used.
+	UnderlineInk   Ink
+	BaselineOffset float32
+	Underline      bool
+	StrikeThrough  bool
+	// WavyUnderline draws a wavy underline, as is commonly used to mark errors, such as misspelled words. It is drawn
+	// in place of the straight underline if both are set.
+	WavyUnderline bool
 }
 
 // Equivalent returns true if this TextDecoration is equivalent to the other.
@@ -34,8 +39,9 @@ func (d *TextDecoration) Equivalent(other *TextDecoration) bool {
 		return false
 	}
 	return d.Underline == other.Underline && d.StrikeThrough == other.StrikeThrough &&
-		d.BaselineOffset == other.BaselineOffset && d.OnBackgroundInk == other.OnBackgroundInk &&
-		d.BackgroundInk == other.BackgroundInk && d.Font.Descriptor() == other.Font.Descriptor()
+		d.WavyUnderline == other.WavyUnderline && d.BaselineOffset == other.BaselineOffset &&
+		d.OnBackgroundInk == other.OnBackgroundInk && d.BackgroundInk == other.BackgroundInk &&
+		d.UnderlineInk == other.UnderlineInk && d.Font.Descriptor() == other.Font.Descriptor()
 }
 
 // Clone the TextDecoration.
@@ -49,6 +55,27 @@ func (d *TextDecoration) Clone() *TextDecoration {
 
 // DrawText draws the given text using this TextDecoration.
 func (d *TextDecoration) DrawText(canvas *Canvas, text string, pt geom.Point, width float32) {
+	d.draw(canvas, pt, width, func(at geom.Point, paint *Paint) { canvas.DrawSimpleString(text, at, d.Font, paint) })
+}
+
+// drawGlyphs draws shaped glyphs using this TextDecoration. The x positions are relative to pt and the y offsets are
+// relative to the baseline.
+func (d *TextDecoration) drawGlyphs(canvas *Canvas, glyphs []uint16, xs, ys []float32, pt geom.Point, width float32) {
+	d.draw(canvas, pt, width, func(at geom.Point, paint *Paint) {
+		// Text blobs only support a single baseline per run when positioned horizontally, so glyphs that have been
+		// shifted vertically (marks, mostly) are placed in runs of their own.
+		for i := 0; i < len(glyphs); {
+			j := i + 1
+			for j < len(glyphs) && ys[j] == ys[i] {
+				j++
+			}
+			canvas.DrawTextBlob(d.Font.TextBlobPosH(glyphs[i:j], xs[i:j], ys[i]), at, paint)
+			i = j
+		}
+	})
+}
+
+func (d *TextDecoration) draw(canvas *Canvas, pt geom.Point, width float32, drawer func(at geom.Point, paint *Paint)) {
 	pt.Y += d.BaselineOffset
 	r := geom.NewRect(pt.X, pt.Y-d.Font.Baseline(), width, d.Font.LineHeight())
 	if !xreflect.IsNil(d.BackgroundInk) {
@@ -56,17 +83,43 @@ func (d *TextDecoration) DrawText(canvas *Canvas, text string, pt geom.Point, wi
 		canvas.DrawRect(r, backgroundPaint)
 	}
 	paint := d.OnBackgroundInk.Paint(canvas, r, paintstyle.Fill)
-	canvas.DrawSimpleString(text, pt, d.Font, paint)
-	if d.Underline || d.StrikeThrough {
+	drawer(pt, paint)
+	if d.Underline || d.StrikeThrough || d.WavyUnderline {
 		pt.Y++
 		if d.StrikeThrough {
 			yy := pt.Y + 0.5 - d.Font.Baseline()/2
 			paint.SetStrokeWidth(1)
 			canvas.DrawLine(geom.NewPoint(pt.X, yy), geom.NewPoint(pt.X+width, yy), paint)
 		}
-		if d.Underline {
-			paint.SetStrokeWidth(1)
-			canvas.DrawLine(geom.NewPoint(pt.X, pt.Y+1), geom.NewPoint(pt.X+width, pt.Y+1), paint)
+		if d.Underline || d.WavyUnderline {
+			underlinePaint := paint
+			if !xreflect.IsNil(d.UnderlineInk) {
+				underlinePaint = d.UnderlineInk.Paint(canvas, r, paintstyle.Stroke)
+			}
+			if d.WavyUnderline {
+				drawWavyLine(canvas, pt.X, pt.X+width, pt.Y+1, underlinePaint)
+			} else {
+				underlinePaint.SetStrokeWidth(1)
+				canvas.DrawLine(geom.NewPoint(pt.X, pt.Y+1), geom.NewPoint(pt.X+width, pt.Y+1), underlinePaint)
+			}
 		}
 	}
 }
+
+// drawWavyLine draws a wavy line from left to right, centered vertically on y.
+func drawWavyLine(canvas *Canvas, left, right, y float32, paint *Paint) {
+	const (
+		amplitude  = 1
+		halfPeriod = 2
+	)
+	path := NewPath()
+	path.MoveTo(geom.NewPoint(left, y+amplitude))
+	dir := float32(-1)
+	for x := left; x < right; x += halfPeriod {
+		path.LineTo(geom.NewPoint(min(x+halfPeriod, right), y+dir*amplitude))
+		dir = -dir
+	}
+	paint.SetStyle(paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	canvas.DrawPath(path, paint)
+}
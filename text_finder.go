@@ -0,0 +1,124 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// FindOptions holds the options used when searching text.
+type FindOptions struct {
+	// MatchCase requires the case of the text to match the case of the pattern.
+	MatchCase bool
+	// WholeWord only permits matches that are not directly preceded or followed by a letter, digit or underscore.
+	WholeWord bool
+	// Regex treats the pattern as a regular expression, using the syntax of the standard regexp package, rather than as
+	// literal text.
+	Regex bool
+}
+
+// TextFinder locates occurrences of a pattern within text.
+type TextFinder struct {
+	re      *regexp.Regexp
+	options FindOptions
+}
+
+// NewTextFinder creates a new TextFinder for the pattern. An error is returned only if the pattern is to be treated as
+// a regular expression and it cannot be compiled.
+func NewTextFinder(pattern string, options FindOptions) (*TextFinder, error) {
+	if !options.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !options.MatchCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errs.NewWithCause("invalid search pattern", err)
+	}
+	return &TextFinder{re: re, options: options}, nil
+}
+
+// Options returns the options the TextFinder was created with.
+func (f *TextFinder) Options() FindOptions {
+	return f.options
+}
+
+// FindAll returns the rune ranges [start, end) of the non-overlapping matches within the text, in order. Empty matches
+// are never returned.
+func (f *TextFinder) FindAll(text []rune) [][2]int {
+	str := string(text)
+	var matches [][2]int
+	bytePos := 0
+	runePos := 0
+	toRunes := func(offset int) int {
+		runePos += utf8.RuneCountInString(str[bytePos:offset])
+		bytePos = offset
+		return runePos
+	}
+	for _, m := range f.re.FindAllStringIndex(str, -1) {
+		if m[0] == m[1] {
+			continue
+		}
+		start := toRunes(m[0])
+		end := toRunes(m[1])
+		if f.options.WholeWord && (start > 0 && isFindWordPart(text[start-1]) ||
+			end < len(text) && isFindWordPart(text[end])) {
+			continue
+		}
+		matches = append(matches, [2]int{start, end})
+	}
+	return matches
+}
+
+// Replacement returns the text that should replace the match. When the pattern is a regular expression, $1, ${name}
+// and the like within the replacement are expanded to the text of the corresponding submatch; otherwise, the
+// replacement is used as-is.
+func (f *TextFinder) Replacement(text []rune, match [2]int, replacement string) []rune {
+	if !f.options.Regex {
+		return []rune(replacement)
+	}
+	str := string(text[match[0]:match[1]])
+	submatches := f.re.FindStringSubmatchIndex(str)
+	if submatches == nil {
+		return []rune(replacement)
+	}
+	return []rune(string(f.re.ExpandString(nil, replacement, str, submatches)))
+}
+
+// nextMatch returns the match to select when moving forward or backward from the selection [start, end), wrapping
+// around at either end of the text. Returns false if there are no matches.
+func nextMatch(matches [][2]int, start, end int, backward bool) ([2]int, bool) {
+	if len(matches) == 0 {
+		return [2]int{}, false
+	}
+	if backward {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i][0] < start {
+				return matches[i], true
+			}
+		}
+		return matches[len(matches)-1], true
+	}
+	for _, m := range matches {
+		if m[0] >= end && (m[0] != start || m[1] != end) {
+			return m, true
+		}
+	}
+	return matches[0], true
+}
+
+func isFindWordPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
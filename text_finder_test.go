@@ -0,0 +1,51 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison"
+)
+
+func TestTextFinderOptions(t *testing.T) {
+	c := check.New(t)
+	text := []rune("Ünï foo Foo foobar _foo")
+
+	finder, err := unison.NewTextFinder("foo", unison.FindOptions{})
+	c.NoError(err)
+	c.Equal([][2]int{{4, 7}, {8, 11}, {12, 15}, {20, 23}}, finder.FindAll(text), "offsets are in runes")
+
+	finder, err = unison.NewTextFinder("foo", unison.FindOptions{MatchCase: true, WholeWord: true})
+	c.NoError(err)
+	c.Equal([][2]int{{4, 7}}, finder.FindAll(text))
+
+	finder, err = unison.NewTextFinder("f.o", unison.FindOptions{})
+	c.NoError(err)
+	c.Equal(0, len(finder.FindAll(text)), "patterns are literal unless Regex is set")
+
+	_, err = unison.NewTextFinder("(", unison.FindOptions{Regex: true})
+	c.HasError(err)
+}
+
+func TestTextFinderReplacement(t *testing.T) {
+	c := check.New(t)
+	text := []rune("a=1, b=2")
+	finder, err := unison.NewTextFinder(`(\w)=(\d)`, unison.FindOptions{Regex: true})
+	c.NoError(err)
+	matches := finder.FindAll(text)
+	c.Equal([][2]int{{0, 3}, {5, 8}}, matches)
+	c.Equal("2:b", string(finder.Replacement(text, matches[1], "$2:$1")))
+
+	finder, err = unison.NewTextFinder("a", unison.FindOptions{})
+	c.NoError(err)
+	c.Equal("$1", string(finder.Replacement(text, [2]int{0, 1}, "$1")))
+}
@@ -80,16 +80,17 @@ func trimmedWidth(t *Text) float32 {
 	return sumWidths(t, 0, end)
 }
 
-// isSingleWord reports whether the runes, with trailing whitespace removed, hold at most one word break and that break
-// is the final rune. BreakToWidth() documents that a minimum of one word is placed on a line even when that word is
-// wider than the requested width; such a line always looks like this.
+// isSingleWord reports whether the runes, with trailing whitespace removed, hold no line break opportunity, as found by
+// lineBreakOpportunities(). BreakToWidth() documents that a minimum of one word is placed on a line even when that word
+// is wider than the requested width; such a line always looks like this.
 func isSingleWord(runes []rune) bool {
 	end := len(runes)
 	for end > 0 && unicode.IsSpace(runes[end-1]) {
 		end--
 	}
-	for i := range end - 1 {
-		if isWordBreak(runes[i]) {
+	breaks := lineBreakOpportunities(runes, false)
+	for i := 1; i < end; i++ {
+		if breaks[i] {
 			return false
 		}
 	}
@@ -185,8 +186,8 @@ func hasInk(pix *raster.Pixmap) bool {
 	return top != -1
 }
 
-// randomLayoutText builds a deterministic pseudo-random string from an alphabet that exercises both kinds of word
-// break recognized by isWordBreak(): whitespace and the slash characters.
+// randomLayoutText builds a deterministic pseudo-random string from an alphabet that exercises the line break
+// opportunities found by lineBreakOpportunities() at whitespace, as well as those it adds around the slash characters.
 func randomLayoutText(rng *rand.Rand, count int) string {
 	const alphabet = "aaabbbcccdddeee   //\\"
 	var buffer strings.Builder
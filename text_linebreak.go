@@ -0,0 +1,139 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/go-text/typesetting/segmenter"
+	"github.com/richardwilkes/unison/internal/f32"
+)
+
+const softHyphen = '\u00AD'
+
+// LineBreakOptions holds the optional behaviors of Text.BreakToWidthWithOptions().
+type LineBreakOptions struct {
+	// SoftHyphens permits breaking a line after a soft hyphen (U+00AD), in which case a hyphen is displayed at the end
+	// of the line.
+	SoftHyphens bool
+	// EmergencyBreaks permits breaking within a word that is too wide to fit on a line by itself, rather than letting
+	// it extend past the width.
+	EmergencyBreaks bool
+}
+
+// lineBreakOpportunities returns whether a line may be broken before each position of the runes, per the Unicode Line
+// Breaking Algorithm (UAX #14). The result has one more entry than the runes, the last of which is always true.
+//
+// Two tailorings are applied: a break is permitted after a slash or backslash and before one that follows whitespace,
+// so that paths and URLs can be wrapped, and the break after a soft hyphen is only permitted when requested.
+func lineBreakOpportunities(runes []rune, softHyphens bool) []bool {
+	breaks := make([]bool, len(runes)+1)
+	if len(runes) == 0 {
+		return breaks
+	}
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	iter := seg.LineIterator()
+	for iter.Next() {
+		line := iter.Line()
+		breaks[line.Offset+len(line.Text)] = true
+	}
+	breaks[0] = false
+	breaks[len(runes)] = true
+	for i := 1; i < len(runes); i++ {
+		prev := runes[i-1]
+		switch {
+		case isPathSeparator(prev) && !unicode.IsSpace(runes[i]):
+			breaks[i] = true
+		case isPathSeparator(runes[i]) && unicode.IsSpace(prev):
+			breaks[i] = true
+		case prev == softHyphen && !softHyphens:
+			breaks[i] = false
+		default:
+		}
+	}
+	return breaks
+}
+
+func isPathSeparator(ch rune) bool {
+	return ch == '/' || ch == '\\'
+}
+
+// isGraphemeExtender returns true if the rune is displayed as part of the character before it, and so must not be
+// separated from it by an emergency break.
+func isGraphemeExtender(ch rune) bool {
+	return ch == 0x200D || unicode.In(ch, unicode.Mn, unicode.Mc, unicode.Me) ||
+		unicode.Is(unicode.Variation_Selector, ch)
+}
+
+// nextLineEnd returns the position at which the line starting at start should end.
+func (t *Text) nextLineEnd(start int, width float32, breaks []bool, emergency bool) int {
+	var w, visible float32
+	candidate := -1
+	for i := start; i < len(t.runes); i++ {
+		w += t.widths[i]
+		if !unicode.IsSpace(t.runes[i]) {
+			visible = w
+		}
+		if visible > width && candidate != -1 {
+			return candidate
+		}
+		if breaks[i+1] {
+			switch {
+			case visible <= width:
+				candidate = i + 1
+			case emergency:
+				return t.emergencyLineEnd(start, width)
+			default:
+				return i + 1
+			}
+		}
+	}
+	return len(t.runes)
+}
+
+// emergencyLineEnd returns the furthest position that fits within the width, without splitting a character from the
+// marks that follow it. At least one character is always placed on the line.
+func (t *Text) emergencyLineEnd(start int, width float32) int {
+	end := start + 1
+	for end < len(t.runes) && isGraphemeExtender(t.runes[end]) {
+		end++
+	}
+	w := f32.Sum(t.widths[start:end])
+	for end < len(t.runes) {
+		next := end + 1
+		for next < len(t.runes) && isGraphemeExtender(t.runes[next]) {
+			next++
+		}
+		nw := w + f32.Sum(t.widths[end:next])
+		if nw > width {
+			break
+		}
+		w = nw
+		end = next
+	}
+	return end
+}
+
+// hyphenated returns a copy of this Text with its final soft hyphen replaced by a visible hyphen.
+func (t *Text) hyphenated() *Text {
+	last := len(t.runes) - 1
+	other := *t
+	other.text = ""
+	other.layout = nil
+	other.extents.Width = -1
+	other.runes = slices.Clone(t.runes)
+	other.runes[last] = '-'
+	other.widths = slices.Clone(t.widths)
+	f := t.decorations[last].Font
+	other.widths[last] = f.GlyphWidth(f.RuneToGlyph('-'))
+	return &other
+}
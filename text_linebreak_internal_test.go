@@ -0,0 +1,88 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+// breakPositions returns the positions at which a line may be broken, not counting the end of the text.
+func breakPositions(str string, softHyphens bool) []int {
+	runes := []rune(str)
+	var positions []int
+	for i, ok := range lineBreakOpportunities(runes, softHyphens)[:len(runes)] {
+		if ok {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// TestLineBreakOpportunities verifies the break opportunities found for a variety of scripts and punctuation.
+func TestLineBreakOpportunities(t *testing.T) {
+	c := check.New(t)
+	for _, one := range []struct {
+		str       string
+		positions []int
+	}{
+		{"ab cd", []int{3}},
+		{"日本語", []int{1, 2}},
+		{"a\u00A0b c", []int{4}},
+		{"co-op", []int{3}},
+		{"a/b\\c", []int{2, 4}},
+		{"a /b", []int{2, 3}},
+		{"a\u00ADb", nil},
+		{"(a) b!", []int{4}},
+		{"", nil},
+	} {
+		c.Equal(one.positions, breakPositions(one.str, false), one.str)
+	}
+	c.Equal([]int{2}, breakPositions("a\u00ADb", true))
+}
+
+// TestTextBreakToWidthOptions verifies the soft hyphen and emergency break options.
+func TestTextBreakToWidthOptions(t *testing.T) {
+	c := check.New(t)
+	dec := monoDec()
+	advance := NewText("a", dec).Width()
+	lineStrings := func(lines []*Text) []string {
+		result := make([]string, len(lines))
+		for i, line := range lines {
+			result[i] = line.String()
+		}
+		return result
+	}
+
+	txt := NewText("aaaaaaaaaa", dec)
+	c.Equal([]string{"aaaaaaaaaa"}, lineStrings(txt.BreakToWidth(advance*3.7)))
+	c.Equal([]string{"aaa", "aaa", "aaa", "a"},
+		lineStrings(txt.BreakToWidthWithOptions(advance*3.7, LineBreakOptions{EmergencyBreaks: true})))
+	// A word that fits is moved to the next line rather than being split.
+	txt = NewText("aa bbbbbbbb", dec)
+	c.Equal([]string{"aa ", "bbbb", "bbbb"},
+		lineStrings(txt.BreakToWidthWithOptions(advance*4.7, LineBreakOptions{EmergencyBreaks: true})))
+
+	txt = NewText("aaa\u00ADbbb", dec)
+	c.Equal([]string{"aaa\u00ADbbb"}, lineStrings(txt.BreakToWidth(advance*4.7)))
+	lines := txt.BreakToWidthWithOptions(advance*4.7, LineBreakOptions{SoftHyphens: true})
+	c.Equal([]string{"aaa-", "bbb"}, lineStrings(lines))
+	checkClose(c, advance*4, lines[0].Width(), "width of the hyphenated line")
+	c.Equal("aaa\u00ADbbb", txt.String(), "the original text must not be modified")
+
+	// Text without spaces breaks between ideographs.
+	txt = NewText("日本語日本語", dec)
+	lines = txt.BreakToWidth(txt.Width() / 2)
+	c.True(len(lines) > 1)
+	for _, line := range lines[:len(lines)-1] {
+		c.True(line.Width() <= txt.Width()/2+0.001, line.String())
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"sync"
+	"unicode"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	"github.com/richardwilkes/toolbox/v2/xmath"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/unicode/bidi"
+)
+
+var (
+	textShaperLock sync.Mutex
+	textShaper     shaping.HarfbuzzShaper
+)
+
+// complexScripts are the scripts whose characters change shape or position depending on their neighbors.
+var complexScripts = []*unicode.RangeTable{
+	unicode.Arabic,
+	unicode.Bengali,
+	unicode.Devanagari,
+	unicode.Gujarati,
+	unicode.Gurmukhi,
+	unicode.Hebrew,
+	unicode.Kannada,
+	unicode.Khmer,
+	unicode.Lao,
+	unicode.Malayalam,
+	unicode.Mongolian,
+	unicode.Myanmar,
+	unicode.Oriya,
+	unicode.Sinhala,
+	unicode.Syriac,
+	unicode.Tamil,
+	unicode.Telugu,
+	unicode.Thaana,
+	unicode.Thai,
+	unicode.Tibetan,
+}
+
+// textLayout holds the visual arrangement of a Text that contains right-to-left or complex-script characters. Text
+// without any such characters is drawn one glyph per rune in logical order and doesn't need one.
+type textLayout struct {
+	runs   []shapedRun
+	order  []int
+	slots  []float32
+	levels []uint8
+	// awaitingSystemFonts is true if shaping was skipped for a run because the system fonts hadn't been scanned yet.
+	awaitingSystemFonts bool
+}
+
+// shapedRun is a sequence of glyphs drawn with a single decoration.
+type shapedRun struct {
+	decoration *TextDecoration
+	glyphs     []uint16
+	xs         []float32
+	ys         []float32
+	x          float32
+	width      float32
+}
+
+// needsComplexLayout returns true if the rune requires bidi reordering or shaping to be displayed correctly.
+func needsComplexLayout(r rune) bool {
+	if r < 0x0300 {
+		return false
+	}
+	if r == 0x200C || r == 0x200D || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me) {
+		return true
+	}
+	p, _ := bidi.LookupRune(r)
+	switch p.Class() {
+	case bidi.R, bidi.AL, bidi.AN, bidi.LRO, bidi.RLO, bidi.LRE, bidi.RLE, bidi.PDF, bidi.LRI, bidi.RLI, bidi.FSI,
+		bidi.PDI:
+		return true
+	default:
+		return unicode.In(r, complexScripts...)
+	}
+}
+
+func isZeroWidthInCluster(r rune) bool {
+	return r == 0x200C || r == 0x200D || unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// layoutComplex reorders the runes for display and shapes them, replacing the per-rune widths with those derived from
+// the shaped glyphs. Where a ligature or cluster covers several runes, its advance is shared among them so that a
+// caret can still be placed within it, with combining marks getting none.
+func (t *Text) layoutComplex() {
+	levels, _ := bidiLevels(t.runes)
+	layout := &textLayout{
+		order:  bidiVisualOrder(levels),
+		slots:  make([]float32, len(t.runes)),
+		levels: levels,
+	}
+	// Slice() shares the widths with the Text it came from, so they must not be modified in place.
+	t.widths = slices.Clone(t.widths)
+	segmentOf := make([]int, len(t.runes))
+	var segments [][2]int
+	var scripts []language.Script
+	for i := 0; i < len(t.runes); {
+		d := t.decorations[i]
+		script := language.LookupScript(t.runes[i])
+		j := i + 1
+		for j < len(t.runes) && levels[j] == levels[i] && (t.decorations[j] == d || d.Equivalent(t.decorations[j])) {
+			s := language.LookupScript(t.runes[j])
+			if s != language.Common && s != language.Inherited {
+				if script == language.Common || script == language.Inherited {
+					script = s
+				} else if s != script {
+					break
+				}
+			}
+			j++
+		}
+		for k := i; k < j; k++ {
+			segmentOf[k] = len(segments)
+		}
+		segments = append(segments, [2]int{i, j})
+		scripts = append(scripts, script)
+		i = j
+	}
+	// Each segment has a uniform embedding level, so it remains contiguous in visual order.
+	done := make([]bool, len(segments))
+	var x float32
+	for _, i := range layout.order {
+		if seg := segmentOf[i]; !done[seg] {
+			done[seg] = true
+			run := t.shapeSegment(layout, segments[seg][0], segments[seg][1], levels[i]&1 == 1, scripts[seg], x)
+			layout.runs = append(layout.runs, run)
+			x += run.width
+		}
+	}
+	t.layout = layout
+}
+
+func (t *Text) shapeSegment(layout *textLayout, start, end int, rtl bool, script language.Script, x float32) shapedRun {
+	run := shapedRun{decoration: t.decorations[start], x: x}
+	face, ready := run.decoration.Font.Face().shapingFace()
+	if !ready {
+		layout.awaitingSystemFonts = true
+	}
+	if face != nil {
+		direction := di.DirectionLTR
+		if rtl {
+			direction = di.DirectionRTL
+		}
+		textShaperLock.Lock()
+		out := textShaper.Shape(shaping.Input{
+			Text:      t.runes,
+			RunStart:  start,
+			RunEnd:    end,
+			Direction: direction,
+			Face:      face,
+			Size:      fixed.Int26_6(run.decoration.Font.emSize()*64 + 0.5),
+			Script:    script,
+		})
+		textShaperLock.Unlock()
+		if len(out.Glyphs) != 0 {
+			t.placeShapedGlyphs(layout, &run, out.Glyphs, start, end, rtl)
+			return run
+		}
+	}
+	// No shaping is available for the font, so fall back to one glyph per rune, reordered and mirrored as needed.
+	runes := slices.Clone(t.runes[start:end])
+	if rtl {
+		slices.Reverse(runes)
+		for i, r := range runes {
+			runes[i] = bidiMirror(r)
+		}
+	}
+	run.glyphs = run.decoration.Font.RunesToGlyphs(runes)
+	run.xs = make([]float32, len(runes))
+	run.ys = make([]float32, len(runes))
+	for i := range runes {
+		logical := start + i
+		if rtl {
+			logical = end - 1 - i
+		}
+		run.xs[i] = run.width
+		layout.slots[logical] = x + run.width
+		run.width += t.widths[logical]
+	}
+	return run
+}
+
+func (t *Text) placeShapedGlyphs(layout *textLayout, run *shapedRun, glyphs []shaping.Glyph, start, end int, rtl bool) {
+	run.glyphs = make([]uint16, len(glyphs))
+	run.xs = make([]float32, len(glyphs))
+	run.ys = make([]float32, len(glyphs))
+	clusterLeft := make(map[int]float32)
+	clusterWidth := make(map[int]float32)
+	for i, g := range glyphs {
+		run.glyphs[i] = uint16(g.GlyphID)
+		run.xs[i] = run.width + fixedToFloat(g.XOffset)
+		run.ys[i] = -fixedToFloat(g.YOffset)
+		if _, exists := clusterLeft[g.ClusterIndex]; !exists {
+			clusterLeft[g.ClusterIndex] = run.width
+		}
+		advance := fixedToFloat(g.XAdvance)
+		clusterWidth[g.ClusterIndex] += advance
+		run.width += advance
+	}
+	clusters := make([]int, 0, len(clusterLeft))
+	for cluster := range clusterLeft {
+		clusters = append(clusters, cluster)
+	}
+	slices.Sort(clusters)
+	for i, cluster := range clusters {
+		clusterEnd := end
+		if i < len(clusters)-1 {
+			clusterEnd = clusters[i+1]
+		}
+		bases := 0
+		for j := cluster; j < clusterEnd; j++ {
+			if j == cluster || !isZeroWidthInCluster(t.runes[j]) {
+				bases++
+			}
+		}
+		share := clusterWidth[cluster] / float32(bases)
+		var offset float32
+		for j := cluster; j < clusterEnd; j++ {
+			w := share
+			if j != cluster && isZeroWidthInCluster(t.runes[j]) {
+				w = 0
+			}
+			t.widths[j] = w
+			if rtl {
+				layout.slots[j] = run.x + clusterLeft[cluster] + clusterWidth[cluster] - offset - w
+			} else {
+				layout.slots[j] = run.x + clusterLeft[cluster] + offset
+			}
+			offset += w
+		}
+	}
+	// Runes before the first cluster can only occur if the shaper dropped them; give them no room.
+	if len(clusters) != 0 {
+		for j := start; j < clusters[0]; j++ {
+			t.widths[j] = 0
+			layout.slots[j] = run.x
+		}
+	}
+}
+
+func (l *textLayout) rtl(index int) bool {
+	return l.levels[index]&1 == 1
+}
+
+// caretPosition returns the x-coordinate of the caret placed before the rune at the given logical index, which is the
+// leading edge of that rune. The caret placed after the last rune is at its trailing edge.
+func (l *textLayout) caretPosition(index int, widths []float32) float32 {
+	index = max(index, 0)
+	if index >= len(l.slots) {
+		index = len(l.slots) - 1
+		if l.rtl(index) {
+			return l.slots[index]
+		}
+		return l.slots[index] + widths[index]
+	}
+	if l.rtl(index) {
+		return l.slots[index] + widths[index]
+	}
+	return l.slots[index]
+}
+
+// runeIndex returns the logical index of the caret position nearest to x.
+func (l *textLayout) runeIndex(x float32, widths []float32) int {
+	for k, i := range l.order {
+		w := widths[i]
+		if k < len(l.order)-1 && (w == 0 || x >= l.slots[i]+w) {
+			continue
+		}
+		index := i
+		if (x < l.slots[i]+w/2) == l.rtl(i) {
+			index++
+		}
+		for index < len(widths) && widths[index] == 0 {
+			index++
+		}
+		return index
+	}
+	return 0
+}
+
+// visualRanges returns the horizontal extents covered by the runes in the logical range [start, end), which may not be
+// contiguous when the text is bidirectional.
+func (l *textLayout) visualRanges(start, end int, widths []float32) [][2]float32 {
+	var ranges [][2]float32
+	for _, i := range l.order {
+		if i < start || i >= end || widths[i] == 0 {
+			continue
+		}
+		left := l.slots[i]
+		right := left + widths[i]
+		if n := len(ranges); n != 0 && xmath.Abs(ranges[n-1][1]-left) < 0.01 {
+			ranges[n-1][1] = right
+		} else {
+			ranges = append(ranges, [2]float32{left, right})
+		}
+	}
+	return ranges
+}
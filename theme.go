@@ -27,6 +27,7 @@ var (
 	ThemeTooltip          = DefaultThemeTooltip()
 	ThemeError            = DefaultThemeError()
 	ThemeWarning          = DefaultThemeWarning()
+	ThemeSearchMatch      = DefaultThemeSearchMatch()
 	ThemeCursorForeground = DefaultThemeCursorForeground()
 	ThemeCursorBackground = DefaultThemeCursorBackground()
 )
@@ -86,6 +87,12 @@ func DefaultThemeWarning() *ThemeColor {
 	return &ThemeColor{Light: RGB(217, 76, 0), Dark: RGB(191, 67, 0)}
 }
 
+// DefaultThemeSearchMatch returns the default search match color, used to highlight the matches found by a search.
+// It is translucent, since it may be drawn over the text it highlights.
+func DefaultThemeSearchMatch() *ThemeColor {
+	return &ThemeColor{Light: RGB(255, 200, 0).SetAlphaIntensity(0.45), Dark: RGB(204, 153, 0).SetAlphaIntensity(0.45)}
+}
+
 // DefaultThemeCursorForeground returns the default cursor foreground color, used for the body and linework of the
 // built-in cursors. The light and dark values are identical by default, since cursors conventionally do not invert when
 // the system switches between light and dark mode.
@@ -0,0 +1,131 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison/enums/mod"
+)
+
+// TimeField holds a time of day that can be edited. The Up and Down keys step the hour, minute, second or AM/PM under
+// the caret, wrapping around at midnight.
+type TimeField struct {
+	*Field
+	layout string
+	value  time.Time
+}
+
+// NewTimeField creates a new field that holds the time of day of the value, presented using DefaultDateTimeFormat.
+func NewTimeField(value time.Time) *TimeField {
+	f := &TimeField{
+		Field:  NewField(),
+		layout: DefaultDateTimeFormat.TimeLayout,
+		value:  timeOfDay(value),
+	}
+	f.Self = f
+	UninstallFocusBorders(f, f)
+	f.LostFocusCallback = f.DefaultFocusLost
+	InstallDefaultFieldBorder(f, f)
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.ValidateCallback = f.DefaultValidate
+	f.SetText(f.value.Format(f.layout))
+	f.adjustMinimumTextWidth()
+	return f
+}
+
+// timeOfDay returns the time of day of the value on January 1 of year 0 in UTC, matching what time.Parse produces for a
+// layout without a date.
+func timeOfDay(value time.Time) time.Time {
+	hour, minute, second := value.Clock()
+	return time.Date(0, time.January, 1, hour, minute, second, 0, time.UTC)
+}
+
+// Layout returns the layout used to present the time, in the form accepted by time.Format.
+func (f *TimeField) Layout() string {
+	return f.layout
+}
+
+// SetLayout sets the layout used to present the time, in the form accepted by time.Format. See
+// DateTimeFormat.TimeLayout for the elements that are supported.
+func (f *TimeField) SetLayout(layout string) {
+	if f.layout != layout {
+		value := f.Value()
+		f.layout = layout
+		f.SetText(value.Format(layout))
+		f.adjustMinimumTextWidth()
+	}
+}
+
+// Value returns the time in the field. Only the hour, minute and second are meaningful; the date is always January 1
+// of year 0 in UTC. If the text in the field is not a valid time, the most recent valid one is returned.
+func (f *TimeField) Value() time.Time {
+	if value, err := f.parse(); err == nil {
+		return value
+	}
+	return f.value
+}
+
+// SetValue sets the field to the time of day of the value.
+func (f *TimeField) SetValue(value time.Time) {
+	f.value = timeOfDay(value)
+	if text := f.value.Format(f.layout); text != f.Text() {
+		f.SetText(text)
+	}
+}
+
+func (f *TimeField) parse() (time.Time, error) {
+	value, err := time.Parse(f.layout, strings.TrimSpace(f.Text()))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return timeOfDay(value), nil
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (f *TimeField) DefaultFocusLost() {
+	f.SetValue(f.Value())
+	f.Field.DefaultFocusLost()
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *TimeField) DefaultKeyDown(keyCode KeyCode, mods mod.Modifiers, repeat bool) bool {
+	if (keyCode == KeyUp || keyCode == KeyDown) && mods&mod.NonSticky == 0 {
+		delta := 1
+		if keyCode == KeyDown {
+			delta = -1
+		}
+		if stepDateTimeField(f.Field, f.layout, f.Value(), delta, timeOfDay) {
+			return true
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mods, repeat)
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (f *TimeField) DefaultValidate() bool {
+	value, err := f.parse()
+	if err != nil {
+		example := time.Date(0, time.January, 1, 13, 45, 0, 0, time.UTC).Format(f.layout)
+		f.Tooltip = NewTooltipWithText(fmt.Sprintf(i18n.Text("Invalid time; expected a time such as %s"), example))
+		return false
+	}
+	// Remember the most recent valid time, so that it can be restored should the text become invalid
+	f.value = value
+	f.Tooltip = nil
+	return true
+}
+
+func (f *TimeField) adjustMinimumTextWidth() {
+	f.SetMinimumTextWidthUsing(time.Date(0, time.January, 1, 20, 58, 58, 0, time.UTC).Format(f.layout))
+}
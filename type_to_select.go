@@ -0,0 +1,90 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+	"unicode"
+)
+
+// DefaultTypeToSelectTimeout is the default amount of time that may pass between keystrokes before typing to select
+// an item begins a new search.
+const DefaultTypeToSelectTimeout = time.Second
+
+// typeToSelect tracks the runes typed in quick succession to select an item by the start of its text.
+type typeToSelect struct {
+	last   time.Time
+	prefix []rune
+}
+
+// active returns true if a search is in progress, i.e. the timeout has not passed since the last rune was typed.
+func (s *typeToSelect) active(timeout time.Duration) bool {
+	return len(s.prefix) != 0 && time.Since(s.last) <= timeout
+}
+
+// find adds the rune to the search and returns the index of the item that should be selected, or -1 if none of the
+// items match. When the prefix is extended, the search begins with the current item, so that it remains selected for
+// as long as it matches. When a new search begins, or the same rune is typed repeatedly, the search begins with the
+// item after the current one, so that successive items starting with that rune are visited in turn.
+func (s *typeToSelect) find(ch rune, timeout time.Duration, count, current int, text func(index int) string) int {
+	if !s.active(timeout) {
+		s.prefix = s.prefix[:0]
+	}
+	s.last = time.Now()
+	s.prefix = append(s.prefix, ch)
+	prefix := s.prefix
+	start := current
+	if allSameRune(prefix) {
+		prefix = prefix[:1]
+		start++
+	}
+	start = max(start, 0)
+	for i := range count {
+		index := (start + i) % count
+		if hasPrefixFold([]rune(text(index)), prefix) {
+			return index
+		}
+	}
+	return -1
+}
+
+func allSameRune(runes []rune) bool {
+	for _, r := range runes[1:] {
+		if r != runes[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefixFold(text, prefix []rune) bool {
+	if len(text) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if !equalFoldRune(text[i], r) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalFoldRune returns true if the runes are equal under simple Unicode case-folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
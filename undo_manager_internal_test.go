@@ -0,0 +1,30 @@
+// Copyright (c) 2021-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import "testing"
+
+// undoHostPanel is an UndoManagerProvider for the panels added to it.
+type undoHostPanel struct {
+	mgr *UndoManager
+	Panel
+}
+
+func (p *undoHostPanel) UndoManager() *UndoManager {
+	return p.mgr
+}
+
+// newUndoHost returns an undoHostPanel whose UndoManager reports any errors to the test.
+func newUndoHost(t *testing.T) *undoHostPanel {
+	t.Helper()
+	host := &undoHostPanel{mgr: NewUndoManager(100, func(err error) { t.Error(err) })}
+	host.Self = host
+	return host
+}
@@ -76,6 +76,7 @@ type Window struct {
 	surface                     *surface
 	glCtx                       *apiGLContext
 	root                        *rootPanel
+	headless                    *headlessWindow
 	focus                       *Panel
 	cursor                      *Cursor
 	lastDropTarget              *Panel
@@ -83,6 +84,7 @@ type Window struct {
 	lastMouseDownPanel          *Panel
 	lastMouseOverPanel          *Panel
 	lastKeyDownPanel            *Panel
+	compositionPanel            *Panel
 	lastTooltip                 *Panel
 	lastTooltipShownAt          time.Time
 	lastButtonTime              time.Time
@@ -98,6 +100,7 @@ type Window struct {
 	lastButton                  int
 	lastButtonCount             int
 	lastContentRect             geom.Rect
+	lastCompositionCaretRect    geom.Rect
 	firstButtonLocation         geom.Point
 	dragDataLocation            geom.Point
 	lastWidth                   float32
@@ -117,6 +120,7 @@ type Window struct {
 	inMouseDown                 bool
 	cursorHiddenUntilMouseMoves bool
 	cursorHidden                bool
+	composing                   bool
 	minimized                   bool
 	maximized                   bool
 }
@@ -244,10 +248,15 @@ func NewWindow(title string, options ...WindowOption) (*Window, error) {
 		}
 	}
 	windowList = append(windowList, w)
-	err := w.apiInit()
-	if err == nil && !cpuRenderingActive {
-		if glErr := w.glCtx.apiCreate(w); glErr != nil {
-			fallbackToCPURendering(glErr)
+	var err error
+	if headlessActive.Load() {
+		w.headless = &headlessWindow{contentRect: geom.NewRect(0, 0, 1, 1)}
+	} else {
+		err = w.apiInit()
+		if err == nil && !cpuRenderingActive {
+			if glErr := w.glCtx.apiCreate(w); glErr != nil {
+				fallbackToCPURendering(glErr)
+			}
 		}
 	}
 	if err != nil {
@@ -310,13 +319,12 @@ func (w *Window) gainedFocus() {
 	}
 	SafeCall(w.GainedFocusCallback)
 	w.mouseEnter(w.MouseLocation(), 0)
-	if w.apiCursorInContentArea() {
-		w.apiUpdateCursorImage()
-	}
+	w.adjustToCursorChange()
 }
 
 func (w *Window) lostFocus() {
 	w.restoreHiddenCursor()
+	w.cancelComposition()
 	w.focused = false
 	w.ClearTooltip()
 	if w.focus != nil {
@@ -442,7 +450,11 @@ func (w *Window) destroy() {
 	if w == wndWithCurrentCtx {
 		w.releaseGLCtxCurrent()
 	}
-	w.apiDestroy()
+	if w.headless != nil {
+		w.headlessDestroy()
+	} else {
+		w.apiDestroy()
+	}
 	windowList = slices.DeleteFunc(windowList, func(wnd *Window) bool { return wnd == w })
 }
 
@@ -455,7 +467,7 @@ func (w *Window) Title() string {
 func (w *Window) SetTitle(title string) {
 	if w.title != title {
 		w.title = title
-		if w.IsValid() {
+		if w.IsValid() && w.headless == nil {
 			w.apiSetTitle(title)
 		}
 	}
@@ -482,7 +494,9 @@ func (w *Window) SetTitleIcons(images []*Image) {
 				imgs = append(imgs, nrgba)
 			}
 		}
-		w.apiSetTitleIcons(imgs)
+		if w.headless == nil {
+			w.apiSetTitleIcons(imgs)
+		}
 	}
 }
 
@@ -507,6 +521,9 @@ func (w *Window) ValidateLayout() {
 // Display returns the display that this window is currently on, or the primary display if the window is not valid.
 func (w *Window) Display() *Display {
 	if w.IsValid() {
+		if w.headless != nil {
+			return BestDisplayForRect(w.FrameRect())
+		}
 		return w.apiDisplay()
 	}
 	return PrimaryDisplay()
@@ -516,6 +533,9 @@ func (w *Window) Display() *Display {
 // the content and its border and window controls).
 func (w *Window) FrameRect() geom.Rect {
 	if w.IsValid() {
+		if w.headless != nil {
+			return w.headless.contentRect
+		}
 		return w.apiFrameRect()
 	}
 	return geom.NewRect(0, 0, 1, 1)
@@ -523,7 +543,7 @@ func (w *Window) FrameRect() geom.Rect {
 
 // FrameRectForContentRect returns the frame rect for the given content rect.
 func (w *Window) FrameRectForContentRect(contentRect geom.Rect) geom.Rect {
-	if w.IsValid() {
+	if w.IsValid() && w.headless == nil {
 		return w.apiFrameRectForContentRect(contentRect)
 	}
 	return contentRect
@@ -538,6 +558,13 @@ func (w *Window) SetFrameRect(rect geom.Rect) {
 // to preserve its size if it is necessary to reposition it, though shrinking the window if necessary to fit.
 func (w *Window) EnsureOnDisplay() {
 	if w.IsValid() {
+		if w.headless != nil {
+			frameRect := w.FrameRect()
+			if revisedRect := w.Display().FitRectOnto(frameRect); revisedRect != frameRect {
+				w.SetFrameRect(revisedRect)
+			}
+			return
+		}
 		w.apiEnsureOnDisplay()
 	}
 }
@@ -545,6 +572,9 @@ func (w *Window) EnsureOnDisplay() {
 // ContentRect returns the boundaries in display coordinates of the window's content area.
 func (w *Window) ContentRect() geom.Rect {
 	if w.IsValid() {
+		if w.headless != nil {
+			return w.headless.contentRect
+		}
 		return w.apiContentRect()
 	}
 	return geom.NewRect(0, 0, 1, 1)
@@ -552,7 +582,7 @@ func (w *Window) ContentRect() geom.Rect {
 
 // ContentRectForFrameRect returns the content rect for the given frame rect.
 func (w *Window) ContentRectForFrameRect(frameRect geom.Rect) geom.Rect {
-	if w.IsValid() {
+	if w.IsValid() && w.headless == nil {
 		return w.apiContentRectForFrameRect(frameRect)
 	}
 	return frameRect
@@ -563,7 +593,11 @@ func (w *Window) ContentRectForFrameRect(frameRect geom.Rect) geom.Rect {
 func (w *Window) SetContentRect(rect geom.Rect) {
 	if w.IsValid() {
 		rect = w.adjustContentRectForMinMax(rect)
-		w.apiSetContentRect(rect)
+		if w.headless != nil {
+			w.headlessSetContentRect(rect)
+		} else {
+			w.apiSetContentRect(rect)
+		}
 	}
 }
 
@@ -702,6 +736,7 @@ func (w *Window) SetFocus(target Paneler) {
 			}
 		}
 		if !newFocus.Is(w.focus) {
+			w.cancelComposition()
 			if w.focus != nil {
 				SafeCall(oldFocus.LostFocusCallback)
 			}
@@ -717,6 +752,7 @@ func (w *Window) SetFocus(target Paneler) {
 func (w *Window) removeFocus() {
 	oldFocus := w.focus
 	if oldFocus != nil {
+		w.cancelComposition()
 		SafeCall(oldFocus.LostFocusCallback)
 		w.focus = nil
 		w.notifyOfFocusChangeInHierarchy(oldFocus, nil)
@@ -798,7 +834,13 @@ func collectFocusables(current, target *Panel, focusables []*Panel) (match int,
 
 // IsVisible returns true if the window is currently being shown.
 func (w *Window) IsVisible() bool {
-	return w.IsValid() && w.apiVisible()
+	if w.IsValid() {
+		if w.headless != nil {
+			return w.headless.visible
+		}
+		return w.apiVisible()
+	}
+	return false
 }
 
 // IsTransparent returns true if the window was created with a transparent backing buffer.
@@ -810,7 +852,11 @@ func (w *Window) IsTransparent() bool {
 // mode, this function does nothing.
 func (w *Window) Show() {
 	if w.IsValid() {
-		w.apiShow()
+		if w.headless != nil {
+			w.headlessShow()
+		} else {
+			w.apiShow()
+		}
 	}
 }
 
@@ -818,7 +864,11 @@ func (w *Window) Show() {
 // function does nothing.
 func (w *Window) Hide() {
 	if w.IsValid() {
-		w.apiHide()
+		if w.headless != nil {
+			w.headlessHide()
+		} else {
+			w.apiHide()
+		}
 	}
 }
 
@@ -828,7 +878,11 @@ func (w *Window) ToFront() {
 	if w.IsValid() && !w.keepHidden {
 		w.Show()
 		w.focused = true // Don't wait for the focus event to set this, as Linux delays the notification too much
-		w.apiAcquireFocusAndBringToFront()
+		if w.headless != nil {
+			w.headlessAcquireFocus()
+		} else {
+			w.apiAcquireFocusAndBringToFront()
+		}
 	}
 }
 
@@ -840,7 +894,14 @@ func (w *Window) IsMinimized() bool {
 // Minimize performs the minimize function on the window, or restores it if it is already minimized.
 func (w *Window) Minimize() {
 	if w.IsValid() {
-		w.apiMinimize()
+		if w.headless != nil {
+			w.minimized = !w.minimized
+			if w.MinimizedCallback != nil {
+				SafeCall(func() { w.MinimizedCallback(w.minimized) })
+			}
+		} else {
+			w.apiMinimize()
+		}
 	}
 }
 
@@ -852,7 +913,14 @@ func (w *Window) IsMaximized() bool {
 // Maximize performs the maximize function on the window, or restores it if it is already maximized.
 func (w *Window) Maximize() {
 	if w.IsValid() {
-		w.apiMaximize()
+		if w.headless != nil {
+			w.maximized = !w.maximized
+			if w.MaximizedCallback != nil {
+				SafeCall(func() { w.MaximizedCallback(w.maximized) })
+			}
+		} else {
+			w.apiMaximize()
+		}
 	}
 }
 
@@ -864,12 +932,18 @@ func (w *Window) Resizable() bool {
 // MouseLocation returns the current mouse location relative to this window.
 func (w *Window) MouseLocation() geom.Point {
 	if w.IsValid() {
+		if w.headless != nil {
+			return w.headless.mouse
+		}
 		return w.apiCursorPosition()
 	}
 	return geom.Point{}
 }
 
 func (w *Window) adjustToCursorChange() {
+	if w.headless != nil {
+		return
+	}
 	if w.apiCursorInContentArea() {
 		w.apiUpdateCursorImage()
 	}
@@ -878,6 +952,9 @@ func (w *Window) adjustToCursorChange() {
 // BackingScale returns the scale of the backing store for this window.
 func (w *Window) BackingScale() geom.Point {
 	if w.IsValid() {
+		if w.headless != nil {
+			return w.Display().Scale
+		}
 		return w.apiBackingScale()
 	}
 	return geom.NewPoint(1, 1)
@@ -928,6 +1005,14 @@ func (w *Window) draw() {
 		c.Restore()
 		c.Flush()
 		w.lastDrawDuration = time.Since(start)
+		w.updateCompositionCaretRect()
+		if w.headless != nil {
+			// Nothing to present to; the rendered content is retrieved on demand via CaptureImage().
+			return
+		}
+		// Anything assistive technologies might be told about changes visibly only as the result of a redraw, so this is
+		// where the platform looks for changes to announce.
+		w.apiUpdateAccessibility()
 		if pixels := w.surface.rasterPixmap(); pixels != nil {
 			// The window may have a live GL context even though rendering fell back to the CPU (the fallback was
 			// triggered while preparing this window's canvas). Destroy it so it cannot obscure the CPU-rendered content.
@@ -966,7 +1051,7 @@ func (w *Window) MarkForRedraw() {
 	if _, exists := redrawSet[w]; !exists {
 		redrawSet[w] = struct{}{}
 		if len(redrawSet) == 1 {
-			apiPostEmptyEvent()
+			postEmptyEvent()
 		}
 	}
 }
@@ -996,9 +1081,7 @@ func (w *Window) ShowCursor() {
 
 func (w *Window) updateCursorVisibility() {
 	if w.focused {
-		if w.apiCursorInContentArea() {
-			w.apiUpdateCursorImage()
-		}
+		w.adjustToCursorChange()
 	}
 }
 
@@ -1426,6 +1509,9 @@ func (w *Window) keyReleased(key KeyCode, mods mod.Modifiers) {
 // however, on platforms that are using native menus, this will also capture modifier changes that occurred while the
 // menu is being displayed.
 func (w *Window) CurrentKeyModifiers() mod.Modifiers {
+	if w.headless != nil {
+		return w.lastKeyModifiers
+	}
 	return w.apiCurrentKeyModifiers()
 }
 
@@ -1460,6 +1546,11 @@ func (w *Window) StartDrag(img *Image, origin geom.Point, cleanup func(), opMask
 	}
 	w.synthesizeMouseUp()
 	w.dragSourceCleanup = cleanup
+	if w.headless != nil {
+		// There is no platform drag & drop to hand the data to, so the drag finishes immediately without a drop.
+		w.dragSourceFinished()
+		return
+	}
 	w.apiStartDrag(img, origin, opMask, data...)
 }
 
@@ -1581,7 +1672,7 @@ func (w *Window) UnregisterForDragTypes(types ...*uti.DataType) {
 func (w *Window) ClearRegisteredDragTypes() {
 	needUpdate := len(w.dragTypes) != 0
 	w.dragTypes = nil
-	if needUpdate {
+	if needUpdate && w.headless == nil {
 		w.apiUpdateRegisteredDragTypes(nil)
 	}
 }
@@ -1594,7 +1685,7 @@ func (w *Window) collectedRegisteredDragTypes() []*uti.DataType {
 
 func (w *Window) finishRegisteredDragTypesUpdate(previous []*uti.DataType) {
 	revised := w.collectedRegisteredDragTypes()
-	if !slices.Equal(previous, revised) {
+	if !slices.Equal(previous, revised) && w.headless == nil {
 		w.apiUpdateRegisteredDragTypes(revised)
 	}
 }
@@ -421,6 +421,18 @@ func (w *Window) apiUpdateRegisteredDragTypes(types []*uti.DataType) {
 	}
 }
 
+func (w *Window) apiSetCompositionCaretRect(_ geom.Rect) {
+	// AppKit's text input client drives composition itself and currently anchors the candidate window to the view as
+	// a whole (see viewFirstRectForCharacterRange), so there is nothing to pass along.
+}
+
+func (w *Window) apiCancelComposition() {
+}
+
+func (w *Window) apiUpdateAccessibility() {
+	// Not yet exposed to VoiceOver.
+}
+
 func (w *Window) apiDestroy() {
 	w.glCtx.apiDestroy()
 	if w.wnd.wnd != 0 {
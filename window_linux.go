@@ -20,7 +20,6 @@ import (
 	"slices"
 	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
@@ -951,6 +950,10 @@ func (w *Window) apiUpdateRegisteredDragTypes(types []*uti.DataType) {
 }
 
 func (w *Window) apiDestroy() {
+	if x11InputMethodFocus == w {
+		x11InputMethodFocus = nil
+	}
+	x11AccessibilityWindowClosed(w)
 	w.glCtx.apiDestroy()
 	if w.wnd.gc != 0 {
 		x11Conn.FreeGC(w.wnd.gc)
@@ -1043,18 +1046,11 @@ func x11ProcessEvent(e x11.Event) {
 		}
 	case *x11.KeyPressEvent:
 		if w := x11FindWindow(ev.Event); w != nil {
-			mods := x11TranslateModifierState(ev.State)
-			w.keyPressed(rawScanCodeToKeyCodeMap[uint16(ev.Detail)], mods)
-			if mods&(mod.Control|mod.Option|mod.Command) == 0 {
-				keySym := x11ScanCodeToKeySym(uint16(ev.Detail), mods, ev.State&x11Mod5Mask != 0)
-				if ch := x11KeySymToUnicode(keySym); ch != utf8.RuneError {
-					w.runeTyped(ch)
-				}
-			}
+			w.x11HandleKey(ev.Detail, ev.State, false)
 		}
 	case *x11.KeyReleaseEvent:
 		if w := x11FindWindow(ev.Event); w != nil {
-			w.keyReleased(rawScanCodeToKeyCodeMap[uint16(ev.Detail)], x11TranslateModifierState(ev.State))
+			w.x11HandleKey(ev.Detail, ev.State, true)
 		}
 	case *x11.MappingNotifyEvent:
 		if ev.Request != x11.MappingPointer {
@@ -1249,6 +1245,8 @@ func x11ProcessEvent(e x11.Event) {
 				return
 			}
 			w.gainedFocus()
+			x11InputMethodFocusIn(w)
+			x11AccessibilityWindowActivated(w, true)
 		}
 	case *x11.FocusOutEvent:
 		if w := x11FindWindow(ev.Window); w != nil {
@@ -1256,6 +1254,8 @@ func x11ProcessEvent(e x11.Event) {
 				return
 			}
 			w.lostFocus()
+			x11InputMethodFocusOut(w)
+			x11AccessibilityWindowActivated(w, false)
 		}
 	case *x11.ExposeEvent:
 		if w := x11FindWindow(ev.Window); w != nil {
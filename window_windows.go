@@ -976,6 +976,17 @@ func (w *Window) apiUpdateRegisteredDragTypes(types []*uti.DataType) {
 	}
 }
 
+func (w *Window) apiSetCompositionCaretRect(_ geom.Rect) {
+	// Composition is left to the system's default IME window, which tracks the caret on its own.
+}
+
+func (w *Window) apiCancelComposition() {
+}
+
+func (w *Window) apiUpdateAccessibility() {
+	// Not yet exposed to UI Automation.
+}
+
 func (w *Window) apiDestroy() {
 	w.glCtx.apiDestroy()
 	w.w32DisposePresentSurface()